@@ -2,6 +2,7 @@ package app
 
 import (
 	"encoding/json"
+	"fmt"
 	"github.com/iov-one/iovns/x/configuration"
 	"github.com/iov-one/iovns/x/signutil"
 	"github.com/iov-one/iovns/x/starname"
@@ -76,6 +77,8 @@ var (
 		staking.BondedPoolName:    {supply.Burner, supply.Staking},
 		staking.NotBondedPoolName: {supply.Burner, supply.Staking},
 		gov.ModuleName:            {supply.Burner},
+		// starname moves the price of transfer offers
+		starname.ModuleName: nil,
 	}
 )
 
@@ -293,8 +296,13 @@ func NewNameService(
 		app.supplyKeeper,
 		app.subspaces[starname.ModuleName],
 	)
+	// register here the hooks of the modules reacting to domains and accounts state changes
+	app.domainKeeper.SetHooks(starname.NewMultiStarnameHooks())
 	// iovns keepers - end
 
+	// register the upgrade handlers
+	app.registerUpgradeHandlers()
+
 	// NOTE: Any module instantiated in the module manager that is later modified
 	// must be passed by reference here.
 	app.mm = module.NewManager(
@@ -308,8 +316,8 @@ func NewNameService(
 		slashing.NewAppModule(app.slashingKeeper, app.accountKeeper, app.stakingKeeper),
 		distr.NewAppModule(app.distrKeeper, app.accountKeeper, app.supplyKeeper, app.stakingKeeper),
 		// iovns modules
-		configuration.NewAppModule(app.configurationKeeper),
-		starname.NewAppModule(app.domainKeeper),
+		configuration.NewAppModule(app.configurationKeeper, app.accountKeeper),
+		starname.NewAppModule(app.domainKeeper, app.accountKeeper),
 		signutil.NewAppModule(),
 		// iovns modules - end
 		staking.NewAppModule(app.stakingKeeper, app.accountKeeper, app.supplyKeeper),
@@ -321,15 +329,18 @@ func NewNameService(
 	// CanWithdrawInvariant invariant.
 
 	app.mm.SetOrderBeginBlockers(upgrade.ModuleName, mint.ModuleName, distr.ModuleName, slashing.ModuleName)
-	app.mm.SetOrderEndBlockers(crisis.ModuleName, gov.ModuleName, staking.ModuleName)
+	// starname removes the expired transfer offers before invariants are asserted
+	app.mm.SetOrderEndBlockers(starname.ModuleName, crisis.ModuleName, gov.ModuleName, staking.ModuleName)
 
 	// Sets the order of Genesis - Order matters, genutil is to always come last
 	// NOTE: The genutils module must occur after staking so that pools are
 	// properly initialized with tokens from genesis accounts.
+	// The auth module must occur first so that genesis accounts keep their
+	// account numbers instead of following the module accounts created by other modules.
 	app.mm.SetOrderInitGenesis(
+		auth.ModuleName,
 		distr.ModuleName,
 		staking.ModuleName,
-		auth.ModuleName,
 		bank.ModuleName,
 		slashing.ModuleName,
 		gov.ModuleName,
@@ -344,6 +355,9 @@ func NewNameService(
 		evidence.ModuleName,
 	)
 
+	// register the starname invariants to the crisis keeper
+	starname.RegisterInvariants(&app.crisisKeeper, app.domainKeeper)
+
 	// register all module routes and module queriers
 	app.mm.RegisterRoutes(app.Router(), app.QueryRouter())
 
@@ -375,7 +389,8 @@ func NewNameService(
 		staking.NewAppModule(app.stakingKeeper, app.accountKeeper, app.supplyKeeper),
 		slashing.NewAppModule(app.slashingKeeper, app.accountKeeper, app.stakingKeeper),
 		// iovns module start
-		// TODO configuration.NewAppModule(app.configurationKeeper),
+		configuration.NewAppModule(app.configurationKeeper, app.accountKeeper),
+		starname.NewAppModule(app.domainKeeper, app.accountKeeper),
 		// iovns module end
 	)
 
@@ -403,6 +418,23 @@ func NewDefaultGenesisState() GenesisState {
 	return ModuleBasics.DefaultGenesis()
 }
 
+// ValidateGenesis validates the genesis state of every module, then checks the domains
+// and accounts of the starname genesis state against the configuration genesis state
+func ValidateGenesis(cdc *codec.Codec, genesis GenesisState) error {
+	if err := ModuleBasics.ValidateGenesis(genesis); err != nil {
+		return err
+	}
+	var confState configuration.GenesisState
+	if err := cdc.UnmarshalJSON(genesis[configuration.ModuleName], &confState); err != nil {
+		return fmt.Errorf("failed to unmarshal %s genesis state: %w", configuration.ModuleName, err)
+	}
+	var starnameState starname.GenesisState
+	if err := cdc.UnmarshalJSON(genesis[starname.ModuleName], &starnameState); err != nil {
+		return fmt.Errorf("failed to unmarshal %s genesis state: %w", starname.ModuleName, err)
+	}
+	return starname.ValidateGenesisWithConfig(starnameState, confState.Config)
+}
+
 // InitChainer application update at chain initialization
 func (app *NameService) InitChainer(ctx sdk.Context, req abci.RequestInitChain) abci.ResponseInitChain {
 	var genesisState simapp.GenesisState
@@ -0,0 +1,35 @@
+package app
+
+import (
+	"errors"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/iov-one/iovns/app/config"
+	"github.com/iov-one/iovns/pkg/utils"
+	"github.com/iov-one/iovns/x/starname"
+	"github.com/iov-one/iovns/x/starname/types"
+)
+
+func TestValidateGenesis(t *testing.T) {
+	// the default configuration genesis holds star addresses
+	config.ApplyChangesAndSeal(sdk.GetConfig())
+	cdc := MakeCodec()
+	genesis := NewDefaultGenesisState()
+	if err := ValidateGenesis(cdc, genesis); err != nil {
+		t.Fatalf("default genesis: %s", err)
+	}
+	// the domain name is too short for the default configuration
+	admin := sdk.AccAddress("admin_______________")
+	genesis[starname.ModuleName] = cdc.MustMarshalJSON(starname.NewGenesisState(
+		[]types.Domain{{Name: "ab", Admin: admin, Type: types.ClosedDomain}},
+		[]types.Account{{Domain: "ab", Name: utils.StrPtr(""), Owner: admin}},
+	))
+	if err := ModuleBasics.ValidateGenesis(genesis); err != nil {
+		t.Fatalf("module genesis: %s", err)
+	}
+	if err := ValidateGenesis(cdc, genesis); !errors.Is(err, types.ErrInvalidDomainName) {
+		t.Fatalf("want error %s, got %v", types.ErrInvalidDomainName, err)
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/iov-one/iovns/x/configuration"
+	"github.com/iov-one/iovns/x/starname"
+	starnamekeeper "github.com/iov-one/iovns/x/starname/keeper"
 	"math/rand"
 	"os"
 	"testing"
@@ -17,6 +19,7 @@ import (
 	"github.com/cosmos/cosmos-sdk/simapp"
 	"github.com/cosmos/cosmos-sdk/simapp/helpers"
 	"github.com/cosmos/cosmos-sdk/store"
+	"github.com/cosmos/cosmos-sdk/store/prefix"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/x/auth"
 	distr "github.com/cosmos/cosmos-sdk/x/distribution"
@@ -155,7 +158,6 @@ func TestAppImportExport(t *testing.T) {
 		{app.keys[supply.StoreKey], newApp.keys[supply.StoreKey], [][]byte{}},
 		{app.keys[params.StoreKey], newApp.keys[params.StoreKey], [][]byte{}},
 		{app.keys[gov.StoreKey], newApp.keys[gov.StoreKey], [][]byte{}},
-		// TODO personal keys
 		{app.keys[configuration.StoreKey], newApp.keys[configuration.StoreKey], [][]byte{}},
 	}
 
@@ -169,6 +171,33 @@ func TestAppImportExport(t *testing.T) {
 		fmt.Printf("compared %d key/value pairs between %s and %s\n", len(failedKVAs), skp.A, skp.B)
 		require.Equal(t, len(failedKVAs), 0, simapp.GetSimulationLog(skp.A.Name(), app.SimulationManager().StoreDecoders, app.Codec(), failedKVAs, failedKVBs))
 	}
+
+	// starname history is not exported, so it is not compared
+	for _, p := range [][]byte{
+		starnamekeeper.DomainStorePrefix,
+		starnamekeeper.AccountStorePrefix,
+		starnamekeeper.StoreVersionKey,
+		starnamekeeper.RegistrationPolicyStorePrefix,
+		starnamekeeper.InviteCodeStorePrefix,
+		starnamekeeper.TransferOfferStorePrefix,
+		starnamekeeper.TransferOfferSequenceKey,
+		starnamekeeper.TransferOfferQueuePrefix,
+		starnamekeeper.SponsorshipStorePrefix,
+		starnamekeeper.AccountCountStorePrefix,
+	} {
+		storeA := prefix.NewStore(ctxA.KVStore(app.keys[starname.DomainStoreKey]), p)
+		storeB := prefix.NewStore(ctxB.KVStore(newApp.keys[starname.DomainStoreKey]), p)
+
+		failedKVAs, failedKVBs := sdk.DiffKVStores(storeA, storeB, nil)
+		require.Equal(t, len(failedKVAs), len(failedKVBs), "unequal sets of key-values to compare")
+		for i := range failedKVAs {
+			failedKVAs[i].Key = append(append([]byte{}, p...), failedKVAs[i].Key...)
+			failedKVBs[i].Key = append(append([]byte{}, p...), failedKVBs[i].Key...)
+		}
+
+		fmt.Printf("compared %d key/value pairs with prefix %X between %s and %s\n", len(failedKVAs), p, app.keys[starname.DomainStoreKey], newApp.keys[starname.DomainStoreKey])
+		require.Equal(t, len(failedKVAs), 0, simapp.GetSimulationLog(starname.DomainStoreKey, app.SimulationManager().StoreDecoders, app.Codec(), failedKVAs, failedKVBs))
+	}
 }
 
 func TestAppSimulationAfterImport(t *testing.T) {
@@ -0,0 +1,30 @@
+package app
+
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/upgrade"
+	"github.com/iov-one/iovns/pkg/migration"
+)
+
+// StoreMigrationsUpgrade is the name of the upgrade plan
+// which applies the pending store migrations of iovns modules
+const StoreMigrationsUpgrade = "store-migrations-v1"
+
+// registerUpgradeHandlers registers the handlers applied when upgrade plans are reached
+func (app *NameService) registerUpgradeHandlers() {
+	app.upgradeKeeper.SetUpgradeHandler(StoreMigrationsUpgrade, app.migrateStores)
+}
+
+// migrateStores applies the pending migrations of iovns module stores
+func (app *NameService) migrateStores(ctx sdk.Context, _ upgrade.Plan) {
+	for _, m := range []migration.Manager{
+		app.configurationKeeper.Migrations(),
+		app.domainKeeper.Migrations(),
+	} {
+		if err := m.Migrate(ctx); err != nil {
+			panic(fmt.Sprintf("store migrations failed: %s", err))
+		}
+	}
+}
@@ -8,10 +8,10 @@ import (
 	"os/signal"
 	"time"
 
-	keys2 "github.com/cosmos/cosmos-sdk/crypto/keys"
 	"github.com/gorilla/mux"
 	"github.com/iov-one/iovns/cmd/faucet/pkg"
 	rpchttp "github.com/tendermint/tendermint/rpc/client/http"
+	dbm "github.com/tendermint/tm-db"
 )
 
 func main() {
@@ -22,20 +22,74 @@ func main() {
 	}
 	// setup node
 	node, err := rpchttp.New(conf.TendermintRPC, "/websocket")
-	kb := keys2.NewInMemory()
-	if err := kb.ImportPrivKey("faucet", conf.Armor, conf.Passphrase); err != nil {
+	if err != nil {
+		log.Fatalf("node: %v", err)
+	}
+	kb, err := pkg.NewKeybase(*conf)
+	if err != nil {
 		log.Fatalf("keybase: %v", err)
 	}
+	// setup metrics and audit log
+	metrics := pkg.NewMetrics()
+	auditOut := os.Stdout
+	if conf.AuditLog != "" {
+		auditOut, err = os.OpenFile(conf.AuditLog, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+		if err != nil {
+			log.Fatalf("audit log: %v", err)
+		}
+		defer auditOut.Close()
+	}
+	audit := pkg.NewAuditLog(auditOut)
+
 	// setup tx manager
-	txManager := pkg.NewTxManager(*conf, node).WithKeybase(kb)
+	txManager := pkg.NewTxManager(*conf, node).WithKeybase(kb).WithMetrics(metrics)
 	if err := txManager.Init(); err != nil {
 		log.Fatalf("tx manager: %v", err)
 	}
 
+	// setup rate limiter
+	db, err := dbm.NewGoLevelDB("faucet", conf.DBDir)
+	if err != nil {
+		log.Fatalf("database: %v", err)
+	}
+	defer db.Close()
+	limiter := pkg.NewRateLimiter(*conf, db)
+	budget := pkg.NewBudget(*conf, db)
+
+	// setup queue
+	queue := pkg.NewQueue(*conf, txManager).
+		WithRateLimiter(limiter).
+		WithBudget(budget).
+		WithMetrics(metrics).
+		WithAuditLog(audit)
+	stopQueue := make(chan struct{})
+	queueStopped := make(chan struct{})
+	go func() {
+		queue.Run(stopQueue)
+		close(queueStopped)
+	}()
+	go metrics.PollBalance(txManager, conf.Denoms(), conf.BalanceInterval, stopQueue)
+
 	// Wait for ListenAndServe goroutine to close.
 	r := mux.NewRouter()
-	faucet := pkg.NewFaucetHandler(txManager)
-	r.Handle("/credit", faucet)
+	// setup starname registrations
+	var registrar *pkg.Registrar
+	if conf.StarnameDomain != "" {
+		registrar = pkg.NewRegistrar(*conf, txManager)
+		if err := registrar.Init(); err != nil {
+			log.Fatalf("registrar: %v", err)
+		}
+	}
+	for _, tier := range conf.Tiers {
+		faucet := pkg.NewFaucetHandler(*conf, tier, queue).WithRateLimiter(limiter).WithMetrics(metrics)
+		if registrar != nil {
+			faucet.WithRegistrar(registrar)
+		}
+		r.Handle(tier.Path, metrics.Instrument(tier.Name, faucet))
+	}
+	r.Handle("/jobs/{id}", pkg.NewJobHandler(queue))
+	r.Handle("/status", pkg.NewStatusHandler(txManager))
+	r.Handle("/metrics", metrics.Handler())
 	r.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
 		return
@@ -44,7 +98,8 @@ func main() {
 
 	go func() {
 		log.Print("server started")
-		if err := server.ListenAndServe(); err != nil {
+		// ErrServerClosed is returned after Shutdown, the pending credits are still sent below
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 			log.Fatalf("http server: %s", err)
 		}
 	}()
@@ -57,4 +112,7 @@ func main() {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 	_ = server.Shutdown(ctx)
+	// send the pending credits
+	close(stopQueue)
+	<-queueStopped
 }
@@ -0,0 +1,57 @@
+package pkg
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// AuditEntry is a line of the audit log, written for every processed credit
+type AuditEntry struct {
+	Time     time.Time `json:"time"`
+	JobID    string    `json:"job_id"`
+	Status   JobStatus `json:"status"`
+	Tier     string    `json:"tier"`
+	Address  string    `json:"address"`
+	Amount   string    `json:"amount"`
+	Starname string    `json:"starname,omitempty"`
+	TxHash   string    `json:"tx_hash,omitempty"`
+	IP       string    `json:"ip"`
+	Error    string    `json:"error,omitempty"`
+}
+
+// AuditLog writes the processed credits as JSON lines
+type AuditLog struct {
+	mux sync.Mutex
+	enc *json.Encoder
+	now func() time.Time
+}
+
+func NewAuditLog(w io.Writer) *AuditLog {
+	return &AuditLog{
+		enc: json.NewEncoder(w),
+		now: time.Now,
+	}
+}
+
+// Record writes the entry of the processed job
+func (a *AuditLog) Record(job *Job) error {
+	entry := AuditEntry{
+		Time:     a.now().UTC(),
+		JobID:    job.ID,
+		Status:   job.Status,
+		Tier:     job.request.Tier,
+		Address:  job.Address,
+		Amount:   job.credit.Amount.String(),
+		Starname: job.Starname,
+		TxHash:   job.Hash,
+		IP:       job.request.IP,
+		Error:    job.Error,
+	}
+	a.mux.Lock()
+	defer a.mux.Unlock()
+	return errors.Wrap(a.enc.Encode(entry), "audit log write failed")
+}
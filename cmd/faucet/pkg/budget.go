@@ -0,0 +1,128 @@
+package pkg
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/pkg/errors"
+	dbm "github.com/tendermint/tm-db"
+)
+
+// ErrBudgetExceeded is returned for credits that exceed the daily budget
+var ErrBudgetExceeded = sdkerrors.Register("faucet", 3, "daily budget exceeded")
+
+// budgetKey is the key of the coins spent during the current day
+var budgetKey = []byte("budget")
+
+// Spend is an amount charged to the budget of a day
+type Spend struct {
+	Day    string    `json:"day"`
+	Amount sdk.Coins `json:"amount"`
+}
+
+// Budget caps the coins credited per UTC day, the spent coins are kept in
+// a database so that the budget survives restarts
+type Budget struct {
+	db    dbm.DB
+	limit sdk.Coins
+	now   func() time.Time
+	mux   sync.Mutex
+}
+
+func NewBudget(conf Configuration, db dbm.DB) *Budget {
+	return &Budget{
+		db:    db,
+		limit: conf.DailyBudget,
+		now:   time.Now,
+	}
+}
+
+// WithClock sets the function returning the current time
+func (b *Budget) WithClock(now func() time.Time) *Budget {
+	b.now = now
+	return b
+}
+
+// Take charges the amount to the budget of the day if the denoms of the budget
+// are not exceeded, the denoms not in the budget are not capped
+func (b *Budget) Take(amount sdk.Coins) (Spend, error) {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	day := b.day()
+	spent, err := b.spent(day)
+	if err != nil {
+		return Spend{}, err
+	}
+	spent = spent.Add(amount...)
+	for _, coin := range b.limit {
+		if spent.AmountOf(coin.Denom).GT(coin.Amount) {
+			return Spend{}, errors.Wrapf(ErrBudgetExceeded, "%s per day", coin)
+		}
+	}
+	if err := b.setSpent(day, spent); err != nil {
+		return Spend{}, err
+	}
+	return Spend{Day: day, Amount: amount}, nil
+}
+
+// Refund gives the spent amount back to the budget, used when the credit could not be sent
+func (b *Budget) Refund(spend Spend) error {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	if spend.Day != b.day() {
+		return nil
+	}
+	spent, err := b.spent(spend.Day)
+	if err != nil {
+		return err
+	}
+	spent, negative := spent.SafeSub(spend.Amount)
+	if negative {
+		return errors.New("refund larger than the spent budget")
+	}
+	return b.setSpent(spend.Day, spent)
+}
+
+// Spent returns the coins spent during the current day
+func (b *Budget) Spent() (sdk.Coins, error) {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	return b.spent(b.day())
+}
+
+// day returns the current UTC day
+func (b *Budget) day() string {
+	return b.now().UTC().Format("2006-01-02")
+}
+
+// spent returns the coins spent during the day
+func (b *Budget) spent(day string) (sdk.Coins, error) {
+	bz, err := b.db.Get(budgetKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "budget read failed")
+	}
+	if bz == nil {
+		return nil, nil
+	}
+	var spend Spend
+	if err := json.Unmarshal(bz, &spend); err != nil {
+		return nil, errors.Wrap(err, "budget decoding failed")
+	}
+	// a new day starts with the whole budget
+	if spend.Day != day {
+		return nil, nil
+	}
+	return spend.Amount, nil
+}
+
+// setSpent saves the coins spent during the day
+func (b *Budget) setSpent(day string, spent sdk.Coins) error {
+	bz, err := json.Marshal(Spend{Day: day, Amount: spent})
+	if err != nil {
+		return errors.Wrap(err, "budget encoding failed")
+	}
+	return errors.Wrap(b.db.SetSync(budgetKey, bz), "budget write failed")
+}
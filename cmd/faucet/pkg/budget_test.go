@@ -0,0 +1,74 @@
+package pkg
+
+import (
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	dbm "github.com/tendermint/tm-db"
+)
+
+func TestBudget(t *testing.T) {
+	conf := Configuration{DailyBudget: sdk.NewCoins(sdk.NewInt64Coin("tiov", 250))}
+	now := time.Date(2020, 10, 19, 23, 0, 0, 0, time.UTC)
+	b := NewBudget(conf, dbm.NewMemDB()).WithClock(func() time.Time { return now })
+	amount := sdk.NewCoins(sdk.NewInt64Coin("tiov", 100), sdk.NewInt64Coin("tvoi", 1000))
+
+	first, err := b.Take(amount)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.Take(amount); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.Take(amount); !ErrBudgetExceeded.Is(err) {
+		t.Fatalf("want budget exceeded, got %v", err)
+	}
+	if err := b.Refund(first); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.Take(amount); err != nil {
+		t.Fatal(err)
+	}
+	spent, err := b.Spent()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the denoms not in the budget are not capped but recorded
+	if !spent.IsEqual(sdk.NewCoins(sdk.NewInt64Coin("tiov", 200), sdk.NewInt64Coin("tvoi", 2000))) {
+		t.Fatalf("unexpected spent %s", spent)
+	}
+	// the budget is reset the next day and the refunds of the previous days are ignored
+	now = now.Add(2 * time.Hour)
+	if err := b.Refund(first); err != nil {
+		t.Fatal(err)
+	}
+	if spent, err := b.Spent(); err != nil || !spent.Empty() {
+		t.Fatalf("unexpected spent %s, err %v", spent, err)
+	}
+	if _, err := b.Take(amount); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestQueue_Budget(t *testing.T) {
+	tm, node := newTestTxManager(t)
+	budget := NewBudget(Configuration{DailyBudget: testAmount}, dbm.NewMemDB())
+	q := NewQueue(Configuration{BatchMax: 10, JobTTL: time.Hour}, tm).WithBudget(budget)
+	node.failures = 1
+	job, err := q.Submit(Credit{Target: testAddress("a"), Amount: testAmount}, CreditRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := q.Submit(Credit{Target: testAddress("b"), Amount: testAmount}, CreditRequest{}); !ErrBudgetExceeded.Is(err) {
+		t.Fatalf("want budget exceeded, got %v", err)
+	}
+	q.Flush()
+	if j := q.Wait(job, time.Second); j.Status != JobFailed {
+		t.Fatalf("unexpected job %+v", j)
+	}
+	// the failed credit is refunded
+	if _, err := q.Submit(Credit{Target: testAddress("b"), Amount: testAmount}, CreditRequest{}); err != nil {
+		t.Fatal(err)
+	}
+}
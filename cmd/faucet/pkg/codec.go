@@ -6,6 +6,7 @@ import (
 	"github.com/cosmos/cosmos-sdk/x/auth"
 	"github.com/cosmos/cosmos-sdk/x/bank"
 	config2 "github.com/iov-one/iovns/app/config"
+	"github.com/iov-one/iovns/x/starname/types"
 )
 
 // ModuleCdc instantiates a new codec for the domain module
@@ -26,5 +27,6 @@ func RegisterCodec(cdc *codec.Codec) {
 	sdk.RegisterCodec(cdc)
 	bank.RegisterCodec(cdc)
 	auth.RegisterCodec(cdc)
+	types.RegisterCodec(cdc)
 	codec.RegisterCrypto(cdc)
 }
@@ -0,0 +1,190 @@
+package pkg
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// setenv sets the environment variable for the duration of the test
+func setenv(t *testing.T, name, value string) {
+	t.Helper()
+	restoreEnv(t, name)
+	if err := os.Setenv(name, value); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// unsetenv unsets the environment variable for the duration of the test
+func unsetenv(t *testing.T, name string) {
+	t.Helper()
+	restoreEnv(t, name)
+	if err := os.Unsetenv(name); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// restoreEnv restores the environment variable at the end of the test
+func restoreEnv(t *testing.T, name string) {
+	old, ok := os.LookupEnv(name)
+	t.Cleanup(func() {
+		if ok {
+			os.Setenv(name, old)
+		} else {
+			os.Unsetenv(name)
+		}
+	})
+}
+
+// writeConfig writes the configuration file and sets CONFIG_FILE to it
+func writeConfig(t *testing.T, name, content string) {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "faucet")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	path := filepath.Join(dir, name)
+	if err := ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	setenv(t, "CONFIG_FILE", path)
+}
+
+const yamlConfig = `
+chain_id: iovns-galaxynet
+keyring_dir: /keys
+key_name: bank
+daily_budget: 5000tiov,50tvoi
+address_window: 1h
+batch_max: 20
+tiers:
+  - name: user
+    path: /credit
+    amount: 100tiov
+  - name: developer
+    path: /credit/developer
+    amount: 1000tiov,10tvoi
+    address_limit: 10
+    ip_window: 1m
+    token: secret
+`
+
+const tomlConfig = `
+chain_id = "iovns-galaxynet"
+keyring_dir = "/keys"
+key_name = "bank"
+daily_budget = "5000tiov,50tvoi"
+address_window = "1h"
+batch_max = 20
+
+[[tiers]]
+name = "user"
+path = "/credit"
+amount = "100tiov"
+
+[[tiers]]
+name = "developer"
+path = "/credit/developer"
+amount = "1000tiov,10tvoi"
+address_limit = 10
+ip_window = "1m"
+token = "secret"
+`
+
+func TestNewConfiguration_File(t *testing.T) {
+	for name, content := range map[string]string{"faucet.yaml": yamlConfig, "faucet.toml": tomlConfig} {
+		t.Run(name, func(t *testing.T) {
+			writeConfig(t, name, content)
+			// the environment takes precedence over the file
+			setenv(t, "BATCH_MAX", "30")
+			conf, err := NewConfiguration()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if conf.ChainID != "iovns-galaxynet" || conf.KeyringDir != "/keys" || conf.KeyName != "bank" {
+				t.Fatalf("unexpected configuration %+v", conf)
+			}
+			if conf.AddressWindow != time.Hour || conf.BatchMax != 30 || conf.IPLimit != 5 {
+				t.Fatalf("unexpected limits %+v", conf)
+			}
+			if !conf.DailyBudget.IsEqual(sdk.NewCoins(sdk.NewInt64Coin("tiov", 5000), sdk.NewInt64Coin("tvoi", 50))) {
+				t.Fatalf("unexpected budget %s", conf.DailyBudget)
+			}
+			want := []Tier{
+				{
+					Name:   "user",
+					Path:   "/credit",
+					Amount: sdk.NewCoins(sdk.NewInt64Coin("tiov", 100)),
+					Limits: Limits{AddressWindow: time.Hour, AddressLimit: 1, IPWindow: 24 * time.Hour, IPLimit: 5},
+				},
+				{
+					Name:   "developer",
+					Path:   "/credit/developer",
+					Amount: sdk.NewCoins(sdk.NewInt64Coin("tiov", 1000), sdk.NewInt64Coin("tvoi", 10)),
+					Limits: Limits{AddressWindow: time.Hour, AddressLimit: 10, IPWindow: time.Minute, IPLimit: 5},
+					Token:  "secret",
+				},
+			}
+			if len(conf.Tiers) != len(want) {
+				t.Fatalf("unexpected tiers %+v", conf.Tiers)
+			}
+			for i, tier := range conf.Tiers {
+				if tier.Name != want[i].Name || tier.Path != want[i].Path || !tier.Amount.IsEqual(want[i].Amount) ||
+					tier.Limits != want[i].Limits || tier.Token != want[i].Token {
+					t.Fatalf("want tier %+v, got %+v", want[i], tier)
+				}
+			}
+			if denoms := conf.Denoms(); len(denoms) != 2 || denoms[0] != "tiov" || denoms[1] != "tvoi" {
+				t.Fatalf("unexpected denoms %v", denoms)
+			}
+		})
+	}
+}
+
+func TestNewConfiguration_Env(t *testing.T) {
+	setenv(t, "CONFIG_FILE", "")
+	setenv(t, "ARMOR", "armor")
+	setenv(t, "COIN_DENOM", "tvoi")
+	setenv(t, "SEND_AMOUNT", "42")
+	conf, err := NewConfiguration()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(conf.Tiers) != 1 || conf.Tiers[0].Path != "/credit" || conf.Tiers[0].Amount.String() != "42tvoi" {
+		t.Fatalf("unexpected tiers %+v", conf.Tiers)
+	}
+	if want := (Limits{AddressWindow: 24 * time.Hour, AddressLimit: 1, IPWindow: 24 * time.Hour, IPLimit: 5}); conf.Tiers[0].Limits != want {
+		t.Fatalf("want limits %+v, got %+v", want, conf.Tiers[0].Limits)
+	}
+	if !conf.DailyBudget.Empty() || conf.KeyName != "faucet" {
+		t.Fatalf("unexpected configuration %+v", conf)
+	}
+}
+
+func TestNewConfiguration_Invalid(t *testing.T) {
+	cases := map[string]string{
+		"no key":         "chain_id: local\n",
+		"no amount":      "keyring_dir: /keys\ntiers:\n  - name: user\n    path: /credit\n",
+		"relative path":  "keyring_dir: /keys\ntiers:\n  - name: user\n    path: credit\n    amount: 1tiov\n",
+		"duplicate path": "keyring_dir: /keys\ntiers:\n  - name: a\n    path: /credit\n    amount: 1tiov\n  - name: b\n    path: /credit\n    amount: 2tiov\n",
+		"reserved path":  "keyring_dir: /keys\ntiers:\n  - name: a\n    path: /status\n    amount: 1tiov\n",
+		"duplicate name": "keyring_dir: /keys\ntiers:\n  - name: a\n    path: /a\n    amount: 1tiov\n  - name: a\n    path: /b\n    amount: 2tiov\n",
+		"no name":        "keyring_dir: /keys\ntiers:\n  - path: /credit\n    amount: 1tiov\n",
+		"invalid window": "keyring_dir: /keys\ntiers:\n  - name: a\n    path: /credit\n    amount: 1tiov\n    ip_window: soon\n",
+		"invalid budget": "keyring_dir: /keys\ndaily_budget: lots\n",
+	}
+	for name, content := range cases {
+		t.Run(name, func(t *testing.T) {
+			unsetenv(t, "ARMOR")
+			writeConfig(t, "faucet.yaml", content)
+			if _, err := NewConfiguration(); err == nil {
+				t.Fatal("invalid configuration accepted")
+			}
+		})
+	}
+}
@@ -1,26 +1,59 @@
 package pkg
 
 import (
+	"crypto/subtle"
 	"encoding/json"
+	"fmt"
+	"math"
+	"net"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/cosmos/cosmos-sdk/types/errors"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/gorilla/mux"
 	"github.com/prometheus/common/log"
 )
 
-// keeps tx manager and mutex locks sequence bump
+// queues the credit requests of the amount of its tier, whose transactions are sent in batches
 type FaucetHandler struct {
-	tm *TxManager
+	conf      Configuration
+	tier      Tier
+	queue     *Queue
+	limiter   *RateLimiter
+	registrar *Registrar
+	metrics   *Metrics
 }
 
-func NewFaucetHandler(tm *TxManager) *FaucetHandler {
+func NewFaucetHandler(conf Configuration, tier Tier, queue *Queue) *FaucetHandler {
 	return &FaucetHandler{
-		tm: tm,
+		conf:  conf,
+		tier:  tier,
+		queue: queue,
 	}
 }
 
+// WithRateLimiter limits the credits per address and per ip with the limits of the tier
+func (f *FaucetHandler) WithRateLimiter(limiter *RateLimiter) *FaucetHandler {
+	f.limiter = limiter
+	return f
+}
+
+// WithRegistrar registers the names requested with the credits in the domain of the faucet
+func (f *FaucetHandler) WithRegistrar(registrar *Registrar) *FaucetHandler {
+	f.registrar = registrar
+	return f
+}
+
+// WithMetrics counts the rejected requests by reason
+func (f *FaucetHandler) WithMetrics(metrics *Metrics) *FaucetHandler {
+	f.metrics = metrics
+	return f
+}
+
 func jsonErr(w http.ResponseWriter, status int, msg string) {
 	errJson := struct {
 		Error string `json:"error"`
@@ -35,48 +68,207 @@ func jsonErr(w http.ResponseWriter, status int, msg string) {
 	return
 }
 
+// rateLimitErr tells the client how many seconds to wait before the next credit
+func rateLimitErr(w http.ResponseWriter, retryAfter time.Duration) {
+	seconds := int64(math.Ceil(retryAfter.Seconds()))
+	errJson := struct {
+		Error      string `json:"error"`
+		RetryAfter int64  `json:"retry_after"`
+	}{
+		Error:      fmt.Sprintf("rate limit exceeded, retry in %d seconds", seconds),
+		RetryAfter: seconds,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Retry-After", strconv.FormatInt(seconds, 10))
+	w.WriteHeader(http.StatusTooManyRequests)
+	json.NewEncoder(w).Encode(errJson)
+}
+
+// clientIP returns the ip of the client, when the faucet runs behind trustedProxies proxies
+// it is the entry of the X-Forwarded-For header appended by the outermost trusted proxy,
+// counted from the right as the entries on its left are set by the client and can be forged
+func clientIP(r *http.Request, trustedProxies int) string {
+	if trustedProxies > 0 {
+		var forwarded []string
+		for _, header := range r.Header.Values("X-Forwarded-For") {
+			forwarded = append(forwarded, strings.Split(header, ",")...)
+		}
+		if len(forwarded) >= trustedProxies {
+			if ip := strings.TrimSpace(forwarded[len(forwarded)-trustedProxies]); ip != "" {
+				return ip
+			}
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// authorized checks that the request carries the bearer token of the tier, if any
+func (f *FaucetHandler) authorized(r *http.Request) bool {
+	if f.tier.Token == "" {
+		return true
+	}
+	auth := r.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, "Bearer ") {
+		return false
+	}
+	token := strings.TrimPrefix(auth, "Bearer ")
+	return subtle.ConstantTimeCompare([]byte(token), []byte(f.tier.Token)) == 1
+}
+
 func (f *FaucetHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !f.authorized(r) {
+		f.metrics.failure(reasonUnauthorized)
+		jsonErr(w, http.StatusUnauthorized, "provide the access token of the tier")
+		return
+	}
 	addrStr := r.URL.Query().Get("address")
 	if addrStr == "" {
+		f.metrics.failure(reasonInvalidAddress)
 		jsonErr(w, http.StatusBadRequest, "provide a bech32 address")
 		return
 	}
 	addr, err := sdk.AccAddressFromBech32(addrStr)
 	if err != nil {
 		log.Error(errors.Wrap(err, "incorrect bech32 address"))
+		f.metrics.failure(reasonInvalidAddress)
 		jsonErr(w, http.StatusBadRequest, "provide a bech32 address")
 		return
 	}
 
-	tx, err := f.tm.BuildAndSignTx(addr)
-	if err != nil {
-		log.Error(errors.Wrap(err, "tx signing failed"))
-		jsonErr(w, http.StatusInternalServerError, "internal error")
-		return
+	name := r.URL.Query().Get("name")
+	if name != "" {
+		if f.registrar == nil {
+			f.metrics.failure(reasonRegistrations)
+			jsonErr(w, http.StatusBadRequest, "starname registrations are disabled")
+			return
+		}
+		if err := f.registrar.Validate(name, addr); err != nil {
+			if ErrInvalidName.Is(err) {
+				f.metrics.failure(reasonInvalidName)
+				jsonErr(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			log.Error(errors.Wrap(err, "name validation failed"))
+			f.metrics.failure(reasonInternal)
+			jsonErr(w, http.StatusInternalServerError, "internal error")
+			return
+		}
 	}
 
-	res, err := f.tm.BroadcastTx(tx)
-	if err != nil {
-		log.Error(errors.Wrap(err, "broadcast tx failed"))
-		jsonErr(w, http.StatusInternalServerError, "internal error")
-		return
+	req := CreditRequest{Tier: f.tier.Name, IP: clientIP(r, f.conf.TrustedProxies)}
+	if f.limiter != nil {
+		g, wait, err := f.limiter.Take(f.tier.Name, addr.String(), req.IP)
+		if err != nil {
+			log.Error(errors.Wrap(err, "rate limiter failed"))
+			f.metrics.failure(reasonInternal)
+			jsonErr(w, http.StatusInternalServerError, "internal error")
+			return
+		}
+		if wait > 0 {
+			f.metrics.failure(reasonRateLimited)
+			rateLimitErr(w, wait)
+			return
+		}
+		req.Grant = &g
 	}
 
-	if res.Code != errors.SuccessABCICode {
-		log.Error(errors.Wrap(err, "broadcast tx failed"))
+	job, err := f.queue.Submit(Credit{Target: addr, Name: name, Amount: f.tier.Amount}, req)
+	if err != nil {
+		if req.Grant != nil {
+			if err := f.limiter.Refund(*req.Grant); err != nil {
+				log.Error(errors.Wrap(err, "grant refund failed"))
+			}
+		}
+		if ErrNameTaken.Is(err) {
+			f.metrics.failure(reasonNameTaken)
+			jsonErr(w, http.StatusConflict, err.Error())
+			return
+		}
+		if ErrBudgetExceeded.Is(err) {
+			f.metrics.failure(reasonBudgetExceeded)
+			jsonErr(w, http.StatusServiceUnavailable, err.Error())
+			return
+		}
+		log.Error(errors.Wrap(err, "credit request queueing failed"))
+		f.metrics.failure(reasonInternal)
 		jsonErr(w, http.StatusInternalServerError, "internal error")
 		return
 	}
 
 	resp := struct {
-		Msg  string `json:"msg"`
-		Hash string `json:"hash"`
+		Msg      string `json:"msg"`
+		Hash     string `json:"hash,omitempty"`
+		JobID    string `json:"job_id"`
+		Amount   string `json:"amount"`
+		Starname string `json:"starname,omitempty"`
 	}{
-		Msg:  "check your balance :-)",
-		Hash: res.Hash.String(),
+		JobID:    job.ID,
+		Amount:   f.tier.Amount.String(),
+		Starname: job.Starname,
+	}
+	status := http.StatusOK
+	switch j := f.queue.Wait(job, f.conf.CreditTimeout); j.Status {
+	case JobSent:
+		resp.Msg = "check your balance :-)"
+		resp.Hash = j.Hash
+	case JobFailed:
+		jsonErr(w, http.StatusInternalServerError, "internal error")
+		return
+	default:
+		resp.Msg = "credit queued, check the job status"
+		status = http.StatusAccepted
 	}
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
+	w.WriteHeader(status)
 	err = json.NewEncoder(w).Encode(resp)
 	return
 }
+
+// JobHandler returns the status of the credit requests
+type JobHandler struct {
+	queue *Queue
+}
+
+func NewJobHandler(queue *Queue) *JobHandler {
+	return &JobHandler{
+		queue: queue,
+	}
+}
+
+func (h *JobHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	job, ok := h.queue.Job(mux.Vars(r)["id"])
+	if !ok {
+		jsonErr(w, http.StatusNotFound, "job not found")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(job)
+}
+
+// StatusHandler returns the health of the faucet and the sequence of its account
+type StatusHandler struct {
+	tm *TxManager
+}
+
+func NewStatusHandler(tm *TxManager) *StatusHandler {
+	return &StatusHandler{
+		tm: tm,
+	}
+}
+
+func (h *StatusHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	status := h.tm.Status()
+	code := http.StatusOK
+	if !status.Healthy {
+		code = http.StatusServiceUnavailable
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(status)
+}
@@ -0,0 +1,40 @@
+package pkg
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestFaucetHandler_Token(t *testing.T) {
+	tm, _ := newTestTxManager(t)
+	metrics := NewMetrics()
+	q := NewQueue(Configuration{BatchMax: 10, JobTTL: time.Hour}, tm)
+	tier := testTier
+	tier.Token = "secret"
+	handler := NewFaucetHandler(tm.conf, tier, q).WithMetrics(metrics)
+	cases := map[string]struct {
+		authorization string
+		status        int
+	}{
+		"missing token": {status: http.StatusUnauthorized},
+		"wrong token":   {authorization: "Bearer wrong", status: http.StatusUnauthorized},
+		"no bearer":     {authorization: "secret", status: http.StatusUnauthorized},
+		// the missing address is checked once authorized
+		"valid token": {authorization: "Bearer secret", status: http.StatusBadRequest},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/credit", nil)
+			if c.authorization != "" {
+				r.Header.Set("Authorization", c.authorization)
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+			if w.Code != c.status {
+				t.Fatalf("want %d, got %d: %s", c.status, w.Code, w.Body)
+			}
+		})
+	}
+}
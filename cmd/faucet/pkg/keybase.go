@@ -0,0 +1,32 @@
+package pkg
+
+import (
+	"strings"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keys"
+	"github.com/pkg/errors"
+)
+
+// keyringAppName is the name iovnscli stores its keyring under
+const keyringAppName = "iovns"
+
+// NewKeybase returns the keyring in the configured directory, or else an
+// in memory keybase with the armored key imported as KeyName
+func NewKeybase(conf Configuration) (keys.Keybase, error) {
+	if conf.KeyringDir != "" {
+		// the file backend reads the keyring passphrase from the input
+		kb, err := keys.NewKeyring(keyringAppName, conf.KeyringBackend, conf.KeyringDir, strings.NewReader(conf.KeyringPass+"\n"))
+		if err != nil {
+			return nil, errors.Wrap(err, "keyring")
+		}
+		return kb, nil
+	}
+	if conf.Armor == "" {
+		return nil, errors.New("no faucet key, set a keyring directory or an armored key")
+	}
+	kb := keys.NewInMemory()
+	if err := kb.ImportPrivKey(conf.KeyName, conf.Armor, conf.Passphrase); err != nil {
+		return nil, errors.Wrap(err, "armored key import")
+	}
+	return kb, nil
+}
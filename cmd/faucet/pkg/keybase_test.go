@@ -0,0 +1,41 @@
+package pkg
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keys"
+)
+
+func TestNewKeybase(t *testing.T) {
+	dir, err := ioutil.TempDir("", "faucet")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	kr, err := keys.NewKeyring(keyringAppName, keys.BackendTest, dir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, _, err := kr.CreateMnemonic("bank", keys.English, "", keys.Secp256k1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conf := Configuration{KeyringDir: dir, KeyringBackend: keys.BackendTest, KeyName: "bank"}
+	kb, err := NewKeybase(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := kb.Get(conf.KeyName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.GetAddress().Equals(info.GetAddress()) {
+		t.Fatalf("want key %s, got %s", info.GetAddress(), got.GetAddress())
+	}
+	// no key configured
+	if _, err := NewKeybase(Configuration{KeyName: "faucet"}); err == nil {
+		t.Fatal("missing key accepted")
+	}
+}
@@ -0,0 +1,161 @@
+package pkg
+
+import (
+	"math/big"
+	"net/http"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/prometheus/common/log"
+)
+
+// failure reasons of the failures metric
+const (
+	reasonUnauthorized     = "unauthorized"
+	reasonInvalidAddress   = "invalid_address"
+	reasonInvalidName      = "invalid_name"
+	reasonRegistrations    = "registrations_disabled"
+	reasonRateLimited      = "rate_limited"
+	reasonNameTaken        = "name_taken"
+	reasonBudgetExceeded   = "budget_exceeded"
+	reasonInternal         = "internal"
+	reasonSequenceMismatch = "sequence_mismatch"
+	reasonBroadcast        = "broadcast"
+)
+
+// Metrics are the prometheus metrics of the faucet, a nil *Metrics records nothing
+type Metrics struct {
+	registry         *prometheus.Registry
+	requests         *prometheus.CounterVec
+	grants           *prometheus.CounterVec
+	grantedCoins     *prometheus.CounterVec
+	failures         *prometheus.CounterVec
+	balance          *prometheus.GaugeVec
+	broadcastLatency prometheus.Histogram
+}
+
+func NewMetrics() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "faucet",
+			Name:      "requests_total",
+			Help:      "Credit requests by tier and response status code.",
+		}, []string{"tier", "code"}),
+		grants: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "faucet",
+			Name:      "grants_total",
+			Help:      "Credits sent by tier.",
+		}, []string{"tier"}),
+		grantedCoins: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "faucet",
+			Name:      "granted_coins_total",
+			Help:      "Coins sent by denom.",
+		}, []string{"denom"}),
+		failures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "faucet",
+			Name:      "failures_total",
+			Help:      "Rejected credit requests and failed transactions by reason.",
+		}, []string{"reason"}),
+		balance: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "faucet",
+			Name:      "balance",
+			Help:      "Balance of the faucet account by denom, polled from the chain.",
+		}, []string{"denom"}),
+		broadcastLatency: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: "faucet",
+			Name:      "broadcast_duration_seconds",
+			Help:      "Duration of the transaction broadcasts.",
+			Buckets:   prometheus.ExponentialBuckets(0.01, 2, 12),
+		}),
+	}
+	m.registry.MustRegister(
+		m.requests,
+		m.grants,
+		m.grantedCoins,
+		m.failures,
+		m.balance,
+		m.broadcastLatency,
+		prometheus.NewGoCollector(),
+	)
+	return m
+}
+
+// Handler serves the metrics in the prometheus format
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// Instrument counts the requests served by the handler of the tier by status code
+func (m *Metrics) Instrument(tier string, handler http.Handler) http.Handler {
+	if m == nil {
+		return handler
+	}
+	return promhttp.InstrumentHandlerCounter(m.requests.MustCurryWith(prometheus.Labels{"tier": tier}), handler)
+}
+
+// PollBalance sets the balance of the faucet account every interval until stop is closed,
+// the denoms are set to zero when the account has none of them
+func (m *Metrics) PollBalance(tm *TxManager, denoms []string, interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		balance, err := tm.Balance()
+		if err != nil {
+			log.Error(errors.Wrap(err, "balance poll failed"))
+		} else {
+			m.setBalance(denoms, balance)
+		}
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (m *Metrics) setBalance(denoms []string, balance sdk.Coins) {
+	if m == nil {
+		return
+	}
+	// spent denoms are missing from the balance
+	for _, denom := range denoms {
+		m.balance.WithLabelValues(denom).Set(toFloat(balance.AmountOf(denom)))
+	}
+	for _, coin := range balance {
+		m.balance.WithLabelValues(coin.Denom).Set(toFloat(coin.Amount))
+	}
+}
+
+func (m *Metrics) grant(tier string, amount sdk.Coins) {
+	if m == nil {
+		return
+	}
+	m.grants.WithLabelValues(tier).Inc()
+	for _, coin := range amount {
+		m.grantedCoins.WithLabelValues(coin.Denom).Add(toFloat(coin.Amount))
+	}
+}
+
+func (m *Metrics) failure(reason string) {
+	if m == nil {
+		return
+	}
+	m.failures.WithLabelValues(reason).Inc()
+}
+
+func (m *Metrics) broadcast(d time.Duration) {
+	if m == nil {
+		return
+	}
+	m.broadcastLatency.Observe(d.Seconds())
+}
+
+// toFloat converts the amount, which may not fit an int64, to a float
+func toFloat(amount sdk.Int) float64 {
+	f, _ := new(big.Float).SetInt(amount.BigInt()).Float64()
+	return f
+}
@@ -0,0 +1,113 @@
+package pkg
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestMetrics_Requests(t *testing.T) {
+	tm, _ := newTestTxManager(t)
+	metrics := NewMetrics()
+	q := NewQueue(Configuration{BatchMax: 10, JobTTL: time.Hour}, tm).WithMetrics(metrics)
+	handler := metrics.Instrument(testTier.Name, NewFaucetHandler(tm.conf, testTier, q).WithMetrics(metrics))
+	for _, address := range []string{"", "star1invalid"} {
+		r := httptest.NewRequest(http.MethodGet, "/credit?address="+address, nil)
+		handler.ServeHTTP(httptest.NewRecorder(), r)
+	}
+	if got := testutil.ToFloat64(metrics.requests.WithLabelValues(testTier.Name, "400")); got != 2 {
+		t.Fatalf("want 2 requests, got %v", got)
+	}
+	if got := testutil.ToFloat64(metrics.failures.WithLabelValues(reasonInvalidAddress)); got != 2 {
+		t.Fatalf("want 2 failures, got %v", got)
+	}
+	// the metrics are served in the prometheus format
+	w := httptest.NewRecorder()
+	metrics.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if !strings.Contains(w.Body.String(), `faucet_failures_total{reason="invalid_address"} 2`) {
+		t.Fatalf("unexpected metrics %s", w.Body)
+	}
+}
+
+func TestMetrics_Grants(t *testing.T) {
+	tm, node := newTestTxManager(t)
+	metrics := NewMetrics()
+	tm.WithMetrics(metrics)
+	var out bytes.Buffer
+	q := NewQueue(Configuration{BatchMax: 10, JobTTL: time.Hour}, tm).WithMetrics(metrics).WithAuditLog(NewAuditLog(&out))
+	sent, err := q.Submit(Credit{Target: testAddress("a"), Amount: testAmount}, CreditRequest{Tier: "test", IP: "1.1.1.1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	q.Flush()
+	node.failures = 1
+	failed, err := q.Submit(Credit{Target: testAddress("b"), Amount: testAmount}, CreditRequest{Tier: "test", IP: "2.2.2.2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	q.Flush()
+
+	if got := testutil.ToFloat64(metrics.grants.WithLabelValues("test")); got != 1 {
+		t.Fatalf("want 1 grant, got %v", got)
+	}
+	if got := testutil.ToFloat64(metrics.grantedCoins.WithLabelValues("tiov")); got != 100 {
+		t.Fatalf("want 100 granted coins, got %v", got)
+	}
+	if got := testutil.ToFloat64(metrics.failures.WithLabelValues(reasonBroadcast)); got != 1 {
+		t.Fatalf("want 1 failure, got %v", got)
+	}
+	if got := testutil.CollectAndCount(metrics.broadcastLatency); got != 1 {
+		t.Fatalf("want the broadcast histogram, got %d metrics", got)
+	}
+
+	// the audit log has a line per processed credit
+	dec := json.NewDecoder(&out)
+	for _, want := range []AuditEntry{
+		{JobID: sent.ID, Status: JobSent, Tier: "test", Address: testAddress("a").String(), Amount: "100tiov", IP: "1.1.1.1"},
+		{JobID: failed.ID, Status: JobFailed, Tier: "test", Address: testAddress("b").String(), Amount: "100tiov", IP: "2.2.2.2", Error: "credit failed"},
+	} {
+		var got AuditEntry
+		if err := dec.Decode(&got); err != nil {
+			t.Fatal(err)
+		}
+		if want.Status == JobSent && got.TxHash == "" {
+			t.Fatalf("no tx hash in %+v", got)
+		}
+		got.Time, got.TxHash = time.Time{}, ""
+		if got != want {
+			t.Fatalf("want %+v, got %+v", want, got)
+		}
+	}
+}
+
+func TestMetrics_PollBalance(t *testing.T) {
+	tm, node := newTestTxManager(t)
+	node.account.Coins = sdk.NewCoins(sdk.NewInt64Coin("tiov", 1000), sdk.NewInt64Coin("tvoi", 7))
+	metrics := NewMetrics()
+	stop := make(chan struct{})
+	close(stop)
+	// polls once before stopping
+	metrics.PollBalance(tm, []string{"tiov", "tvoi"}, time.Minute, stop)
+	if got := testutil.ToFloat64(metrics.balance.WithLabelValues("tiov")); got != 1000 {
+		t.Fatalf("want balance 1000, got %v", got)
+	}
+	if got := testutil.ToFloat64(metrics.balance.WithLabelValues("tvoi")); got != 7 {
+		t.Fatalf("want balance 7, got %v", got)
+	}
+	// the spent denoms are set to zero
+	node.account.Coins = sdk.NewCoins(sdk.NewInt64Coin("tiov", 400))
+	metrics.PollBalance(tm, []string{"tiov", "tvoi"}, time.Minute, stop)
+	if got := testutil.ToFloat64(metrics.balance.WithLabelValues("tiov")); got != 400 {
+		t.Fatalf("want balance 400, got %v", got)
+	}
+	if got := testutil.ToFloat64(metrics.balance.WithLabelValues("tvoi")); got != 0 {
+		t.Fatalf("want balance 0, got %v", got)
+	}
+}
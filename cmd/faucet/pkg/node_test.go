@@ -0,0 +1,193 @@
+package pkg
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keys"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/iov-one/iovns/pkg/queries"
+	"github.com/iov-one/iovns/x/configuration"
+	"github.com/iov-one/iovns/x/starname/keeper"
+	starname "github.com/iov-one/iovns/x/starname/types"
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/libs/bytes"
+	rpcclient "github.com/tendermint/tendermint/rpc/client"
+	coretypes "github.com/tendermint/tendermint/rpc/core/types"
+	"github.com/tendermint/tendermint/types"
+)
+
+// mockNode keeps the faucet account and checks the signatures of the broadcast
+// transactions against its sequence like the chain does
+type mockNode struct {
+	chainID string
+	mux     sync.Mutex
+	account auth.BaseAccount
+	txs     []auth.StdTx
+	// failures is the number of next broadcasts that fail
+	failures int
+	// mismatches is the number of next broadcasts rejected for a sequence mismatch
+	mismatches int
+	// broadcasts is the number of broadcasts
+	broadcasts int
+	// domains, config, reserved and accounts are the starname state of the chain
+	domains  map[string]starname.Domain
+	config   configuration.Config
+	reserved configuration.ReservedNames
+	accounts map[string]bool
+}
+
+var _ rpcclient.ABCIClient = (*mockNode)(nil)
+
+func (n *mockNode) ABCIInfo() (*coretypes.ResultABCIInfo, error) {
+	return &coretypes.ResultABCIInfo{}, nil
+}
+
+func (n *mockNode) ABCIQuery(path string, data bytes.HexBytes) (*coretypes.ResultABCIQuery, error) {
+	return n.ABCIQueryWithOptions(path, data, rpcclient.DefaultABCIQueryOptions)
+}
+
+func (n *mockNode) ABCIQueryWithOptions(path string, data bytes.HexBytes, _ rpcclient.ABCIQueryOptions) (*coretypes.ResultABCIQuery, error) {
+	n.mux.Lock()
+	defer n.mux.Unlock()
+	var value []byte
+	switch path {
+	case fmt.Sprintf("custom/%s/%s", auth.QuerierRoute, auth.QueryAccount):
+		value = ModuleCdc.MustMarshalJSON(n.account)
+	case "/app/simulate":
+		value = ModuleCdc.MustMarshalBinaryBare(sdk.SimulationResponse{GasInfo: sdk.GasInfo{GasUsed: 50000}})
+	case "custom/starname/domainInfo":
+		q := new(keeper.QueryResolveDomain)
+		if err := queries.DefaultQueryDecode(data, q); err != nil {
+			return nil, err
+		}
+		domain, ok := n.domains[q.Name]
+		if !ok {
+			return notFound(q.Name), nil
+		}
+		value, _ = queries.DefaultQueryEncode(keeper.QueryResolveDomainResponse{Domain: domain})
+	case "custom/starname/resolve":
+		q := new(keeper.QueryResolveAccount)
+		if err := queries.DefaultQueryDecode(data, q); err != nil {
+			return nil, err
+		}
+		if !n.accounts[starname.AccountStarname(q.Domain, q.Name)] {
+			return notFound(starname.AccountStarname(q.Domain, q.Name)), nil
+		}
+		value, _ = queries.DefaultQueryEncode(keeper.QueryResolveAccountResponse{})
+	case "custom/configuration/configuration":
+		value, _ = queries.DefaultQueryEncode(configuration.QueryConfigurationResponse{Config: n.config})
+	case "custom/configuration/reservedNames":
+		value, _ = queries.DefaultQueryEncode(configuration.QueryReservedNamesResponse{ReservedNames: n.reserved})
+	default:
+		return nil, fmt.Errorf("unexpected path %s", path)
+	}
+	return &coretypes.ResultABCIQuery{Response: abci.ResponseQuery{Value: value}}, nil
+}
+
+func (n *mockNode) BroadcastTxCommit(tx types.Tx) (*coretypes.ResultBroadcastTxCommit, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (n *mockNode) BroadcastTxAsync(tx types.Tx) (*coretypes.ResultBroadcastTx, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (n *mockNode) BroadcastTxSync(tx types.Tx) (*coretypes.ResultBroadcastTx, error) {
+	n.mux.Lock()
+	defer n.mux.Unlock()
+	var stdTx auth.StdTx
+	if err := ModuleCdc.UnmarshalBinaryLengthPrefixed(tx, &stdTx); err != nil {
+		return nil, err
+	}
+	n.broadcasts++
+	if n.failures > 0 {
+		n.failures--
+		return &coretypes.ResultBroadcastTx{
+			Code:      sdkerrors.ErrInsufficientFunds.ABCICode(),
+			Codespace: sdkerrors.RootCodespace,
+			Log:       "insufficient funds",
+			Hash:      tx.Hash(),
+		}, nil
+	}
+	signBytes := auth.StdSignBytes(n.chainID, n.account.AccountNumber, n.account.Sequence, stdTx.Fee, stdTx.Msgs, stdTx.Memo)
+	sig := stdTx.Signatures[0]
+	if n.mismatches > 0 || !sig.PubKey.VerifyBytes(signBytes, sig.Signature) {
+		if n.mismatches > 0 {
+			n.mismatches--
+		}
+		return &coretypes.ResultBroadcastTx{
+			Code:      sdkerrors.ErrUnauthorized.ABCICode(),
+			Codespace: sdkerrors.RootCodespace,
+			Log:       "signature verification failed; verify correct account sequence and chain-id",
+			Hash:      tx.Hash(),
+		}, nil
+	}
+	// registrations of existing accounts fail the transaction
+	for _, msg := range stdTx.Msgs {
+		if msg, ok := msg.(*starname.MsgRegisterAccount); ok && n.accounts[starname.AccountStarname(msg.Domain, msg.Name)] {
+			return &coretypes.ResultBroadcastTx{
+				Code:      starname.ErrAccountExists.ABCICode(),
+				Codespace: starname.ModuleName,
+				Log:       "account exists",
+				Hash:      tx.Hash(),
+			}, nil
+		}
+	}
+	for _, msg := range stdTx.Msgs {
+		if msg, ok := msg.(*starname.MsgRegisterAccount); ok {
+			n.accounts[starname.AccountStarname(msg.Domain, msg.Name)] = true
+		}
+	}
+	n.account.Sequence++
+	n.txs = append(n.txs, stdTx)
+	return &coretypes.ResultBroadcastTx{Hash: tx.Hash()}, nil
+}
+
+// notFound is the response of the queries of missing domains and accounts
+func notFound(name string) *coretypes.ResultABCIQuery {
+	return &coretypes.ResultABCIQuery{Response: abci.ResponseQuery{Code: 1, Log: fmt.Sprintf("not found: %s", name)}}
+}
+
+// sentTxs returns the transactions accepted by the node
+func (n *mockNode) sentTxs() []auth.StdTx {
+	n.mux.Lock()
+	defer n.mux.Unlock()
+	return append([]auth.StdTx(nil), n.txs...)
+}
+
+// newTestTxManager returns a tx manager of a faucet account known by the returned node
+func newTestTxManager(t *testing.T) (*TxManager, *mockNode) {
+	conf := Configuration{
+		ChainID:    "test",
+		KeyName:    "faucet",
+		Passphrase: "12345678",
+		GasPrices:  "10.0tiov",
+		GasAdjust:  1.2,
+	}
+	kb := keys.NewInMemory()
+	info, _, err := kb.CreateMnemonic("faucet", keys.English, conf.Passphrase, keys.Secp256k1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	node := &mockNode{chainID: conf.ChainID, accounts: make(map[string]bool)}
+	node.account = auth.BaseAccount{Address: info.GetAddress(), PubKey: info.GetPubKey(), AccountNumber: 7}
+	tm := NewTxManager(conf, node).WithKeybase(kb)
+	if err := tm.Init(); err != nil {
+		t.Fatal(err)
+	}
+	return tm, node
+}
+
+// testTier credits testAmount on /credit
+var testAmount = sdk.NewCoins(sdk.NewInt64Coin("tiov", 100))
+var testTier = Tier{Name: "test", Path: "/credit", Amount: testAmount}
+
+// testAddress returns a test address with the given seed
+func testAddress(seed string) sdk.AccAddress {
+	return sdk.AccAddress([]byte(strings.Repeat(seed, 20)[:20]))
+}
@@ -0,0 +1,300 @@
+package pkg
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/types/errors"
+	starname "github.com/iov-one/iovns/x/starname/types"
+	"github.com/prometheus/common/log"
+)
+
+// JobStatus is the status of a credit request
+type JobStatus string
+
+const (
+	JobPending JobStatus = "pending"
+	JobSent    JobStatus = "sent"
+	JobFailed  JobStatus = "failed"
+)
+
+// Job is a credit request waiting in the queue or processed
+type Job struct {
+	ID        string    `json:"id"`
+	Address   string    `json:"address"`
+	Starname  string    `json:"starname,omitempty"`
+	Status    JobStatus `json:"status"`
+	Hash      string    `json:"hash,omitempty"`
+	Error     string    `json:"error,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+
+	credit  Credit
+	request CreditRequest
+	spend   *Spend
+	done    chan struct{}
+}
+
+// CreditRequest describes the requester of a credit
+type CreditRequest struct {
+	// Tier is the name of the tier of the credit
+	Tier string
+	// IP is the ip of the requester
+	IP string
+	// Grant is the rate limiter grant refunded if the credit fails
+	Grant *Grant
+}
+
+// Queue collects the credit requests and sends them in a single transaction
+// every batch window or as soon as the batch is full
+type Queue struct {
+	tm      *TxManager
+	limiter *RateLimiter
+	budget  *Budget
+	metrics *Metrics
+	audit   *AuditLog
+	domain  string
+	window  time.Duration
+	max     int
+	jobTTL  time.Duration
+	now     func() time.Time
+	full    chan struct{}
+
+	mux     sync.Mutex
+	pending []*Job
+	jobs    map[string]*Job
+	// names are the names requested by the pending jobs
+	names map[string]struct{}
+}
+
+func NewQueue(conf Configuration, tm *TxManager) *Queue {
+	return &Queue{
+		tm:     tm,
+		domain: conf.StarnameDomain,
+		window: conf.BatchWindow,
+		max:    conf.BatchMax,
+		jobTTL: conf.JobTTL,
+		now:    time.Now,
+		full:   make(chan struct{}, 1),
+		jobs:   make(map[string]*Job),
+		names:  make(map[string]struct{}),
+	}
+}
+
+// WithRateLimiter refunds the grants of the failed jobs to the rate limiter
+func (q *Queue) WithRateLimiter(limiter *RateLimiter) *Queue {
+	q.limiter = limiter
+	return q
+}
+
+// WithBudget charges the credits to the daily budget, the failed jobs are refunded
+func (q *Queue) WithBudget(budget *Budget) *Queue {
+	q.budget = budget
+	return q
+}
+
+// WithMetrics records the sent credits and the failed transactions
+func (q *Queue) WithMetrics(metrics *Metrics) *Queue {
+	q.metrics = metrics
+	return q
+}
+
+// WithAuditLog records every processed credit in the audit log
+func (q *Queue) WithAuditLog(audit *AuditLog) *Queue {
+	q.audit = audit
+	return q
+}
+
+// Run sends the batches until stop is closed
+func (q *Queue) Run(stop <-chan struct{}) {
+	ticker := time.NewTicker(q.window)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			q.Flush()
+			return
+		case <-ticker.C:
+			q.Flush()
+		case <-q.full:
+			q.Flush()
+		}
+	}
+}
+
+// Submit queues the credit, the grant and the budget are refunded if the credit fails
+func (q *Queue) Submit(credit Credit, req CreditRequest) (*Job, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return nil, errors.Wrap(err, "job id generation failed")
+	}
+	job := &Job{
+		ID:        hex.EncodeToString(id),
+		Address:   credit.Target.String(),
+		Status:    JobPending,
+		CreatedAt: q.now(),
+		credit:    credit,
+		request:   req,
+		done:      make(chan struct{}),
+	}
+	if credit.Name != "" {
+		job.Starname = starname.AccountStarname(q.domain, credit.Name)
+	}
+	if q.budget != nil {
+		spend, err := q.budget.Take(credit.Amount)
+		if err != nil {
+			return nil, err
+		}
+		job.spend = &spend
+	}
+	q.mux.Lock()
+	if credit.Name != "" {
+		if _, ok := q.names[credit.Name]; ok {
+			q.mux.Unlock()
+			// the grant is given back by the caller
+			job.request.Grant = nil
+			q.refund(job)
+			return nil, errors.Wrap(ErrNameTaken, job.Starname)
+		}
+		q.names[credit.Name] = struct{}{}
+	}
+	q.jobs[job.ID] = job
+	q.pending = append(q.pending, job)
+	full := q.max > 0 && len(q.pending) >= q.max
+	q.mux.Unlock()
+	if full {
+		select {
+		case q.full <- struct{}{}:
+		default:
+		}
+	}
+	return job, nil
+}
+
+// Job returns the job with the given id
+func (q *Queue) Job(id string) (Job, bool) {
+	q.mux.Lock()
+	defer q.mux.Unlock()
+	job, ok := q.jobs[id]
+	if !ok {
+		return Job{}, false
+	}
+	return *job, true
+}
+
+// Wait waits for the job to be processed or the timeout to expire and returns the job
+func (q *Queue) Wait(job *Job, timeout time.Duration) Job {
+	select {
+	case <-job.done:
+	case <-time.After(timeout):
+	}
+	j, _ := q.Job(job.ID)
+	return j
+}
+
+// Flush sends the pending jobs, up to the batch size, in a single transaction
+func (q *Queue) Flush() {
+	q.mux.Lock()
+	now := q.now()
+	for id, job := range q.jobs {
+		if job.Status != JobPending && now.Sub(job.CreatedAt) > q.jobTTL {
+			delete(q.jobs, id)
+		}
+	}
+	batch := q.pending
+	if q.max > 0 && len(batch) > q.max {
+		batch = batch[:q.max]
+		// send the rest at the next flush
+		select {
+		case q.full <- struct{}{}:
+		default:
+		}
+	}
+	q.pending = q.pending[len(batch):]
+	q.mux.Unlock()
+	if len(batch) == 0 {
+		return
+	}
+
+	// give the grants and the budget back since the credits were not sent
+	for _, job := range q.process(batch) {
+		q.refund(job)
+	}
+}
+
+// refund gives back the grant and the budget spent by the job
+func (q *Queue) refund(job *Job) {
+	if job.request.Grant != nil && q.limiter != nil {
+		if err := q.limiter.Refund(*job.request.Grant); err != nil {
+			log.Error(errors.Wrap(err, "grant refund failed"))
+		}
+	}
+	if job.spend != nil && q.budget != nil {
+		if err := q.budget.Refund(*job.spend); err != nil {
+			log.Error(errors.Wrap(err, "budget refund failed"))
+		}
+	}
+}
+
+// process sends the batch and returns the failed jobs, a failed batch with
+// registrations is sent again one job at a time since a single name rejected
+// by the chain fails the whole transaction
+func (q *Queue) process(batch []*Job) []*Job {
+	credits := make([]Credit, len(batch))
+	registrations := false
+	for i, job := range batch {
+		credits[i] = job.credit
+		registrations = registrations || job.credit.Name != ""
+	}
+	hash, err := q.send(credits)
+	if err != nil && registrations && len(batch) > 1 {
+		log.Error(errors.Wrap(err, "batch failed, sending the credits one by one"))
+		var failed []*Job
+		for _, job := range batch {
+			failed = append(failed, q.process([]*Job{job})...)
+		}
+		return failed
+	}
+	if err != nil {
+		log.Error(err)
+		q.metrics.failure(reasonBroadcast)
+	}
+
+	q.mux.Lock()
+	for _, job := range batch {
+		if err != nil {
+			job.Status = JobFailed
+			job.Error = "credit failed"
+		} else {
+			job.Status = JobSent
+			job.Hash = hash
+		}
+		delete(q.names, job.credit.Name)
+	}
+	q.mux.Unlock()
+	for _, job := range batch {
+		if err == nil {
+			q.metrics.grant(job.request.Tier, job.credit.Amount)
+		}
+		if q.audit != nil {
+			if err := q.audit.Record(job); err != nil {
+				log.Error(err)
+			}
+		}
+		close(job.done)
+	}
+	if err != nil {
+		return batch
+	}
+	return nil
+}
+
+// send sends the credits and returns the hash of the transaction
+func (q *Queue) send(credits []Credit) (string, error) {
+	res, err := q.tm.SendTx(credits...)
+	if err != nil {
+		return "", err
+	}
+	return res.Hash.String(), nil
+}
@@ -0,0 +1,125 @@
+package pkg
+
+import (
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/bank"
+	dbm "github.com/tendermint/tm-db"
+)
+
+func TestQueue_Flush(t *testing.T) {
+	tm, node := newTestTxManager(t)
+	q := NewQueue(Configuration{BatchMax: 10, JobTTL: time.Hour}, tm)
+	targets := []sdk.AccAddress{testAddress("a"), testAddress("b"), testAddress("c")}
+	jobs := make([]*Job, len(targets))
+	for i, target := range targets {
+		job, err := q.Submit(Credit{Target: target, Amount: testAmount}, CreditRequest{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		jobs[i] = job
+	}
+	q.Flush()
+	txs := node.sentTxs()
+	if len(txs) != 1 {
+		t.Fatalf("want 1 tx, got %d", len(txs))
+	}
+	msg, ok := txs[0].Msgs[0].(bank.MsgMultiSend)
+	if !ok || len(msg.Outputs) != len(targets) {
+		t.Fatalf("unexpected msgs %v", txs[0].Msgs)
+	}
+	for i, job := range jobs {
+		j, ok := q.Job(job.ID)
+		if !ok {
+			t.Fatal("job not found")
+		}
+		if j.Status != JobSent || j.Hash == "" {
+			t.Fatalf("unexpected job %+v", j)
+		}
+		if !msg.Outputs[i].Address.Equals(targets[i]) {
+			t.Fatalf("unexpected output %s", msg.Outputs[i].Address)
+		}
+	}
+	// single credits are sent with a MsgSend
+	if _, err := q.Submit(Credit{Target: testAddress("d"), Amount: testAmount}, CreditRequest{}); err != nil {
+		t.Fatal(err)
+	}
+	q.Flush()
+	txs = node.sentTxs()
+	if _, ok := txs[1].Msgs[0].(bank.MsgSend); !ok {
+		t.Fatalf("unexpected msg %v", txs[1].Msgs[0])
+	}
+}
+
+func TestQueue_BatchMax(t *testing.T) {
+	tm, node := newTestTxManager(t)
+	q := NewQueue(Configuration{BatchMax: 2, JobTTL: time.Hour}, tm)
+	for _, seed := range []string{"a", "b", "c"} {
+		if _, err := q.Submit(Credit{Target: testAddress(seed), Amount: testAmount}, CreditRequest{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	q.Flush()
+	q.Flush()
+	txs := node.sentTxs()
+	if len(txs) != 2 {
+		t.Fatalf("want 2 txs, got %d", len(txs))
+	}
+	if len(txs[0].Msgs[0].(bank.MsgMultiSend).Outputs) != 2 {
+		t.Fatalf("unexpected first batch %v", txs[0].Msgs)
+	}
+}
+
+func TestQueue_Failure(t *testing.T) {
+	tm, node := newTestTxManager(t)
+	tier := Tier{Name: "test", Limits: Limits{AddressWindow: time.Hour, AddressLimit: 1}}
+	limiter := NewRateLimiter(Configuration{Tiers: []Tier{tier}}, dbm.NewMemDB())
+	q := NewQueue(Configuration{BatchMax: 10, JobTTL: time.Hour}, tm).WithRateLimiter(limiter)
+	target := testAddress("a")
+	grant, _, err := limiter.Take(tier.Name, target.String(), "1.1.1.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	node.failures = 1
+	job, err := q.Submit(Credit{Target: target, Amount: testAmount}, CreditRequest{Grant: &grant})
+	if err != nil {
+		t.Fatal(err)
+	}
+	q.Flush()
+	if j := q.Wait(job, time.Second); j.Status != JobFailed {
+		t.Fatalf("unexpected job %+v", j)
+	}
+	// the grant is refunded
+	if _, wait, err := limiter.Take(tier.Name, target.String(), "1.1.1.1"); err != nil || wait != 0 {
+		t.Fatalf("grant not refunded, wait %s, err %v", wait, err)
+	}
+	// the sequence is synced back to the chain
+	job, err = q.Submit(Credit{Target: target, Amount: testAmount}, CreditRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	q.Flush()
+	if j := q.Wait(job, time.Second); j.Status != JobSent {
+		t.Fatalf("unexpected job %+v", j)
+	}
+}
+
+func TestQueue_Run(t *testing.T) {
+	tm, node := newTestTxManager(t)
+	q := NewQueue(Configuration{BatchWindow: 10 * time.Millisecond, BatchMax: 10, JobTTL: time.Hour}, tm)
+	stop := make(chan struct{})
+	defer close(stop)
+	go q.Run(stop)
+	job, err := q.Submit(Credit{Target: testAddress("a"), Amount: testAmount}, CreditRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if j := q.Wait(job, 30*time.Second); j.Status != JobSent {
+		t.Fatalf("unexpected job %+v", j)
+	}
+	if len(node.sentTxs()) != 1 {
+		t.Fatal("credit not sent")
+	}
+}
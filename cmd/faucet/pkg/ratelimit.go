@@ -0,0 +1,173 @@
+package pkg
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	dbm "github.com/tendermint/tm-db"
+)
+
+// key prefixes of the grants records, under the prefix of their tier
+const (
+	tierGrantsPrefix    = "tier/"
+	addressGrantsPrefix = "address/"
+	ipGrantsPrefix      = "ip/"
+)
+
+// Grant is a credit of a tier granted to an address requested from an ip
+type Grant struct {
+	Tier    string
+	Address string
+	IP      string
+	Time    time.Time
+}
+
+// RateLimiter limits the grants per address and per ip with the limits of each tier,
+// the grants of a tier do not count towards the limits of the others, the grants
+// are kept in a database so that the limits survive restarts
+type RateLimiter struct {
+	db     dbm.DB
+	limits map[string]Limits
+	now    func() time.Time
+	mux    sync.Mutex
+}
+
+func NewRateLimiter(conf Configuration, db dbm.DB) *RateLimiter {
+	limits := make(map[string]Limits, len(conf.Tiers))
+	for _, tier := range conf.Tiers {
+		limits[tier.Name] = tier.Limits
+	}
+	return &RateLimiter{
+		db:     db,
+		limits: limits,
+		now:    time.Now,
+	}
+}
+
+// WithClock sets the function returning the current time
+func (l *RateLimiter) WithClock(now func() time.Time) *RateLimiter {
+	l.now = now
+	return l
+}
+
+// Take records a grant of the tier to the address requested from the ip if the limits
+// of the tier allow it, otherwise it returns the time to wait before the next grant
+func (l *RateLimiter) Take(tier, address, ip string) (Grant, time.Duration, error) {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+	limits, ok := l.limits[tier]
+	if !ok {
+		return Grant{}, 0, errors.Errorf("unknown tier %s", tier)
+	}
+	now := l.now()
+	addressKey := grantsKey(tier, addressGrantsPrefix, address)
+	ipKey := grantsKey(tier, ipGrantsPrefix, ip)
+	addressGrants, err := l.grants(addressKey, now, limits.AddressWindow)
+	if err != nil {
+		return Grant{}, 0, err
+	}
+	ipGrants, err := l.grants(ipKey, now, limits.IPWindow)
+	if err != nil {
+		return Grant{}, 0, err
+	}
+	wait := retryAfter(addressGrants, limits.AddressLimit, limits.AddressWindow, now)
+	if ipWait := retryAfter(ipGrants, limits.IPLimit, limits.IPWindow, now); ipWait > wait {
+		wait = ipWait
+	}
+	if wait > 0 {
+		return Grant{}, wait, nil
+	}
+	if err := l.setGrants(addressKey, append(addressGrants, now.UnixNano())); err != nil {
+		return Grant{}, 0, err
+	}
+	if err := l.setGrants(ipKey, append(ipGrants, now.UnixNano())); err != nil {
+		return Grant{}, 0, err
+	}
+	return Grant{Tier: tier, Address: address, IP: ip, Time: now}, 0, nil
+}
+
+// Refund removes the grant, used when the credit could not be sent
+func (l *RateLimiter) Refund(grant Grant) error {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+	limits, ok := l.limits[grant.Tier]
+	if !ok {
+		return errors.Errorf("unknown tier %s", grant.Tier)
+	}
+	now := l.now()
+	for _, k := range []struct {
+		key    []byte
+		window time.Duration
+	}{
+		{grantsKey(grant.Tier, addressGrantsPrefix, grant.Address), limits.AddressWindow},
+		{grantsKey(grant.Tier, ipGrantsPrefix, grant.IP), limits.IPWindow},
+	} {
+		grants, err := l.grants(k.key, now, k.window)
+		if err != nil {
+			return err
+		}
+		for i, t := range grants {
+			if t == grant.Time.UnixNano() {
+				grants = append(grants[:i], grants[i+1:]...)
+				break
+			}
+		}
+		if err := l.setGrants(k.key, grants); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// grantsKey returns the key of the grants of the tier to the address or ip id
+func grantsKey(tier, prefix, id string) []byte {
+	return []byte(tierGrantsPrefix + tier + "/" + prefix + id)
+}
+
+// grants returns the times of the grants of the key within the window
+func (l *RateLimiter) grants(key []byte, now time.Time, window time.Duration) ([]int64, error) {
+	b, err := l.db.Get(key)
+	if err != nil {
+		return nil, errors.Wrap(err, "grants read failed")
+	}
+	if b == nil {
+		return nil, nil
+	}
+	var all []int64
+	if err := json.Unmarshal(b, &all); err != nil {
+		return nil, errors.Wrap(err, "grants decoding failed")
+	}
+	since := now.Add(-window).UnixNano()
+	grants := make([]int64, 0, len(all))
+	for _, t := range all {
+		if t > since {
+			grants = append(grants, t)
+		}
+	}
+	return grants, nil
+}
+
+// setGrants saves the times of the grants of the key
+func (l *RateLimiter) setGrants(key []byte, grants []int64) error {
+	if len(grants) == 0 {
+		return errors.Wrap(l.db.DeleteSync(key), "grants deletion failed")
+	}
+	b, err := json.Marshal(grants)
+	if err != nil {
+		return errors.Wrap(err, "grants encoding failed")
+	}
+	return errors.Wrap(l.db.SetSync(key, b), "grants write failed")
+}
+
+// retryAfter returns the time to wait before the grants, sorted by time, go below the limit,
+// a limit of zero disables it
+func retryAfter(grants []int64, limit int, window time.Duration, now time.Time) time.Duration {
+	if limit <= 0 || len(grants) < limit {
+		return 0
+	}
+	// the grant that has to leave the window
+	oldest := time.Unix(0, grants[len(grants)-limit])
+	return oldest.Add(window).Sub(now)
+}
@@ -0,0 +1,129 @@
+package pkg
+
+import (
+	"io/ioutil"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	dbm "github.com/tendermint/tm-db"
+)
+
+func TestRateLimiter(t *testing.T) {
+	conf := Configuration{Tiers: []Tier{
+		{Name: "user", Limits: Limits{AddressWindow: 24 * time.Hour, AddressLimit: 1, IPWindow: time.Hour, IPLimit: 2}},
+		{Name: "developer", Limits: Limits{AddressWindow: time.Hour, AddressLimit: 2}},
+	}}
+	now := time.Unix(1600000000, 0)
+	clock := func() time.Time { return now }
+	l := NewRateLimiter(conf, dbm.NewMemDB()).WithClock(clock)
+
+	takeTier := func(tier, address, ip string, wantWait time.Duration) Grant {
+		t.Helper()
+		grant, wait, err := l.Take(tier, address, ip)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if wait != wantWait {
+			t.Fatalf("want wait %s, got %s", wantWait, wait)
+		}
+		return grant
+	}
+	take := func(address, ip string, wantWait time.Duration) Grant {
+		t.Helper()
+		return takeTier("user", address, ip, wantWait)
+	}
+
+	take("alice", "1.1.1.1", 0)
+	// address limited for a day
+	take("alice", "2.2.2.2", 24*time.Hour)
+	now = now.Add(time.Minute)
+	take("bob", "1.1.1.1", 0)
+	// ip limited until the first grant leaves the window
+	take("charlie", "1.1.1.1", time.Hour-time.Minute)
+	now = now.Add(time.Hour)
+	charlie := take("charlie", "1.1.1.1", 0)
+	// refunded grants do not count
+	if err := l.Refund(charlie); err != nil {
+		t.Fatal(err)
+	}
+	take("charlie", "3.3.3.3", 0)
+	now = now.Add(24 * time.Hour)
+	take("alice", "2.2.2.2", 0)
+	// the grants of a tier do not count towards the limits of the others
+	takeTier("developer", "alice", "2.2.2.2", 0)
+	takeTier("developer", "alice", "2.2.2.2", 0)
+	takeTier("developer", "alice", "2.2.2.2", time.Hour)
+	take("alice", "2.2.2.2", 24*time.Hour)
+	if _, _, err := l.Take("unknown", "alice", "2.2.2.2"); err == nil {
+		t.Fatal("grant of an unknown tier")
+	}
+}
+
+func TestRateLimiter_Persistence(t *testing.T) {
+	dir, err := ioutil.TempDir("", "faucet")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	conf := Configuration{Tiers: []Tier{{Name: "user", Limits: Limits{AddressWindow: time.Hour, AddressLimit: 1}}}}
+	db, err := dbm.NewGoLevelDB("faucet", dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, wait, err := NewRateLimiter(conf, db).Take("user", "alice", "1.1.1.1"); err != nil || wait != 0 {
+		t.Fatalf("unexpected wait %s, err %v", wait, err)
+	}
+	if err := db.Close(); err != nil {
+		t.Fatal(err)
+	}
+	// restart
+	db, err = dbm.NewGoLevelDB("faucet", dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if _, wait, err := NewRateLimiter(conf, db).Take("user", "alice", "1.1.1.1"); err != nil || wait == 0 {
+		t.Fatalf("grant not persisted, wait %s, err %v", wait, err)
+	}
+}
+
+func TestClientIP(t *testing.T) {
+	cases := map[string]struct {
+		forwarded      []string
+		trustedProxies int
+		want           string
+	}{
+		"no proxy ignores the header": {
+			forwarded: []string{"1.1.1.1"},
+			want:      "192.0.2.1",
+		},
+		"one proxy takes the rightmost entry": {
+			forwarded:      []string{"6.6.6.6, 1.1.1.1"},
+			trustedProxies: 1,
+			want:           "1.1.1.1",
+		},
+		"two proxies skip the entry of the inner proxy": {
+			forwarded:      []string{"6.6.6.6, 1.1.1.1", "10.0.0.1"},
+			trustedProxies: 2,
+			want:           "1.1.1.1",
+		},
+		"missing entries fall back to the connection": {
+			forwarded:      []string{"1.1.1.1"},
+			trustedProxies: 2,
+			want:           "192.0.2.1",
+		},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/credit", nil)
+			for _, forwarded := range c.forwarded {
+				r.Header.Add("X-Forwarded-For", forwarded)
+			}
+			if got := clientIP(r, c.trustedProxies); got != c.want {
+				t.Fatalf("want: %s, got: %s", c.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package pkg
+
+import (
+	"fmt"
+	"regexp"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/iov-one/iovns/pkg/idn"
+	"github.com/iov-one/iovns/pkg/queries"
+	"github.com/iov-one/iovns/x/configuration"
+	configtypes "github.com/iov-one/iovns/x/configuration/types"
+	"github.com/iov-one/iovns/x/starname/keeper"
+	"github.com/iov-one/iovns/x/starname/types"
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrInvalidName is returned for names that can't be registered
+	ErrInvalidName = sdkerrors.Register("faucet", 1, "invalid name")
+	// ErrNameTaken is returned for names requested by a pending credit
+	ErrNameTaken = sdkerrors.Register("faucet", 2, "name requested by a pending credit")
+)
+
+// Registrar checks the names the faucet registers in its closed domain
+type Registrar struct {
+	tm     *TxManager
+	domain string
+}
+
+func NewRegistrar(conf Configuration, tm *TxManager) *Registrar {
+	return &Registrar{
+		tm:     tm,
+		domain: conf.StarnameDomain,
+	}
+}
+
+// Init checks that the domain is a closed domain administered by the faucet account
+func (r *Registrar) Init() error {
+	var res keeper.QueryResolveDomainResponse
+	if err := r.query(types.QuerierRoute, &keeper.QueryResolveDomain{Name: r.domain}, &res); err != nil {
+		return errors.Wrapf(err, "domain %s", r.domain)
+	}
+	if res.Domain.Type != types.ClosedDomain {
+		return fmt.Errorf("domain %s is not a closed domain", r.domain)
+	}
+	if !res.Domain.Admin.Equals(r.tm.Address()) {
+		return fmt.Errorf("domain %s is not administered by the faucet account %s", r.domain, r.tm.Address())
+	}
+	return nil
+}
+
+// Starname returns the starname of the name in the domain
+func (r *Registrar) Starname(name string) string {
+	return types.AccountStarname(r.domain, name)
+}
+
+// Validate checks the name against the configuration of the chain and that
+// it is not registered in the domain yet
+func (r *Registrar) Validate(name string, owner sdk.AccAddress) error {
+	if !idn.IsCanonical(name) {
+		return errors.Wrapf(ErrInvalidName, "not in canonical form: %q", name)
+	}
+	var conf configuration.QueryConfigurationResponse
+	if err := r.query(configtypes.QuerierRoute, &configuration.QueryConfiguration{}, &conf); err != nil {
+		return errors.Wrap(err, "configuration query failed")
+	}
+	validName, err := regexp.Compile(conf.Config.ValidAccountName)
+	if err != nil {
+		return errors.Wrap(err, "invalid account name regexp")
+	}
+	if !validName.MatchString(name) {
+		return errors.Wrapf(ErrInvalidName, "%s does not match %s", name, conf.Config.ValidAccountName)
+	}
+	if err := idn.ValidateScripts(name); err != nil {
+		return errors.Wrap(ErrInvalidName, err.Error())
+	}
+	var reserved configuration.QueryReservedNamesResponse
+	if err := r.query(configtypes.QuerierRoute, &configuration.QueryReservedNames{Name: name}, &reserved); err != nil {
+		return errors.Wrap(err, "reserved names query failed")
+	}
+	if _, ok := reserved.ReservedNames.Blocking(name, owner); ok {
+		return errors.Wrapf(ErrInvalidName, "%s is reserved", name)
+	}
+	var account keeper.QueryResolveAccountResponse
+	if err := r.query(types.QuerierRoute, &keeper.QueryResolveAccount{Starname: r.Starname(name)}, &account); err == nil {
+		return errors.Wrapf(ErrInvalidName, "%s is already registered", r.Starname(name))
+	}
+	return nil
+}
+
+// query runs the query and decodes its response in res
+func (r *Registrar) query(route string, q queries.QueryHandler, res interface{}) error {
+	if err := q.Validate(); err != nil {
+		return err
+	}
+	data, err := queries.DefaultQueryEncode(q)
+	if err != nil {
+		return errors.Wrap(err, "query encoding failed")
+	}
+	path := fmt.Sprintf("custom/%s/%s", route, q.QueryPath())
+	result, err := r.tm.node.ABCIQuery(path, data)
+	if err != nil {
+		return errors.Wrap(err, "abci query failed")
+	}
+	if !result.Response.IsOK() {
+		return errors.New(result.Response.Log)
+	}
+	return queries.DefaultQueryDecode(result.Response.Value, res)
+}
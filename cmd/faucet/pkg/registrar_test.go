@@ -0,0 +1,158 @@
+package pkg
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/x/bank"
+	"github.com/iov-one/iovns/x/configuration"
+	starname "github.com/iov-one/iovns/x/starname/types"
+)
+
+// newTestRegistrar returns a registrar of the closed domain of the faucet account of the returned tx manager
+func newTestRegistrar(t *testing.T) (*Registrar, *TxManager, *mockNode) {
+	tm, node := newTestTxManager(t)
+	tm.conf.StarnameDomain = "faucet"
+	node.domains = map[string]starname.Domain{
+		"faucet": {Name: "faucet", Admin: tm.Address(), Type: starname.ClosedDomain},
+		"open":   {Name: "open", Admin: tm.Address(), Type: starname.OpenDomain},
+		"other":  {Name: "other", Admin: testAddress("o"), Type: starname.ClosedDomain},
+	}
+	node.config = configuration.Config{ValidAccountName: "^[-_\\.a-z0-9]{1,63}$"}
+	node.reserved = configuration.ReservedNames{{Name: "admin"}, {Name: "^iov.*$", Pattern: true, Claimant: testAddress("c")}}
+	node.accounts["taken*faucet"] = true
+	registrar := NewRegistrar(tm.conf, tm)
+	if err := registrar.Init(); err != nil {
+		t.Fatal(err)
+	}
+	return registrar, tm, node
+}
+
+func TestRegistrar_Init(t *testing.T) {
+	_, tm, _ := newTestRegistrar(t)
+	for _, domain := range []string{"open", "other", "missing"} {
+		conf := tm.conf
+		conf.StarnameDomain = domain
+		if err := NewRegistrar(conf, tm).Init(); err == nil {
+			t.Fatalf("domain %s accepted", domain)
+		}
+	}
+}
+
+func TestRegistrar_Validate(t *testing.T) {
+	registrar, _, _ := newTestRegistrar(t)
+	cases := map[string]struct {
+		name    string
+		owner   string
+		invalid bool
+	}{
+		"valid":                 {name: "alice", owner: "a"},
+		"not canonical":         {name: "Alice", owner: "a", invalid: true},
+		"invalid chars":         {name: "al ice", owner: "a", invalid: true},
+		"mixed scripts":         {name: "аlice", owner: "a", invalid: true},
+		"reserved":              {name: "admin", owner: "a", invalid: true},
+		"reserved pattern":      {name: "iovteam", owner: "a", invalid: true},
+		"reserved for claimant": {name: "iovteam", owner: "c"},
+		"registered":            {name: "taken", owner: "a", invalid: true},
+		"registered in another": {name: "other", owner: "a"},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := registrar.Validate(c.name, testAddress(c.owner))
+			if !c.invalid && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if c.invalid && !ErrInvalidName.Is(err) {
+				t.Fatalf("want invalid name, got %v", err)
+			}
+		})
+	}
+}
+
+func TestQueue_Registrations(t *testing.T) {
+	_, tm, node := newTestRegistrar(t)
+	q := NewQueue(tm.conf, tm)
+	q.max = 10
+	q.jobTTL = time.Hour
+	alice, err := q.Submit(Credit{Target: testAddress("a"), Name: "alice", Amount: testAmount}, CreditRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if alice.Starname != "alice*faucet" {
+		t.Fatalf("unexpected starname %s", alice.Starname)
+	}
+	// the name of a pending credit can't be requested again
+	if _, err := q.Submit(Credit{Target: testAddress("b"), Name: "alice", Amount: testAmount}, CreditRequest{}); !ErrNameTaken.Is(err) {
+		t.Fatalf("want name taken, got %v", err)
+	}
+	// registered after its validation, fails the batch
+	taken, err := q.Submit(Credit{Target: testAddress("b"), Name: "taken", Amount: testAmount}, CreditRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	plain, err := q.Submit(Credit{Target: testAddress("c"), Amount: testAmount}, CreditRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	q.Flush()
+	// the failed batch is sent again one credit at a time
+	txs := node.sentTxs()
+	if len(txs) != 2 {
+		t.Fatalf("want 2 txs, got %d", len(txs))
+	}
+	msg, ok := txs[0].Msgs[1].(*starname.MsgRegisterAccount)
+	if !ok || msg.Name != "alice" || msg.Domain != "faucet" || !msg.Owner.Equals(testAddress("a")) || !msg.Registerer.Equals(tm.Address()) {
+		t.Fatalf("unexpected msgs %v", txs[0].Msgs)
+	}
+	if _, ok := txs[0].Msgs[0].(bank.MsgSend); !ok {
+		t.Fatalf("unexpected msgs %v", txs[0].Msgs)
+	}
+	if len(txs[1].Msgs) != 1 {
+		t.Fatalf("unexpected msgs %v", txs[1].Msgs)
+	}
+	for job, status := range map[*Job]JobStatus{alice: JobSent, taken: JobFailed, plain: JobSent} {
+		if j := q.Wait(job, time.Second); j.Status != status {
+			t.Fatalf("unexpected job %+v", j)
+		}
+	}
+	// the name can be requested again once the credit is processed
+	if _, err := q.Submit(Credit{Target: testAddress("b"), Name: "taken", Amount: testAmount}, CreditRequest{}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFaucetHandler_Name(t *testing.T) {
+	registrar, tm, _ := newTestRegistrar(t)
+	q := NewQueue(tm.conf, tm)
+	cases := map[string]struct {
+		handler *FaucetHandler
+		name    string
+		status  int
+	}{
+		"disabled": {handler: NewFaucetHandler(tm.conf, testTier, q), name: "alice", status: http.StatusBadRequest},
+		"invalid":  {handler: NewFaucetHandler(tm.conf, testTier, q).WithRegistrar(registrar), name: "Alice", status: http.StatusBadRequest},
+		"taken":    {handler: NewFaucetHandler(tm.conf, testTier, q).WithRegistrar(registrar), name: "taken", status: http.StatusBadRequest},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/credit?address="+testAddress("a").String()+"&name="+c.name, nil)
+			w := httptest.NewRecorder()
+			c.handler.ServeHTTP(w, r)
+			if w.Code != c.status {
+				t.Fatalf("want %d, got %d: %s", c.status, w.Code, w.Body)
+			}
+		})
+	}
+	// a name requested by a pending credit is a conflict
+	if _, err := q.Submit(Credit{Target: testAddress("b"), Name: "bob", Amount: testAmount}, CreditRequest{}); err != nil {
+		t.Fatal(err)
+	}
+	r := httptest.NewRequest(http.MethodGet, "/credit?address="+testAddress("a").String()+"&name=bob", nil)
+	w := httptest.NewRecorder()
+	NewFaucetHandler(tm.conf, testTier, q).WithRegistrar(registrar).ServeHTTP(w, r)
+	if w.Code != http.StatusConflict {
+		t.Fatalf("want %d, got %d: %s", http.StatusConflict, w.Code, w.Body)
+	}
+}
@@ -2,7 +2,11 @@ package pkg
 
 import (
 	"fmt"
+	"strings"
 	"sync"
+	"time"
+
+	"github.com/prometheus/common/log"
 
 	"github.com/cosmos/cosmos-sdk/x/auth/client/utils"
 
@@ -14,6 +18,7 @@ import (
 	keys "github.com/cosmos/cosmos-sdk/crypto/keys"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/x/auth"
+	starname "github.com/iov-one/iovns/x/starname/types"
 	rpchttp "github.com/tendermint/tendermint/rpc/client"
 	coretypes "github.com/tendermint/tendermint/rpc/core/types"
 )
@@ -24,8 +29,33 @@ type TxManager struct {
 	kb        keys.Keybase
 	faucetAcc *auth.BaseAccount
 	mux       sync.Mutex
+	status    TxManagerStatus
+	metrics   *Metrics
+}
+
+// TxManagerStatus reports the health of the tx manager and the sequence of the faucet account
+type TxManagerStatus struct {
+	Healthy       bool       `json:"healthy"`
+	Address       string     `json:"address"`
+	AccountNumber uint64     `json:"account_number"`
+	Sequence      uint64     `json:"sequence"`
+	Resyncs       uint64     `json:"resyncs"`
+	LastError     string     `json:"last_error,omitempty"`
+	LastErrorAt   *time.Time `json:"last_error_at,omitempty"`
+	LastSentAt    *time.Time `json:"last_sent_at,omitempty"`
 }
 
+// Credit is a send of Amount to the target, with the registration of the
+// account Name in the starname domain of the faucet if Name is not empty
+type Credit struct {
+	Target sdk.AccAddress
+	Name   string
+	Amount sdk.Coins
+}
+
+// sequenceMismatchLog is the log of the ante handler when the signature does not match the sequence
+const sequenceMismatchLog = "account sequence"
+
 func (tm *TxManager) queryWithData(path string, data []byte) ([]byte, int64, error) {
 	res, err := tm.node.ABCIQuery(path, data)
 	if err != nil {
@@ -43,8 +73,14 @@ func (tm *TxManager) WithKeybase(kb keys.Keybase) *TxManager {
 	return tm
 }
 
+// WithMetrics records the broadcast latency and the sequence mismatches
+func (tm *TxManager) WithMetrics(metrics *Metrics) *TxManager {
+	tm.metrics = metrics
+	return tm
+}
+
 func (tm *TxManager) Init() error {
-	info, err := tm.kb.Get("faucet")
+	info, err := tm.kb.Get(tm.conf.KeyName)
 	if err != nil {
 		return err
 	}
@@ -55,6 +91,7 @@ func (tm *TxManager) Init() error {
 		return err
 	}
 	tm.faucetAcc = acc
+	tm.status.Healthy = true
 	return nil
 }
 
@@ -80,19 +117,144 @@ func (tm *TxManager) fetchAccount(addr sdk.AccAddress) (*auth.BaseAccount, error
 	return &baseAcc, nil
 }
 
+// Resync refetches the faucet account to set the local sequence back to the one of the chain
+func (tm *TxManager) Resync() error {
+	acc, err := tm.fetchAccount(tm.faucetAcc.GetAddress())
+	if err != nil {
+		return err
+	}
+	tm.mux.Lock()
+	tm.faucetAcc = acc
+	tm.status.Resyncs++
+	tm.mux.Unlock()
+	return nil
+}
+
+// Balance returns the balance of the faucet account on the chain
+func (tm *TxManager) Balance() (sdk.Coins, error) {
+	acc, err := tm.fetchAccount(tm.Address())
+	if err != nil {
+		return nil, err
+	}
+	return acc.GetCoins(), nil
+}
+
+// Status returns the status of the tx manager
+func (tm *TxManager) Status() TxManagerStatus {
+	tm.mux.Lock()
+	defer tm.mux.Unlock()
+	status := tm.status
+	status.Address = tm.faucetAcc.GetAddress().String()
+	status.AccountNumber = tm.faucetAcc.GetAccountNumber()
+	status.Sequence = tm.faucetAcc.GetSequence()
+	return status
+}
+
+// Address returns the address of the faucet account
+func (tm *TxManager) Address() sdk.AccAddress {
+	tm.mux.Lock()
+	defer tm.mux.Unlock()
+	return tm.faucetAcc.GetAddress()
+}
+
+// SendTx builds, signs and broadcasts a transaction with the credits, the transaction
+// is built again with the sequence of the chain when it is rejected for a sequence mismatch,
+// up to the configured number of retries
+func (tm *TxManager) SendTx(credits ...Credit) (*coretypes.ResultBroadcastTx, error) {
+	res, err := tm.sendTx(credits)
+	tm.mux.Lock()
+	defer tm.mux.Unlock()
+	now := time.Now()
+	if err != nil {
+		tm.status.Healthy = false
+		tm.status.LastError = err.Error()
+		tm.status.LastErrorAt = &now
+		return nil, err
+	}
+	tm.status.Healthy = true
+	tm.status.LastSentAt = &now
+	return res, nil
+}
+
+func (tm *TxManager) sendTx(credits []Credit) (*coretypes.ResultBroadcastTx, error) {
+	for attempt := 0; ; attempt++ {
+		tx, err := tm.BuildAndSignTx(credits...)
+		if err != nil {
+			// the sequence was bumped for a transaction that is not sent
+			if err := tm.Resync(); err != nil {
+				log.Error(errors.Wrap(err, "sequence resync failed"))
+			}
+			return nil, errors.Wrap(err, "tx signing failed")
+		}
+		res, err := tm.BroadcastTx(tx)
+		if err != nil {
+			if err := tm.Resync(); err != nil {
+				log.Error(errors.Wrap(err, "sequence resync failed"))
+			}
+			return nil, errors.Wrap(err, "broadcast tx failed")
+		}
+		if res.Code == errors.SuccessABCICode {
+			return res, nil
+		}
+		// the tx was rejected by CheckTx so the sequence was not used
+		if err := tm.Resync(); err != nil {
+			return nil, errors.Wrap(err, "sequence resync failed")
+		}
+		mismatch := isSequenceMismatch(res)
+		if mismatch {
+			tm.metrics.failure(reasonSequenceMismatch)
+		}
+		if !mismatch || attempt >= tm.conf.BroadcastRetries {
+			return nil, fmt.Errorf("broadcast tx failed: code %d: %s", res.Code, res.Log)
+		}
+		log.Infof("sequence mismatch, retrying with sequence %d: %s", tm.Status().Sequence, res.Log)
+	}
+}
+
+// isSequenceMismatch tells if the transaction was rejected because it was signed with a wrong sequence
+func isSequenceMismatch(res *coretypes.ResultBroadcastTx) bool {
+	if res.Codespace != "" && res.Codespace != errors.RootCodespace {
+		return false
+	}
+	switch res.Code {
+	case errors.ErrInvalidSequence.ABCICode():
+		return true
+	case errors.ErrUnauthorized.ABCICode():
+		return strings.Contains(res.Log, sequenceMismatchLog)
+	default:
+		return false
+	}
+}
+
 func (tm *TxManager) BroadcastTx(tx []byte) (*coretypes.ResultBroadcastTx, error) {
+	start := time.Now()
+	defer func() { tm.metrics.broadcast(time.Since(start)) }()
 	return tm.node.BroadcastTxSync(tx)
 }
 
-func (tm *TxManager) BuildAndSignTx(targetAcc sdk.AccAddress) ([]byte, error) {
+// BuildAndSignTx builds a transaction sending the amount of each credit to its target,
+// with a bank.MsgSend for a single target and a bank.MsgMultiSend otherwise, followed by
+// the registrations of the named credits in the starname domain of the faucet
+func (tm *TxManager) BuildAndSignTx(credits ...Credit) ([]byte, error) {
+	if len(credits) == 0 {
+		return nil, errors.Wrap(errors.ErrInvalidRequest, "no target account")
+	}
+	total := sdk.NewCoins()
+	for _, credit := range credits {
+		if credit.Amount.Empty() || !credit.Amount.IsValid() {
+			return nil, errors.Wrapf(errors.ErrInvalidCoins, "credit to %s: %s", credit.Target, credit.Amount)
+		}
+		total = total.Add(credit.Amount...)
+	}
 	/* CONTRACT
 	a faucet wallet must be used by single actor otherwise successful tx will bump
 	account sequence on chain.
 	*/
 	//
 	tm.mux.Lock()
-	seq := tm.faucetAcc.GetSequence()
-	err := tm.faucetAcc.SetSequence(seq + 1)
+	faucetAcc := tm.faucetAcc
+	seq := faucetAcc.GetSequence()
+	err := faucetAcc.SetSequence(seq + 1)
 	tm.mux.Unlock()
 	if err != nil {
 		return nil, err
@@ -100,29 +262,53 @@ func (tm *TxManager) BuildAndSignTx(targetAcc sdk.AccAddress) ([]byte, error) {
 
 	txBuilder := auth.TxBuilder{}.
 		WithTxEncoder(auth.DefaultTxEncoder(ModuleCdc)).
-		WithAccountNumber(tm.faucetAcc.AccountNumber).
+		WithAccountNumber(faucetAcc.AccountNumber).
 		WithSequence(seq).
 		WithGasPrices(tm.conf.GasPrices).
 		WithChainID(tm.conf.ChainID).
 		WithMemo(tm.conf.Memo).WithKeybase(tm.kb)
 
-	sendMsg := bank.MsgSend{
-		FromAddress: tm.faucetAcc.GetAddress(),
-		ToAddress:   targetAcc,
-		Amount: sdk.Coins{
-			sdk.NewInt64Coin(tm.conf.CoinDenom, tm.conf.SendAmount),
-		},
+	var sendMsg sdk.Msg = bank.MsgSend{
+		FromAddress: faucetAcc.GetAddress(),
+		ToAddress:   credits[0].Target,
+		Amount:      credits[0].Amount,
+	}
+	if len(credits) > 1 {
+		outputs := make([]bank.Output, len(credits))
+		for i, credit := range credits {
+			outputs[i] = bank.NewOutput(credit.Target, credit.Amount)
+		}
+		sendMsg = bank.NewMsgMultiSend([]bank.Input{bank.NewInput(faucetAcc.GetAddress(), total)}, outputs)
+	}
+	msgs := []sdk.Msg{sendMsg}
+	for _, credit := range credits {
+		if credit.Name == "" {
+			continue
+		}
+		if tm.conf.StarnameDomain == "" {
+			return nil, errors.Wrap(errors.ErrInvalidRequest, "no starname domain configured")
+		}
+		// the faucet administers the closed domain so it registers the account and pays the fees
+		msgs = append(msgs, &starname.MsgRegisterAccount{
+			Domain:     tm.conf.StarnameDomain,
+			Name:       credit.Name,
+			Owner:      credit.Target,
+			Registerer: faucetAcc.GetAddress(),
+		})
 	}
 
 	// adjust gas
-	simTx, err := txBuilder.BuildTxForSim([]sdk.Msg{sendMsg})
+	simTx, err := txBuilder.BuildTxForSim(msgs)
 	if err != nil {
 		return nil, errors.Wrap(err, "tx gas adjustment failed")
 	}
 	_, adjusted, err := utils.CalculateGas(tm.queryWithData, ModuleCdc, simTx, tm.conf.GasAdjust)
+	if err != nil {
+		return nil, errors.Wrap(err, "tx simulation failed")
+	}
 
 	txBuilder = txBuilder.WithGas(adjusted)
-	tx, err := txBuilder.BuildAndSign("faucet", tm.conf.Passphrase, []sdk.Msg{sendMsg})
+	tx, err := txBuilder.BuildAndSign(tm.conf.KeyName, tm.conf.Passphrase, msgs)
 	if err != nil {
 		return nil, errors.Wrap(err, "tx signing failed")
 	}
@@ -0,0 +1,129 @@
+package pkg
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	coretypes "github.com/tendermint/tendermint/rpc/core/types"
+)
+
+func TestTxManager_SendTx(t *testing.T) {
+	t.Run("success out of band transaction", func(t *testing.T) {
+		tm, node := newTestTxManager(t)
+		tm.conf.BroadcastRetries = 1
+		// the faucet key was used by another client
+		node.account.Sequence += 2
+		if _, err := tm.SendTx(Credit{Target: testAddress("a"), Amount: testAmount}); err != nil {
+			t.Fatal(err)
+		}
+		status := tm.Status()
+		if !status.Healthy || status.Sequence != 3 || status.Resyncs != 1 || status.LastSentAt == nil {
+			t.Fatalf("unexpected status %+v", status)
+		}
+		if node.broadcasts != 2 {
+			t.Fatalf("want 2 broadcasts, got %d", node.broadcasts)
+		}
+	})
+
+	t.Run("fail retries exhausted", func(t *testing.T) {
+		tm, node := newTestTxManager(t)
+		tm.conf.BroadcastRetries = 2
+		node.mismatches = 5
+		if _, err := tm.SendTx(Credit{Target: testAddress("a"), Amount: testAmount}); err == nil {
+			t.Fatal("expected error")
+		}
+		if node.broadcasts != 3 {
+			t.Fatalf("want 3 broadcasts, got %d", node.broadcasts)
+		}
+		status := tm.Status()
+		if status.Healthy || status.LastError == "" || status.Sequence != 0 {
+			t.Fatalf("unexpected status %+v", status)
+		}
+		// recovers once the chain accepts the transactions
+		node.mismatches = 0
+		if _, err := tm.SendTx(Credit{Target: testAddress("a"), Amount: testAmount}); err != nil {
+			t.Fatal(err)
+		}
+		if !tm.Status().Healthy {
+			t.Fatal("unhealthy tx manager")
+		}
+	})
+
+	t.Run("fail no retry on other errors", func(t *testing.T) {
+		tm, node := newTestTxManager(t)
+		tm.conf.BroadcastRetries = 3
+		node.failures = 1
+		if _, err := tm.SendTx(Credit{Target: testAddress("a"), Amount: testAmount}); err == nil {
+			t.Fatal("expected error")
+		}
+		if node.broadcasts != 1 {
+			t.Fatalf("want 1 broadcast, got %d", node.broadcasts)
+		}
+		// the unused sequence is given back
+		if tm.Status().Sequence != 0 {
+			t.Fatalf("unexpected sequence %d", tm.Status().Sequence)
+		}
+	})
+}
+
+func TestIsSequenceMismatch(t *testing.T) {
+	cases := map[string]struct {
+		res  coretypes.ResultBroadcastTx
+		want bool
+	}{
+		"signature verification": {
+			res: coretypes.ResultBroadcastTx{
+				Code:      sdkerrors.ErrUnauthorized.ABCICode(),
+				Codespace: sdkerrors.RootCodespace,
+				Log:       "signature verification failed; verify correct account sequence and chain-id",
+			},
+			want: true,
+		},
+		"invalid sequence": {
+			res:  coretypes.ResultBroadcastTx{Code: sdkerrors.ErrInvalidSequence.ABCICode(), Codespace: sdkerrors.RootCodespace},
+			want: true,
+		},
+		"other unauthorized": {
+			res:  coretypes.ResultBroadcastTx{Code: sdkerrors.ErrUnauthorized.ABCICode(), Codespace: sdkerrors.RootCodespace, Log: "unauthorized"},
+			want: false,
+		},
+		"other codespace": {
+			res:  coretypes.ResultBroadcastTx{Code: sdkerrors.ErrInvalidSequence.ABCICode(), Codespace: "starname"},
+			want: false,
+		},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := isSequenceMismatch(&c.res); got != c.want {
+				t.Fatalf("want %t, got %t", c.want, got)
+			}
+		})
+	}
+}
+
+func TestStatusHandler(t *testing.T) {
+	tm, node := newTestTxManager(t)
+	get := func(wantCode int) TxManagerStatus {
+		rec := httptest.NewRecorder()
+		NewStatusHandler(tm).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/status", nil))
+		if rec.Code != wantCode {
+			t.Fatalf("want status code %d, got %d", wantCode, rec.Code)
+		}
+		var status TxManagerStatus
+		if err := json.NewDecoder(rec.Body).Decode(&status); err != nil {
+			t.Fatal(err)
+		}
+		return status
+	}
+	if status := get(http.StatusOK); status.Address != node.account.Address.String() || status.AccountNumber != 7 {
+		t.Fatalf("unexpected status %+v", status)
+	}
+	node.failures = 1
+	if _, err := tm.SendTx(Credit{Target: testAddress("a"), Amount: testAmount}); err == nil {
+		t.Fatal("expected error")
+	}
+	get(http.StatusServiceUnavailable)
+}
@@ -30,10 +30,9 @@ const (
 func TestMain(m *testing.M) {
 	conf = Configuration{
 		TendermintRPC: tendermintRPC,
-		CoinDenom:     coindenom,
 		ChainID:       chainID,
+		KeyName:       "faucet",
 		Passphrase:    pass,
-		SendAmount:    send,
 	}
 	var err error
 	node, err = rpchttp.NewHTTP(conf.TendermintRPC, "/websocket")
@@ -71,7 +70,7 @@ func Test_TxManagerBuildAndSignTx(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	_, err = mn.BuildAndSignTx(addr)
+	_, err = mn.BuildAndSignTx(Credit{Target: addr, Amount: sdk.NewCoins(sdk.NewInt64Coin(coindenom, send))})
 	if err != nil {
 		t.Error(err)
 	}
@@ -91,7 +90,7 @@ func Test_TxManagerBroadcastTx(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	tx, err := mn.BuildAndSignTx(addr)
+	tx, err := mn.BuildAndSignTx(Credit{Target: addr, Amount: sdk.NewCoins(sdk.NewInt64Coin(coindenom, send))})
 	if err != nil {
 		t.Error(err)
 	}
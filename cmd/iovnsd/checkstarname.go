@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/libs/log"
+	dbm "github.com/tendermint/tm-db"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	"github.com/cosmos/cosmos-sdk/server"
+	"github.com/cosmos/cosmos-sdk/store"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/iov-one/iovns/x/configuration"
+	"github.com/iov-one/iovns/x/starname"
+	"github.com/iov-one/iovns/x/starname/keeper"
+)
+
+// CheckStarnameInvariantsCmd returns check-starname-invariants cobra Command.
+func CheckStarnameInvariantsCmd(ctx *server.Context, cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "check-starname-invariants [file]",
+		Args:  cobra.RangeArgs(0, 1),
+		Short: "runs the starname invariants on the exported state at the default genesis location or at the location passed as an arg",
+		Long: `Loads the configuration and starname module state of a genesis file, usually
+obtained through the export command, in an in-memory store and runs the
+starname module invariants on it, reporting every broken invariant.
+`,
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			// load default if passed no args, otherwise load passed file
+			var genesis string
+			if len(args) == 0 {
+				genesis = ctx.Config.GenesisFile()
+			} else {
+				genesis = args[0]
+			}
+
+			fmt.Fprintf(os.Stderr, "checking starname invariants of state in file at %s\n", genesis)
+
+			confState, starnameState, err := loadStarnameGenesis(cdc, genesis)
+			if err != nil {
+				return err
+			}
+
+			msg, broken, err := checkStarnameInvariants(cdc, confState, starnameState)
+			if err != nil {
+				return err
+			}
+			fmt.Print(msg)
+			if broken {
+				return fmt.Errorf("starname invariants broken for state in file at %s", genesis)
+			}
+
+			fmt.Printf("Starname invariants hold for state in file at %s\n", genesis)
+			return nil
+		},
+	}
+}
+
+// checkStarnameInvariants initializes an in-memory store with the provided
+// genesis states and runs all the starname invariants on it
+func checkStarnameInvariants(cdc *codec.Codec, confState configuration.GenesisState, starnameState starname.GenesisState) (msg string, broken bool, err error) {
+	// genesis initialization panics on invalid states, like duplicate objects
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("unable to load state: %v", r)
+		}
+	}()
+	db := dbm.NewMemDB()
+	ms := store.NewCommitMultiStore(db)
+	confKey := sdk.NewKVStoreKey(configuration.StoreKey)
+	starnameKey := sdk.NewKVStoreKey(starname.DomainStoreKey)
+	ms.MountStoreWithDB(confKey, sdk.StoreTypeIAVL, db)
+	ms.MountStoreWithDB(starnameKey, sdk.StoreTypeIAVL, db)
+	if err = ms.LoadLatestVersion(); err != nil {
+		return "", false, err
+	}
+	sdkCtx := sdk.NewContext(ms, abci.Header{Time: time.Now()}, false, log.NewNopLogger())
+	confKeeper := configuration.NewKeeper(cdc, confKey, nil)
+	configuration.InitGenesis(sdkCtx, confKeeper, confState)
+	k := starname.NewKeeper(cdc, starnameKey, confKeeper, nil, nil)
+	starname.InitGenesis(sdkCtx, k, starnameState)
+	msg, broken = keeper.AllInvariants(k)(sdkCtx)
+	return msg, broken, nil
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	tmtypes "github.com/tendermint/tendermint/types"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	"github.com/cosmos/cosmos-sdk/server"
+
+	"github.com/iov-one/iovns/x/configuration"
+	"github.com/iov-one/iovns/x/starname"
+)
+
+// ValidateStarnameGenesisCmd returns validate-starname-genesis cobra Command.
+func ValidateStarnameGenesisCmd(ctx *server.Context, cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "validate-starname-genesis [file]",
+		Args:  cobra.RangeArgs(0, 1),
+		Short: "reports every issue of the starname state in the genesis file at the default location or at the location passed as an arg",
+		Long: `Validates the domains and accounts of the starname module genesis state,
+cross-checking them against the configuration module genesis state, and reports
+every issue found instead of stopping at the first one.
+`,
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			// load default if passed no args, otherwise load passed file
+			var genesis string
+			if len(args) == 0 {
+				genesis = ctx.Config.GenesisFile()
+			} else {
+				genesis = args[0]
+			}
+
+			fmt.Fprintf(os.Stderr, "validating starname genesis state in file at %s\n", genesis)
+
+			confState, starnameState, err := loadStarnameGenesis(cdc, genesis)
+			if err != nil {
+				return err
+			}
+
+			errs := starname.GenesisErrors(starnameState, &confState.Config)
+			fmt.Printf("domains: %d\naccounts: %d\nissues: %d\n", len(starnameState.Domains), len(starnameState.Accounts), len(errs))
+			for _, e := range errs {
+				fmt.Printf("- %s\n", e)
+			}
+			if len(errs) != 0 {
+				return fmt.Errorf("starname genesis state in file at %s is invalid", genesis)
+			}
+
+			fmt.Printf("Starname genesis state in file at %s is valid\n", genesis)
+			return nil
+		},
+	}
+}
+
+// loadStarnameGenesis reads the configuration and starname
+// module genesis states from the genesis file at the given path
+func loadStarnameGenesis(cdc *codec.Codec, genesis string) (confState configuration.GenesisState, starnameState starname.GenesisState, err error) {
+	var genDoc *tmtypes.GenesisDoc
+	if genDoc, err = tmtypes.GenesisDocFromFile(genesis); err != nil {
+		err = fmt.Errorf("error loading genesis doc from %s: %s", genesis, err.Error())
+		return
+	}
+
+	var genState map[string]json.RawMessage
+	if err = cdc.UnmarshalJSON(genDoc.AppState, &genState); err != nil {
+		err = fmt.Errorf("error unmarshalling genesis doc %s: %s", genesis, err.Error())
+		return
+	}
+
+	if err = cdc.UnmarshalJSON(genState[configuration.ModuleName], &confState); err != nil {
+		err = fmt.Errorf("error unmarshalling %s genesis state: %s", configuration.ModuleName, err.Error())
+		return
+	}
+
+	if err = cdc.UnmarshalJSON(genState[starname.ModuleName], &starnameState); err != nil {
+		err = fmt.Errorf("error unmarshalling %s genesis state: %s", starname.ModuleName, err.Error())
+		return
+	}
+	return
+}
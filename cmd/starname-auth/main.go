@@ -0,0 +1,44 @@
+package main
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/iov-one/iovns/cmd/starname-auth/pkg"
+	rpchttp "github.com/tendermint/tendermint/rpc/client/http"
+)
+
+func main() {
+	// setup configuration
+	conf, err := pkg.NewConfiguration()
+	if err != nil {
+		log.Fatalf("configuration: %s", err)
+	}
+	// setup node
+	node, err := rpchttp.New(conf.TendermintRPC, "/websocket")
+	if err != nil {
+		log.Fatalf("node: %s", err)
+	}
+	authenticator := pkg.NewAuthenticator(node, pkg.NewChallengeStore(*conf), pkg.NewSessions(*conf))
+	server := &http.Server{Addr: conf.Port, Handler: authenticator.Router()}
+
+	go func() {
+		log.Print("server started")
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatalf("http server: %s", err)
+		}
+	}()
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt)
+
+	<-stop
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_ = server.Shutdown(ctx)
+}
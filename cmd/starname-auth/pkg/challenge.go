@@ -0,0 +1,150 @@
+package pkg
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/iov-one/iovns/x/signutil"
+	"github.com/pkg/errors"
+)
+
+// keys of the challenge pairs the client signs with signutil
+const (
+	PairDomain  = "domain"
+	PairNonce   = "nonce"
+	PairExpiry  = "expiry"
+	PairChainID = "chain_id"
+)
+
+// nonceSize is the number of random bytes of a nonce
+const nonceSize = 32
+
+// Challenge is the statement a client signs to prove it controls an address
+type Challenge struct {
+	Domain  string    `json:"domain"`
+	Nonce   string    `json:"nonce"`
+	Expiry  time.Time `json:"expiry"`
+	ChainID string    `json:"chain_id"`
+}
+
+// Pairs returns the pairs of the signutil.MsgSignText the client has to sign
+func (c Challenge) Pairs() []signutil.Pair {
+	return []signutil.Pair{
+		{Key: PairDomain, Value: c.Domain},
+		{Key: PairNonce, Value: c.Nonce},
+		{Key: PairExpiry, Value: strconv.FormatInt(c.Expiry.Unix(), 10)},
+		{Key: PairChainID, Value: c.ChainID},
+	}
+}
+
+// ChallengeStore issues stateless challenges, their nonce is a random value followed by
+// an hmac of the challenge so that only the challenges it issued are accepted, the nonces
+// used are kept until they expire so that a challenge can be used only once
+type ChallengeStore struct {
+	conf Configuration
+	now  func() time.Time
+	mux  sync.Mutex
+	used map[string]time.Time
+}
+
+func NewChallengeStore(conf Configuration) *ChallengeStore {
+	return &ChallengeStore{
+		conf: conf,
+		now:  time.Now,
+		used: make(map[string]time.Time),
+	}
+}
+
+// WithClock sets the function returning the current time
+func (s *ChallengeStore) WithClock(now func() time.Time) *ChallengeStore {
+	s.now = now
+	return s
+}
+
+// mac returns the hmac of the challenge made of the random part of the nonce and of
+// the expiry, the domain and the chain id of the store, it is keyed with the JWT secret
+// and prefixed so that it cannot be mistaken for the signature of a session token
+func (s *ChallengeStore) mac(random []byte, expiry time.Time) []byte {
+	h := hmac.New(sha256.New, s.conf.JWTSecret)
+	h.Write([]byte("starname-auth challenge\n"))
+	h.Write(random)
+	for _, v := range []string{strconv.FormatInt(expiry.Unix(), 10), s.conf.Domain, s.conf.ChainID} {
+		h.Write([]byte("\n" + v))
+	}
+	return h.Sum(nil)
+}
+
+// Issue creates a new challenge
+func (s *ChallengeStore) Issue() (Challenge, error) {
+	random := make([]byte, nonceSize)
+	if _, err := rand.Read(random); err != nil {
+		return Challenge{}, errors.Wrap(err, "nonce generation failed")
+	}
+	expiry := s.now().Add(s.conf.ChallengeTTL).Truncate(time.Second)
+	return Challenge{
+		Domain:  s.conf.Domain,
+		Nonce:   hex.EncodeToString(append(random, s.mac(random, expiry)...)),
+		Expiry:  expiry,
+		ChainID: s.conf.ChainID,
+	}, nil
+}
+
+// Use checks that the pairs signed by the client match a challenge issued by the
+// store which is neither expired nor used and marks it used, so that a challenge
+// can be used only once
+func (s *ChallengeStore) Use(pairs []signutil.Pair) error {
+	values := make(map[string]string, len(pairs))
+	for _, p := range pairs {
+		if _, ok := values[p.Key]; ok {
+			return errors.Errorf("duplicate pair %s", p.Key)
+		}
+		values[p.Key] = p.Value
+	}
+	nonce, err := hex.DecodeString(values[PairNonce])
+	if err != nil || len(nonce) != nonceSize+sha256.Size {
+		return errors.New("unknown challenge")
+	}
+	unix, err := strconv.ParseInt(values[PairExpiry], 10, 64)
+	if err != nil {
+		return errors.New("unknown challenge")
+	}
+	challenge := Challenge{
+		Domain:  s.conf.Domain,
+		Nonce:   values[PairNonce],
+		Expiry:  time.Unix(unix, 0),
+		ChainID: s.conf.ChainID,
+	}
+	if !hmac.Equal(nonce[nonceSize:], s.mac(nonce[:nonceSize], challenge.Expiry)) {
+		return errors.New("unknown challenge")
+	}
+	now := s.now()
+	if now.After(challenge.Expiry) {
+		return errors.New("challenge expired")
+	}
+	for _, p := range challenge.Pairs() {
+		if values[p.Key] != p.Value {
+			return errors.Errorf("pair %s does not match the challenge", p.Key)
+		}
+	}
+	if len(values) != len(challenge.Pairs()) {
+		return errors.New("unexpected pairs")
+	}
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	// drop the nonces of the expired challenges, they cannot be used anymore
+	for used, expiry := range s.used {
+		if now.After(expiry) {
+			delete(s.used, used)
+		}
+	}
+	if _, ok := s.used[challenge.Nonce]; ok {
+		return errors.New("challenge already used")
+	}
+	s.used[challenge.Nonce] = challenge.Expiry
+	return nil
+}
@@ -0,0 +1,24 @@
+package pkg
+
+import (
+	"github.com/cosmos/cosmos-sdk/codec"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	config2 "github.com/iov-one/iovns/app/config"
+	"github.com/iov-one/iovns/x/signutil"
+)
+
+// ModuleCdc instantiates a new codec for the signed transactions
+var ModuleCdc = codec.New()
+
+func init() {
+	RegisterCodec(ModuleCdc)
+	config2.ApplyChangesAndSeal(sdk.GetConfig())
+}
+
+func RegisterCodec(cdc *codec.Codec) {
+	sdk.RegisterCodec(cdc)
+	auth.RegisterCodec(cdc)
+	codec.RegisterCrypto(cdc)
+	signutil.RegisterCodec(cdc)
+}
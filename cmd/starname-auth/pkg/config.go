@@ -0,0 +1,56 @@
+package pkg
+
+import (
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+type Configuration struct {
+	// TendermintRPC is the address of the node queried to resolve starnames
+	TendermintRPC string
+	// Port is the address the server listens on
+	Port string
+	// ChainID is the chain the starnames are resolved on, it is part of the challenges
+	ChainID string
+	// Domain is the domain of the relying party, it is part of the challenges
+	Domain string
+	// ChallengeTTL is the time a challenge can be signed in
+	ChallengeTTL time.Duration
+	// SessionTTL is the lifetime of the session tokens
+	SessionTTL time.Duration
+	// JWTSecret is the key signing the session tokens
+	JWTSecret []byte
+}
+
+func env(name, fallback string) string {
+	if v, ok := os.LookupEnv(name); ok {
+		return v
+	}
+	return fallback
+}
+
+func NewConfiguration() (*Configuration, error) {
+	challengeTTL, err := time.ParseDuration(env("CHALLENGE_TTL", "5m"))
+	if err != nil {
+		return nil, errors.Wrap(err, "CHALLENGE_TTL")
+	}
+	sessionTTL, err := time.ParseDuration(env("SESSION_TTL", "24h"))
+	if err != nil {
+		return nil, errors.Wrap(err, "SESSION_TTL")
+	}
+	secret := env("JWT_SECRET", "")
+	if secret == "" {
+		return nil, errors.New("JWT_SECRET must be set")
+	}
+	return &Configuration{
+		TendermintRPC: env("TENDERMINT_RPC", "http://localhost:26657"),
+		Port:          env("PORT", ":8080"),
+		ChainID:       env("CHAIN_ID", "local"),
+		Domain:        env("AUTH_DOMAIN", "localhost"),
+		ChallengeTTL:  challengeTTL,
+		SessionTTL:    sessionTTL,
+		JWTSecret:     []byte(secret),
+	}, nil
+}
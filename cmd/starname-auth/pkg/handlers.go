@@ -0,0 +1,157 @@
+package pkg
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/gorilla/mux"
+	"github.com/iov-one/iovns/x/signutil"
+	"github.com/pkg/errors"
+	"github.com/prometheus/common/log"
+)
+
+// Authenticator signs clients in with the starnames they own
+type Authenticator struct {
+	node       Node
+	challenges *ChallengeStore
+	sessions   *Sessions
+}
+
+func NewAuthenticator(node Node, challenges *ChallengeStore, sessions *Sessions) *Authenticator {
+	return &Authenticator{
+		node:       node,
+		challenges: challenges,
+		sessions:   sessions,
+	}
+}
+
+// Router returns the routes of the service
+func (a *Authenticator) Router() *mux.Router {
+	r := mux.NewRouter()
+	r.HandleFunc("/challenge", a.challengeHandler).Methods(http.MethodGet)
+	r.HandleFunc("/login", a.loginHandler).Methods(http.MethodPost)
+	r.HandleFunc("/session", a.sessionHandler).Methods(http.MethodGet)
+	r.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	return r
+}
+
+// Login verifies the transaction signed by the client, which must hold a single
+// signutil.MsgSignText with the pairs of a pending challenge, then checks that
+// the signer owns the starname, which must not be expired, and returns a session token
+func (a *Authenticator) Login(starname string, tx auth.StdTx) (string, Claims, error) {
+	msgs := tx.GetMsgs()
+	if len(msgs) != 1 {
+		return "", Claims{}, errors.Errorf("expected 1 msg but got %d", len(msgs))
+	}
+	msg, ok := msgs[0].(signutil.MsgSignText)
+	if !ok {
+		return "", Claims{}, errors.Errorf("unexpected msg type %s", msgs[0].Type())
+	}
+	if err := msg.ValidateBasic(); err != nil {
+		return "", Claims{}, err
+	}
+	if err := signutil.Verify(tx, signutil.DefaultChainID, signutil.DefaultAccountNumber, signutil.DefaultSequence); err != nil {
+		return "", Claims{}, err
+	}
+	// the signature must come from the key of the signer
+	pubKeys := tx.GetPubKeys()
+	if len(pubKeys) != 1 || !msg.Signer.Equals(sdk.AccAddress(pubKeys[0].Address())) {
+		return "", Claims{}, errors.Errorf("transaction not signed by %s", msg.Signer)
+	}
+	if err := a.challenges.Use(msg.Pairs); err != nil {
+		return "", Claims{}, err
+	}
+	// starnames expired at the latest block are not resolved
+	owner, _, err := signutil.ResolveOwner(nodeQuerier{node: a.node}, starname, 0)
+	if err != nil {
+		return "", Claims{}, err
+	}
+	if !owner.Equals(msg.Signer) {
+		return "", Claims{}, errors.Errorf("%s is not the owner of %s", msg.Signer, starname)
+	}
+	return a.sessions.Issue(starname, msg.Signer.String())
+}
+
+func jsonErr(w http.ResponseWriter, status int, msg string) {
+	errJson := struct {
+		Error string `json:"error"`
+	}{
+		Error: msg,
+	}
+	jsonResponse(w, status, errJson)
+}
+
+func jsonResponse(w http.ResponseWriter, status int, resp interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func (a *Authenticator) challengeHandler(w http.ResponseWriter, r *http.Request) {
+	challenge, err := a.challenges.Issue()
+	if err != nil {
+		log.Error(err)
+		jsonErr(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+	resp := struct {
+		Challenge Challenge       `json:"challenge"`
+		Pairs     []signutil.Pair `json:"pairs"`
+	}{
+		Challenge: challenge,
+		Pairs:     challenge.Pairs(),
+	}
+	jsonResponse(w, http.StatusOK, resp)
+}
+
+// LoginRequest is the request model of the login route
+type LoginRequest struct {
+	// Starname is the account starname the client signs in with
+	Starname string `json:"starname"`
+	// SignedTx is the auth.StdTx signed with iovnscli tx signutil create
+	SignedTx json.RawMessage `json:"signed_tx"`
+}
+
+// LoginResponse is the response model of the login route
+type LoginResponse struct {
+	Token  string `json:"token"`
+	Claims Claims `json:"claims"`
+}
+
+func (a *Authenticator) loginHandler(w http.ResponseWriter, r *http.Request) {
+	var req LoginRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		jsonErr(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	var tx auth.StdTx
+	if err := ModuleCdc.UnmarshalJSON(req.SignedTx, &tx); err != nil {
+		jsonErr(w, http.StatusBadRequest, "invalid signed transaction")
+		return
+	}
+	token, claims, err := a.Login(req.Starname, tx)
+	if err != nil {
+		jsonErr(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+	jsonResponse(w, http.StatusOK, LoginResponse{Token: token, Claims: claims})
+}
+
+func (a *Authenticator) sessionHandler(w http.ResponseWriter, r *http.Request) {
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if token == "" {
+		jsonErr(w, http.StatusUnauthorized, "provide a bearer token")
+		return
+	}
+	claims, err := a.sessions.Verify(token)
+	if err != nil {
+		jsonErr(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+	jsonResponse(w, http.StatusOK, claims)
+}
@@ -0,0 +1,240 @@
+package pkg
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/iov-one/iovns/pkg/utils"
+	"github.com/iov-one/iovns/x/signutil"
+	"github.com/iov-one/iovns/x/starname/types"
+	"github.com/tendermint/tendermint/crypto"
+	"github.com/tendermint/tendermint/crypto/secp256k1"
+)
+
+func signText(t *testing.T, key crypto.PrivKey, pairs []signutil.Pair) auth.StdTx {
+	msgs := []sdk.Msg{signutil.MsgSignText{
+		Message: "sign in",
+		Pairs:   pairs,
+		Signer:  sdk.AccAddress(key.PubKey().Address()),
+	}}
+	fee := auth.NewStdFee(200000, nil)
+	signBytes := auth.StdSignBytes(signutil.DefaultChainID, signutil.DefaultAccountNumber, signutil.DefaultSequence, fee, msgs, "")
+	sig, err := key.Sign(signBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return auth.NewStdTx(msgs, fee, []auth.StdSignature{{PubKey: key.PubKey(), Signature: sig}}, "")
+}
+
+func TestAuthenticator(t *testing.T) {
+	owner := secp256k1.GenPrivKey()
+	other := secp256k1.GenPrivKey()
+	conf := Configuration{
+		ChainID:      "iov-test",
+		Domain:       "example.com",
+		ChallengeTTL: time.Minute,
+		SessionTTL:   time.Hour,
+		JWTSecret:    []byte("secret"),
+	}
+	ownerAddr := sdk.AccAddress(owner.PubKey().Address())
+	now := time.Now()
+	clock := func() time.Time { return now }
+	node := newAppNode(t, now,
+		[]types.Domain{
+			{Name: "domain", Admin: ownerAddr, ValidUntil: types.MaxValidUntil, Type: types.OpenDomain},
+			{Name: "closed", Admin: ownerAddr, ValidUntil: now.Add(-time.Hour).Unix(), Type: types.ClosedDomain},
+		},
+		types.Account{Domain: "domain", Name: utils.StrPtr("name"), Owner: ownerAddr, ValidUntil: now.Add(time.Hour).Unix()},
+		types.Account{Domain: "domain", Name: utils.StrPtr("expired"), Owner: ownerAddr, ValidUntil: now.Add(-time.Hour).Unix()},
+		// accounts of closed domains without an expiration follow the domain
+		types.Account{Domain: "closed", Name: utils.StrPtr("name"), Owner: ownerAddr, ValidUntil: types.MaxValidUntil},
+	)
+
+	cases := map[string]struct {
+		starname string
+		tx       func(c Challenge) auth.StdTx
+		wantErr  bool
+	}{
+		"success": {
+			starname: "name*domain",
+			tx: func(c Challenge) auth.StdTx {
+				return signText(t, owner, c.Pairs())
+			},
+		},
+		"fail not the owner": {
+			starname: "name*domain",
+			tx: func(c Challenge) auth.StdTx {
+				return signText(t, other, c.Pairs())
+			},
+			wantErr: true,
+		},
+		"fail starname does not exist": {
+			starname: "unknown*domain",
+			tx: func(c Challenge) auth.StdTx {
+				return signText(t, owner, c.Pairs())
+			},
+			wantErr: true,
+		},
+		"fail starname expired": {
+			starname: "expired*domain",
+			tx: func(c Challenge) auth.StdTx {
+				return signText(t, owner, c.Pairs())
+			},
+			wantErr: true,
+		},
+		"fail domain of the starname expired": {
+			starname: "name*closed",
+			tx: func(c Challenge) auth.StdTx {
+				return signText(t, owner, c.Pairs())
+			},
+			wantErr: true,
+		},
+		"fail tampered pairs": {
+			starname: "name*domain",
+			tx: func(c Challenge) auth.StdTx {
+				pairs := c.Pairs()
+				pairs[0].Value = "evil.com"
+				return signText(t, owner, pairs)
+			},
+			wantErr: true,
+		},
+		"fail extra pairs": {
+			starname: "name*domain",
+			tx: func(c Challenge) auth.StdTx {
+				return signText(t, owner, append(c.Pairs(), signutil.Pair{Key: "extra", Value: "value"}))
+			},
+			wantErr: true,
+		},
+		"fail nonce not issued by the store": {
+			starname: "name*domain",
+			tx: func(c Challenge) auth.StdTx {
+				pairs := c.Pairs()
+				pairs[1].Value = strings.Repeat("0", len(c.Nonce))
+				return signText(t, owner, pairs)
+			},
+			wantErr: true,
+		},
+		"fail signer is not the key": {
+			starname: "name*domain",
+			tx: func(c Challenge) auth.StdTx {
+				tx := signText(t, other, c.Pairs())
+				msg := tx.Msgs[0].(signutil.MsgSignText)
+				msg.Signer = sdk.AccAddress(owner.PubKey().Address())
+				tx.Msgs[0] = msg
+				return tx
+			},
+			wantErr: true,
+		},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			a := NewAuthenticator(node, NewChallengeStore(conf).WithClock(clock), NewSessions(conf).WithClock(clock))
+			challenge, err := a.challenges.Issue()
+			if err != nil {
+				t.Fatal(err)
+			}
+			token, claims, err := a.Login(c.starname, c.tx(challenge))
+			if (err != nil) != c.wantErr {
+				t.Fatalf("want err: %t, got: %v", c.wantErr, err)
+			}
+			if c.wantErr {
+				return
+			}
+			if claims.Subject != c.starname {
+				t.Fatalf("unexpected subject %s", claims.Subject)
+			}
+			verified, err := a.sessions.Verify(token)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if verified != claims {
+				t.Fatalf("unexpected claims %+v", verified)
+			}
+		})
+	}
+
+	t.Run("fail replayed challenge", func(t *testing.T) {
+		a := NewAuthenticator(node, NewChallengeStore(conf).WithClock(clock), NewSessions(conf).WithClock(clock))
+		challenge, err := a.challenges.Issue()
+		if err != nil {
+			t.Fatal(err)
+		}
+		tx := signText(t, owner, challenge.Pairs())
+		if _, _, err := a.Login("name*domain", tx); err != nil {
+			t.Fatal(err)
+		}
+		if _, _, err := a.Login("name*domain", tx); err == nil {
+			t.Fatal("replayed challenge accepted")
+		}
+	})
+
+	t.Run("fail expired challenge", func(t *testing.T) {
+		current := now
+		later := func() time.Time { return current }
+		a := NewAuthenticator(node, NewChallengeStore(conf).WithClock(later), NewSessions(conf).WithClock(later))
+		challenge, err := a.challenges.Issue()
+		if err != nil {
+			t.Fatal(err)
+		}
+		current = current.Add(2 * conf.ChallengeTTL)
+		if _, _, err := a.Login("name*domain", signText(t, owner, challenge.Pairs())); err == nil {
+			t.Fatal("expired challenge accepted")
+		}
+	})
+
+	t.Run("success http", func(t *testing.T) {
+		server := httptest.NewServer(NewAuthenticator(node, NewChallengeStore(conf), NewSessions(conf)).Router())
+		defer server.Close()
+		resp, err := http.Get(server.URL + "/challenge")
+		if err != nil {
+			t.Fatal(err)
+		}
+		var challenge struct {
+			Pairs []signutil.Pair `json:"pairs"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&challenge); err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		signedTx, err := ModuleCdc.MarshalJSON(signText(t, owner, challenge.Pairs))
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, err := json.Marshal(LoginRequest{Starname: "name*domain", SignedTx: signedTx})
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err = http.Post(server.URL+"/login", "application/json", bytes.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("unexpected status %d", resp.StatusCode)
+		}
+		var login LoginResponse
+		if err := json.NewDecoder(resp.Body).Decode(&login); err != nil {
+			t.Fatal(err)
+		}
+		req, err := http.NewRequest(http.MethodGet, server.URL+"/session", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Authorization", "Bearer "+login.Token)
+		session, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer session.Body.Close()
+		if session.StatusCode != http.StatusOK {
+			t.Fatalf("unexpected session status %d", session.StatusCode)
+		}
+	})
+}
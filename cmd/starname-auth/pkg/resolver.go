@@ -0,0 +1,45 @@
+package pkg
+
+import (
+	"time"
+
+	"github.com/iov-one/iovns/x/signutil"
+	"github.com/pkg/errors"
+	"github.com/tendermint/tendermint/libs/bytes"
+	rpcclient "github.com/tendermint/tendermint/rpc/client"
+	coretypes "github.com/tendermint/tendermint/rpc/core/types"
+)
+
+// Node is the node interface needed to resolve starnames,
+// it is implemented by the tendermint rpc clients
+type Node interface {
+	ABCIQueryWithOptions(path string, data bytes.HexBytes, opts rpcclient.ABCIQueryOptions) (*coretypes.ResultABCIQuery, error)
+	Block(height *int64) (*coretypes.ResultBlock, error)
+}
+
+// nodeQuerier implements signutil.Querier with the rpc client of a node
+type nodeQuerier struct {
+	node Node
+}
+
+func (q nodeQuerier) QueryAt(path string, data []byte, height int64) ([]byte, int64, error) {
+	result, err := q.node.ABCIQueryWithOptions(path, data, rpcclient.ABCIQueryOptions{Height: height})
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "abci query failed")
+	}
+	resp := result.Response
+	if !resp.IsOK() {
+		return nil, 0, errors.Errorf("query %s failed: %s", path, resp.Log)
+	}
+	return resp.Value, resp.Height, nil
+}
+
+func (q nodeQuerier) BlockTime(height int64) (time.Time, error) {
+	block, err := q.node.Block(&height)
+	if err != nil {
+		return time.Time{}, errors.Wrap(err, "block query failed")
+	}
+	return block.Block.Time, nil
+}
+
+var _ signutil.Querier = nodeQuerier{}
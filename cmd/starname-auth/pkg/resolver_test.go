@@ -0,0 +1,99 @@
+package pkg
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/iov-one/iovns/app"
+	"github.com/iov-one/iovns/pkg/utils"
+	"github.com/iov-one/iovns/x/starname"
+	"github.com/iov-one/iovns/x/starname/keeper"
+	"github.com/iov-one/iovns/x/starname/types"
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/crypto/secp256k1"
+	tmbytes "github.com/tendermint/tendermint/libs/bytes"
+	"github.com/tendermint/tendermint/libs/log"
+	rpcclient "github.com/tendermint/tendermint/rpc/client"
+	coretypes "github.com/tendermint/tendermint/rpc/core/types"
+	tmtypes "github.com/tendermint/tendermint/types"
+	dbm "github.com/tendermint/tm-db"
+)
+
+// appNode queries an in-process application the way the node of the chain does,
+// its blocks are all at blockTime
+type appNode struct {
+	app       *app.NameService
+	blockTime time.Time
+}
+
+func (n appNode) ABCIQueryWithOptions(path string, data tmbytes.HexBytes, opts rpcclient.ABCIQueryOptions) (*coretypes.ResultABCIQuery, error) {
+	return &coretypes.ResultABCIQuery{Response: n.app.Query(abci.RequestQuery{Path: path, Data: data, Height: opts.Height})}, nil
+}
+
+func (n appNode) Block(height *int64) (*coretypes.ResultBlock, error) {
+	return &coretypes.ResultBlock{Block: &tmtypes.Block{Header: tmtypes.Header{Height: *height, Time: n.blockTime}}}, nil
+}
+
+// newAppNode starts an application whose genesis holds the provided domains and accounts
+func newAppNode(t *testing.T, blockTime time.Time, domains []types.Domain, accounts ...types.Account) appNode {
+	nameService := app.NewNameService(log.NewNopLogger(), dbm.NewMemDB(), nil, true, 0, map[int64]bool{})
+	genesis := app.NewDefaultGenesisState()
+	var emptyAccounts []types.Account
+	for _, domain := range domains {
+		emptyAccounts = append(emptyAccounts, types.Account{Domain: domain.Name, Name: utils.StrPtr(""), Owner: domain.Admin, ValidUntil: domain.ValidUntil})
+	}
+	genesis[starname.ModuleName] = nameService.Codec().MustMarshalJSON(starname.NewGenesisState(
+		domains,
+		append(emptyAccounts, accounts...),
+	))
+	state, err := json.Marshal(genesis)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nameService.InitChain(abci.RequestInitChain{ChainId: "iov-test", AppStateBytes: state})
+	nameService.Commit()
+	return appNode{app: nameService, blockTime: blockTime}
+}
+
+func TestNodeQuerier(t *testing.T) {
+	admin := sdk.AccAddress(secp256k1.GenPrivKey().PubKey().Address())
+	now := time.Now()
+	node := newAppNode(t, now, []types.Domain{
+		{Name: "domain", Admin: admin, ValidUntil: types.MaxValidUntil, Type: types.OpenDomain},
+	})
+	q := nodeQuerier{node: node}
+	data, err := json.Marshal(keeper.QueryResolveDomain{Name: "domain"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	value, height, err := q.QueryAt("custom/starname/domainInfo", data, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if height != 1 {
+		t.Fatalf("unexpected height %d", height)
+	}
+	var res keeper.QueryResolveDomainResponse
+	if err := json.Unmarshal(value, &res); err != nil {
+		t.Fatal(err)
+	}
+	if res.Domain.Name != "domain" || !res.Domain.Admin.Equals(admin) {
+		t.Fatalf("unexpected domain %+v", res.Domain)
+	}
+	data, err = json.Marshal(keeper.QueryResolveDomain{Name: "unknown"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := q.QueryAt("custom/starname/domainInfo", data, 0); err == nil {
+		t.Fatal("query of a non existing domain succeeded")
+	}
+	blockTime, err := q.BlockTime(height)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !blockTime.Equal(now) {
+		t.Fatalf("unexpected block time %s", blockTime)
+	}
+}
@@ -0,0 +1,83 @@
+package pkg
+
+import (
+	"encoding/json"
+	"time"
+
+	jose "github.com/dvsekhvalnov/jose2go"
+	"github.com/pkg/errors"
+)
+
+// Claims are the claims of a session token
+type Claims struct {
+	// Issuer is the domain of the relying party
+	Issuer string `json:"iss"`
+	// Subject is the starname the client signed in with
+	Subject string `json:"sub"`
+	// Address is the bech32 address owning the starname
+	Address string `json:"address"`
+	// ChainID is the chain the starname was resolved on
+	ChainID  string `json:"chain_id"`
+	IssuedAt int64  `json:"iat"`
+	Expiry   int64  `json:"exp"`
+}
+
+// Sessions issues and verifies session tokens, which are JWTs signed with HS256
+type Sessions struct {
+	conf Configuration
+	now  func() time.Time
+}
+
+func NewSessions(conf Configuration) *Sessions {
+	return &Sessions{conf: conf, now: time.Now}
+}
+
+// WithClock sets the function returning the current time
+func (s *Sessions) WithClock(now func() time.Time) *Sessions {
+	s.now = now
+	return s
+}
+
+// Issue returns a session token for the address owning the starname
+func (s *Sessions) Issue(starname, address string) (string, Claims, error) {
+	now := s.now()
+	claims := Claims{
+		Issuer:   s.conf.Domain,
+		Subject:  starname,
+		Address:  address,
+		ChainID:  s.conf.ChainID,
+		IssuedAt: now.Unix(),
+		Expiry:   now.Add(s.conf.SessionTTL).Unix(),
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", Claims{}, err
+	}
+	token, err := jose.SignBytes(payload, jose.HS256, s.conf.JWTSecret, jose.Header("typ", "JWT"))
+	if err != nil {
+		return "", Claims{}, errors.Wrap(err, "token signing failed")
+	}
+	return token, claims, nil
+}
+
+// Verify checks the signature and the expiration of the token and returns its claims
+func (s *Sessions) Verify(token string) (Claims, error) {
+	payload, headers, err := jose.DecodeBytes(token, s.conf.JWTSecret)
+	if err != nil {
+		return Claims{}, errors.Wrap(err, "invalid token")
+	}
+	if headers["alg"] != jose.HS256 {
+		return Claims{}, errors.Errorf("unexpected token algorithm %v", headers["alg"])
+	}
+	var claims Claims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return Claims{}, errors.Wrap(err, "invalid token claims")
+	}
+	if claims.Issuer != s.conf.Domain {
+		return Claims{}, errors.Errorf("unexpected token issuer %s", claims.Issuer)
+	}
+	if s.now().Unix() >= claims.Expiry {
+		return Claims{}, errors.New("token expired")
+	}
+	return claims, nil
+}
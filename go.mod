@@ -4,6 +4,7 @@ go 1.14
 
 require (
 	github.com/cosmos/cosmos-sdk v0.39.2
+	github.com/dvsekhvalnov/jose2go v0.0.0-20200901110807-248326c1351b
 	github.com/fatih/structs v1.1.0
 	github.com/golang/mock v1.3.1 // indirect
 	github.com/gorilla/mux v1.7.4
@@ -15,6 +16,7 @@ require (
 	github.com/onsi/gomega v1.5.0 // indirect
 	github.com/pkg/errors v0.9.1
 	github.com/pmezard/go-difflib v1.0.0
+	github.com/prometheus/client_golang v1.5.1
 	github.com/prometheus/common v0.9.1
 	github.com/rakyll/statik v0.1.7
 	github.com/spf13/afero v1.2.2 // indirect
@@ -24,5 +26,6 @@ require (
 	github.com/tendermint/go-amino v0.15.1
 	github.com/tendermint/tendermint v0.33.9
 	github.com/tendermint/tm-db v0.5.1
+	golang.org/x/text v0.3.3
 	golang.org/x/xerrors v0.0.0-20200804184101-5ec99f83aff1 // indirect
 )
@@ -29,6 +29,10 @@ func (c Configuration) IsOwner(_ sdk.Context, addr sdk.AccAddress) bool {
 	return c.conf.Configurer.Equals(addr)
 }
 
+func (c Configuration) GetReservedNamesMatching(_ sdk.Context, _ string) configuration.ReservedNames {
+	return nil
+}
+
 func (c Configuration) GetValidDomainNameRegexp(_ sdk.Context) string {
 	return c.conf.ValidDomainName
 }
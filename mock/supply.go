@@ -4,16 +4,22 @@ import sdk "github.com/cosmos/cosmos-sdk/types"
 
 type SupplyKeeper interface {
 	SendCoinsFromAccountToModule(ctx sdk.Context, addr sdk.AccAddress, moduleName string, coins sdk.Coins) error
+	SendCoinsFromModuleToAccount(ctx sdk.Context, moduleName string, addr sdk.AccAddress, coins sdk.Coins) error
 }
 
 type supplyKeeper struct {
 	sendCoinsFromAccountToModule func(ctx sdk.Context, addr sdk.AccAddress, moduleName string, coins sdk.Coins) error
+	sendCoinsFromModuleToAccount func(ctx sdk.Context, moduleName string, addr sdk.AccAddress, coins sdk.Coins) error
 }
 
 func (s *supplyKeeper) SendCoinsFromAccountToModule(ctx sdk.Context, addr sdk.AccAddress, moduleName string, coins sdk.Coins) error {
 	return s.sendCoinsFromAccountToModule(ctx, addr, moduleName, coins)
 }
 
+func (s *supplyKeeper) SendCoinsFromModuleToAccount(ctx sdk.Context, moduleName string, addr sdk.AccAddress, coins sdk.Coins) error {
+	return s.sendCoinsFromModuleToAccount(ctx, moduleName, addr, coins)
+}
+
 type SupplyKeeperMock struct {
 	s *supplyKeeper
 }
@@ -22,6 +28,10 @@ func (s *SupplyKeeperMock) SetSendCoinsFromAccountToModule(f func(ctx sdk.Contex
 	s.s.sendCoinsFromAccountToModule = f
 }
 
+func (s *SupplyKeeperMock) SetSendCoinsFromModuleToAccount(f func(ctx sdk.Context, moduleName string, addr sdk.AccAddress, coins sdk.Coins) error) {
+	s.s.sendCoinsFromModuleToAccount = f
+}
+
 func (s *SupplyKeeperMock) Mock() SupplyKeeper {
 	return s.s
 }
@@ -32,5 +42,8 @@ func NewSupplyKeeper() *SupplyKeeperMock {
 	mock.SetSendCoinsFromAccountToModule(func(ctx sdk.Context, addr sdk.AccAddress, moduleName string, coins sdk.Coins) error {
 		return nil
 	})
+	mock.SetSendCoinsFromModuleToAccount(func(ctx sdk.Context, moduleName string, addr sdk.AccAddress, coins sdk.Coins) error {
+		return nil
+	})
 	return mock
 }
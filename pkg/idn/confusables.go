@@ -0,0 +1,49 @@
+package idn
+
+// confusables maps lowercase characters to the latin prototype they are
+// visually confusable with, it is a subset of the Unicode confusables table,
+// ascii characters are never mapped so that ascii names, like corn and com,
+// which are distinct on every keyboard, are never confusable with each other
+var confusables = map[rune]string{
+	// latin
+	'ı': "i",
+	'ɑ': "a",
+	'ɡ': "g",
+	'ɩ': "i",
+	'ɪ': "i",
+	'ʏ': "y",
+	'ſ': "f",
+	// greek
+	'α': "a",
+	'γ': "y",
+	'ι': "i",
+	'ν': "v",
+	'ο': "o",
+	'ρ': "p",
+	'σ': "o",
+	'υ': "u",
+	// cyrillic
+	'а': "a",
+	'е': "e",
+	'һ': "h",
+	'і': "i",
+	'ј': "j",
+	'ӏ': "l",
+	'о': "o",
+	'р': "p",
+	'с': "c",
+	'ԁ': "d",
+	'ԛ': "q",
+	'ѕ': "s",
+	'у': "y",
+	'х': "x",
+	'ԝ': "w",
+	'ү': "y",
+	// armenian
+	'հ': "h",
+	'ո': "n",
+	'ս': "u",
+	'օ': "o",
+	'ց': "g",
+	'զ': "q",
+}
@@ -0,0 +1,15 @@
+/*
+Package idn implements the rules used to handle internationalized starnames.
+
+Names are stored in their canonical form, which is the NFC normalization of
+the name, so that equivalent inputs always resolve to the same name. To protect
+users from homograph attacks a name must not mix scripts which are not commonly
+used together, and names can be compared through their skeleton: two names
+having the same skeleton are visually confusable.
+
+The skeleton is built following the Unicode Technical Standard #39: the name
+is decomposed, characters are mapped to the prototype they are confusable with
+and the result is decomposed again. Only a subset of the Unicode confusables
+table is used, covering the non ascii characters which are confusable with latin ones.
+*/
+package idn
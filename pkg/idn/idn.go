@@ -0,0 +1,123 @@
+package idn
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// scriptNames contains the names of the unicode scripts sorted
+// in order to make script detection independent of map ordering
+var scriptNames []string
+
+func init() {
+	for name := range unicode.Scripts {
+		// common and inherited characters are shared across scripts
+		if name == "Common" || name == "Inherited" {
+			continue
+		}
+		scriptNames = append(scriptNames, name)
+	}
+	sort.Strings(scriptNames)
+}
+
+// allowedScriptSets defines the sets of scripts which can be mixed
+// in a single name as they are commonly written together
+var allowedScriptSets = [][]string{
+	{"Latin", "Han", "Hiragana", "Katakana"},
+	{"Latin", "Han", "Bopomofo"},
+	{"Latin", "Han", "Hangul"},
+}
+
+// Canonical returns the canonical form of the given name
+func Canonical(name string) string {
+	return norm.NFC.String(name)
+}
+
+// IsCanonical checks if the given name is valid utf8 and in its canonical form
+func IsCanonical(name string) bool {
+	return utf8.ValidString(name) && norm.NFC.IsNormalString(name)
+}
+
+// Scripts returns the sorted list of scripts used by the given name,
+// characters shared across scripts, like digits, are not accounted for
+func Scripts(name string) []string {
+	found := make(map[string]struct{})
+	for _, r := range name {
+		if unicode.In(r, unicode.Common, unicode.Inherited) {
+			continue
+		}
+		for _, script := range scriptNames {
+			if unicode.Is(unicode.Scripts[script], r) {
+				found[script] = struct{}{}
+				break
+			}
+		}
+	}
+	scripts := make([]string, 0, len(found))
+	for script := range found {
+		scripts = append(scripts, script)
+	}
+	sort.Strings(scripts)
+	return scripts
+}
+
+// ValidateScripts returns an error if the given name
+// mixes scripts which are not commonly used together
+func ValidateScripts(name string) error {
+	scripts := Scripts(name)
+	if len(scripts) <= 1 {
+		return nil
+	}
+	for _, set := range allowedScriptSets {
+		if subset(scripts, set) {
+			return nil
+		}
+	}
+	return fmt.Errorf("name %s mixes scripts: %s", name, strings.Join(scripts, ", "))
+}
+
+// Skeleton returns the skeleton of the given name, names which
+// have the same skeleton are visually confusable with each other
+func Skeleton(name string) string {
+	decomposed := norm.NFKD.String(strings.ToLower(name))
+	var b strings.Builder
+	for _, r := range decomposed {
+		// invisible formatting characters are ignored
+		if unicode.Is(unicode.Cf, r) {
+			continue
+		}
+		if prototype, ok := confusables[r]; ok {
+			b.WriteString(prototype)
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return norm.NFD.String(b.String())
+}
+
+// Confusable checks if the two given names are visually confusable
+func Confusable(a, b string) bool {
+	return Skeleton(a) == Skeleton(b)
+}
+
+// subset checks if all the elements of a are contained in b
+func subset(a, b []string) bool {
+	for _, x := range a {
+		var found bool
+		for _, y := range b {
+			if x == y {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
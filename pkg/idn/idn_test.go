@@ -0,0 +1,125 @@
+package idn
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCanonical(t *testing.T) {
+	// e followed by the combining acute accent
+	decomposed := "cafe\u0301"
+	composed := "caf\u00e9"
+	if got := Canonical(decomposed); got != composed {
+		t.Fatalf("want: %q, got: %q", composed, got)
+	}
+	if IsCanonical(decomposed) {
+		t.Fatalf("decomposed name %q reported as canonical", decomposed)
+	}
+	if !IsCanonical(composed) {
+		t.Fatalf("composed name %q not reported as canonical", composed)
+	}
+	if IsCanonical(string([]byte{0xff})) {
+		t.Fatal("invalid utf8 reported as canonical")
+	}
+}
+
+func TestScripts(t *testing.T) {
+	cases := map[string]struct {
+		name    string
+		scripts []string
+		wantErr bool
+	}{
+		"ascii": {
+			name:    "iov-1",
+			scripts: []string{"Latin"},
+		},
+		"digits only": {
+			name:    "0123",
+			scripts: []string{},
+		},
+		"cyrillic": {
+			name:    "пример",
+			scripts: []string{"Cyrillic"},
+		},
+		"latin with cyrillic": {
+			name:    "pаypal",
+			scripts: []string{"Cyrillic", "Latin"},
+			wantErr: true,
+		},
+		"japanese": {
+			name:    "東京すたー",
+			scripts: []string{"Han", "Hiragana"},
+		},
+		"han with hangul and latin": {
+			name:    "iov한국語",
+			scripts: []string{"Han", "Hangul", "Latin"},
+		},
+		"hangul with hiragana": {
+			name:    "한すた",
+			scripts: []string{"Hangul", "Hiragana"},
+			wantErr: true,
+		},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := Scripts(c.name); !reflect.DeepEqual(got, c.scripts) {
+				t.Fatalf("want: %v, got: %v", c.scripts, got)
+			}
+			err := ValidateScripts(c.name)
+			if c.wantErr != (err != nil) {
+				t.Fatalf("want error: %t, got: %v", c.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestConfusable(t *testing.T) {
+	cases := map[string]struct {
+		a, b       string
+		confusable bool
+	}{
+		"same": {
+			a: "iov", b: "iov", confusable: true,
+		},
+		"cyrillic a": {
+			a: "paypal", b: "pаypаl", confusable: true,
+		},
+		"whole script cyrillic": {
+			a: "coop", b: "\u0441\u043e\u043e\u0440", confusable: true,
+		},
+		"greek omicron": {
+			a: "iov", b: "iοv", confusable: true,
+		},
+		"ascii digits": {
+			a: "google", b: "g00g1e", confusable: false,
+		},
+		"ascii rn": {
+			a: "corn", b: "com", confusable: false,
+		},
+		"ascii ones": {
+			a: "hello", b: "he11o", confusable: false,
+		},
+		"fullwidth": {
+			a: "iov", b: "ｉｏｖ", confusable: true,
+		},
+		"zero width joiner": {
+			a: "iov", b: "i\u200dov", confusable: true,
+		},
+		"decomposition": {
+			a: "caf\u00e9", b: "cafe\u0301", confusable: true,
+		},
+		"different": {
+			a: "iov", b: "iow", confusable: false,
+		},
+		"accents": {
+			a: "cafe", b: "caf\u00e9", confusable: false,
+		},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := Confusable(c.a, c.b); got != c.confusable {
+				t.Fatalf("want: %t, got: %t, skeletons: %q %q", c.confusable, got, Skeleton(c.a), Skeleton(c.b))
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package migration
+
+import (
+	"github.com/cosmos/cosmos-sdk/codec"
+	"github.com/cosmos/cosmos-sdk/store/prefix"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/iov-one/cosmos-sdk-crud/pkg/crud"
+	crudtypes "github.com/iov-one/cosmos-sdk-crud/pkg/crud/types"
+)
+
+// the prefixes crud.Store uses to save objects, index pointers and index lists
+var (
+	crudObjectPrefix    = []byte{0x0}
+	crudIndexPrefix     = []byte{0x1}
+	crudIndexListPrefix = []byte{0x2}
+)
+
+// ObjectDecoder decodes the raw value of an object saved by a crud.Store, which can
+// be in a previous format, into its current representation. A nil object deletes it.
+type ObjectDecoder func(value []byte) (crudtypes.Object, error)
+
+// RewriteObjects rewrites in place every object of the crud.Store identified by storeKey and
+// uniquePrefix: all the indexes are dropped, then each raw object is decoded through decode
+// and saved back through the crud.Store, so that both the object and its indexes are written
+// in the current format. Objects can change their primary key during the rewrite.
+// CONTRACT: decoded objects must have unique primary keys
+func RewriteObjects(ctx sdk.Context, storeKey sdk.StoreKey, cdc *codec.Codec, uniquePrefix []byte, decode ObjectDecoder) error {
+	raw := ctx.KVStore(storeKey)
+	if len(uniquePrefix) != 0 {
+		raw = prefix.NewStore(raw, uniquePrefix)
+	}
+	// drop indexes
+	clearPrefix(prefix.NewStore(raw, crudIndexPrefix))
+	clearPrefix(prefix.NewStore(raw, crudIndexListPrefix))
+	// decode all the objects before writing
+	objectStore := prefix.NewStore(raw, crudObjectPrefix)
+	var keys [][]byte
+	var objects []crudtypes.Object
+	it := objectStore.Iterator(nil, nil)
+	for ; it.Valid(); it.Next() {
+		o, err := decode(it.Value())
+		if err != nil {
+			it.Close()
+			return err
+		}
+		keys = append(keys, it.Key())
+		objects = append(objects, o)
+	}
+	it.Close()
+	// remove old objects and save them back
+	for _, key := range keys {
+		objectStore.Delete(key)
+	}
+	store := crud.NewStore(ctx, storeKey, cdc, uniquePrefix)
+	for _, o := range objects {
+		if o == nil {
+			continue
+		}
+		store.Create(o)
+	}
+	return nil
+}
+
+// clearPrefix deletes all the keys of the given store
+func clearPrefix(store sdk.KVStore) {
+	var keys [][]byte
+	it := store.Iterator(nil, nil)
+	for ; it.Valid(); it.Next() {
+		keys = append(keys, it.Key())
+	}
+	it.Close()
+	for _, key := range keys {
+		store.Delete(key)
+	}
+}
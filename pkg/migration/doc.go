@@ -0,0 +1,9 @@
+/*
+Package migration defines a framework to migrate the state of a module in place.
+
+Every module keeps the version of its store under a key of its own and
+registers an ordered list of migrations, each one upgrading the store
+to the next version. Migrations are applied by a Manager, usually from
+an upgrade handler, while fresh chains start from the latest version.
+*/
+package migration
@@ -0,0 +1,85 @@
+package migration
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// Migration defines a change of the state of a module
+type Migration struct {
+	// Version is the store version the migration upgrades to
+	Version uint64
+	// Description describes the change applied by the migration
+	Description string
+	// Migrate applies the change to the store
+	Migrate func(ctx sdk.Context) error
+}
+
+// Manager keeps track of the store version of a module and applies its migrations
+type Manager struct {
+	module     string
+	storeKey   sdk.StoreKey
+	versionKey []byte
+	migrations []Migration
+}
+
+// NewManager builds a Manager given the module name, the store key of the module,
+// the key used to save the store version and the migrations of the module
+// CONTRACT: migrations must be sorted by version, starting from version 1
+func NewManager(module string, storeKey sdk.StoreKey, versionKey []byte, migrations ...Migration) Manager {
+	for i, m := range migrations {
+		if m.Version != uint64(i+1) {
+			panic(fmt.Sprintf("module %s: expected migration version %d, got %d", module, i+1, m.Version))
+		}
+		if m.Migrate == nil {
+			panic(fmt.Sprintf("module %s: nil migration for version %d", module, m.Version))
+		}
+	}
+	return Manager{
+		module:     module,
+		storeKey:   storeKey,
+		versionKey: versionKey,
+		migrations: migrations,
+	}
+}
+
+// LatestVersion returns the version of the store after all the migrations are applied
+func (m Manager) LatestVersion() uint64 {
+	return uint64(len(m.migrations))
+}
+
+// Version returns the current version of the store,
+// stores which never had their version set are at version 0
+func (m Manager) Version(ctx sdk.Context) uint64 {
+	b := ctx.KVStore(m.storeKey).Get(m.versionKey)
+	if b == nil {
+		return 0
+	}
+	return binary.BigEndian.Uint64(b)
+}
+
+// SetVersion sets the current version of the store
+func (m Manager) SetVersion(ctx sdk.Context, version uint64) {
+	ctx.KVStore(m.storeKey).Set(m.versionKey, sdk.Uint64ToBigEndian(version))
+}
+
+// Migrate applies in order the migrations the store has not been upgraded with yet,
+// the store is left untouched if any of the migrations fails
+func (m Manager) Migrate(ctx sdk.Context) error {
+	current := m.Version(ctx)
+	if current > m.LatestVersion() {
+		return fmt.Errorf("module %s: store version %d is greater than latest version %d", m.module, current, m.LatestVersion())
+	}
+	cacheCtx, write := ctx.CacheContext()
+	for _, migration := range m.migrations[current:] {
+		if err := migration.Migrate(cacheCtx); err != nil {
+			return fmt.Errorf("module %s: migration to version %d failed: %w", m.module, migration.Version, err)
+		}
+		m.SetVersion(cacheCtx, migration.Version)
+		ctx.Logger().Info("applied store migration", "module", m.module, "version", migration.Version, "description", migration.Description)
+	}
+	write()
+	return nil
+}
@@ -0,0 +1,83 @@
+package migration
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/store"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/libs/log"
+	db "github.com/tendermint/tm-db"
+)
+
+func newTestContext(t *testing.T) (sdk.Context, sdk.StoreKey) {
+	mdb := db.NewMemDB()
+	ms := store.NewCommitMultiStore(mdb)
+	key := sdk.NewKVStoreKey("test")
+	ms.MountStoreWithDB(key, sdk.StoreTypeIAVL, mdb)
+	if err := ms.LoadLatestVersion(); err != nil {
+		t.Fatal(err)
+	}
+	return sdk.NewContext(ms, abci.Header{}, false, log.NewNopLogger()), key
+}
+
+func TestManager_Migrate(t *testing.T) {
+	ctx, key := newTestContext(t)
+	var applied []uint64
+	migrate := func(version uint64, err error) Migration {
+		return Migration{
+			Version: version,
+			Migrate: func(ctx sdk.Context) error {
+				ctx.KVStore(key).Set([]byte{byte(version)}, []byte{0x1})
+				applied = append(applied, version)
+				return err
+			},
+		}
+	}
+	m := NewManager("test", key, []byte("version"), migrate(1, nil), migrate(2, nil))
+	if m.Version(ctx) != 0 {
+		t.Fatalf("unexpected version: %d", m.Version(ctx))
+	}
+	// only pending migrations are applied
+	m.SetVersion(ctx, 1)
+	if err := m.Migrate(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if len(applied) != 1 || applied[0] != 2 {
+		t.Fatalf("unexpected applied migrations: %v", applied)
+	}
+	if m.Version(ctx) != m.LatestVersion() {
+		t.Fatalf("unexpected version: %d", m.Version(ctx))
+	}
+	// migrating an up to date store is a no-op
+	if err := m.Migrate(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if len(applied) != 1 {
+		t.Fatalf("unexpected applied migrations: %v", applied)
+	}
+	// failed migrations leave the store untouched
+	failure := errors.New("failure")
+	m = NewManager("test", key, []byte("failing"), migrate(1, nil), migrate(2, nil), migrate(3, failure))
+	ctx.KVStore(key).Delete([]byte{0x1})
+	if err := m.Migrate(ctx); !errors.Is(err, failure) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if m.Version(ctx) != 0 {
+		t.Fatalf("unexpected version: %d", m.Version(ctx))
+	}
+	if ctx.KVStore(key).Has([]byte{0x1}) {
+		t.Fatal("failed migration was written")
+	}
+}
+
+func TestNewManager(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatal("expected panic on non consecutive versions")
+		}
+	}()
+	noop := func(sdk.Context) error { return nil }
+	NewManager("test", sdk.NewKVStoreKey("test"), []byte("version"), Migration{Version: 1, Migrate: noop}, Migration{Version: 3, Migrate: noop})
+}
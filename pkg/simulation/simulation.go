@@ -0,0 +1,63 @@
+// Package simulation contains helpers shared by the simulations of iovns modules
+package simulation
+
+import (
+	"fmt"
+	"math/rand"
+
+	"github.com/cosmos/cosmos-sdk/baseapp"
+	"github.com/cosmos/cosmos-sdk/simapp/helpers"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	authexported "github.com/cosmos/cosmos-sdk/x/auth/exported"
+	"github.com/cosmos/cosmos-sdk/x/simulation"
+	"github.com/tendermint/tendermint/crypto"
+)
+
+// AccountKeeper defines the account keeper functionalities required by simulations
+type AccountKeeper interface {
+	GetAccount(ctx sdk.Context, addr sdk.AccAddress) authexported.Account
+}
+
+// nameAlphabet contains the characters used to generate random names
+const nameAlphabet = "abcdefghijklmnopqrstuvwxyz0123456789"
+
+// RandName returns a random lowercase alphanumeric name with length in [min, max]
+func RandName(r *rand.Rand, min, max int) string {
+	n := min
+	if max > min {
+		n = simulation.RandIntBetween(r, min, max+1)
+	}
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = nameAlphabet[r.Intn(len(nameAlphabet))]
+	}
+	return string(b)
+}
+
+// DeliverMsg signs the msg with the keys of its signers, which must be simulation accounts,
+// and delivers it in a transaction without fees. It returns false if any of the signers
+// is not a simulation account, in which case the msg is not delivered.
+func DeliverMsg(app *baseapp.BaseApp, ctx sdk.Context, ak AccountKeeper, accs []simulation.Account, msg sdk.Msg, chainID string) (bool, error) {
+	signers := msg.GetSigners()
+	accNums := make([]uint64, len(signers))
+	sequences := make([]uint64, len(signers))
+	privKeys := make([]crypto.PrivKey, len(signers))
+	for i, signer := range signers {
+		simAcc, ok := simulation.FindAccount(accs, signer)
+		if !ok {
+			return false, nil
+		}
+		account := ak.GetAccount(ctx, signer)
+		if account == nil {
+			return false, nil
+		}
+		accNums[i] = account.GetAccountNumber()
+		sequences[i] = account.GetSequence()
+		privKeys[i] = simAcc.PrivKey
+	}
+	tx := helpers.GenTx([]sdk.Msg{msg}, sdk.NewCoins(), helpers.DefaultGenTxGas, chainID, accNums, sequences, privKeys...)
+	if _, _, err := app.Deliver(tx); err != nil {
+		return true, fmt.Errorf("unable to deliver %s: %w", msg.Type(), err)
+	}
+	return true, nil
+}
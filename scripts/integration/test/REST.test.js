@@ -39,6 +39,7 @@ describe( "Tests the REST API.", () => {
          "certificate_size_max",
          "certificate_count_max",
          "metadata_size_max",
+         "history_retention",
       ];
 
       keys.forEach( key => expect( fetched.result.configuration.hasOwnProperty( key ) ).toEqual( true ) );
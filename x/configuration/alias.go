@@ -8,6 +8,10 @@ type (
 	Config = types.Config
 	// Fees aliases types.Fees
 	Fees = types.Fees
+	// ReservedName aliases types.ReservedName
+	ReservedName = types.ReservedName
+	// ReservedNames aliases types.ReservedNames
+	ReservedNames = types.ReservedNames
 )
 
 // alias for consts
@@ -27,6 +27,7 @@ func GetQueryCmd(queryRoute string, cdc *codec.Codec) *cobra.Command {
 		flags.GetCommands(
 			getCmdQueryConfig(queryRoute, cdc),
 			getCmdQueryFees(queryRoute, cdc),
+			getCmdQueryReservedNames(queryRoute, cdc),
 		)...,
 	)
 	// return cmd list
@@ -77,3 +78,37 @@ func getCmdQueryFees(route string, cdc *codec.Codec) *cobra.Command {
 		},
 	}
 }
+
+func getCmdQueryReservedNames(route string, cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "get-reserved-names",
+		Short: "gets the reserved names registry, or the entries reserving the provided name",
+		Args:  cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+			name, err := cmd.Flags().GetString("name")
+			if err != nil {
+				return err
+			}
+			req, err := queries.DefaultQueryEncode(struct {
+				Name string `json:"name"`
+			}{Name: name})
+			if err != nil {
+				return err
+			}
+			path := fmt.Sprintf("custom/%s/%s", route, types.QueryReservedNames)
+			resp, _, err := cliCtx.QueryWithData(path, req)
+			if err != nil {
+				return err
+			}
+			var jsonResp types.QueryReservedNamesResponse
+			err = queries.DefaultQueryDecode(resp, &jsonResp)
+			if err != nil {
+				return err
+			}
+			return cliCtx.PrintOutput(jsonResp)
+		},
+	}
+	cmd.Flags().String("name", "", "the domain or account name to check, optional")
+	return cmd
+}
@@ -35,6 +35,8 @@ func GetTxCmd(storeKey string, cdc *codec.Codec) *cobra.Command {
 		getCmdUpdateConfig(cdc),
 		getCmdUpdateFees(cdc),
 		getCmdUpdateFee(cdc),
+		getCmdAddReservedName(cdc),
+		getCmdRemoveReservedName(cdc),
 	)...)
 	return configTxCmd
 }
@@ -307,6 +309,13 @@ func getCmdUpdateConfig(cdc *codec.Codec) *cobra.Command {
 			if metadataSizeMax != defaultNumber {
 				config.MetadataSizeMax = metadataSizeMax
 			}
+			historyRetention, err := cmd.Flags().GetDuration("history-retention")
+			if err != nil {
+				return err
+			}
+			if historyRetention != defaultDuration {
+				config.HistoryRetention = historyRetention
+			}
 
 			if err := config.Validate(); err != nil {
 				return err
@@ -344,5 +353,80 @@ func getCmdUpdateConfig(cdc *codec.Codec) *cobra.Command {
 	cmd.Flags().Uint64("certificate-size-max", uint64(defaultNumber), "maximum size of a certificate that could be saved under an account")
 	cmd.Flags().Uint32("certificate-count-max", uint32(defaultNumber), "maximum number of certificates that could be saved under an account")
 	cmd.Flags().Uint64("metadata-size-max", uint64(defaultNumber), "maximum size of metadata that could be saved under an account")
+	cmd.Flags().Duration("history-retention", defaultDuration, "duration the starname history records are kept, 0 disables the history")
+	return cmd
+}
+
+func getCmdAddReservedName(cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "add-reserved-name",
+		Short:   "reserve a domain and account name, or all the names matching a pattern, optionally for a claimant",
+		Example: "iovnscli tx configuration add-reserved-name --from iovSAS --name admin",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := context.NewCLIContext().WithCodec(cdc).WithBroadcastMode(flags.BroadcastBlock)
+			inBuf := bufio.NewReader(cmd.InOrStdin())
+			txBuilder := auth.NewTxBuilderFromCLI(inBuf).WithTxEncoder(utils.GetTxEncoder(cdc))
+			name, err := cmd.Flags().GetString("name")
+			if err != nil {
+				return err
+			}
+			pattern, err := cmd.Flags().GetBool("pattern")
+			if err != nil {
+				return err
+			}
+			claimantStr, err := cmd.Flags().GetString("claimant")
+			if err != nil {
+				return err
+			}
+			var claimant sdk.AccAddress
+			if claimantStr != "" {
+				claimant, err = sdk.AccAddressFromBech32(claimantStr)
+				if err != nil {
+					return err
+				}
+			}
+			msg := types.MsgAddReservedName{
+				ReservedName: types.ReservedName{
+					Name:     name,
+					Pattern:  pattern,
+					Claimant: claimant,
+				},
+				Configurer: cliCtx.GetFromAddress(),
+			}
+			if err := msg.ValidateBasic(); err != nil {
+				return fmt.Errorf("invalid tx: %w", err)
+			}
+			return utils.GenerateOrBroadcastMsgs(cliCtx, txBuilder, []sdk.Msg{msg})
+		},
+	}
+	cmd.Flags().String("name", "", "the reserved name, or a regexp if pattern is set")
+	cmd.Flags().Bool("pattern", false, "reserve all the names matching the name regexp")
+	cmd.Flags().String("claimant", "", "the address allowed to register the reserved name, optional")
+	return cmd
+}
+
+func getCmdRemoveReservedName(cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "remove-reserved-name",
+		Short: "remove a name or pattern from the reserved names",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := context.NewCLIContext().WithCodec(cdc).WithBroadcastMode(flags.BroadcastBlock)
+			inBuf := bufio.NewReader(cmd.InOrStdin())
+			txBuilder := auth.NewTxBuilderFromCLI(inBuf).WithTxEncoder(utils.GetTxEncoder(cdc))
+			name, err := cmd.Flags().GetString("name")
+			if err != nil {
+				return err
+			}
+			msg := types.MsgRemoveReservedName{
+				Name:       name,
+				Configurer: cliCtx.GetFromAddress(),
+			}
+			if err := msg.ValidateBasic(); err != nil {
+				return fmt.Errorf("invalid tx: %w", err)
+			}
+			return utils.GenerateOrBroadcastMsgs(cliCtx, txBuilder, []sdk.Msg{msg})
+		},
+	}
+	cmd.Flags().String("name", "", "the reserved name or pattern to remove")
 	return cmd
 }
@@ -15,8 +15,10 @@ import (
 // txRouteList clubs together all the transaction routes, which are the transactions
 // // that return the bytes to sign to send a request that modifies state to the domain module
 var txRoutesList = map[string]func(cliContext context.CLIContext) http.HandlerFunc{
-	"updateConfig": updateConfigHandler,
-	"updateFees":   updateFeesHandler,
+	"updateConfig":       updateConfigHandler,
+	"updateFees":         updateFeesHandler,
+	"addReservedName":    addReservedNameHandler,
+	"removeReservedName": removeReservedNameHandler,
 }
 
 // registerTxRoutes registers all the transaction routes to the router
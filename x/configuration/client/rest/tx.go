@@ -55,3 +55,33 @@ func updateFeesHandler(cliCtx context.CLIContext) http.HandlerFunc {
 		handleTxRequest(cliCtx, req.BaseReq, req.Message, writer)
 	}
 }
+
+type addReservedName struct {
+	BaseReq rest.BaseReq              `json:"base_req"`
+	Message *types.MsgAddReservedName `json:"message"`
+}
+
+func addReservedNameHandler(cliCtx context.CLIContext) http.HandlerFunc {
+	return func(writer http.ResponseWriter, request *http.Request) {
+		var req addReservedName
+		if !rest.ReadRESTReq(writer, request, cliCtx.Codec, &req) {
+			return
+		}
+		handleTxRequest(cliCtx, req.BaseReq, req.Message, writer)
+	}
+}
+
+type removeReservedName struct {
+	BaseReq rest.BaseReq                 `json:"base_req"`
+	Message *types.MsgRemoveReservedName `json:"message"`
+}
+
+func removeReservedNameHandler(cliCtx context.CLIContext) http.HandlerFunc {
+	return func(writer http.ResponseWriter, request *http.Request) {
+		var req removeReservedName
+		if !rest.ReadRESTReq(writer, request, cliCtx.Codec, &req) {
+			return
+		}
+		handleTxRequest(cliCtx, req.BaseReq, req.Message, writer)
+	}
+}
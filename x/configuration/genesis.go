@@ -13,6 +13,8 @@ type GenesisState struct {
 	Config types.Config `json:"config"`
 	// Fees contains the fees
 	Fees *types.Fees `json:"fees"`
+	// ReservedNames contains the reserved names registry
+	ReservedNames types.ReservedNames `json:"reserved_names,omitempty"`
 }
 
 // NewGenesisState is GenesisState constructor
@@ -32,6 +34,9 @@ func ValidateGenesis(data GenesisState) error {
 	if err := data.Fees.Validate(); err != nil {
 		return err
 	}
+	if err := data.ReservedNames.Validate(); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -79,12 +84,25 @@ func DefaultGenesisState() GenesisState {
 func InitGenesis(ctx sdk.Context, k Keeper, data GenesisState) {
 	k.SetConfig(ctx, data.Config)
 	k.SetFees(ctx, data.Fees)
+	for _, reserved := range data.ReservedNames {
+		if err := k.SetReservedName(ctx, reserved); err != nil {
+			panic(err)
+		}
+	}
+	// genesis state is always in the latest format
+	migrations := k.Migrations()
+	migrations.SetVersion(ctx, migrations.LatestVersion())
 }
 
 // ExportGenesis saves the state of the configuration module
 func ExportGenesis(ctx sdk.Context, k Keeper) GenesisState {
+	reserved := k.GetReservedNames(ctx)
+	if len(reserved) == 0 {
+		reserved = nil
+	}
 	return GenesisState{
-		Config: k.GetConfiguration(ctx),
-		Fees:   k.GetFees(ctx),
+		Config:        k.GetConfiguration(ctx),
+		Fees:          k.GetFees(ctx),
+		ReservedNames: reserved,
 	}
 }
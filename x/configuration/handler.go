@@ -14,6 +14,10 @@ func NewHandler(k Keeper) sdk.Handler {
 			return handleUpdateConfig(ctx, msg, k)
 		case types.MsgUpdateFees:
 			return handleUpdateFees(ctx, msg, k)
+		case types.MsgAddReservedName:
+			return handleAddReservedName(ctx, msg, k)
+		case types.MsgRemoveReservedName:
+			return handleRemoveReservedName(ctx, msg, k)
 		default:
 			return nil, sdkerrors.Wrapf(sdkerrors.ErrInvalidRequest, "unknown request")
 		}
@@ -40,3 +44,26 @@ func handleUpdateConfig(ctx sdk.Context, msg types.MsgUpdateConfig, k Keeper) (*
 	// TODO emit event
 	return &sdk.Result{}, nil
 }
+
+func handleAddReservedName(ctx sdk.Context, msg types.MsgAddReservedName, k Keeper) (*sdk.Result, error) {
+	configurer := k.GetConfigurer(ctx)
+	if !configurer.Equals(msg.Configurer) {
+		return nil, sdkerrors.Wrapf(sdkerrors.ErrUnauthorized, "%s is not allowed to reserve names", msg.Configurer)
+	}
+	if err := k.SetReservedName(ctx, msg.ReservedName); err != nil {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrInvalidRequest, err.Error())
+	}
+	return &sdk.Result{}, nil
+}
+
+func handleRemoveReservedName(ctx sdk.Context, msg types.MsgRemoveReservedName, k Keeper) (*sdk.Result, error) {
+	configurer := k.GetConfigurer(ctx)
+	if !configurer.Equals(msg.Configurer) {
+		return nil, sdkerrors.Wrapf(sdkerrors.ErrUnauthorized, "%s is not allowed to remove reserved names", msg.Configurer)
+	}
+	if _, ok := k.GetReservedName(ctx, msg.Name); !ok {
+		return nil, sdkerrors.Wrapf(sdkerrors.ErrInvalidRequest, "%s is not reserved", msg.Name)
+	}
+	k.DeleteReservedName(ctx, msg.Name)
+	return &sdk.Result{}, nil
+}
@@ -41,3 +41,75 @@ func Test_HandleUpdateConfig(t *testing.T) {
 	}
 	RunTests(t, cases)
 }
+
+func Test_HandleReservedNames(t *testing.T) {
+	cases := map[string]SubTest{
+		"only configurer can reserve names": {
+			BeforeTest: func(t *testing.T, k Keeper, ctx sdk.Context) {
+				k.SetConfig(ctx, Config{Configurer: AliceKey})
+			},
+			Test: func(t *testing.T, k Keeper, ctx sdk.Context) {
+				msg := types.MsgAddReservedName{
+					ReservedName: ReservedName{Name: "admin"},
+					Configurer:   CharlieKey,
+				}
+				_, err := handleAddReservedName(ctx, msg, k)
+				if !errors.Is(err, sdkerrors.ErrUnauthorized) {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				msg.Configurer = AliceKey
+				_, err = handleAddReservedName(ctx, msg, k)
+				if err != nil {
+					t.Fatalf("handleAddReservedName() got error: %s", err)
+				}
+			},
+			AfterTest: func(t *testing.T, k Keeper, ctx sdk.Context) {
+				if _, ok := k.GetReservedName(ctx, "admin"); !ok {
+					t.Fatal("admin should be reserved")
+				}
+			},
+		},
+		"invalid patterns are not reserved": {
+			BeforeTest: func(t *testing.T, k Keeper, ctx sdk.Context) {
+				k.SetConfig(ctx, Config{Configurer: AliceKey})
+			},
+			Test: func(t *testing.T, k Keeper, ctx sdk.Context) {
+				msg := types.MsgAddReservedName{
+					ReservedName: ReservedName{Name: "(", Pattern: true},
+					Configurer:   AliceKey,
+				}
+				_, err := handleAddReservedName(ctx, msg, k)
+				if !errors.Is(err, sdkerrors.ErrInvalidRequest) {
+					t.Fatalf("unexpected error: %s", err)
+				}
+			},
+			AfterTest: func(t *testing.T, k Keeper, ctx sdk.Context) {
+				if reserved := k.GetReservedNames(ctx); len(reserved) != 0 {
+					t.Fatalf("unexpected reserved names: %v", reserved)
+				}
+			},
+		},
+		"remove reserved name": {
+			BeforeTest: func(t *testing.T, k Keeper, ctx sdk.Context) {
+				k.SetConfig(ctx, Config{Configurer: AliceKey})
+				k.SetReservedName(ctx, ReservedName{Name: "^iov.*$", Pattern: true, Claimant: BobKey})
+			},
+			Test: func(t *testing.T, k Keeper, ctx sdk.Context) {
+				_, err := handleRemoveReservedName(ctx, types.MsgRemoveReservedName{Name: "iov", Configurer: AliceKey}, k)
+				if !errors.Is(err, sdkerrors.ErrInvalidRequest) {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				_, err = handleRemoveReservedName(ctx, types.MsgRemoveReservedName{Name: "^iov.*$", Configurer: AliceKey}, k)
+				if err != nil {
+					t.Fatalf("handleRemoveReservedName() got error: %s", err)
+				}
+			},
+			AfterTest: func(t *testing.T, k Keeper, ctx sdk.Context) {
+				if reserved := k.GetReservedNames(ctx); len(reserved) != 0 {
+					t.Fatalf("unexpected reserved names: %v", reserved)
+				}
+			},
+		},
+	}
+	RunTests(t, cases)
+}
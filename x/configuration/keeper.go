@@ -19,6 +19,8 @@ type Keeper struct {
 	storeKey   sdk.StoreKey
 	cdc        *codec.Codec
 	paramspace ParamSubspace
+	// patterns keeps the compiled patterns of the reserved names registry
+	patterns *types.PatternCache
 }
 
 // NewKeeper is Keeper constructor
@@ -27,6 +29,7 @@ func NewKeeper(cdc *codec.Codec, key sdk.StoreKey, paramspace ParamSubspace) Kee
 		storeKey:   key,
 		cdc:        cdc,
 		paramspace: paramspace,
+		patterns:   types.NewPatternCache(),
 	}
 }
 
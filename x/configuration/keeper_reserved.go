@@ -0,0 +1,113 @@
+package configuration
+
+import (
+	"github.com/cosmos/cosmos-sdk/store/prefix"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/iov-one/iovns/pkg/idn"
+	"github.com/iov-one/iovns/x/configuration/types"
+)
+
+// reservedNameStore returns the store of the reserved names keyed by name
+func (k Keeper) reservedNameStore(ctx sdk.Context) prefix.Store {
+	return prefix.NewStore(ctx.KVStore(k.storeKey), []byte(types.ReservedNamePrefix))
+}
+
+// reservedSkeletonStore returns the index of the exact reserved names keyed by skeleton
+func (k Keeper) reservedSkeletonStore(ctx sdk.Context) prefix.Store {
+	return prefix.NewStore(ctx.KVStore(k.storeKey), []byte(types.ReservedSkeletonPrefix))
+}
+
+// reservedPatternStore returns the index of the reserved name patterns
+func (k Keeper) reservedPatternStore(ctx sdk.Context) prefix.Store {
+	return prefix.NewStore(ctx.KVStore(k.storeKey), []byte(types.ReservedPatternPrefix))
+}
+
+// reservedSkeletonPrefix returns the prefix of the skeleton index keys of the exact
+// reserved names confusable with the given name
+func reservedSkeletonPrefix(name string) []byte {
+	return []byte(idn.Skeleton(name) + "/")
+}
+
+// GetReservedName returns the registry entry of the provided name or pattern, if any
+func (k Keeper) GetReservedName(ctx sdk.Context, name string) (types.ReservedName, bool) {
+	b := k.reservedNameStore(ctx).Get([]byte(name))
+	if b == nil {
+		return types.ReservedName{}, false
+	}
+	return k.mustUnmarshalReservedName(b), true
+}
+
+// GetReservedNames returns all the entries of the reserved names registry, with their patterns compiled
+func (k Keeper) GetReservedNames(ctx sdk.Context) types.ReservedNames {
+	reserved := types.ReservedNames{}
+	it := k.reservedNameStore(ctx).Iterator(nil, nil)
+	defer it.Close()
+	for ; it.Valid(); it.Next() {
+		reserved = append(reserved, k.mustUnmarshalReservedName(it.Value()))
+	}
+	return reserved
+}
+
+// GetReservedNamesMatching returns the entries of the reserved names registry which
+// reserve the name, the exact names confusable with it are looked up by skeleton
+// so that only the patterns are iterated
+func (k Keeper) GetReservedNamesMatching(ctx sdk.Context, name string) types.ReservedNames {
+	var names []string
+	skeletons := prefix.NewStore(k.reservedSkeletonStore(ctx), reservedSkeletonPrefix(name))
+	it := skeletons.Iterator(nil, nil)
+	for ; it.Valid(); it.Next() {
+		names = append(names, string(it.Value()))
+	}
+	it.Close()
+	it = k.reservedPatternStore(ctx).Iterator(nil, nil)
+	for ; it.Valid(); it.Next() {
+		names = append(names, string(it.Value()))
+	}
+	it.Close()
+	matching := types.ReservedNames{}
+	for _, n := range names {
+		reserved, ok := k.GetReservedName(ctx, n)
+		// skeletons containing the separator can share a prefix with other skeletons
+		if !ok || !reserved.Matches(name) {
+			continue
+		}
+		matching = append(matching, reserved)
+	}
+	return matching
+}
+
+// SetReservedName adds or replaces an entry of the reserved names registry,
+// it fails if the entry is not valid
+func (k Keeper) SetReservedName(ctx sdk.Context, reserved types.ReservedName) error {
+	if err := reserved.Validate(); err != nil {
+		return err
+	}
+	// the replaced entry may be indexed differently
+	k.DeleteReservedName(ctx, reserved.Name)
+	k.reservedNameStore(ctx).Set([]byte(reserved.Name), k.cdc.MustMarshalBinaryBare(reserved))
+	if reserved.Pattern {
+		k.reservedPatternStore(ctx).Set([]byte(reserved.Name), []byte(reserved.Name))
+		return nil
+	}
+	k.reservedSkeletonStore(ctx).Set(append(reservedSkeletonPrefix(reserved.Name), reserved.Name...), []byte(reserved.Name))
+	return nil
+}
+
+// mustUnmarshalReservedName decodes a stored entry and compiles its pattern, once
+// per pattern thanks to the keeper cache, the entries are validated when they are set
+func (k Keeper) mustUnmarshalReservedName(b []byte) types.ReservedName {
+	var reserved types.ReservedName
+	k.cdc.MustUnmarshalBinaryBare(b, &reserved)
+	compiled, err := k.patterns.Compile(reserved)
+	if err != nil {
+		panic(err)
+	}
+	return compiled
+}
+
+// DeleteReservedName removes an entry of the reserved names registry
+func (k Keeper) DeleteReservedName(ctx sdk.Context, name string) {
+	k.reservedNameStore(ctx).Delete([]byte(name))
+	k.reservedPatternStore(ctx).Delete([]byte(name))
+	k.reservedSkeletonStore(ctx).Delete(append(reservedSkeletonPrefix(name), name...))
+}
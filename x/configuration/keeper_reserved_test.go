@@ -0,0 +1,63 @@
+package configuration
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+func TestKeeper_GetReservedNamesMatching(t *testing.T) {
+	k, ctx := NewTestKeeper(t, false)
+	for _, reserved := range []ReservedName{
+		{Name: "admin", Claimant: sdk.AccAddress("claimant")},
+		{Name: "alice"},
+		{Name: "^iov.*$", Pattern: true},
+	} {
+		if err := k.SetReservedName(ctx, reserved); err != nil {
+			t.Fatal(err)
+		}
+	}
+	matching := func(name string) []string {
+		var names []string
+		for _, reserved := range k.GetReservedNamesMatching(ctx, name) {
+			names = append(names, reserved.Name)
+		}
+		return names
+	}
+	cases := map[string]struct {
+		name string
+		want []string
+	}{
+		"exact name":      {name: "admin", want: []string{"admin"}},
+		"confusable name": {name: "аdmin", want: []string{"admin"}},
+		"pattern":         {name: "iovns", want: []string{"^iov.*$"}},
+		"not reserved":    {name: "bob", want: nil},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := matching(c.name)
+			if len(got) != len(c.want) || (len(got) != 0 && got[0] != c.want[0]) {
+				t.Fatalf("want: %v, got: %v", c.want, got)
+			}
+		})
+	}
+	// replacing an exact name with a pattern moves it from the skeleton index
+	if err := k.SetReservedName(ctx, ReservedName{Name: "alice", Pattern: true}); err != nil {
+		t.Fatal(err)
+	}
+	if got := matching("аlice"); len(got) != 0 {
+		t.Fatalf("unexpected reserved names: %v", got)
+	}
+	if got := matching("alice"); len(got) != 1 || got[0] != "alice" {
+		t.Fatalf("unexpected reserved names: %v", got)
+	}
+	// deleted entries are removed from the indexes
+	k.DeleteReservedName(ctx, "alice")
+	k.DeleteReservedName(ctx, "^iov.*$")
+	if got := matching("iovns"); len(got) != 0 {
+		t.Fatalf("unexpected reserved names: %v", got)
+	}
+	if got := matching("alice"); len(got) != 0 {
+		t.Fatalf("unexpected reserved names: %v", got)
+	}
+}
@@ -0,0 +1,41 @@
+package configuration
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/iov-one/iovns/pkg/migration"
+	"github.com/iov-one/iovns/x/configuration/types"
+)
+
+// renewDomainClosedMaxAccounts is the number of accounts of a closed domain,
+// empty account included, above which the renewal fee set by the migration of
+// the fees stops growing, the previous fee had no cap so closed domains with
+// more accounts pay less after the migration, which is a deliberate price change
+const renewDomainClosedMaxAccounts = 1000
+
+// Migrations returns the manager of the configuration store migrations,
+// new migrations must be appended to the list with the next version
+func (k Keeper) Migrations() migration.Manager {
+	return migration.NewManager(types.ModuleName, k.storeKey, []byte(types.VersionKey),
+		migration.Migration{
+			Version:     1,
+			Description: "set the fees to renew closed domains",
+			Migrate:     k.migrateRenewDomainClosedFees,
+		},
+	)
+}
+
+// migrateRenewDomainClosedFees sets the fees to renew closed domains so that they match
+// the previous fee, which charged the register account closed fee for every account
+// of the domain, empty account included, the new fee is however capped at the price
+// of renewDomainClosedMaxAccounts accounts while the previous one was not capped
+func (k Keeper) migrateRenewDomainClosedFees(ctx sdk.Context) error {
+	if !ctx.KVStore(k.storeKey).Has([]byte(types.FeeKey)) {
+		return nil
+	}
+	fees := k.GetFees(ctx)
+	fees.RenewDomainClosedBase = fees.RegisterAccountClosed
+	fees.RenewDomainClosedPerAccount = fees.RegisterAccountClosed
+	fees.RenewDomainClosedMax = fees.RegisterAccountClosed.MulInt64(renewDomainClosedMaxAccounts)
+	k.SetFees(ctx, fees)
+	return nil
+}
@@ -0,0 +1,82 @@
+package configuration
+
+import (
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/iov-one/iovns/x/configuration/types"
+)
+
+// legacyFees are the fees as stored at store version 0, before the closed domains
+// renewal fees existed, the type is frozen so that the test keeps the encoding of
+// that version whatever happens to types.Fees
+type legacyFees struct {
+	FeeCoinDenom                 string
+	FeeCoinPrice                 sdk.Dec
+	FeeDefault                   sdk.Dec
+	RegisterAccountClosed        sdk.Dec
+	RegisterAccountOpen          sdk.Dec
+	TransferAccountClosed        sdk.Dec
+	TransferAccountOpen          sdk.Dec
+	ReplaceAccountResources      sdk.Dec
+	AddAccountCertificate        sdk.Dec
+	DelAccountCertificate        sdk.Dec
+	SetAccountMetadata           sdk.Dec
+	RegisterDomain1              sdk.Dec
+	RegisterDomain2              sdk.Dec
+	RegisterDomain3              sdk.Dec
+	RegisterDomain4              sdk.Dec
+	RegisterDomain5              sdk.Dec
+	RegisterDomainDefault        sdk.Dec
+	RegisterOpenDomainMultiplier sdk.Dec
+	TransferDomainClosed         sdk.Dec
+	TransferDomainOpen           sdk.Dec
+	RenewDomainOpen              sdk.Dec
+}
+
+func TestMigrations(t *testing.T) {
+	k, ctx := NewTestKeeper(t, false)
+	one := sdk.NewDec(1)
+	legacy := legacyFees{
+		FeeCoinDenom:                 "tiov",
+		FeeCoinPrice:                 one,
+		FeeDefault:                   one,
+		RegisterAccountClosed:        sdk.NewDec(3),
+		RegisterAccountOpen:          one,
+		TransferAccountClosed:        one,
+		TransferAccountOpen:          one,
+		ReplaceAccountResources:      one,
+		AddAccountCertificate:        one,
+		DelAccountCertificate:        one,
+		SetAccountMetadata:           one,
+		RegisterDomain1:              one,
+		RegisterDomain2:              one,
+		RegisterDomain3:              one,
+		RegisterDomain4:              one,
+		RegisterDomain5:              one,
+		RegisterDomainDefault:        one,
+		RegisterOpenDomainMultiplier: one,
+		TransferDomainClosed:         one,
+		TransferDomainOpen:           one,
+		RenewDomainOpen:              one,
+	}
+	ctx.KVStore(k.storeKey).Set([]byte(types.FeeKey), codec.New().MustMarshalBinaryBare(legacy))
+	migrations := k.Migrations()
+	if err := migrations.Migrate(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if migrations.Version(ctx) != migrations.LatestVersion() {
+		t.Fatalf("unexpected store version: %d", migrations.Version(ctx))
+	}
+	fees := k.GetFees(ctx)
+	if err := fees.Validate(); err != nil {
+		t.Fatalf("invalid migrated fees: %s", err)
+	}
+	if !fees.RenewDomainClosedBase.Equal(sdk.NewDec(3)) || !fees.RenewDomainClosedPerAccount.Equal(sdk.NewDec(3)) {
+		t.Fatalf("unexpected renew domain closed fees: %s, %s", fees.RenewDomainClosedBase, fees.RenewDomainClosedPerAccount)
+	}
+	if !fees.RenewDomainClosedMax.Equal(sdk.NewDec(3000)) {
+		t.Fatalf("unexpected renew domain closed max fee: %s", fees.RenewDomainClosedMax)
+	}
+}
@@ -2,22 +2,27 @@ package configuration
 
 import (
 	"encoding/json"
+	"math/rand"
 
 	"github.com/cosmos/cosmos-sdk/client/context"
 	"github.com/cosmos/cosmos-sdk/codec"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/types/module"
+	"github.com/cosmos/cosmos-sdk/x/simulation"
 	"github.com/gorilla/mux"
+	simutil "github.com/iov-one/iovns/pkg/simulation"
 	"github.com/iov-one/iovns/x/configuration/client/cli"
 	"github.com/iov-one/iovns/x/configuration/client/rest"
+	configsim "github.com/iov-one/iovns/x/configuration/simulation"
 	"github.com/iov-one/iovns/x/configuration/types"
 	"github.com/spf13/cobra"
 	abci "github.com/tendermint/tendermint/abci/types"
 )
 
 var (
-	_ module.AppModule      = AppModule{}
-	_ module.AppModuleBasic = AppModuleBasic{}
+	_ module.AppModule           = AppModule{}
+	_ module.AppModuleBasic      = AppModuleBasic{}
+	_ module.AppModuleSimulation = AppModule{}
 )
 
 // nolint
@@ -52,13 +57,15 @@ func (AppModuleBasic) GetQueryCmd(cdc *codec.Codec) *cobra.Command {
 // - - FILL APP MODULE - -
 type AppModule struct {
 	AppModuleBasic
-	keeper Keeper
+	keeper        Keeper
+	accountKeeper simutil.AccountKeeper
 }
 
-func NewAppModule(k Keeper) AppModule {
+func NewAppModule(k Keeper, ak simutil.AccountKeeper) AppModule {
 	return AppModule{
 		AppModuleBasic: AppModuleBasic{},
 		keeper:         k,
+		accountKeeper:  ak,
 	}
 }
 func (AppModule) Name() string                                       { return types.ModuleName }
@@ -87,3 +94,30 @@ func (a AppModule) ExportGenesis(ctx sdk.Context) json.RawMessage {
 	genesisState := ExportGenesis(ctx, a.keeper)
 	return types.ModuleCdc.MustMarshalJSON(genesisState)
 }
+
+// - - APP MODULE SIMULATION - -
+
+// GenerateGenesisState creates a randomized GenesisState of the configuration module
+func (AppModule) GenerateGenesisState(simState *module.SimulationState) {
+	configsim.RandomizedGenState(simState)
+}
+
+// ProposalContents doesn't return any content functions for governance proposals
+func (AppModule) ProposalContents(_ module.SimulationState) []simulation.WeightedProposalContent {
+	return nil
+}
+
+// RandomizedParams returns nil because the configuration module has no params
+func (AppModule) RandomizedParams(_ *rand.Rand) []simulation.ParamChange {
+	return nil
+}
+
+// RegisterStoreDecoder registers a decoder for the configuration module store
+func (AppModule) RegisterStoreDecoder(sdr sdk.StoreDecoderRegistry) {
+	sdr[types.StoreKey] = configsim.DecodeStore
+}
+
+// WeightedOperations returns the configuration module operations with their respective weights
+func (a AppModule) WeightedOperations(simState module.SimulationState) []simulation.WeightedOperation {
+	return configsim.WeightedOperations(simState.AppParams, simState.Cdc, a.accountKeeper, a.keeper)
+}
@@ -6,6 +6,7 @@ import (
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/iov-one/iovns/x/configuration/types"
 	abci "github.com/tendermint/tendermint/abci/types"
 )
 
@@ -17,6 +18,7 @@ func AvailableQueries() []queries.QueryHandler {
 	queries := []queries.QueryHandler{
 		&QueryConfiguration{},
 		&QueryFees{},
+		&QueryReservedNames{},
 	}
 	return queries
 }
@@ -151,3 +153,59 @@ type QueryFeesResponse struct {
 	// Fees represents the current fees of the network
 	Fees Fees `json:"fees"`
 }
+
+// QueryReservedNames is the request model used to get the reserved names registry
+type QueryReservedNames struct {
+	// Name optionally filters the entries to the ones reserving it
+	Name string `json:"name"`
+}
+
+// Use is a placeholder
+func (q *QueryReservedNames) Use() string {
+	return "query-reserved-names"
+}
+
+// Description is a placeholder
+func (q *QueryReservedNames) Description() string {
+	return "return the reserved names registry or the entries reserving a name"
+}
+
+// Handler implements QueryHandler
+func (q *QueryReservedNames) Handler() QueryHandlerFunc {
+	return queryReservedNamesHandler
+}
+
+// Validate implements QueryHandler
+func (q *QueryReservedNames) Validate() error {
+	return nil
+}
+
+// QueryPath implements QueryHandler
+func (q *QueryReservedNames) QueryPath() string {
+	return types.QueryReservedNames
+}
+
+func queryReservedNamesHandler(ctx sdk.Context, _ []string, req abci.RequestQuery, k Keeper) ([]byte, error) {
+	q := new(QueryReservedNames)
+	if len(req.Data) != 0 {
+		if err := queries.DefaultQueryDecode(req.Data, q); err != nil {
+			return nil, sdkerrors.Wrapf(sdkerrors.ErrJSONUnmarshal, err.Error())
+		}
+	}
+	reserved := k.GetReservedNames(ctx)
+	if q.Name != "" {
+		reserved = k.GetReservedNamesMatching(ctx, q.Name)
+	}
+	// return response
+	respBytes, err := queries.DefaultQueryEncode(QueryReservedNamesResponse{ReservedNames: reserved})
+	if err != nil {
+		return nil, sdkerrors.Wrapf(sdkerrors.ErrJSONMarshal, err.Error())
+	}
+	return respBytes, nil
+}
+
+// QueryReservedNamesResponse is returned after querying reserved names
+type QueryReservedNamesResponse struct {
+	// ReservedNames contains the entries of the reserved names registry
+	ReservedNames types.ReservedNames `json:"reserved_names"`
+}
@@ -0,0 +1,38 @@
+package simulation
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	"github.com/iov-one/iovns/x/configuration/types"
+	tmkv "github.com/tendermint/tendermint/libs/kv"
+)
+
+// DecodeStore unmarshals the KVPair's values to the corresponding configuration type
+func DecodeStore(cdc *codec.Codec, kvA, kvB tmkv.Pair) string {
+	switch key := string(kvA.Key); {
+	case key == types.ConfigKey:
+		var confA, confB types.Config
+		cdc.MustUnmarshalBinaryBare(kvA.Value, &confA)
+		cdc.MustUnmarshalBinaryBare(kvB.Value, &confB)
+		return fmt.Sprintf("%v\n%v", confA, confB)
+	case key == types.FeeKey:
+		var feesA, feesB types.Fees
+		cdc.MustUnmarshalBinaryBare(kvA.Value, &feesA)
+		cdc.MustUnmarshalBinaryBare(kvB.Value, &feesB)
+		return fmt.Sprintf("%v\n%v", feesA, feesB)
+	case key == types.VersionKey:
+		return fmt.Sprintf("%d\n%d", binary.BigEndian.Uint64(kvA.Value), binary.BigEndian.Uint64(kvB.Value))
+	case strings.HasPrefix(key, types.ReservedNamePrefix):
+		var reservedA, reservedB types.ReservedName
+		cdc.MustUnmarshalBinaryBare(kvA.Value, &reservedA)
+		cdc.MustUnmarshalBinaryBare(kvB.Value, &reservedB)
+		return fmt.Sprintf("%v\n%v", reservedA, reservedB)
+	case strings.HasPrefix(key, types.ReservedSkeletonPrefix), strings.HasPrefix(key, types.ReservedPatternPrefix):
+		return fmt.Sprintf("%s\n%s", kvA.Value, kvB.Value)
+	default:
+		panic(fmt.Sprintf("invalid configuration key %X", kvA.Key))
+	}
+}
@@ -0,0 +1,55 @@
+package simulation
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/iov-one/iovns/x/configuration/types"
+	tmkv "github.com/tendermint/tendermint/libs/kv"
+)
+
+func TestDecodeStore(t *testing.T) {
+	cdc := codec.New()
+	r := rand.New(rand.NewSource(1))
+	conf := RandomConfig(r, sdk.AccAddress("configurer"))
+	fees := RandomFees(r, sdk.DefaultBondDenom)
+	reserved := types.ReservedName{Name: "admin", Claimant: sdk.AccAddress("claimant")}
+
+	kvPairs := []tmkv.Pair{
+		{Key: []byte(types.ConfigKey), Value: cdc.MustMarshalBinaryBare(conf)},
+		{Key: []byte(types.FeeKey), Value: cdc.MustMarshalBinaryBare(fees)},
+		{Key: []byte(types.VersionKey), Value: sdk.Uint64ToBigEndian(1)},
+		{Key: []byte(types.ReservedNamePrefix + reserved.Name), Value: cdc.MustMarshalBinaryBare(reserved)},
+		{Key: []byte(types.ReservedSkeletonPrefix + reserved.Name + "/" + reserved.Name), Value: []byte(reserved.Name)},
+		{Key: []byte{0x99}, Value: []byte{0x99}},
+	}
+	tests := []struct {
+		name        string
+		expectedLog string
+	}{
+		{"Config", fmt.Sprintf("%v\n%v", conf, conf)},
+		{"Fees", fmt.Sprintf("%v\n%v", *fees, *fees)},
+		{"Version", "1\n1"},
+		{"ReservedName", fmt.Sprintf("%v\n%v", reserved, reserved)},
+		{"ReservedSkeleton", "admin\nadmin"},
+		{"other", ""},
+	}
+	for i, tt := range tests {
+		i, tt := i, tt
+		t.Run(tt.name, func(t *testing.T) {
+			if i == len(tests)-1 {
+				defer func() {
+					if r := recover(); r == nil {
+						t.Fatal("expected panic")
+					}
+				}()
+			}
+			if got := DecodeStore(cdc, kvPairs[i], kvPairs[i]); got != tt.expectedLog {
+				t.Fatalf("expected %q, got %q", tt.expectedLog, got)
+			}
+		})
+	}
+}
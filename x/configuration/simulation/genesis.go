@@ -0,0 +1,115 @@
+package simulation
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/module"
+	"github.com/cosmos/cosmos-sdk/x/simulation"
+	simutil "github.com/iov-one/iovns/pkg/simulation"
+	"github.com/iov-one/iovns/x/configuration/types"
+)
+
+// GenesisState mirrors configuration.GenesisState, which can not be imported here
+type GenesisState struct {
+	Config        types.Config        `json:"config"`
+	Fees          *types.Fees         `json:"fees"`
+	ReservedNames types.ReservedNames `json:"reserved_names,omitempty"`
+}
+
+// RandomConfig generates a random configuration owned by configurer,
+// names, URIs and resources regexps match the ones generated by simulations
+func RandomConfig(r *rand.Rand, configurer sdk.AccAddress) types.Config {
+	return types.Config{
+		Configurer:             configurer,
+		ValidDomainName:        "^[a-z0-9]{4,16}$",
+		ValidAccountName:       "^[-_\\.a-z0-9]{1,64}$",
+		ValidURI:               "^[-a-z0-9A-Z:]+$",
+		ValidResource:          "^[a-z0-9A-Z]+$",
+		DomainRenewalPeriod:    time.Duration(simulation.RandIntBetween(r, 1, 72)) * time.Hour,
+		DomainRenewalCountMax:  uint32(simulation.RandIntBetween(r, 1, 4)),
+		DomainGracePeriod:      time.Duration(simulation.RandIntBetween(r, 1, 24)) * time.Hour,
+		AccountRenewalPeriod:   time.Duration(simulation.RandIntBetween(r, 1, 72)) * time.Hour,
+		AccountRenewalCountMax: uint32(simulation.RandIntBetween(r, 1, 4)),
+		AccountGracePeriod:     time.Duration(simulation.RandIntBetween(r, 1, 24)) * time.Hour,
+		ResourcesMax:           uint32(simulation.RandIntBetween(r, 1, 6)),
+		CertificateSizeMax:     uint64(simulation.RandIntBetween(r, 16, 1024)),
+		CertificateCountMax:    uint32(simulation.RandIntBetween(r, 1, 6)),
+		MetadataSizeMax:        uint64(simulation.RandIntBetween(r, 16, 1024)),
+		HistoryRetention:       time.Duration(simulation.RandIntBetween(r, 0, 72)) * time.Hour,
+	}
+}
+
+// RandomFees generates random fees paid in the given denom
+func RandomFees(r *rand.Rand, denom string) *types.Fees {
+	fee := func() sdk.Dec { return sdk.NewDec(int64(simulation.RandIntBetween(r, 1, 100))) }
+	return &types.Fees{
+		FeeCoinDenom:                 denom,
+		FeeCoinPrice:                 sdk.NewDec(int64(simulation.RandIntBetween(r, 1, 10))),
+		FeeDefault:                   fee(),
+		RegisterAccountClosed:        fee(),
+		RegisterAccountOpen:          fee(),
+		TransferAccountClosed:        fee(),
+		TransferAccountOpen:          fee(),
+		ReplaceAccountResources:      fee(),
+		AddAccountCertificate:        fee(),
+		DelAccountCertificate:        fee(),
+		SetAccountMetadata:           fee(),
+		RegisterDomain1:              fee(),
+		RegisterDomain2:              fee(),
+		RegisterDomain3:              fee(),
+		RegisterDomain4:              fee(),
+		RegisterDomain5:              fee(),
+		RegisterDomainDefault:        fee(),
+		RegisterOpenDomainMultiplier: sdk.NewDec(int64(simulation.RandIntBetween(r, 1, 4))),
+		TransferDomainClosed:         fee(),
+		TransferDomainOpen:           fee(),
+		RenewDomainOpen:              fee(),
+		RenewDomainClosedBase:        fee(),
+		RenewDomainClosedPerAccount:  fee(),
+		RenewDomainClosedMax:         sdk.NewDec(int64(simulation.RandIntBetween(r, 100, 1000))),
+	}
+}
+
+// RandomReservedName generates a random exact reserved name, claimable by
+// one of the accounts half of the time
+func RandomReservedName(r *rand.Rand, accs []simulation.Account) types.ReservedName {
+	reserved := types.ReservedName{Name: simutil.RandName(r, 4, 16)}
+	if r.Intn(2) == 0 {
+		claimant, _ := simulation.RandomAcc(r, accs)
+		reserved.Claimant = claimant.Address
+	}
+	return reserved
+}
+
+// RandomReservedNames generates a random reserved names registry
+func RandomReservedNames(r *rand.Rand, accs []simulation.Account) types.ReservedNames {
+	n := r.Intn(5)
+	reserved := make(types.ReservedNames, 0, n)
+	names := make(map[string]struct{}, n)
+	for i := 0; i < n; i++ {
+		entry := RandomReservedName(r, accs)
+		if _, ok := names[entry.Name]; ok {
+			continue
+		}
+		names[entry.Name] = struct{}{}
+		reserved = append(reserved, entry)
+	}
+	return reserved
+}
+
+// RandomizedGenState generates a random GenesisState for the configuration module
+func RandomizedGenState(simState *module.SimulationState) {
+	configurer, _ := simulation.RandomAcc(simState.Rand, simState.Accounts)
+	genesis := GenesisState{
+		Config: RandomConfig(simState.Rand, configurer.Address),
+		Fees:   RandomFees(simState.Rand, sdk.DefaultBondDenom),
+	}
+	if reserved := RandomReservedNames(simState.Rand, simState.Accounts); len(reserved) != 0 {
+		genesis.ReservedNames = reserved
+	}
+	fmt.Printf("Selected randomly generated configuration parameters:\n%s\n", simState.Cdc.MustMarshalJSON(genesis.Config))
+	simState.GenState[types.ModuleName] = simState.Cdc.MustMarshalJSON(genesis)
+}
@@ -0,0 +1,144 @@
+package simulation
+
+import (
+	"math/rand"
+
+	"github.com/cosmos/cosmos-sdk/baseapp"
+	"github.com/cosmos/cosmos-sdk/codec"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/simulation"
+	simutil "github.com/iov-one/iovns/pkg/simulation"
+	"github.com/iov-one/iovns/x/configuration/types"
+)
+
+// Simulation operation weights constants
+const (
+	OpWeightMsgUpdateConfig = "op_weight_msg_update_config"
+	OpWeightMsgUpdateFees   = "op_weight_msg_update_fees"
+
+	OpWeightMsgAddReservedName    = "op_weight_msg_add_reserved_name"
+	OpWeightMsgRemoveReservedName = "op_weight_msg_remove_reserved_name"
+)
+
+// Default simulation operation weights
+const (
+	DefaultWeightMsgUpdateConfig = 5
+	DefaultWeightMsgUpdateFees   = 5
+
+	DefaultWeightMsgAddReservedName    = 5
+	DefaultWeightMsgRemoveReservedName = 3
+)
+
+// Keeper defines the configuration keeper functionalities required by simulations
+type Keeper interface {
+	GetConfiguration(ctx sdk.Context) types.Config
+	GetFees(ctx sdk.Context) *types.Fees
+	GetReservedNames(ctx sdk.Context) types.ReservedNames
+}
+
+// WeightedOperations returns all the operations from the module with their respective weights
+func WeightedOperations(appParams simulation.AppParams, cdc *codec.Codec, ak simutil.AccountKeeper, k Keeper) simulation.WeightedOperations {
+	var weightMsgUpdateConfig, weightMsgUpdateFees, weightMsgAddReservedName, weightMsgRemoveReservedName int
+	appParams.GetOrGenerate(cdc, OpWeightMsgUpdateConfig, &weightMsgUpdateConfig, nil,
+		func(_ *rand.Rand) { weightMsgUpdateConfig = DefaultWeightMsgUpdateConfig },
+	)
+	appParams.GetOrGenerate(cdc, OpWeightMsgUpdateFees, &weightMsgUpdateFees, nil,
+		func(_ *rand.Rand) { weightMsgUpdateFees = DefaultWeightMsgUpdateFees },
+	)
+	appParams.GetOrGenerate(cdc, OpWeightMsgAddReservedName, &weightMsgAddReservedName, nil,
+		func(_ *rand.Rand) { weightMsgAddReservedName = DefaultWeightMsgAddReservedName },
+	)
+	appParams.GetOrGenerate(cdc, OpWeightMsgRemoveReservedName, &weightMsgRemoveReservedName, nil,
+		func(_ *rand.Rand) { weightMsgRemoveReservedName = DefaultWeightMsgRemoveReservedName },
+	)
+	return simulation.WeightedOperations{
+		simulation.NewWeightedOperation(weightMsgUpdateConfig, SimulateMsgUpdateConfig(ak, k)),
+		simulation.NewWeightedOperation(weightMsgUpdateFees, SimulateMsgUpdateFees(ak, k)),
+		simulation.NewWeightedOperation(weightMsgAddReservedName, SimulateMsgAddReservedName(ak, k)),
+		simulation.NewWeightedOperation(weightMsgRemoveReservedName, SimulateMsgRemoveReservedName(ak, k)),
+	}
+}
+
+// SimulateMsgUpdateConfig updates the configuration with random periods and limits,
+// limits are never lowered so that existing accounts keep respecting them
+func SimulateMsgUpdateConfig(ak simutil.AccountKeeper, k Keeper) simulation.Operation {
+	return func(r *rand.Rand, app *baseapp.BaseApp, ctx sdk.Context, accs []simulation.Account, chainID string,
+	) (simulation.OperationMsg, []simulation.FutureOperation, error) {
+		current := k.GetConfiguration(ctx)
+		conf := RandomConfig(r, current.Configurer)
+		conf.ValidDomainName = current.ValidDomainName
+		conf.ValidAccountName = current.ValidAccountName
+		conf.ValidURI = current.ValidURI
+		conf.ValidResource = current.ValidResource
+		if conf.ResourcesMax < current.ResourcesMax {
+			conf.ResourcesMax = current.ResourcesMax
+		}
+		if conf.CertificateCountMax < current.CertificateCountMax {
+			conf.CertificateCountMax = current.CertificateCountMax
+		}
+		if conf.CertificateSizeMax < current.CertificateSizeMax {
+			conf.CertificateSizeMax = current.CertificateSizeMax
+		}
+		msg := types.MsgUpdateConfig{
+			Signer:           current.Configurer,
+			NewConfiguration: conf,
+		}
+		delivered, err := simutil.DeliverMsg(app, ctx, ak, accs, msg, chainID)
+		if err != nil || !delivered {
+			return simulation.NoOpMsg(types.ModuleName), nil, err
+		}
+		return simulation.NewOperationMsg(msg, true, ""), nil, nil
+	}
+}
+
+// SimulateMsgUpdateFees updates the fees with random values in the current fee denom
+func SimulateMsgUpdateFees(ak simutil.AccountKeeper, k Keeper) simulation.Operation {
+	return func(r *rand.Rand, app *baseapp.BaseApp, ctx sdk.Context, accs []simulation.Account, chainID string,
+	) (simulation.OperationMsg, []simulation.FutureOperation, error) {
+		msg := types.MsgUpdateFees{
+			Fees:       RandomFees(r, k.GetFees(ctx).FeeCoinDenom),
+			Configurer: k.GetConfiguration(ctx).Configurer,
+		}
+		delivered, err := simutil.DeliverMsg(app, ctx, ak, accs, msg, chainID)
+		if err != nil || !delivered {
+			return simulation.NoOpMsg(types.ModuleName), nil, err
+		}
+		return simulation.NewOperationMsg(msg, true, ""), nil, nil
+	}
+}
+
+// SimulateMsgAddReservedName reserves a random exact name, optionally for a claimant
+func SimulateMsgAddReservedName(ak simutil.AccountKeeper, k Keeper) simulation.Operation {
+	return func(r *rand.Rand, app *baseapp.BaseApp, ctx sdk.Context, accs []simulation.Account, chainID string,
+	) (simulation.OperationMsg, []simulation.FutureOperation, error) {
+		msg := types.MsgAddReservedName{
+			ReservedName: RandomReservedName(r, accs),
+			Configurer:   k.GetConfiguration(ctx).Configurer,
+		}
+		delivered, err := simutil.DeliverMsg(app, ctx, ak, accs, msg, chainID)
+		if err != nil || !delivered {
+			return simulation.NoOpMsg(types.ModuleName), nil, err
+		}
+		return simulation.NewOperationMsg(msg, true, ""), nil, nil
+	}
+}
+
+// SimulateMsgRemoveReservedName removes a random entry of the reserved names registry
+func SimulateMsgRemoveReservedName(ak simutil.AccountKeeper, k Keeper) simulation.Operation {
+	return func(r *rand.Rand, app *baseapp.BaseApp, ctx sdk.Context, accs []simulation.Account, chainID string,
+	) (simulation.OperationMsg, []simulation.FutureOperation, error) {
+		reserved := k.GetReservedNames(ctx)
+		if len(reserved) == 0 {
+			return simulation.NoOpMsg(types.ModuleName), nil, nil
+		}
+		msg := types.MsgRemoveReservedName{
+			Name:       reserved[r.Intn(len(reserved))].Name,
+			Configurer: k.GetConfiguration(ctx).Configurer,
+		}
+		delivered, err := simutil.DeliverMsg(app, ctx, ak, accs, msg, chainID)
+		if err != nil || !delivered {
+			return simulation.NoOpMsg(types.ModuleName), nil, err
+		}
+		return simulation.NewOperationMsg(msg, true, ""), nil, nil
+	}
+}
@@ -9,6 +9,8 @@ import (
 func RegisterCodec(cdc *codec.Codec) {
 	cdc.RegisterConcrete(MsgUpdateFees{}, fmt.Sprintf("%s/MsgUpdateFees", ModuleName), nil)
 	cdc.RegisterConcrete(MsgUpdateConfig{}, fmt.Sprintf("%s/MsgUpdateConfig", ModuleName), nil)
+	cdc.RegisterConcrete(MsgAddReservedName{}, fmt.Sprintf("%s/MsgAddReservedName", ModuleName), nil)
+	cdc.RegisterConcrete(MsgRemoveReservedName{}, fmt.Sprintf("%s/MsgRemoveReservedName", ModuleName), nil)
 }
 
 // ModuleCdc defines the module codec
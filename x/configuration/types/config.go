@@ -43,6 +43,9 @@ type Config struct {
 	CertificateCountMax uint32 `json:"certificate_count_max"`
 	// MetadataSizeMax defines maximum size of metadata that could be saved under an account
 	MetadataSizeMax uint64 `json:"metadata_size_max"`
+	// HistoryRetention defines how long the starname history records are kept,
+	// the history is not recorded when it is zero
+	HistoryRetention time.Duration `json:"history_retention"`
 }
 
 func (c Config) Validate() error {
@@ -61,6 +64,9 @@ func (c Config) Validate() error {
 	if c.AccountGracePeriod < 0 {
 		return fmt.Errorf("empty account grace period")
 	}
+	if c.HistoryRetention < 0 {
+		return fmt.Errorf("negative history retention")
+	}
 	if _, err := regexp.Compile(c.ValidAccountName); err != nil {
 		return err
 	}
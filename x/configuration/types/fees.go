@@ -61,6 +61,12 @@ type Fees struct {
 	TransferDomainOpen sdk.Dec `json:"transfer_domain_open"`
 	// RenewDomainOpen is the fee to be paid to renew an open domain
 	RenewDomainOpen sdk.Dec `json:"renew_domain_open"`
+	// RenewDomainClosedBase is the fee to be paid to renew a closed domain regardless of its accounts
+	RenewDomainClosedBase sdk.Dec `json:"renew_domain_closed_base"`
+	// RenewDomainClosedPerAccount is the fee added for each account registered in the closed domain being renewed
+	RenewDomainClosedPerAccount sdk.Dec `json:"renew_domain_closed_per_account"`
+	// RenewDomainClosedMax is the maximum fee to be paid to renew a closed domain
+	RenewDomainClosedMax sdk.Dec `json:"renew_domain_closed_max"`
 }
 
 // Validate validates the fee object
@@ -89,6 +95,9 @@ func (f *Fees) Validate() error {
 			panic(fmt.Sprintf("invalid type: %T", fee))
 		}
 	}
+	if f.RenewDomainClosedMax.LT(f.RenewDomainClosedBase) {
+		return fmt.Errorf("renew domain closed max %s is lower than its base %s", f.RenewDomainClosedMax, f.RenewDomainClosedBase)
+	}
 	return nil
 }
 
@@ -124,5 +133,8 @@ func (f *Fees) SetDefaults(denom string) {
 		TransferDomainClosed:         defaultFeeParameter,
 		TransferDomainOpen:           defaultFeeParameter,
 		RenewDomainOpen:              defaultFeeParameter,
+		RenewDomainClosedBase:        defaultFeeParameter,
+		RenewDomainClosedPerAccount:  defaultFeeParameter,
+		RenewDomainClosedMax:         defaultFeeParameter.MulInt64(100),
 	}
 }
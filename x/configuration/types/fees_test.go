@@ -29,6 +29,9 @@ func TestFees_Validate(t *testing.T) {
 		TransferDomainClosed         types.Dec
 		TransferDomainOpen           types.Dec
 		RenewDomainOpen              types.Dec
+		RenewDomainClosedBase        types.Dec
+		RenewDomainClosedPerAccount  types.Dec
+		RenewDomainClosedMax         types.Dec
 	}
 	tests := []struct {
 		name    string
@@ -44,6 +47,16 @@ func TestFees_Validate(t *testing.T) {
 			}(),
 			wantErr: false,
 		},
+		{
+			name: "fail renew domain closed max lower than base",
+			fields: func() fields {
+				fees := NewFees()
+				fees.SetDefaults("test")
+				fees.RenewDomainClosedMax = fees.RenewDomainClosedBase.QuoInt64(2)
+				return fields(*fees)
+			}(),
+			wantErr: true,
+		},
 		{
 			name:    "fail missing fee",
 			fields:  fields{},
@@ -74,6 +87,9 @@ func TestFees_Validate(t *testing.T) {
 				TransferDomainClosed:         tt.fields.TransferDomainClosed,
 				TransferDomainOpen:           tt.fields.TransferDomainOpen,
 				RenewDomainOpen:              tt.fields.RenewDomainOpen,
+				RenewDomainClosedBase:        tt.fields.RenewDomainClosedBase,
+				RenewDomainClosedPerAccount:  tt.fields.RenewDomainClosedPerAccount,
+				RenewDomainClosedMax:         tt.fields.RenewDomainClosedMax,
 			}
 			if err := f.Validate(); (err != nil) != tt.wantErr {
 				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
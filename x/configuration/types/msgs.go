@@ -70,3 +70,75 @@ func (m MsgUpdateFees) GetSignBytes() []byte { return sdk.MustSortJSON(ModuleCdc
 
 // GetSigners implements sdk.Msg
 func (m MsgUpdateFees) GetSigners() []sdk.AccAddress { return []sdk.AccAddress{m.Configurer} }
+
+// MsgAddReservedName is used by the configurer to add
+// or replace an entry of the reserved names registry
+type MsgAddReservedName struct {
+	// ReservedName is the entry to add to the registry
+	ReservedName ReservedName
+	// Configurer is the address that is signing the message
+	Configurer sdk.AccAddress
+}
+
+var _ sdk.Msg = (*MsgAddReservedName)(nil)
+
+// Route implements sdk.Msg
+func (m MsgAddReservedName) Route() string { return RouterKey }
+
+// Type implements sdk.Msg
+func (m MsgAddReservedName) Type() string { return "add_reserved_name" }
+
+// ValidateBasic implements sdk.Msg
+func (m MsgAddReservedName) ValidateBasic() error {
+	if m.Configurer.Empty() {
+		return sdkerrors.Wrapf(sdkerrors.ErrInvalidRequest, "signer is missing")
+	}
+	if err := m.ReservedName.Validate(); err != nil {
+		return sdkerrors.Wrap(sdkerrors.ErrInvalidRequest, err.Error())
+	}
+	return nil
+}
+
+// GetSignBytes implements sdk.Msg
+func (m MsgAddReservedName) GetSignBytes() []byte {
+	return sdk.MustSortJSON(ModuleCdc.MustMarshalJSON(m))
+}
+
+// GetSigners implements sdk.Msg
+func (m MsgAddReservedName) GetSigners() []sdk.AccAddress { return []sdk.AccAddress{m.Configurer} }
+
+// MsgRemoveReservedName is used by the configurer
+// to remove an entry of the reserved names registry
+type MsgRemoveReservedName struct {
+	// Name is the exact name or pattern of the entry to remove
+	Name string
+	// Configurer is the address that is signing the message
+	Configurer sdk.AccAddress
+}
+
+var _ sdk.Msg = (*MsgRemoveReservedName)(nil)
+
+// Route implements sdk.Msg
+func (m MsgRemoveReservedName) Route() string { return RouterKey }
+
+// Type implements sdk.Msg
+func (m MsgRemoveReservedName) Type() string { return "remove_reserved_name" }
+
+// ValidateBasic implements sdk.Msg
+func (m MsgRemoveReservedName) ValidateBasic() error {
+	if m.Configurer.Empty() {
+		return sdkerrors.Wrapf(sdkerrors.ErrInvalidRequest, "signer is missing")
+	}
+	if m.Name == "" {
+		return sdkerrors.Wrapf(sdkerrors.ErrInvalidRequest, "empty reserved name")
+	}
+	return nil
+}
+
+// GetSignBytes implements sdk.Msg
+func (m MsgRemoveReservedName) GetSignBytes() []byte {
+	return sdk.MustSortJSON(ModuleCdc.MustMarshalJSON(m))
+}
+
+// GetSigners implements sdk.Msg
+func (m MsgRemoveReservedName) GetSigners() []sdk.AccAddress { return []sdk.AccAddress{m.Configurer} }
@@ -20,6 +20,9 @@ const QueryConfig = "configuration"
 // QueryFees is the route key used to query fees data
 const QueryFees = "fees"
 
+// QueryReservedNames is the route key used to query the reserved names registry
+const QueryReservedNames = "reservedNames"
+
 const (
 	// ConfigKey defines the key used for the configuration
 	// since the configuration is only one the key will always be one
@@ -29,4 +32,19 @@ const (
 	// since the fee params are only one
 	// this is the only key we will need
 	FeeKey = "fee"
+
+	// VersionKey defines the key used for the store version
+	VersionKey = "version"
+
+	// ReservedNamePrefix defines the prefix of the keys used for reserved names,
+	// which are stored under the prefix followed by the reserved name itself
+	ReservedNamePrefix = "reserved/"
+
+	// ReservedSkeletonPrefix defines the prefix of the index of the exact reserved names,
+	// keys are the prefix followed by the skeleton of the name, a slash and the name
+	ReservedSkeletonPrefix = "reserved-skeleton/"
+
+	// ReservedPatternPrefix defines the prefix of the index of the reserved name
+	// patterns, keys are the prefix followed by the pattern
+	ReservedPatternPrefix = "reserved-pattern/"
 )
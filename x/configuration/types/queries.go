@@ -9,3 +9,8 @@ type QueryConfigResponse struct {
 type QueryFeesResponse struct {
 	Fees *Fees `json:"fees"`
 }
+
+// QueryReservedNamesResponse is the result returned after a query to the reserved names registry
+type QueryReservedNamesResponse struct {
+	ReservedNames ReservedNames `json:"reserved_names"`
+}
@@ -0,0 +1,153 @@
+package types
+
+import (
+	"fmt"
+	"regexp"
+	"sync"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/iov-one/iovns/pkg/idn"
+)
+
+// ReservedName is an entry of the reserved names registry, names matching
+// it can't be registered as domain or account names unless by its claimant
+type ReservedName struct {
+	// Name is either the exact reserved name or a regexp if Pattern is set
+	Name string `json:"name"`
+	// Pattern defines if Name is a regexp matching the reserved names
+	Pattern bool `json:"pattern"`
+	// Claimant is the address allowed to register the reserved name,
+	// if empty the name is blocked for everyone
+	Claimant sdk.AccAddress `json:"claimant"`
+	// compiled is the compiled regexp of patterns, set by Compile
+	compiled *regexp.Regexp
+}
+
+// Validate checks that the reserved name is not empty and that patterns compile
+func (r ReservedName) Validate() error {
+	_, err := r.Compile()
+	return err
+}
+
+// Compile returns the entry with its pattern compiled, so that Matches does not
+// compile it again, it fails if the entry is not valid
+func (r ReservedName) Compile() (ReservedName, error) {
+	if r.Name == "" {
+		return ReservedName{}, fmt.Errorf("empty reserved name")
+	}
+	if !r.Pattern || r.compiled != nil {
+		return r, nil
+	}
+	compiled, err := regexp.Compile(r.Name)
+	if err != nil {
+		return ReservedName{}, fmt.Errorf("invalid reserved name pattern %s: %w", r.Name, err)
+	}
+	r.compiled = compiled
+	return r, nil
+}
+
+// Matches checks if the name is reserved by the entry, exact entries also reserve
+// the names which are confusable with them, patterns which were not compiled
+// with Compile are compiled on every call
+func (r ReservedName) Matches(name string) bool {
+	if !r.Pattern {
+		return idn.Confusable(r.Name, name)
+	}
+	if r.compiled == nil {
+		return regexp.MustCompile(r.Name).MatchString(name)
+	}
+	return r.compiled.MatchString(name)
+}
+
+// PatternCache keeps the compiled patterns of the reserved names registry so
+// that they are compiled once, it is safe for concurrent use
+type PatternCache struct {
+	mu       sync.RWMutex
+	compiled map[string]*regexp.Regexp
+}
+
+// NewPatternCache returns an empty PatternCache
+func NewPatternCache() *PatternCache {
+	return &PatternCache{compiled: make(map[string]*regexp.Regexp)}
+}
+
+// Compile returns the entry with its pattern compiled like ReservedName.Compile
+// does, reusing the regexp of the same pattern if it was compiled before
+func (c *PatternCache) Compile(r ReservedName) (ReservedName, error) {
+	if !r.Pattern || r.compiled != nil {
+		return r.Compile()
+	}
+	c.mu.RLock()
+	compiled, ok := c.compiled[r.Name]
+	c.mu.RUnlock()
+	if ok {
+		r.compiled = compiled
+		return r, nil
+	}
+	r, err := r.Compile()
+	if err != nil {
+		return ReservedName{}, err
+	}
+	c.mu.Lock()
+	c.compiled[r.Name] = r.compiled
+	c.mu.Unlock()
+	return r, nil
+}
+
+// ClaimableBy checks if addr is allowed to register the names reserved by the entry
+func (r ReservedName) ClaimableBy(addr sdk.AccAddress) bool {
+	return !r.Claimant.Empty() && r.Claimant.Equals(addr)
+}
+
+// ReservedNames is the reserved names registry
+type ReservedNames []ReservedName
+
+// Compile returns the entries with their patterns compiled, see ReservedName.Compile
+func (r ReservedNames) Compile() (ReservedNames, error) {
+	compiled := make(ReservedNames, len(r))
+	for i, entry := range r {
+		c, err := entry.Compile()
+		if err != nil {
+			return nil, err
+		}
+		compiled[i] = c
+	}
+	return compiled, nil
+}
+
+// Blocking returns the first entry which reserves the name
+// for someone other than claimant, if any
+func (r ReservedNames) Blocking(name string, claimant sdk.AccAddress) (ReservedName, bool) {
+	for _, entry := range r {
+		if entry.Matches(name) && !entry.ClaimableBy(claimant) {
+			return entry, true
+		}
+	}
+	return ReservedName{}, false
+}
+
+// Matching returns the entries which reserve the name
+func (r ReservedNames) Matching(name string) ReservedNames {
+	matching := ReservedNames{}
+	for _, entry := range r {
+		if entry.Matches(name) {
+			matching = append(matching, entry)
+		}
+	}
+	return matching
+}
+
+// Validate checks every entry of the registry and that no name is declared twice
+func (r ReservedNames) Validate() error {
+	names := make(map[string]struct{}, len(r))
+	for _, entry := range r {
+		if err := entry.Validate(); err != nil {
+			return err
+		}
+		if _, ok := names[entry.Name]; ok {
+			return fmt.Errorf("reserved name %s declared twice", entry.Name)
+		}
+		names[entry.Name] = struct{}{}
+	}
+	return nil
+}
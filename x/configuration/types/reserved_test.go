@@ -0,0 +1,87 @@
+package types
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+func TestReservedNames_Blocking(t *testing.T) {
+	claimant := sdk.AccAddress("claimant")
+	reserved := ReservedNames{
+		{Name: "admin"},
+		{Name: "iov", Claimant: claimant},
+		{Name: "^star.*$", Pattern: true},
+	}
+	compiled, err := reserved.Compile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := map[string]struct {
+		name     string
+		claimant sdk.AccAddress
+		blocked  bool
+	}{
+		"not reserved":                 {name: "alice", blocked: false},
+		"blocked exact name":           {name: "admin", claimant: claimant, blocked: true},
+		"reserved for claimant":        {name: "iov", claimant: claimant, blocked: false},
+		"reserved for someone else":    {name: "iov", claimant: sdk.AccAddress("other"), blocked: true},
+		"reserved without claimant":    {name: "iov", blocked: true},
+		"blocked pattern":              {name: "starname", blocked: true},
+		"exact names are not patterns": {name: "iovns", blocked: false},
+		"blocked confusable name":      {name: "аdmin", blocked: true},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			if _, blocked := reserved.Blocking(c.name, c.claimant); blocked != c.blocked {
+				t.Fatalf("want blocked: %t, got: %t", c.blocked, blocked)
+			}
+			if _, blocked := compiled.Blocking(c.name, c.claimant); blocked != c.blocked {
+				t.Fatalf("compiled: want blocked: %t, got: %t", c.blocked, blocked)
+			}
+		})
+	}
+}
+
+func TestReservedNames_Compile(t *testing.T) {
+	compiled, err := (ReservedNames{{Name: "admin"}, {Name: "^star.*$", Pattern: true}}).Compile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if compiled[0].compiled != nil || compiled[1].compiled == nil {
+		t.Fatalf("unexpected compiled entries: %+v", compiled)
+	}
+	if _, err := (ReservedNames{{Name: "(", Pattern: true}}).Compile(); err == nil {
+		t.Fatal("invalid patterns should not compile")
+	}
+}
+
+func TestPatternCache_Compile(t *testing.T) {
+	cache := NewPatternCache()
+	first, err := cache.Compile(ReservedName{Name: "^star.*$", Pattern: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := cache.Compile(ReservedName{Name: "^star.*$", Pattern: true, Claimant: sdk.AccAddress("claimant")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.compiled == nil || first.compiled != second.compiled {
+		t.Fatal("the pattern was compiled twice")
+	}
+	if _, err := cache.Compile(ReservedName{Name: "(", Pattern: true}); err == nil {
+		t.Fatal("invalid patterns should not compile")
+	}
+}
+
+func TestReservedNames_Validate(t *testing.T) {
+	if err := (ReservedNames{{Name: "admin"}, {Name: "admin", Pattern: true}}).Validate(); err == nil {
+		t.Fatal("duplicate names should be invalid")
+	}
+	if err := (ReservedNames{{Name: "(", Pattern: true}}).Validate(); err == nil {
+		t.Fatal("invalid patterns should be invalid")
+	}
+	if err := (ReservedNames{{Name: ""}}).Validate(); err == nil {
+		t.Fatal("empty names should be invalid")
+	}
+}
@@ -24,6 +24,11 @@ const DefaultChainID = "signed-message-v1"
 const DefaultAccountNumber uint64 = 0
 const DefaultSequence uint64 = 0
 
+const flagStarname = "starname"
+const flagCoSigner = "co-signer"
+const flagDomainName = "domain-name"
+const flagDomainChainID = "domain-chain-id"
+
 // getTxCmd clubs together all the CLI tx commands
 func getTxCmd(storeKey string, cdc *codec.Codec) *cobra.Command {
 	configTxCmd := &cobra.Command{
@@ -37,6 +42,9 @@ func getTxCmd(storeKey string, cdc *codec.Codec) *cobra.Command {
 	configTxCmd.AddCommand(flags.PostCommands(
 		signCmd(cdc),
 		verifyCmd(cdc),
+		signTypedDataCmd(cdc),
+		renderTypedDataCmd(),
+		verifyTypedDataCmd(cdc),
 	)...)
 	return configTxCmd
 }
@@ -66,11 +74,25 @@ func signCmd(cdc *codec.Codec) *cobra.Command {
 			if (text != "") && (file != "" || len(pairs) != 0) || (file != "" && len(pairs) != 0) {
 				return fmt.Errorf("only one of text, file, pairs can be specified")
 			}
+			coSigners, err := cmd.Flags().GetStringArray(flagCoSigner)
+			if err != nil {
+				return err
+			}
+			if len(coSigners) != 0 && !cliCtx.GenerateOnly {
+				return fmt.Errorf("co-signers sign the generated transaction offline, use --%s", flags.FlagGenerateOnly)
+			}
 			msg := MsgSignText{
 				Message: "",
 				Pairs:   nil,
 				Signer:  cliCtx.GetFromAddress(),
 			}
+			for _, raw := range coSigners {
+				coSigner, err := sdk.AccAddressFromBech32(raw)
+				if err != nil {
+					return fmt.Errorf("invalid co-signer %s: %s", raw, err)
+				}
+				msg.CoSigners = append(msg.CoSigners, coSigner)
+			}
 			switch true {
 			case text != "":
 				msg.Message = text
@@ -114,6 +136,7 @@ func signCmd(cdc *codec.Codec) *cobra.Command {
 	cmd.Flags().StringP("file", "f", "", "file to signCmd")
 	cmd.Flags().StringP("text", "t", "", "string to signCmd")
 	cmd.Flags().StringArrayP("pair", "p", nil, "key value pairs, specified as key=value")
+	cmd.Flags().StringArray(flagCoSigner, nil, "address signing the text along with the signer, signatures must follow the order of the co-signers")
 	return cmd
 }
 
@@ -137,26 +160,31 @@ func verifyCmd(cdc *codec.Codec) *cobra.Command {
 			if err != nil {
 				return err
 			}
-			chainID, err := cmd.Flags().GetString(flags.FlagChainID)
-			if err != nil {
-				return err
-			}
-			if chainID == "" {
-				chainID = DefaultChainID
-			}
-			accountNumber, err := cmd.Flags().GetUint64(flags.FlagAccountNumber)
+			chainID, accountNumber, sequence, err := signatureParams(cmd)
 			if err != nil {
 				return err
 			}
-			if accountNumber == 0 {
-				accountNumber = DefaultAccountNumber
-			}
-			sequence, err := cmd.Flags().GetUint64(flags.FlagSequence)
+			starname, err := cmd.Flags().GetString(flagStarname)
 			if err != nil {
 				return err
 			}
-			if sequence == 0 {
-				sequence = DefaultSequence
+			if starname != "" {
+				height, err := cmd.Flags().GetInt64(flags.FlagHeight)
+				if err != nil {
+					return err
+				}
+				cliCtx := context.NewCLIContext().WithCodec(cdc)
+				res, err := VerifyStarname(NewQuerier(cliCtx), tx, starname, height, chainID, accountNumber, sequence)
+				if err != nil {
+					return err
+				}
+				if err := cliCtx.PrintOutput(res); err != nil {
+					return err
+				}
+				if !res.Verified {
+					return fmt.Errorf("signer %s is not the owner of %s", res.Signer, starname)
+				}
+				return nil
 			}
 			if err = Verify(tx, chainID, accountNumber, sequence); err != nil {
 				return err
@@ -185,10 +213,168 @@ func verifyCmd(cdc *codec.Codec) *cobra.Command {
 					return err
 				}
 				fmt.Fprintf(cmd.OutOrStdout(), "signer: %s\nmessage: %s\n", msg.Signer, msg.Message)
+				for _, coSigner := range msg.CoSigners {
+					fmt.Fprintf(cmd.OutOrStdout(), "co-signer: %s\n", coSigner)
+				}
 			}
 			return nil
 		},
 	}
 	cmd.Flags().StringP("file", "f", "", "signed transaction file")
+	cmd.Flags().String(flagStarname, "", "starname the signer must own, e.g. name*domain")
+	cmd.Flags().Int64(flags.FlagHeight, 0, "height to resolve the starname at, defaults to the latest height")
+	return cmd
+}
+
+// signatureParams returns the chain id, account number and sequence the signature
+// is verified with, which default to the signutil ones
+func signatureParams(cmd *cobra.Command) (string, uint64, uint64, error) {
+	chainID, err := cmd.Flags().GetString(flags.FlagChainID)
+	if err != nil {
+		return "", 0, 0, err
+	}
+	if chainID == "" {
+		chainID = DefaultChainID
+	}
+	accountNumber, err := cmd.Flags().GetUint64(flags.FlagAccountNumber)
+	if err != nil {
+		return "", 0, 0, err
+	}
+	if accountNumber == 0 {
+		accountNumber = DefaultAccountNumber
+	}
+	sequence, err := cmd.Flags().GetUint64(flags.FlagSequence)
+	if err != nil {
+		return "", 0, 0, err
+	}
+	if sequence == 0 {
+		sequence = DefaultSequence
+	}
+	return chainID, accountNumber, sequence, nil
+}
+
+// readTypedData reads the typed data of the file given by the file flag
+func readTypedData(cmd *cobra.Command) (TypedData, error) {
+	path, err := cmd.Flags().GetString("file")
+	if err != nil {
+		return TypedData{}, err
+	}
+	if path == "" {
+		return TypedData{}, fmt.Errorf("file flag must be specified")
+	}
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return TypedData{}, err
+	}
+	return ParseTypedData(b)
+}
+
+// printJSON prints the value as indented JSON
+func printJSON(cmd *cobra.Command, v interface{}) error {
+	bin, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(cmd.OutOrStdout(), string(bin))
+	return nil
+}
+
+func signTypedDataCmd(cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "create-typed",
+		Short: "outputs the json string to sign the typed data of a file",
+		Long: `outputs the json string to sign the typed data of a file, which declares the types, the primary type,
+the domain and the message of the primary type, e.g.
+{
+  "types": [{"name": "Order", "fields": [{"name": "id", "type": "string"}, {"name": "amounts", "type": "uint64[]"}]}],
+  "primary_type": "Order",
+  "domain": {"name": "app", "version": "1", "chain_id": "iov-mainnet-ibc"},
+  "message": {"id": "order-1", "amounts": ["10", "20"]}
+}
+the atomic types are string, bool, int64, uint64, address and bytes (base64), a type followed by [] is an array`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+			inBuf := bufio.NewReader(cmd.InOrStdin())
+			txBuilder := auth.NewTxBuilderFromCLI(inBuf).WithTxEncoder(utils.GetTxEncoder(cdc))
+			data, err := readTypedData(cmd)
+			if err != nil {
+				return err
+			}
+			msg := MsgSignTypedData{
+				TypedData: data,
+				Signer:    cliCtx.GetFromAddress(),
+			}
+			if err := msg.ValidateBasic(); err != nil {
+				return err
+			}
+			return utils.GenerateOrBroadcastMsgs(cliCtx, txBuilder, []sdk.Msg{msg})
+		},
+	}
+	cmd.Flags().StringP("file", "f", "", "typed data file")
+	return cmd
+}
+
+func renderTypedDataCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "render-typed",
+		Short: "renders the typed data of a file with its encoding and digest",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			data, err := readTypedData(cmd)
+			if err != nil {
+				return err
+			}
+			rendering, err := data.Render()
+			if err != nil {
+				return err
+			}
+			return printJSON(cmd, rendering)
+		},
+	}
+	cmd.Flags().StringP("file", "f", "", "typed data file")
+	return cmd
+}
+
+func verifyTypedDataCmd(cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "verify-typed",
+		Short: "verify a typed data signature from a file",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, err := cmd.Flags().GetString("file")
+			if err != nil {
+				return err
+			}
+			b, err := ioutil.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			var tx auth.StdTx
+			if err := cdc.UnmarshalJSON(b, &tx); err != nil {
+				return err
+			}
+			chainID, accountNumber, sequence, err := signatureParams(cmd)
+			if err != nil {
+				return err
+			}
+			res, err := VerifyTypedData(tx, chainID, accountNumber, sequence)
+			if err != nil {
+				return err
+			}
+			domainName, err := cmd.Flags().GetString(flagDomainName)
+			if err != nil {
+				return err
+			}
+			domainChainID, err := cmd.Flags().GetString(flagDomainChainID)
+			if err != nil {
+				return err
+			}
+			if err := res.CheckDomain(domainName, domainChainID); err != nil {
+				return err
+			}
+			return printJSON(cmd, res)
+		},
+	}
+	cmd.Flags().StringP("file", "f", "", "signed transaction file")
+	cmd.Flags().String(flagDomainName, "", "application name the typed data must be signed for, required")
+	cmd.Flags().String(flagDomainChainID, "", "chain id the typed data must be signed for, required")
 	return cmd
 }
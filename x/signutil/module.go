@@ -23,6 +23,7 @@ func init() {
 
 func RegisterCodec(cdc *codec.Codec) {
 	cdc.RegisterConcrete(MsgSignText{}, fmt.Sprintf("%s/%s", ModuleName, "MsgSignText"), nil)
+	cdc.RegisterConcrete(MsgSignTypedData{}, fmt.Sprintf("%s/%s", ModuleName, "MsgSignTypedData"), nil)
 }
 
 var (
@@ -1,9 +1,11 @@
 package signutil
 
 import (
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"strconv"
 
 	"github.com/cosmos/cosmos-sdk/client/context"
 	"github.com/cosmos/cosmos-sdk/x/auth"
@@ -11,10 +13,11 @@ import (
 )
 
 type Success struct {
-	Message  string `json:"message"`
-	Signer   string `json:"signer"`
-	Verified bool   `json:"verified"`
-	Signed   string `json:"signed"`
+	Message   string   `json:"message"`
+	Signer    string   `json:"signer"`
+	CoSigners []string `json:"co_signers,omitempty"`
+	Verified  bool     `json:"verified"`
+	Signed    string   `json:"signed"`
 }
 
 type Error struct { // TODO: use the sdk's REST utils
@@ -24,6 +27,8 @@ type Error struct { // TODO: use the sdk's REST utils
 
 func RegisterRestRoutes(ctx context.CLIContext, r *mux.Router) {
 	cdc := ctx.Codec
+	r.HandleFunc(fmt.Sprintf("/%s/query/typed/render", ModuleName), renderTypedDataHandler).Methods(http.MethodPost)
+	r.HandleFunc(fmt.Sprintf("/%s/query/typed/verify", ModuleName), verifyTypedDataHandler(ctx)).Methods(http.MethodPost)
 	r.HandleFunc(fmt.Sprintf("/%s/query/verify", ModuleName), func(writer http.ResponseWriter, request *http.Request) {
 		b, err := ioutil.ReadAll(request.Body)
 		if err != nil {
@@ -45,12 +50,17 @@ func RegisterRestRoutes(ctx context.CLIContext, r *mux.Router) {
 			return
 		}
 
+		if starname := request.URL.Query().Get(flagStarname); starname != "" {
+			verifyStarname(ctx, writer, request, req, starname)
+			return
+		}
+
 		err = Verify(req, DefaultChainID, DefaultAccountNumber, DefaultSequence)
 		if err != nil {
 			writer.WriteHeader(http.StatusUnauthorized)
 			_, _ = writer.Write(cdc.MustMarshalJSON(Error{ // TODO: log error on server
 				Code:   http.StatusUnauthorized,
-				Reason: fmt.Sprintf("Did you sign with --chain-id '%s', --account-number %d, and --sequence %d?", DefaultChainID, DefaultAccountNumber, DefaultSequence),
+				Reason: fmt.Sprintf("%s. Did you sign with --chain-id '%s', --account-number %d, and --sequence %d?", err, DefaultChainID, DefaultAccountNumber, DefaultSequence),
 			}))
 			return
 		}
@@ -76,14 +86,114 @@ func RegisterRestRoutes(ctx context.CLIContext, r *mux.Router) {
 			return
 		}
 
+		coSigners := make([]string, len(msg.CoSigners))
+		for i, coSigner := range msg.CoSigners {
+			coSigners[i] = coSigner.String()
+		}
+
 		// success
 		writer.WriteHeader(http.StatusOK) // TODO: unify response format with other successful REST responses in the starname module
 		writer.Header().Set("Content-Type", "application/json")
 		_, _ = writer.Write(cdc.MustMarshalJSON(Success{ // TODO: log success on server
-			Message:  msg.Message,
-			Signer:   msg.Signer.String(),
-			Verified: true,
-			Signed:   string(b[:]),
+			Message:   msg.Message,
+			Signer:    msg.Signer.String(),
+			CoSigners: coSigners,
+			Verified:  true,
+			Signed:    string(b[:]),
 		}))
 	})
 }
+
+// verifyStarname writes the verification of the signed transaction against the starname,
+// resolved at the height given by the height query parameter if any
+func verifyStarname(ctx context.CLIContext, writer http.ResponseWriter, request *http.Request, tx auth.StdTx, starname string) {
+	cdc := ctx.Codec
+	if h := request.URL.Query().Get("height"); h != "" {
+		height, err := strconv.ParseInt(h, 10, 64)
+		if err != nil || height < 0 {
+			writer.WriteHeader(http.StatusBadRequest)
+			_, _ = writer.Write(cdc.MustMarshalJSON(Error{
+				Code:   http.StatusBadRequest,
+				Reason: fmt.Sprintf("invalid height: %s", h),
+			}))
+			return
+		}
+		ctx = ctx.WithHeight(height)
+	}
+	res, err := VerifyStarname(NewQuerier(ctx), tx, starname, ctx.Height, DefaultChainID, DefaultAccountNumber, DefaultSequence)
+	if err != nil {
+		writer.WriteHeader(http.StatusUnauthorized)
+		_, _ = writer.Write(cdc.MustMarshalJSON(Error{
+			Code:   http.StatusUnauthorized,
+			Reason: err.Error(),
+		}))
+		return
+	}
+	writer.Header().Set("Content-Type", "application/json")
+	if !res.Verified {
+		writer.WriteHeader(http.StatusUnauthorized)
+	} else {
+		writer.WriteHeader(http.StatusOK)
+	}
+	_, _ = writer.Write(cdc.MustMarshalJSON(res))
+}
+
+// writeJSON writes the value as the JSON response
+func writeJSON(writer http.ResponseWriter, status int, v interface{}) {
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(status)
+	_ = json.NewEncoder(writer).Encode(v)
+}
+
+// renderTypedDataHandler renders the typed data of the request body with its encoding and digest
+func renderTypedDataHandler(writer http.ResponseWriter, request *http.Request) {
+	b, err := ioutil.ReadAll(request.Body)
+	if err != nil {
+		writeJSON(writer, http.StatusInternalServerError, Error{Code: http.StatusInternalServerError, Reason: err.Error()})
+		return
+	}
+	data, err := ParseTypedData(b)
+	if err != nil {
+		writeJSON(writer, http.StatusBadRequest, Error{Code: http.StatusBadRequest, Reason: err.Error()})
+		return
+	}
+	rendering, err := data.Render()
+	if err != nil {
+		writeJSON(writer, http.StatusBadRequest, Error{Code: http.StatusBadRequest, Reason: err.Error()})
+		return
+	}
+	writeJSON(writer, http.StatusOK, rendering)
+}
+
+// verifyTypedDataHandler verifies the typed data signed by the transaction of the request body,
+// the required domain_name and domain_chain_id query parameters check the domain of the typed data
+func verifyTypedDataHandler(ctx context.CLIContext) http.HandlerFunc {
+	return func(writer http.ResponseWriter, request *http.Request) {
+		query := request.URL.Query()
+		domainName, domainChainID := query.Get("domain_name"), query.Get("domain_chain_id")
+		if domainName == "" || domainChainID == "" {
+			writeJSON(writer, http.StatusBadRequest, Error{Code: http.StatusBadRequest, Reason: "domain_name and domain_chain_id are required"})
+			return
+		}
+		b, err := ioutil.ReadAll(request.Body)
+		if err != nil {
+			writeJSON(writer, http.StatusInternalServerError, Error{Code: http.StatusInternalServerError, Reason: err.Error()})
+			return
+		}
+		var tx auth.StdTx
+		if err := ctx.Codec.UnmarshalJSON(b, &tx); err != nil {
+			writeJSON(writer, http.StatusBadRequest, Error{Code: http.StatusBadRequest, Reason: err.Error()})
+			return
+		}
+		res, err := VerifyTypedData(tx, DefaultChainID, DefaultAccountNumber, DefaultSequence)
+		if err != nil {
+			writeJSON(writer, http.StatusUnauthorized, Error{Code: http.StatusUnauthorized, Reason: err.Error()})
+			return
+		}
+		if err := res.CheckDomain(domainName, domainChainID); err != nil {
+			writeJSON(writer, http.StatusUnauthorized, Error{Code: http.StatusUnauthorized, Reason: err.Error()})
+			return
+		}
+		writeJSON(writer, http.StatusOK, res)
+	}
+}
@@ -0,0 +1,142 @@
+package signutil
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/client/context"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/iov-one/iovns/pkg/queries"
+	"github.com/iov-one/iovns/pkg/utils"
+	"github.com/iov-one/iovns/x/starname/keeper"
+	"github.com/iov-one/iovns/x/starname/types"
+)
+
+// Querier queries the application state and the time of the blocks,
+// it is implemented for a context.CLIContext by NewQuerier
+type Querier interface {
+	// QueryAt runs the query on the state at height, the latest one if height is
+	// zero, and returns its result along with the height it was run at
+	QueryAt(path string, data []byte, height int64) ([]byte, int64, error)
+	// BlockTime returns the time of the block at height
+	BlockTime(height int64) (time.Time, error)
+}
+
+// cliQuerier implements Querier with a context.CLIContext
+type cliQuerier struct {
+	ctx context.CLIContext
+}
+
+// NewQuerier returns the Querier of the node of the context
+func NewQuerier(ctx context.CLIContext) Querier {
+	return cliQuerier{ctx: ctx}
+}
+
+func (q cliQuerier) QueryAt(path string, data []byte, height int64) ([]byte, int64, error) {
+	return q.ctx.WithHeight(height).QueryWithData(path, data)
+}
+
+func (q cliQuerier) BlockTime(height int64) (time.Time, error) {
+	node, err := q.ctx.GetNode()
+	if err != nil {
+		return time.Time{}, err
+	}
+	block, err := node.Block(&height)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return block.Block.Time, nil
+}
+
+// StarnameVerification is the result of the verification of a signed text against a starname
+type StarnameVerification struct {
+	Message  string `json:"message"`
+	Signer   string `json:"signer"`
+	Starname string `json:"starname"`
+	Owner    string `json:"owner"`
+	Height   int64  `json:"height"`
+	Verified bool   `json:"verified"`
+}
+
+// ResolveOwner returns the owner of the account starname resolved at height, the latest one
+// if height is zero, and the height it was resolved at, it fails if the account was expired
+// at the time of that block
+func ResolveOwner(querier Querier, starname string, height int64) (sdk.AccAddress, int64, error) {
+	q := &keeper.QueryResolveAccount{Starname: starname}
+	if err := q.Validate(); err != nil {
+		return nil, 0, err
+	}
+	var account keeper.QueryResolveAccountResponse
+	height, err := query(querier, q.QueryPath(), q, &account, height)
+	if err != nil {
+		return nil, 0, err
+	}
+	// the domain is resolved at the same height as the account
+	var domain keeper.QueryResolveDomainResponse
+	if _, err := query(querier, (&keeper.QueryResolveDomain{}).QueryPath(), &keeper.QueryResolveDomain{Name: q.Domain}, &domain, height); err != nil {
+		return nil, 0, err
+	}
+	blockTime, err := querier.BlockTime(height)
+	if err != nil {
+		return nil, 0, err
+	}
+	if expiration := expiration(domain.Domain, account.Account); expiration.Before(blockTime) {
+		return nil, 0, fmt.Errorf("starname %s expired at %s, before block %d", starname, expiration.UTC().Format(time.RFC3339), height)
+	}
+	return account.Account.Owner, height, nil
+}
+
+// query runs the starname query q with route at height and decodes its result in res
+func query(querier Querier, route string, q interface{}, res interface{}, height int64) (int64, error) {
+	b, err := queries.DefaultQueryEncode(q)
+	if err != nil {
+		return 0, err
+	}
+	value, height, err := querier.QueryAt(fmt.Sprintf("custom/%s/%s", types.QuerierRoute, route), b, height)
+	if err != nil {
+		return 0, err
+	}
+	return height, queries.DefaultQueryDecode(value, res)
+}
+
+// expiration returns the expiration time of the account, accounts of closed domains without
+// an explicit expiration follow the domain and explicit ones are capped at the domain expiration
+func expiration(domain types.Domain, account types.Account) time.Time {
+	if domain.Type != types.ClosedDomain {
+		return utils.SecondsToTime(account.ValidUntil)
+	}
+	if account.ValidUntil == 0 || account.ValidUntil == types.MaxValidUntil || domain.ValidUntil < account.ValidUntil {
+		return utils.SecondsToTime(domain.ValidUntil)
+	}
+	return utils.SecondsToTime(account.ValidUntil)
+}
+
+// VerifyStarname verifies the signatures of the transaction, which must contain a single
+// MsgSignText, and checks that its signer is the owner of the starname at height, the
+// latest one if height is zero
+func VerifyStarname(querier Querier, tx auth.StdTx, starname string, height int64, chainID string, accountNumber, sequence uint64) (StarnameVerification, error) {
+	if err := Verify(tx, chainID, accountNumber, sequence); err != nil {
+		return StarnameVerification{}, err
+	}
+	msgs := tx.GetMsgs()
+	if len(msgs) != 1 {
+		return StarnameVerification{}, fmt.Errorf("expected 1 msg but got %d", len(msgs))
+	}
+	msg, ok := msgs[0].(MsgSignText)
+	if !ok {
+		return StarnameVerification{}, fmt.Errorf("unexpected msg type %s", msgs[0].Type())
+	}
+	owner, height, err := ResolveOwner(querier, starname, height)
+	if err != nil {
+		return StarnameVerification{}, err
+	}
+	return StarnameVerification{
+		Message:  msg.Message,
+		Signer:   msg.Signer.String(),
+		Starname: starname,
+		Owner:    owner.String(),
+		Height:   height,
+		Verified: owner.Equals(msg.Signer),
+	}, nil
+}
@@ -0,0 +1,177 @@
+package signutil
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/iov-one/iovns/pkg/queries"
+	"github.com/iov-one/iovns/pkg/utils"
+	"github.com/iov-one/iovns/x/starname/keeper"
+	"github.com/iov-one/iovns/x/starname/types"
+	"github.com/tendermint/tendermint/crypto"
+	"github.com/tendermint/tendermint/crypto/secp256k1"
+)
+
+// mockQuerier resolves the accounts of domain at height, whose block is at blockTime
+type mockQuerier struct {
+	domain    types.Domain
+	accounts  map[string]types.Account
+	height    int64
+	blockTime time.Time
+}
+
+func (m mockQuerier) QueryAt(path string, data []byte, height int64) ([]byte, int64, error) {
+	if height != 0 && height != m.height {
+		return nil, 0, fmt.Errorf("unexpected height %d", height)
+	}
+	var res interface{}
+	switch path {
+	case "custom/starname/resolve":
+		q := new(keeper.QueryResolveAccount)
+		if err := queries.DefaultQueryDecode(data, q); err != nil {
+			return nil, 0, err
+		}
+		account, ok := m.accounts[q.Name]
+		if q.Domain != m.domain.Name || !ok {
+			return nil, 0, types.ErrAccountDoesNotExist
+		}
+		res = keeper.QueryResolveAccountResponse{Account: account}
+	case "custom/starname/domainInfo":
+		q := new(keeper.QueryResolveDomain)
+		if err := queries.DefaultQueryDecode(data, q); err != nil {
+			return nil, 0, err
+		}
+		if q.Name != m.domain.Name {
+			return nil, 0, types.ErrDomainDoesNotExist
+		}
+		res = keeper.QueryResolveDomainResponse{Domain: m.domain}
+	default:
+		return nil, 0, fmt.Errorf("unexpected path %s", path)
+	}
+	b, err := queries.DefaultQueryEncode(res)
+	return b, m.height, err
+}
+
+func (m mockQuerier) BlockTime(height int64) (time.Time, error) {
+	if height != m.height {
+		return time.Time{}, fmt.Errorf("unexpected height %d", height)
+	}
+	return m.blockTime, nil
+}
+
+func signText(t *testing.T, key crypto.PrivKey, signer sdk.AccAddress) auth.StdTx {
+	msgs := []sdk.Msg{MsgSignText{Message: "hello", Signer: signer}}
+	fee := auth.NewStdFee(200000, nil)
+	sig, err := key.Sign(auth.StdSignBytes(DefaultChainID, DefaultAccountNumber, DefaultSequence, fee, msgs, ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return auth.NewStdTx(msgs, fee, []auth.StdSignature{{PubKey: key.PubKey(), Signature: sig}}, "")
+}
+
+func TestVerifyStarname(t *testing.T) {
+	owner := secp256k1.GenPrivKey()
+	ownerAddr := sdk.AccAddress(owner.PubKey().Address())
+	other := secp256k1.GenPrivKey()
+	otherAddr := sdk.AccAddress(other.PubKey().Address())
+	blockTime := time.Now()
+	before, after := blockTime.Add(-time.Hour).Unix(), blockTime.Add(time.Hour).Unix()
+	account := func(name string, validUntil int64) types.Account {
+		return types.Account{Domain: "domain", Name: utils.StrPtr(name), Owner: ownerAddr, ValidUntil: validUntil}
+	}
+	open := mockQuerier{
+		domain: types.Domain{Name: "domain", Type: types.OpenDomain, ValidUntil: after},
+		accounts: map[string]types.Account{
+			"name":    account("name", after),
+			"expired": account("expired", before),
+		},
+		height:    10,
+		blockTime: blockTime,
+	}
+	closed := mockQuerier{
+		domain: types.Domain{Name: "domain", Type: types.ClosedDomain, ValidUntil: before},
+		accounts: map[string]types.Account{
+			"name":     account("name", types.MaxValidUntil),
+			"explicit": account("explicit", after),
+		},
+		height:    10,
+		blockTime: blockTime,
+	}
+
+	cases := map[string]struct {
+		querier  mockQuerier
+		tx       auth.StdTx
+		starname string
+		verified bool
+		wantErr  bool
+	}{
+		"success": {
+			querier:  open,
+			tx:       signText(t, owner, ownerAddr),
+			starname: "name*domain",
+			verified: true,
+		},
+		"not the owner": {
+			querier:  open,
+			tx:       signText(t, other, otherAddr),
+			starname: "name*domain",
+			verified: false,
+		},
+		"fail signer impersonated": {
+			querier:  open,
+			tx:       signText(t, other, ownerAddr),
+			starname: "name*domain",
+			wantErr:  true,
+		},
+		"fail starname does not exist": {
+			querier:  open,
+			tx:       signText(t, owner, ownerAddr),
+			starname: "unknown*domain",
+			wantErr:  true,
+		},
+		"fail account expired": {
+			querier:  open,
+			tx:       signText(t, owner, ownerAddr),
+			starname: "expired*domain",
+			wantErr:  true,
+		},
+		"fail closed domain expired": {
+			querier:  closed,
+			tx:       signText(t, owner, ownerAddr),
+			starname: "name*domain",
+			wantErr:  true,
+		},
+		"fail closed domain expired before the account": {
+			querier:  closed,
+			tx:       signText(t, owner, ownerAddr),
+			starname: "explicit*domain",
+			wantErr:  true,
+		},
+		"fail invalid starname": {
+			querier:  open,
+			tx:       signText(t, owner, ownerAddr),
+			starname: "name",
+			wantErr:  true,
+		},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			res, err := VerifyStarname(c.querier, c.tx, c.starname, 0, DefaultChainID, DefaultAccountNumber, DefaultSequence)
+			if (err != nil) != c.wantErr {
+				t.Fatalf("want err: %t, got: %v", c.wantErr, err)
+			}
+			if c.wantErr {
+				return
+			}
+			if res.Verified != c.verified {
+				t.Fatalf("want verified: %t, got: %t", c.verified, res.Verified)
+			}
+			if res.Owner != ownerAddr.String() || res.Height != c.querier.height || res.Starname != c.starname {
+				t.Fatalf("unexpected result: %+v", res)
+			}
+		})
+	}
+}
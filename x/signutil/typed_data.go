@@ -0,0 +1,481 @@
+package signutil
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// atomic types of the typed data fields, any other field type must be declared
+// in the schema; a type followed by [] is an array of that type
+const (
+	TypeString  = "string"
+	TypeBool    = "bool"
+	TypeInt64   = "int64"
+	TypeUint64  = "uint64"
+	TypeAddress = "address"
+	TypeBytes   = "bytes"
+)
+
+// DomainTypeName is the name of the type of the domain separator
+const DomainTypeName = "SignutilDomain"
+
+// arraySuffix marks array types
+const arraySuffix = "[]"
+
+// wordSize is the size of the encoding of a field
+const wordSize = 32
+
+// digestPrefix prefixes the digest preimage so it can't be a valid transaction or text
+var digestPrefix = []byte{0x19, 0x01}
+
+var atomicTypes = map[string]struct{}{
+	TypeString:  {},
+	TypeBool:    {},
+	TypeInt64:   {},
+	TypeUint64:  {},
+	TypeAddress: {},
+	TypeBytes:   {},
+}
+
+var identifierRegexp = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*$`)
+
+// domainSchema declares the type of the domain separator
+var domainSchema = schema{
+	DomainTypeName: {
+		{Name: "name", Type: TypeString},
+		{Name: "version", Type: TypeString},
+		{Name: "chain_id", Type: TypeString},
+	},
+}
+
+// TypedField is a field of a typed data type
+type TypedField struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// TypedDataType declares a struct type of the typed data schema
+type TypedDataType struct {
+	Name   string       `json:"name"`
+	Fields []TypedField `json:"fields"`
+}
+
+// TypedDataDomain separates the signatures of different applications and chains
+type TypedDataDomain struct {
+	// Name is the name of the application asking for the signature
+	Name string `json:"name"`
+	// Version is the version of the application schema
+	Version string `json:"version"`
+	// ChainID is the chain the signed data is meant for
+	ChainID string `json:"chain_id"`
+}
+
+// TypedData is a message of PrimaryType, checked against the types of its schema,
+// which is signed through its digest
+type TypedData struct {
+	Types       []TypedDataType `json:"types"`
+	PrimaryType string          `json:"primary_type"`
+	Domain      TypedDataDomain `json:"domain"`
+	// Message is the JSON object of the primary type, in the canonical form returned by ParseTypedData
+	Message string `json:"message"`
+}
+
+// TypedDataRendering shows the typed data with its encoding and digest
+type TypedDataRendering struct {
+	Domain          TypedDataDomain `json:"domain"`
+	PrimaryType     string          `json:"primary_type"`
+	EncodedType     string          `json:"encoded_type"`
+	DomainSeparator string          `json:"domain_separator"`
+	MessageHash     string          `json:"message_hash"`
+	Digest          string          `json:"digest"`
+	Message         json.RawMessage `json:"message"`
+}
+
+// ParseTypedData parses typed data whose message is a JSON object and validates it
+func ParseTypedData(b []byte) (TypedData, error) {
+	var raw struct {
+		Types       []TypedDataType `json:"types"`
+		PrimaryType string          `json:"primary_type"`
+		Domain      TypedDataDomain `json:"domain"`
+		Message     json.RawMessage `json:"message"`
+	}
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&raw); err != nil {
+		return TypedData{}, fmt.Errorf("invalid typed data: %s", err)
+	}
+	message, err := canonicalJSON(raw.Message)
+	if err != nil {
+		return TypedData{}, fmt.Errorf("invalid message: %s", err)
+	}
+	data := TypedData{
+		Types:       raw.Types,
+		PrimaryType: raw.PrimaryType,
+		Domain:      raw.Domain,
+		Message:     message,
+	}
+	if err := data.Validate(); err != nil {
+		return TypedData{}, err
+	}
+	return data, nil
+}
+
+// Validate checks the schema of the typed data and that the message conforms to the primary type
+func (d TypedData) Validate() error {
+	_, err := d.Render()
+	return err
+}
+
+// Digest returns the hash signed for the typed data
+func (d TypedData) Digest() ([]byte, error) {
+	r, err := d.Render()
+	if err != nil {
+		return nil, err
+	}
+	return hex.DecodeString(r.Digest)
+}
+
+// Render validates the typed data and returns it with its encoding, the digest is
+// sha256(0x19 0x01 || domain separator || message hash), where the hash of a struct is
+// sha256(sha256(encoded type) || encoded fields) as in EIP-712 with sha256 in place of keccak256
+func (d TypedData) Render() (TypedDataRendering, error) {
+	if d.Domain.Name == "" {
+		return TypedDataRendering{}, fmt.Errorf("missing domain name")
+	}
+	if d.Domain.ChainID == "" {
+		return TypedDataRendering{}, fmt.Errorf("missing domain chain id")
+	}
+	s, err := newSchema(d.Types)
+	if err != nil {
+		return TypedDataRendering{}, err
+	}
+	if _, ok := s[d.PrimaryType]; !ok {
+		return TypedDataRendering{}, fmt.Errorf("primary type %s is not declared", d.PrimaryType)
+	}
+	canonical, err := canonicalJSON([]byte(d.Message))
+	if err != nil {
+		return TypedDataRendering{}, fmt.Errorf("invalid message: %s", err)
+	}
+	if canonical != d.Message {
+		return TypedDataRendering{}, fmt.Errorf("message is not in canonical form")
+	}
+	message, err := decodeJSON([]byte(d.Message))
+	if err != nil {
+		return TypedDataRendering{}, fmt.Errorf("invalid message: %s", err)
+	}
+	messageHash, err := s.hashStruct(d.PrimaryType, message)
+	if err != nil {
+		return TypedDataRendering{}, err
+	}
+	domainSeparator, err := domainSchema.hashStruct(DomainTypeName, map[string]interface{}{
+		"name":     d.Domain.Name,
+		"version":  d.Domain.Version,
+		"chain_id": d.Domain.ChainID,
+	})
+	if err != nil {
+		return TypedDataRendering{}, err
+	}
+	digest := sha256.New()
+	digest.Write(digestPrefix)
+	digest.Write(domainSeparator)
+	digest.Write(messageHash)
+	return TypedDataRendering{
+		Domain:          d.Domain,
+		PrimaryType:     d.PrimaryType,
+		EncodedType:     s.encodeType(d.PrimaryType),
+		DomainSeparator: hex.EncodeToString(domainSeparator),
+		MessageHash:     hex.EncodeToString(messageHash),
+		Digest:          hex.EncodeToString(digest.Sum(nil)),
+		Message:         json.RawMessage(d.Message),
+	}, nil
+}
+
+// schema maps the struct type names to their fields
+type schema map[string][]TypedField
+
+// newSchema validates the declared types, which must not be recursive
+func newSchema(types []TypedDataType) (schema, error) {
+	s := make(schema, len(types))
+	for _, t := range types {
+		if !identifierRegexp.MatchString(t.Name) {
+			return nil, fmt.Errorf("invalid type name %q", t.Name)
+		}
+		if _, ok := atomicTypes[t.Name]; ok || t.Name == DomainTypeName {
+			return nil, fmt.Errorf("type name %s is reserved", t.Name)
+		}
+		if _, ok := s[t.Name]; ok {
+			return nil, fmt.Errorf("duplicate type %s", t.Name)
+		}
+		if len(t.Fields) == 0 {
+			return nil, fmt.Errorf("type %s has no fields", t.Name)
+		}
+		names := make(map[string]struct{}, len(t.Fields))
+		for _, f := range t.Fields {
+			if !identifierRegexp.MatchString(f.Name) {
+				return nil, fmt.Errorf("invalid field name %q in type %s", f.Name, t.Name)
+			}
+			if _, ok := names[f.Name]; ok {
+				return nil, fmt.Errorf("duplicate field %s in type %s", f.Name, t.Name)
+			}
+			names[f.Name] = struct{}{}
+		}
+		s[t.Name] = t.Fields
+	}
+	for name, fields := range s {
+		for _, f := range fields {
+			base := baseType(f.Type)
+			if _, ok := atomicTypes[base]; ok {
+				continue
+			}
+			if _, ok := s[base]; !ok {
+				return nil, fmt.Errorf("unknown type %s of field %s in type %s", f.Type, f.Name, name)
+			}
+		}
+	}
+	// reject recursive types
+	visiting := make(map[string]bool, len(s))
+	var visit func(name string) error
+	visit = func(name string) error {
+		done, ok := visiting[name]
+		if ok && !done {
+			return fmt.Errorf("type %s is recursive", name)
+		}
+		if ok {
+			return nil
+		}
+		visiting[name] = false
+		for _, f := range s[name] {
+			if _, ok := s[baseType(f.Type)]; !ok {
+				continue
+			}
+			if err := visit(baseType(f.Type)); err != nil {
+				return err
+			}
+		}
+		visiting[name] = true
+		return nil
+	}
+	for name := range s {
+		if err := visit(name); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// baseType returns the element type of arrays and the type itself otherwise
+func baseType(typ string) string {
+	for strings.HasSuffix(typ, arraySuffix) {
+		typ = strings.TrimSuffix(typ, arraySuffix)
+	}
+	return typ
+}
+
+// encodeType encodes the type as Name(type1 field1,type2 field2) followed by
+// the encoding of the struct types it references, sorted by name
+func (s schema) encodeType(name string) string {
+	deps := make(map[string]struct{})
+	s.collectTypes(name, deps)
+	delete(deps, name)
+	sorted := make([]string, 0, len(deps))
+	for dep := range deps {
+		sorted = append(sorted, dep)
+	}
+	sort.Strings(sorted)
+	var b strings.Builder
+	for _, t := range append([]string{name}, sorted...) {
+		fields := make([]string, len(s[t]))
+		for i, f := range s[t] {
+			fields[i] = f.Type + " " + f.Name
+		}
+		b.WriteString(t + "(" + strings.Join(fields, ",") + ")")
+	}
+	return b.String()
+}
+
+// collectTypes adds the struct types referenced by the type to deps
+func (s schema) collectTypes(name string, deps map[string]struct{}) {
+	if _, ok := deps[name]; ok {
+		return
+	}
+	deps[name] = struct{}{}
+	for _, f := range s[name] {
+		if _, ok := s[baseType(f.Type)]; ok {
+			s.collectTypes(baseType(f.Type), deps)
+		}
+	}
+}
+
+// hashStruct returns sha256(sha256(encoded type) || encoded fields) of the value of the struct type
+func (s schema) hashStruct(name string, value interface{}) ([]byte, error) {
+	object, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s must be an object", name)
+	}
+	fields := s[name]
+	for key := range object {
+		if !hasField(fields, key) {
+			return nil, fmt.Errorf("unexpected field %s in %s", key, name)
+		}
+	}
+	typeHash := sha256.Sum256([]byte(s.encodeType(name)))
+	h := sha256.New()
+	h.Write(typeHash[:])
+	for _, f := range fields {
+		v, ok := object[f.Name]
+		if !ok {
+			return nil, fmt.Errorf("missing field %s in %s", f.Name, name)
+		}
+		enc, err := s.encodeValue(f.Type, v)
+		if err != nil {
+			return nil, fmt.Errorf("%s.%s: %s", name, f.Name, err)
+		}
+		h.Write(enc)
+	}
+	return h.Sum(nil), nil
+}
+
+// encodeValue encodes the value of the type in 32 bytes
+func (s schema) encodeValue(typ string, value interface{}) ([]byte, error) {
+	if strings.HasSuffix(typ, arraySuffix) {
+		items, ok := value.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%s must be an array", typ)
+		}
+		h := sha256.New()
+		for i, item := range items {
+			enc, err := s.encodeValue(strings.TrimSuffix(typ, arraySuffix), item)
+			if err != nil {
+				return nil, fmt.Errorf("[%d]: %s", i, err)
+			}
+			h.Write(enc)
+		}
+		return h.Sum(nil), nil
+	}
+	if _, ok := s[typ]; ok {
+		return s.hashStruct(typ, value)
+	}
+	word := make([]byte, wordSize)
+	switch typ {
+	case TypeString:
+		str, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s must be a string", typ)
+		}
+		sum := sha256.Sum256([]byte(str))
+		return sum[:], nil
+	case TypeBytes:
+		str, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s must be a base64 string", typ)
+		}
+		b, err := base64.StdEncoding.DecodeString(str)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be a base64 string", typ)
+		}
+		sum := sha256.Sum256(b)
+		return sum[:], nil
+	case TypeBool:
+		b, ok := value.(bool)
+		if !ok {
+			return nil, fmt.Errorf("%s must be a boolean", typ)
+		}
+		if b {
+			word[wordSize-1] = 1
+		}
+		return word, nil
+	case TypeUint64:
+		n, err := strconv.ParseUint(numberString(value), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be an unsigned integer", typ)
+		}
+		binary.BigEndian.PutUint64(word[wordSize-8:], n)
+		return word, nil
+	case TypeInt64:
+		n, err := strconv.ParseInt(numberString(value), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be an integer", typ)
+		}
+		// sign extend
+		if n < 0 {
+			for i := range word {
+				word[i] = 0xff
+			}
+		}
+		binary.BigEndian.PutUint64(word[wordSize-8:], uint64(n))
+		return word, nil
+	case TypeAddress:
+		str, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s must be a bech32 string", typ)
+		}
+		addr, err := sdk.AccAddressFromBech32(str)
+		if err != nil || len(addr) > wordSize {
+			return nil, fmt.Errorf("invalid address %s", str)
+		}
+		copy(word[wordSize-len(addr):], addr)
+		return word, nil
+	default:
+		return nil, fmt.Errorf("unknown type %s", typ)
+	}
+}
+
+// hasField tells if the fields contain the named field
+func hasField(fields []TypedField, name string) bool {
+	for _, f := range fields {
+		if f.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// numberString returns the decimal representation of JSON numbers and strings
+func numberString(value interface{}) string {
+	switch v := value.(type) {
+	case json.Number:
+		return v.String()
+	case string:
+		return v
+	default:
+		return ""
+	}
+}
+
+// decodeJSON decodes a JSON value keeping numbers as json.Number
+func decodeJSON(b []byte) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.UseNumber()
+	var v interface{}
+	if err := decoder.Decode(&v); err != nil {
+		return nil, err
+	}
+	if decoder.More() {
+		return nil, fmt.Errorf("unexpected data after the JSON value")
+	}
+	return v, nil
+}
+
+// canonicalJSON returns the compact form of the JSON value with sorted object keys
+func canonicalJSON(b []byte) (string, error) {
+	v, err := decodeJSON(b)
+	if err != nil {
+		return "", err
+	}
+	canonical, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(canonical), nil
+}
@@ -0,0 +1,230 @@
+package signutil
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"sync"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/iov-one/iovns/app/config"
+	"github.com/tendermint/tendermint/crypto/secp256k1"
+)
+
+var configOnce sync.Once
+
+// applyConfig sets the bech32 prefixes of the addresses once
+func applyConfig() {
+	configOnce.Do(func() {
+		config.ApplyChangesAndSeal(sdk.GetConfig())
+	})
+}
+
+type typedDataVectors struct {
+	Valid []struct {
+		Name            string          `json:"name"`
+		TypedData       json.RawMessage `json:"typed_data"`
+		EncodedType     string          `json:"encoded_type"`
+		DomainSeparator string          `json:"domain_separator"`
+		MessageHash     string          `json:"message_hash"`
+		Digest          string          `json:"digest"`
+	} `json:"valid"`
+	Invalid []struct {
+		Name      string          `json:"name"`
+		TypedData json.RawMessage `json:"typed_data"`
+	} `json:"invalid"`
+}
+
+func readTypedDataVectors(t *testing.T) typedDataVectors {
+	b, err := ioutil.ReadFile("testdata/typed_data_vectors.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var vectors typedDataVectors
+	if err := json.Unmarshal(b, &vectors); err != nil {
+		t.Fatal(err)
+	}
+	return vectors
+}
+
+func TestTypedDataVectors(t *testing.T) {
+	applyConfig()
+	vectors := readTypedDataVectors(t)
+	for _, v := range vectors.Valid {
+		t.Run(v.Name, func(t *testing.T) {
+			data, err := ParseTypedData(v.TypedData)
+			if err != nil {
+				t.Fatal(err)
+			}
+			r, err := data.Render()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if r.EncodedType != v.EncodedType {
+				t.Fatalf("want encoded type %s, got %s", v.EncodedType, r.EncodedType)
+			}
+			if r.DomainSeparator != v.DomainSeparator {
+				t.Fatalf("want domain separator %s, got %s", v.DomainSeparator, r.DomainSeparator)
+			}
+			if r.MessageHash != v.MessageHash {
+				t.Fatalf("want message hash %s, got %s", v.MessageHash, r.MessageHash)
+			}
+			if r.Digest != v.Digest {
+				t.Fatalf("want digest %s, got %s", v.Digest, r.Digest)
+			}
+		})
+	}
+	for _, v := range vectors.Invalid {
+		t.Run(v.Name, func(t *testing.T) {
+			if _, err := ParseTypedData(v.TypedData); err == nil {
+				t.Fatal("invalid typed data accepted")
+			}
+		})
+	}
+}
+
+func TestTypedData_Render(t *testing.T) {
+	const raw = `{
+		"types": [{"name": "Consent", "fields": [{"name": "subject", "type": "string"}, {"name": "granted", "type": "bool"}, {"name": "expiry", "type": "uint64"}]}],
+		"primary_type": "Consent",
+		"domain": {"name": "consents", "version": "1", "chain_id": "iov-mainnet-ibc"},
+		"message": {"subject": "marketing", "expiry": 1700000000, "granted": true}
+	}`
+	data, err := ParseTypedData([]byte(raw))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data.Message != `{"expiry":1700000000,"granted":true,"subject":"marketing"}` {
+		t.Fatalf("unexpected canonical message %s", data.Message)
+	}
+	// compute the digest by hand
+	hash := func(parts ...[]byte) []byte {
+		h := sha256.New()
+		for _, p := range parts {
+			h.Write(p)
+		}
+		return h.Sum(nil)
+	}
+	word := func(n uint64) []byte {
+		w := make([]byte, 32)
+		binary.BigEndian.PutUint64(w[24:], n)
+		return w
+	}
+	domainSeparator := hash(
+		hash([]byte("SignutilDomain(string name,string version,string chain_id)")),
+		hash([]byte("consents")),
+		hash([]byte("1")),
+		hash([]byte("iov-mainnet-ibc")),
+	)
+	messageHash := hash(
+		hash([]byte("Consent(string subject,bool granted,uint64 expiry)")),
+		hash([]byte("marketing")),
+		word(1),
+		word(1700000000),
+	)
+	digest := hash([]byte{0x19, 0x01}, domainSeparator, messageHash)
+	r, err := data.Render()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Digest != hex.EncodeToString(digest) {
+		t.Fatalf("want digest %x, got %s", digest, r.Digest)
+	}
+	// messages must be in canonical form
+	data.Message = `{"subject":"marketing","expiry":1700000000,"granted":true}`
+	if err := data.Validate(); err == nil {
+		t.Fatal("non canonical message accepted")
+	}
+}
+
+func TestVerifyTypedData(t *testing.T) {
+	applyConfig()
+	key := secp256k1.GenPrivKey()
+	signer := sdk.AccAddress(key.PubKey().Address())
+	vectors := readTypedDataVectors(t)
+	data, err := ParseTypedData(vectors.Valid[0].TypedData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fee := auth.NewStdFee(200000, nil)
+	sign := func(msg MsgSignTypedData) auth.StdTx {
+		msgs := []sdk.Msg{msg}
+		sig, err := key.Sign(auth.StdSignBytes(DefaultChainID, DefaultAccountNumber, DefaultSequence, fee, msgs, ""))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return auth.NewStdTx(msgs, fee, []auth.StdSignature{{PubKey: key.PubKey(), Signature: sig}}, "")
+	}
+
+	t.Run("success", func(t *testing.T) {
+		res, err := VerifyTypedData(sign(MsgSignTypedData{TypedData: data, Signer: signer}), DefaultChainID, DefaultAccountNumber, DefaultSequence)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.Signer != signer.String() || res.TypedData.Digest != vectors.Valid[0].Digest {
+			t.Fatalf("unexpected verification %+v", res)
+		}
+		if err := res.CheckDomain("Starname Mail", "iov-mainnet-ibc"); err != nil {
+			t.Fatal(err)
+		}
+		if err := res.CheckDomain("Other App", "iov-mainnet-ibc"); err == nil {
+			t.Fatal("unexpected domain name accepted")
+		}
+		if err := res.CheckDomain("Starname Mail", "iovns-galaxynet"); err == nil {
+			t.Fatal("unexpected domain chain id accepted")
+		}
+		// the domain is always checked
+		if err := res.CheckDomain("", "iov-mainnet-ibc"); err == nil {
+			t.Fatal("missing domain name accepted")
+		}
+		if err := res.CheckDomain("Starname Mail", ""); err == nil {
+			t.Fatal("missing domain chain id accepted")
+		}
+	})
+
+	t.Run("fail tampered message", func(t *testing.T) {
+		tx := sign(MsgSignTypedData{TypedData: data, Signer: signer})
+		tampered := data
+		tampered.Domain.ChainID = "iovns-galaxynet"
+		tx.Msgs[0] = MsgSignTypedData{TypedData: tampered, Signer: signer}
+		if _, err := VerifyTypedData(tx, DefaultChainID, DefaultAccountNumber, DefaultSequence); err == nil {
+			t.Fatal("tampered typed data accepted")
+		}
+	})
+
+	t.Run("fail invalid typed data", func(t *testing.T) {
+		tx := sign(MsgSignTypedData{TypedData: data, Signer: signer})
+		invalid := data
+		invalid.PrimaryType = "Unknown"
+		tx.Msgs[0] = MsgSignTypedData{TypedData: invalid, Signer: signer}
+		if _, err := VerifyTypedData(tx, DefaultChainID, DefaultAccountNumber, DefaultSequence); err == nil {
+			t.Fatal("invalid typed data accepted")
+		}
+		// invalid typed data has no sign bytes
+		defer func() {
+			if recover() == nil {
+				t.Fatal("sign bytes of invalid typed data")
+			}
+		}()
+		MsgSignTypedData{TypedData: invalid, Signer: signer}.GetSignBytes()
+	})
+
+	t.Run("success amino json", func(t *testing.T) {
+		cdc := codec.New()
+		sdk.RegisterCodec(cdc)
+		auth.RegisterCodec(cdc)
+		codec.RegisterCrypto(cdc)
+		RegisterCodec(cdc)
+		b := cdc.MustMarshalJSON(sign(MsgSignTypedData{TypedData: data, Signer: signer}))
+		var tx auth.StdTx
+		cdc.MustUnmarshalJSON(b, &tx)
+		if _, err := VerifyTypedData(tx, DefaultChainID, DefaultAccountNumber, DefaultSequence); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
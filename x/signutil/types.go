@@ -1,7 +1,9 @@
 package signutil
 
 import (
+	"encoding/hex"
 	"fmt"
+
 	sdk "github.com/cosmos/cosmos-sdk/types"
 )
 
@@ -14,6 +16,9 @@ type MsgSignText struct {
 	Message string         `json:"message,omitempty"`
 	Pairs   []Pair         `json:"pairs,omitempty"`
 	Signer  sdk.AccAddress `json:"signer"`
+	// CoSigners are the addresses that sign the text along with the signer,
+	// the signatures of the transaction must follow the order of GetSigners
+	CoSigners []sdk.AccAddress `json:"co_signers,omitempty"`
 }
 
 func (m MsgSignText) Route() string {
@@ -31,6 +36,16 @@ func (m MsgSignText) ValidateBasic() error {
 	if m.Signer.Empty() {
 		return fmt.Errorf("missing signer")
 	}
+	signers := map[string]struct{}{m.Signer.String(): {}}
+	for i, coSigner := range m.CoSigners {
+		if coSigner.Empty() {
+			return fmt.Errorf("missing co-signer at index %d", i)
+		}
+		if _, ok := signers[coSigner.String()]; ok {
+			return fmt.Errorf("duplicate signer %s", coSigner)
+		}
+		signers[coSigner.String()] = struct{}{}
+	}
 	return nil
 }
 
@@ -40,4 +55,48 @@ func (m MsgSignText) GetSignBytes() []byte {
 }
 
 // GetSigners implements sdk.Message
-func (m MsgSignText) GetSigners() []sdk.AccAddress { return []sdk.AccAddress{m.Signer} }
+func (m MsgSignText) GetSigners() []sdk.AccAddress {
+	return append([]sdk.AccAddress{m.Signer}, m.CoSigners...)
+}
+
+// MsgSignTypedData is the signature of typed data by the signer
+type MsgSignTypedData struct {
+	TypedData TypedData      `json:"typed_data"`
+	Signer    sdk.AccAddress `json:"signer"`
+}
+
+func (m MsgSignTypedData) Route() string {
+	return ModuleName
+}
+
+func (m MsgSignTypedData) Type() string {
+	return "typed_data_signature"
+}
+
+func (m MsgSignTypedData) ValidateBasic() error {
+	if m.Signer.Empty() {
+		return fmt.Errorf("missing signer")
+	}
+	return m.TypedData.Validate()
+}
+
+// GetSignBytes implements sdk.Message, the digest of the typed data is signed along with it,
+// it panics if the typed data is not valid, which ValidateBasic rejects
+func (m MsgSignTypedData) GetSignBytes() []byte {
+	digest, err := m.TypedData.Digest()
+	if err != nil {
+		panic(fmt.Errorf("typed data digest: %w", err))
+	}
+	return sdk.MustSortJSON(ModuleCdc.MustMarshalJSON(struct {
+		Digest    string         `json:"digest"`
+		TypedData TypedData      `json:"typed_data"`
+		Signer    sdk.AccAddress `json:"signer"`
+	}{
+		Digest:    hex.EncodeToString(digest),
+		TypedData: m.TypedData,
+		Signer:    m.Signer,
+	}))
+}
+
+// GetSigners implements sdk.Message
+func (m MsgSignTypedData) GetSigners() []sdk.AccAddress { return []sdk.AccAddress{m.Signer} }
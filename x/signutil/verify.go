@@ -2,9 +2,15 @@ package signutil
 
 import (
 	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/tendermint/tendermint/crypto"
+	"github.com/tendermint/tendermint/crypto/multisig"
 )
 
+// Verify checks that the transaction carries a valid signature of each of its signers, in the
+// order of the signers; multisig threshold keys must be signed by at least threshold of their keys
 func Verify(tx auth.StdTx, chainID string, accountNumber, sequence uint64) error {
 	signatures := tx.GetSignatures()
 	signers := tx.GetPubKeys()
@@ -14,12 +20,87 @@ func Verify(tx auth.StdTx, chainID string, accountNumber, sequence uint64) error
 	if len(signers) != len(signatures) {
 		return fmt.Errorf("invalid number of signers (%d) and signatures (%d)", len(signers), len(signatures))
 	}
+	addresses := tx.GetSigners()
+	if len(addresses) != len(signers) {
+		return fmt.Errorf("invalid number of signers (%d) and signer addresses (%d)", len(signers), len(addresses))
+	}
+	message := auth.StdSignBytes(chainID, accountNumber, sequence, tx.Fee, tx.Msgs, tx.Memo)
 	for i, sig := range signatures {
 		signer := signers[i]
-		message := auth.StdSignBytes(chainID, accountNumber, sequence, tx.Fee, tx.Msgs, tx.Memo)
-		if !signer.VerifyBytes(message, sig) {
-			return fmt.Errorf("invalid signature from address found at index %d, from address: %s", i, tx.GetSigners()[i])
+		if signer == nil || !addresses[i].Equals(sdk.AccAddress(signer.Address())) {
+			return fmt.Errorf("public key at index %d does not belong to signer %s", i, addresses[i])
 		}
+		if err := verifySignature(signer, message, sig); err != nil {
+			return fmt.Errorf("invalid signature from address found at index %d, from address: %s: %s", i, addresses[i], err)
+		}
+	}
+	return nil
+}
+
+// verifySignature verifies the signature of message by the public key
+func verifySignature(pubKey crypto.PubKey, message, sig []byte) error {
+	multisigPubKey, ok := pubKey.(multisig.PubKeyMultisigThreshold)
+	if ok {
+		var multisignature multisig.Multisignature
+		if err := ModuleCdc.UnmarshalBinaryBare(sig, &multisignature); err != nil {
+			return fmt.Errorf("invalid multisig signature")
+		}
+		if multisignature.BitArray == nil || multisignature.BitArray.Size() != len(multisigPubKey.PubKeys) {
+			return fmt.Errorf("multisig signature does not match the %d keys of the multisig", len(multisigPubKey.PubKeys))
+		}
+		if signed := multisignature.BitArray.NumTrueBitsBefore(len(multisigPubKey.PubKeys)); signed < int(multisigPubKey.K) {
+			return fmt.Errorf("multisig signed by %d keys, %d required", signed, multisigPubKey.K)
+		}
+	}
+	if !pubKey.VerifyBytes(message, sig) {
+		return fmt.Errorf("signature verification failed")
+	}
+	return nil
+}
+
+// TypedDataVerification is the result of the verification of signed typed data
+type TypedDataVerification struct {
+	Signer    string             `json:"signer"`
+	TypedData TypedDataRendering `json:"typed_data"`
+	Verified  bool               `json:"verified"`
+}
+
+// VerifyTypedData verifies the signature of the transaction, which must contain a single
+// MsgSignTypedData, and returns the signed typed data
+func VerifyTypedData(tx auth.StdTx, chainID string, accountNumber, sequence uint64) (TypedDataVerification, error) {
+	msgs := tx.GetMsgs()
+	if len(msgs) != 1 {
+		return TypedDataVerification{}, fmt.Errorf("expected 1 msg but got %d", len(msgs))
+	}
+	msg, ok := msgs[0].(MsgSignTypedData)
+	if !ok {
+		return TypedDataVerification{}, fmt.Errorf("unexpected msg type %s", msgs[0].Type())
+	}
+	rendering, err := msg.TypedData.Render()
+	if err != nil {
+		return TypedDataVerification{}, err
+	}
+	if err := Verify(tx, chainID, accountNumber, sequence); err != nil {
+		return TypedDataVerification{}, err
+	}
+	return TypedDataVerification{
+		Signer:    msg.Signer.String(),
+		TypedData: rendering,
+		Verified:  true,
+	}, nil
+}
+
+// CheckDomain checks that the domain of the typed data matches the expected application
+// name and chain id, which are both required
+func (v TypedDataVerification) CheckDomain(name, chainID string) error {
+	if name == "" || chainID == "" {
+		return fmt.Errorf("the expected application name and chain id of the typed data are required")
+	}
+	if v.TypedData.Domain.Name != name {
+		return fmt.Errorf("typed data signed for application %s, expected %s", v.TypedData.Domain.Name, name)
+	}
+	if v.TypedData.Domain.ChainID != chainID {
+		return fmt.Errorf("typed data signed for chain %s, expected %s", v.TypedData.Domain.ChainID, chainID)
 	}
 	return nil
 }
@@ -1,15 +1,18 @@
 package signutil
 
 import (
+	"testing"
+
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/x/auth"
-	"github.com/iov-one/iovns/app/config"
+	"github.com/tendermint/tendermint/crypto"
 	cryptoamino "github.com/tendermint/tendermint/crypto/encoding/amino"
-	"testing"
+	"github.com/tendermint/tendermint/crypto/multisig"
+	"github.com/tendermint/tendermint/crypto/secp256k1"
 )
 
 func TestVerify(t *testing.T) {
-	config.ApplyChangesAndSeal(sdk.GetConfig())
+	applyConfig()
 	sdk.RegisterCodec(ModuleCdc)
 	auth.RegisterCodec(ModuleCdc)
 	cryptoamino.RegisterAmino(ModuleCdc)
@@ -21,3 +24,102 @@ func TestVerify(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestVerifyMultisig(t *testing.T) {
+	keys := []crypto.PrivKey{secp256k1.GenPrivKey(), secp256k1.GenPrivKey(), secp256k1.GenPrivKey()}
+	pubKeys := make([]crypto.PubKey, len(keys))
+	for i, k := range keys {
+		pubKeys[i] = k.PubKey()
+	}
+	multisigKey := multisig.NewPubKeyMultisigThreshold(2, pubKeys)
+	multisigAddr := sdk.AccAddress(multisigKey.Address())
+	coSigner := secp256k1.GenPrivKey()
+	coSignerAddr := sdk.AccAddress(coSigner.PubKey().Address())
+	fee := auth.NewStdFee(200000, nil)
+	msgs := []sdk.Msg{MsgSignText{Message: "hello", Signer: multisigAddr, CoSigners: []sdk.AccAddress{coSignerAddr}}}
+	signBytes := auth.StdSignBytes(DefaultChainID, DefaultAccountNumber, DefaultSequence, fee, msgs, "")
+	// multisign signs the message with the keys at the given indexes
+	multisign := func(indexes ...int) auth.StdSignature {
+		sig := multisig.NewMultisig(len(keys))
+		for _, i := range indexes {
+			b, err := keys[i].Sign(signBytes)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := sig.AddSignatureFromPubKey(b, pubKeys[i], pubKeys); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return auth.StdSignature{PubKey: multisigKey, Signature: sig.Marshal()}
+	}
+	coSign := func() auth.StdSignature {
+		b, err := coSigner.Sign(signBytes)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return auth.StdSignature{PubKey: coSigner.PubKey(), Signature: b}
+	}
+	cases := map[string]struct {
+		signatures []auth.StdSignature
+		wantErr    bool
+	}{
+		"success": {
+			signatures: []auth.StdSignature{multisign(0, 2), coSign()},
+		},
+		"success all keys": {
+			signatures: []auth.StdSignature{multisign(0, 1, 2), coSign()},
+		},
+		"fail below threshold": {
+			signatures: []auth.StdSignature{multisign(1), coSign()},
+			wantErr:    true,
+		},
+		"fail missing co-signer": {
+			signatures: []auth.StdSignature{multisign(0, 1)},
+			wantErr:    true,
+		},
+		"fail signers out of order": {
+			signatures: []auth.StdSignature{coSign(), multisign(0, 1)},
+			wantErr:    true,
+		},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := Verify(auth.NewStdTx(msgs, fee, c.signatures, ""), DefaultChainID, DefaultAccountNumber, DefaultSequence)
+			if (err != nil) != c.wantErr {
+				t.Fatalf("want err: %t, got: %v", c.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestMsgSignText_ValidateBasic(t *testing.T) {
+	signer := sdk.AccAddress(secp256k1.GenPrivKey().PubKey().Address())
+	coSigner := sdk.AccAddress(secp256k1.GenPrivKey().PubKey().Address())
+	cases := map[string]struct {
+		msg     MsgSignText
+		wantErr bool
+	}{
+		"success": {
+			msg: MsgSignText{Message: "hello", Signer: signer, CoSigners: []sdk.AccAddress{coSigner}},
+		},
+		"fail empty co-signer": {
+			msg:     MsgSignText{Message: "hello", Signer: signer, CoSigners: []sdk.AccAddress{nil}},
+			wantErr: true,
+		},
+		"fail signer is co-signer": {
+			msg:     MsgSignText{Message: "hello", Signer: signer, CoSigners: []sdk.AccAddress{coSigner, signer}},
+			wantErr: true,
+		},
+		"fail duplicate co-signer": {
+			msg:     MsgSignText{Message: "hello", Signer: signer, CoSigners: []sdk.AccAddress{coSigner, coSigner}},
+			wantErr: true,
+		},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			if err := c.msg.ValidateBasic(); (err != nil) != c.wantErr {
+				t.Fatalf("want err: %t, got: %v", c.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package starname
+
+import (
+	"strconv"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/iov-one/iovns/x/starname/controllers/fees"
+	"github.com/iov-one/iovns/x/starname/keeper"
+	"github.com/iov-one/iovns/x/starname/types"
+)
+
+// EndBlocker removes the transfer offers expired at the current block
+// time, renews the sponsored domains and accounts about to expire
+// and prunes the history records older than the history retention
+func EndBlocker(ctx sdk.Context, k keeper.Keeper) {
+	for _, offer := range k.DeleteExpiredTransferOffers(ctx) {
+		ctx.EventManager().EmitEvent(
+			sdk.NewEvent(
+				types.EventTypeExpireTransferOffer,
+				sdk.NewAttribute(sdk.AttributeKeyModule, types.ModuleName),
+				sdk.NewAttribute(types.AttributeKeyTransferOfferID, strconv.FormatUint(offer.ID, 10)),
+				sdk.NewAttribute(types.AttributeKeyDomainName, offer.Domain),
+				sdk.NewAttribute(types.AttributeKeyOwner, offer.Owner.String()),
+				sdk.NewAttribute(types.AttributeKeyTransferOfferRecipient, offer.Recipient.String()),
+			),
+		)
+	}
+	for _, starname := range k.DequeueDueSponsoredRenewals(ctx) {
+		renewSponsored(ctx, k, starname)
+	}
+	k.PruneHistory(ctx)
+}
+
+// renewSponsored renews the domain or account whose sponsored renewal is due if it expires
+// within the renewal window, the renewal goes through the renew handlers with the module
+// account as fee payer and its fee is taken from the balance of the first sponsorship able
+// to pay it, sponsorships are closed once the sponsored domain or account is deleted or once
+// their balance cannot pay the fee, renewals refused by the handlers, like the ones over the
+// renewal limit, are retried after types.SponsoredRenewalRetryDelay
+func renewSponsored(ctx sdk.Context, k keeper.Keeper, starname string) {
+	domainName, name := starname, ""
+	if sname := strings.Split(starname, types.StarnameSeparator); len(sname) == 2 {
+		name, domainName = sname[0], sname[1]
+	}
+	sponsorships := k.GetStarnameSponsorships(ctx, domainName, name)
+	if len(sponsorships) == 0 {
+		return
+	}
+	domain := new(types.Domain)
+	exists := k.DomainStore(ctx).Read((&types.Domain{Name: domainName}).PrimaryKey(), domain)
+	validUntil := domain.ValidUntil
+	var msg types.MsgWithFeePayer = &types.MsgRenewDomain{
+		Domain:       domainName,
+		FeePayerAddr: k.ModuleAddress(),
+	}
+	if exists && name != "" {
+		account := new(types.Account)
+		exists = k.AccountStore(ctx).Read((&types.Account{Domain: domainName, Name: &name}).PrimaryKey(), account)
+		validUntil = account.ValidUntil
+		msg = &types.MsgRenewAccount{
+			Domain:       domainName,
+			Name:         name,
+			FeePayerAddr: k.ModuleAddress(),
+		}
+	}
+	if !exists {
+		for _, sponsorship := range sponsorships {
+			closeSponsorship(ctx, k, sponsorship, types.SponsorshipStarnameDeleted)
+		}
+		return
+	}
+	if due := keeper.SponsoredRenewalDueTime(validUntil); due.After(ctx.BlockTime()) {
+		k.QueueSponsoredRenewal(ctx, starname, due)
+		return
+	}
+	fee := fees.NewController(ctx, k, *domain).GetFee(msg)
+	for _, sponsorship := range sponsorships {
+		balance, negative := sponsorship.Balance.SafeSub(sdk.NewCoins(fee))
+		if negative {
+			closeSponsorship(ctx, k, sponsorship, types.SponsorshipExhausted)
+			continue
+		}
+		switch msg := msg.(type) {
+		case *types.MsgRenewDomain:
+			msg.Signer = sponsorship.Sponsor
+		case *types.MsgRenewAccount:
+			msg.Signer = sponsorship.Sponsor
+		}
+		cacheCtx, write := ctx.CacheContext()
+		res, err := NewHandler(k)(cacheCtx, msg)
+		if err != nil {
+			k.Logger(ctx).Info("sponsored renewal refused", "starname", starname, "sponsor", sponsorship.Sponsor, "err", err)
+			k.QueueSponsoredRenewal(ctx, starname, ctx.BlockTime().Add(types.SponsoredRenewalRetryDelay))
+			return
+		}
+		write()
+		ctx.EventManager().EmitEvents(res.Events)
+		sponsorship.Balance = balance
+		k.SetSponsorship(ctx, sponsorship)
+		ctx.EventManager().EmitEvent(
+			sdk.NewEvent(
+				types.EventTypeSponsoredRenewal,
+				sdk.NewAttribute(sdk.AttributeKeyModule, types.ModuleName),
+				sdk.NewAttribute(types.AttributeKeyDomainName, sponsorship.Domain),
+				sdk.NewAttribute(types.AttributeKeyAccountName, sponsorship.Name),
+				sdk.NewAttribute(types.AttributeKeySponsor, sponsorship.Sponsor.String()),
+				sdk.NewAttribute(types.AttributeKeySponsorshipAmount, fee.String()),
+				sdk.NewAttribute(types.AttributeKeySponsorshipBalance, balance.String()),
+			),
+		)
+		return
+	}
+}
+
+// closeSponsorship refunds the balance left to the sponsor and deletes the sponsorship,
+// failures cannot abort the block so they are logged, reported in an event and retried
+func closeSponsorship(ctx sdk.Context, k keeper.Keeper, sponsorship types.Sponsorship, reason string) {
+	if _, err := k.WithdrawSponsorship(ctx, sponsorship, sponsorship.Balance); err != nil {
+		k.Logger(ctx).Error("unable to close sponsorship", "starname", sponsorship.Starname(), "sponsor", sponsorship.Sponsor, "err", err)
+		ctx.EventManager().EmitEvent(
+			sdk.NewEvent(
+				types.EventTypeCloseSponsorshipFailed,
+				sdk.NewAttribute(sdk.AttributeKeyModule, types.ModuleName),
+				sdk.NewAttribute(types.AttributeKeyDomainName, sponsorship.Domain),
+				sdk.NewAttribute(types.AttributeKeyAccountName, sponsorship.Name),
+				sdk.NewAttribute(types.AttributeKeySponsor, sponsorship.Sponsor.String()),
+				sdk.NewAttribute(types.AttributeKeySponsorshipBalance, sponsorship.Balance.String()),
+				sdk.NewAttribute(types.AttributeKeySponsorshipReason, reason),
+				sdk.NewAttribute(types.AttributeKeySponsorshipError, err.Error()),
+			),
+		)
+		k.QueueSponsoredRenewal(ctx, sponsorship.Starname(), ctx.BlockTime().Add(types.SponsoredRenewalRetryDelay))
+		return
+	}
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			types.EventTypeCloseSponsorship,
+			sdk.NewAttribute(sdk.AttributeKeyModule, types.ModuleName),
+			sdk.NewAttribute(types.AttributeKeyDomainName, sponsorship.Domain),
+			sdk.NewAttribute(types.AttributeKeyAccountName, sponsorship.Name),
+			sdk.NewAttribute(types.AttributeKeySponsor, sponsorship.Sponsor.String()),
+			sdk.NewAttribute(types.AttributeKeySponsorshipBalance, sponsorship.Balance.String()),
+			sdk.NewAttribute(types.AttributeKeySponsorshipReason, reason),
+		),
+	)
+}
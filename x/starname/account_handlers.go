@@ -129,6 +129,7 @@ func handlerMsgDeleteAccount(ctx sdk.Context, k keeper.Keeper, msg *types.MsgDel
 	if err := accountCtrl.
 		MustExist().
 		DeletableBy(msg.Owner).
+		NotOfferedBy(msg.Owner).
 		Validate(); err != nil {
 		return nil, err
 	}
@@ -172,15 +173,30 @@ func handleMsgRegisterAccount(ctx sdk.Context, k keeper.Keeper, msg *types.MsgRe
 	}
 	d := domainCtrl.Domain()
 	accountCtrl := account.NewController(ctx, k, msg.Domain, msg.Name).
-		WithDomainController(domainCtrl)
+		WithDomainController(domainCtrl).
+		WithCredential(msg.Credential)
 	if err := accountCtrl.
-		ValidName().
+		ValidName(msg.Owner).
+		NotConfusable().
 		MustNotExist().
 		ValidResources(msg.Resources).
 		RegistrableBy(msg.Registerer).
 		Validate(); err != nil {
 		return nil, err
 	}
+	// only the admin of a closed domain can set the account expiration
+	if msg.ValidUntil != 0 {
+		adminCtrl := domain.NewController(ctx, k, msg.Domain).WithDomain(d)
+		if err := adminCtrl.Admin(msg.Registerer).Validate(); err != nil {
+			return nil, err
+		}
+		if err := account.NewController(ctx, k, msg.Domain, msg.Name).
+			WithDomainController(adminCtrl).
+			ValidExpiration(msg.ValidUntil).
+			Validate(); err != nil {
+			return nil, err
+		}
+	}
 
 	a := types.Account{
 		Domain:       msg.Domain,
@@ -193,6 +209,9 @@ func handleMsgRegisterAccount(ctx sdk.Context, k keeper.Keeper, msg *types.MsgRe
 	switch d.Type {
 	case types.ClosedDomain:
 		a.ValidUntil = types.MaxValidUntil
+		if msg.ValidUntil != 0 {
+			a.ValidUntil = msg.ValidUntil
+		}
 	case types.OpenDomain:
 		a.ValidUntil = ctx.BlockTime().Add(conf.AccountRenewalPeriod).Unix()
 	}
@@ -205,6 +224,10 @@ func handleMsgRegisterAccount(ctx sdk.Context, k keeper.Keeper, msg *types.MsgRe
 	}
 	ex := executor.NewAccount(ctx, k, a)
 	ex.Create()
+	// invite codes can be used only once
+	if code := accountCtrl.InviteCode(); code != "" {
+		executor.NewDomain(ctx, k, d).UseInviteCode(code)
+	}
 	// success
 	ctx.EventManager().EmitEvent(
 		sdk.NewEvent(
@@ -274,6 +297,56 @@ func handlerMsgRenewAccount(ctx sdk.Context, k keeper.Keeper, msg *types.MsgRene
 	}, nil
 }
 
+// handlerMsgSetAccountValidUntil sets the expiration of an account of a closed domain
+func handlerMsgSetAccountValidUntil(ctx sdk.Context, k keeper.Keeper, msg *types.MsgSetAccountValidUntil) (*sdk.Result, error) {
+	// perform domain checks
+	domainCtrl := domain.NewController(ctx, k, msg.Domain)
+	if err := domainCtrl.
+		MustExist().
+		Type(types.ClosedDomain).
+		Admin(msg.Owner).
+		NotExpired().
+		Validate(); err != nil {
+		return nil, err
+	}
+	// perform account checks
+	accountCtrl := account.NewController(ctx, k, msg.Domain, msg.Name).WithDomainController(domainCtrl).MustExist()
+	// zero makes the account follow the domain expiration
+	validUntil := types.MaxValidUntil
+	if msg.ValidUntil != 0 {
+		accountCtrl = accountCtrl.ValidExpiration(msg.ValidUntil)
+		validUntil = msg.ValidUntil
+	}
+	if err := accountCtrl.Validate(); err != nil {
+		return nil, err
+	}
+	feeCtrl := fees.NewController(ctx, k, domainCtrl.Domain())
+	fee := feeCtrl.GetFee(msg)
+	// collect fees
+	err := k.CollectFees(ctx, msg, fee)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to collect fees")
+	}
+	// set account expiration
+	executor.NewAccount(ctx, k, accountCtrl.Account()).SetValidUntil(validUntil)
+	// success
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			sdk.EventTypeMessage,
+			sdk.NewAttribute(sdk.AttributeKeyModule, types.ModuleName),
+			sdk.NewAttribute(sdk.AttributeKeySender, msg.Owner.String()),
+			sdk.NewAttribute(sdk.AttributeKeyAction, msg.Type()),
+			sdk.NewAttribute(types.AttributeKeyDomainName, msg.Domain),
+			sdk.NewAttribute(types.AttributeKeyAccountName, msg.Name),
+			sdk.NewAttribute(types.AttributeKeyAccountValidUntil, strconv.FormatInt(validUntil, 10)),
+			sdk.NewAttribute(types.AttributeKeyOwner, msg.Owner.String()),
+		),
+	)
+	return &sdk.Result{
+		Events: ctx.EventManager().Events(),
+	}, nil
+}
+
 // handlerMsgReplaceAccountResources replaces account resources
 func handlerMsgReplaceAccountResources(ctx sdk.Context, k keeper.Keeper, msg *types.MsgReplaceAccountResources) (*sdk.Result, error) {
 	// perform domain checks
@@ -382,6 +455,7 @@ func handlerMsgTransferAccount(ctx sdk.Context, k keeper.Keeper, msg *types.MsgT
 		NotExpired().
 		TransferableBy(msg.Owner).
 		ResettableBy(msg.Owner, msg.Reset).
+		NotOfferedBy(msg.Owner).
 		Validate(); err != nil {
 		return nil, err
 	}
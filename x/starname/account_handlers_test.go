@@ -13,6 +13,7 @@ import (
 	"github.com/iov-one/iovns/x/configuration"
 	"github.com/iov-one/iovns/x/starname/keeper"
 	"github.com/iov-one/iovns/x/starname/types"
+	"github.com/tendermint/tendermint/crypto/secp256k1"
 )
 
 func Test_Close_handlerMsgAddAccountCertificates(t *testing.T) {
@@ -1041,6 +1042,7 @@ func Test_Common_handlerMsgDeleteAccount(t *testing.T) {
 }
 
 func Test_ClosedDomain_handlerMsgRegisterAccount(t *testing.T) {
+	inviteAdminKey := secp256k1.GenPrivKey()
 	testCases := map[string]keeper.SubTest{
 		"only domain admin can register account": {
 			BeforeTest: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
@@ -1080,6 +1082,63 @@ func Test_ClosedDomain_handlerMsgRegisterAccount(t *testing.T) {
 				}
 			},
 		},
+		"invite code can be used only once": {
+			BeforeTest: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				setConfig := keeper.GetConfigSetter(k.ConfigurationKeeper).SetConfig
+				setConfig(ctx, configuration.Config{
+					ValidAccountName:     keeper.RegexMatchAll,
+					ValidResource:        keeper.RegexMatchNothing,
+					ValidURI:             keeper.RegexMatchAll,
+					DomainRenewalPeriod:  10,
+					AccountRenewalPeriod: 10,
+				})
+				ex := executor.NewDomain(ctx, k, types.Domain{
+					Name:       "test",
+					Admin:      sdk.AccAddress(inviteAdminKey.PubKey().Address()),
+					ValidUntil: time.Now().Add(100000 * time.Hour).Unix(),
+					Type:       types.ClosedDomain,
+				})
+				ex.Create()
+				ex.SetRegistrationPolicy(types.RegistrationPolicy{InviteCodes: true})
+			},
+			Test: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				sig, err := inviteAdminKey.Sign(types.CredentialSignBytes(ctx.ChainID(), types.InviteCredential, "test", keeper.BobKey, "welcome"))
+				if err != nil {
+					t.Fatal(err)
+				}
+				credential := &types.RegistrationCredential{
+					Type:      types.InviteCredential,
+					Code:      "welcome",
+					PubKey:    inviteAdminKey.PubKey(),
+					Signature: sig,
+				}
+				_, err = handleMsgRegisterAccount(ctx, k, &types.MsgRegisterAccount{
+					Domain:     "test",
+					Name:       "test",
+					Owner:      keeper.BobKey,
+					Registerer: keeper.BobKey,
+					Credential: credential,
+				})
+				if err != nil {
+					t.Fatalf("handlerRegisterAccount() got error: %s", err)
+				}
+				_, err = handleMsgRegisterAccount(ctx, k, &types.MsgRegisterAccount{
+					Domain:     "test",
+					Name:       "test2",
+					Owner:      keeper.BobKey,
+					Registerer: keeper.BobKey,
+					Credential: credential,
+				})
+				if !errors.Is(err, types.ErrInviteCodeUsed) {
+					t.Fatalf("handlerRegisterAccount() want: %s, got: %s", types.ErrInviteCodeUsed, err)
+				}
+			},
+			AfterTest: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				if !k.IsInviteCodeUsed(ctx, "test", "welcome") {
+					t.Fatal("invite code should be used")
+				}
+			},
+		},
 		"account valid until is set to max": {
 			BeforeTest: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
 				setConfig := keeper.GetConfigSetter(k.ConfigurationKeeper).SetConfig
@@ -1159,11 +1218,195 @@ func Test_ClosedDomain_handlerMsgRegisterAccount(t *testing.T) {
 				}
 			},
 		},
+		"domain admin can set account expiration": {
+			BeforeTest: createClosedDomainExpiringIn(100 * time.Hour),
+			Test: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				validUntil := ctx.BlockTime().Add(90 * time.Hour).Unix()
+				_, err := handleMsgRegisterAccount(ctx, k, &types.MsgRegisterAccount{
+					Domain:     "test",
+					Name:       "contractor",
+					Owner:      keeper.AliceKey,
+					Registerer: keeper.BobKey,
+					ValidUntil: validUntil,
+				})
+				if err != nil {
+					t.Fatalf("handlerRegisterAccount() got error: %s", err)
+				}
+				account := new(types.Account)
+				k.AccountStore(ctx).Read((&types.Account{Domain: "test", Name: utils.StrPtr("contractor")}).PrimaryKey(), account)
+				if account.ValidUntil != validUntil {
+					t.Fatalf("unexpected account valid until: %d", account.ValidUntil)
+				}
+			},
+		},
+		"account expiration cannot exceed domain expiration": {
+			BeforeTest: createClosedDomainExpiringIn(100 * time.Hour),
+			Test: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				_, err := handleMsgRegisterAccount(ctx, k, &types.MsgRegisterAccount{
+					Domain:     "test",
+					Name:       "contractor",
+					Owner:      keeper.AliceKey,
+					Registerer: keeper.BobKey,
+					ValidUntil: ctx.BlockTime().Add(101 * time.Hour).Unix(),
+				})
+				if !errors.Is(err, types.ErrInvalidAccountValidUntil) {
+					t.Fatalf("handlerRegisterAccount() want err: %s, got: %s", types.ErrInvalidAccountValidUntil, err)
+				}
+			},
+		},
 	}
 	// run tests
 	keeper.RunTests(t, testCases)
 }
 
+// createClosedDomainExpiringIn creates a closed domain named test administered by bob
+// expiring after d, with the account test owned by alice following the domain expiration
+func createClosedDomainExpiringIn(d time.Duration) func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+	return func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+		keeper.GetConfigSetter(k.ConfigurationKeeper).SetConfig(ctx, configuration.Config{
+			ValidAccountName: keeper.RegexMatchAll,
+			ValidResource:    keeper.RegexMatchAll,
+			ValidURI:         keeper.RegexMatchAll,
+			ResourcesMax:     5,
+		})
+		executor.NewDomain(ctx, k, types.Domain{
+			Name:       "test",
+			Admin:      keeper.BobKey,
+			ValidUntil: ctx.BlockTime().Add(d).Unix(),
+			Type:       types.ClosedDomain,
+		}).Create()
+		executor.NewAccount(ctx, k, types.Account{
+			Domain:     "test",
+			Name:       utils.StrPtr("test"),
+			Owner:      keeper.AliceKey,
+			ValidUntil: types.MaxValidUntil,
+		}).Create()
+	}
+}
+
+func Test_Closed_handlerMsgSetAccountValidUntil(t *testing.T) {
+	getAccount := func(k keeper.Keeper, ctx sdk.Context) types.Account {
+		account := new(types.Account)
+		k.AccountStore(ctx).Read((&types.Account{Domain: "test", Name: utils.StrPtr("test")}).PrimaryKey(), account)
+		return *account
+	}
+	cases := map[string]keeper.SubTest{
+		"success": {
+			BeforeTest: createClosedDomainExpiringIn(100 * time.Hour),
+			Test: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				_, err := handlerMsgSetAccountValidUntil(ctx, k, &types.MsgSetAccountValidUntil{
+					Domain:     "test",
+					Name:       "test",
+					Owner:      keeper.BobKey,
+					ValidUntil: ctx.BlockTime().Add(time.Hour).Unix(),
+				})
+				if err != nil {
+					t.Fatalf("handlerMsgSetAccountValidUntil() got error: %s", err)
+				}
+				if got := getAccount(k, ctx).ValidUntil; got != ctx.BlockTime().Add(time.Hour).Unix() {
+					t.Fatalf("unexpected account valid until: %d", got)
+				}
+			},
+		},
+		"success account expires": {
+			BeforeTest: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				createClosedDomainExpiringIn(100*time.Hour)(t, k, ctx, mocks)
+				_, err := handlerMsgSetAccountValidUntil(ctx, k, &types.MsgSetAccountValidUntil{
+					Domain:     "test",
+					Name:       "test",
+					Owner:      keeper.BobKey,
+					ValidUntil: ctx.BlockTime().Add(time.Hour).Unix(),
+				})
+				if err != nil {
+					t.Fatalf("handlerMsgSetAccountValidUntil() got error: %s", err)
+				}
+			},
+			Test: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				ctx = ctx.WithBlockTime(ctx.BlockTime().Add(2 * time.Hour))
+				_, err := handlerMsgReplaceAccountResources(ctx, k, &types.MsgReplaceAccountResources{
+					Domain:       "test",
+					Name:         "test",
+					Owner:        keeper.AliceKey,
+					NewResources: []types.Resource{{URI: "test", Resource: "test"}},
+				})
+				if !errors.Is(err, types.ErrAccountExpired) {
+					t.Fatalf("handlerMsgReplaceAccountResources() want err: %s, got: %s", types.ErrAccountExpired, err)
+				}
+			},
+		},
+		"success follow domain expiration": {
+			BeforeTest: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				createClosedDomainExpiringIn(100*time.Hour)(t, k, ctx, mocks)
+				account := getAccount(k, ctx)
+				executor.NewAccount(ctx, k, account).SetValidUntil(ctx.BlockTime().Add(time.Hour).Unix())
+			},
+			Test: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				_, err := handlerMsgSetAccountValidUntil(ctx, k, &types.MsgSetAccountValidUntil{
+					Domain: "test",
+					Name:   "test",
+					Owner:  keeper.BobKey,
+				})
+				if err != nil {
+					t.Fatalf("handlerMsgSetAccountValidUntil() got error: %s", err)
+				}
+				if got := getAccount(k, ctx).ValidUntil; got != types.MaxValidUntil {
+					t.Fatalf("unexpected account valid until: %d", got)
+				}
+			},
+		},
+		"fail not domain admin": {
+			BeforeTest: createClosedDomainExpiringIn(100 * time.Hour),
+			Test: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				_, err := handlerMsgSetAccountValidUntil(ctx, k, &types.MsgSetAccountValidUntil{
+					Domain:     "test",
+					Name:       "test",
+					Owner:      keeper.AliceKey,
+					ValidUntil: ctx.BlockTime().Add(time.Hour).Unix(),
+				})
+				if !errors.Is(err, types.ErrUnauthorized) {
+					t.Fatalf("handlerMsgSetAccountValidUntil() want err: %s, got: %s", types.ErrUnauthorized, err)
+				}
+			},
+		},
+		"fail after domain expiration": {
+			BeforeTest: createClosedDomainExpiringIn(100 * time.Hour),
+			Test: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				_, err := handlerMsgSetAccountValidUntil(ctx, k, &types.MsgSetAccountValidUntil{
+					Domain:     "test",
+					Name:       "test",
+					Owner:      keeper.BobKey,
+					ValidUntil: ctx.BlockTime().Add(200 * time.Hour).Unix(),
+				})
+				if !errors.Is(err, types.ErrInvalidAccountValidUntil) {
+					t.Fatalf("handlerMsgSetAccountValidUntil() want err: %s, got: %s", types.ErrInvalidAccountValidUntil, err)
+				}
+			},
+		},
+		"fail open domain": {
+			BeforeTest: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				executor.NewDomain(ctx, k, types.Domain{
+					Name:       "test",
+					Admin:      keeper.BobKey,
+					ValidUntil: ctx.BlockTime().Add(100 * time.Hour).Unix(),
+					Type:       types.OpenDomain,
+				}).Create()
+			},
+			Test: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				_, err := handlerMsgSetAccountValidUntil(ctx, k, &types.MsgSetAccountValidUntil{
+					Domain:     "test",
+					Name:       "test",
+					Owner:      keeper.BobKey,
+					ValidUntil: ctx.BlockTime().Add(time.Hour).Unix(),
+				})
+				if !errors.Is(err, types.ErrInvalidDomainType) {
+					t.Fatalf("handlerMsgSetAccountValidUntil() want err: %s, got: %s", types.ErrInvalidDomainType, err)
+				}
+			},
+		},
+	}
+	keeper.RunTests(t, cases)
+}
+
 func Test_OpenDomain_handleMsgRegisterAccount(t *testing.T) {
 	testCases := map[string]keeper.SubTest{
 		"account valid until is now plus config account renew": {
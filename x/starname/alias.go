@@ -23,6 +23,10 @@ const (
 type (
 	// Keeper aliases the Keeper type
 	Keeper = keeper.Keeper
+	// StarnameHooks aliases types.StarnameHooks
+	StarnameHooks = types.StarnameHooks
+	// MultiStarnameHooks aliases types.MultiStarnameHooks
+	MultiStarnameHooks = types.MultiStarnameHooks
 )
 
 // aliasing for funcs
@@ -31,4 +35,8 @@ var (
 	NewKeeper = keeper.NewKeeper
 	// RegisterCodec aliases types.RegisterCodec
 	RegisterCodec = types.RegisterCodec
+	// NewMultiStarnameHooks aliases types.NewMultiStarnameHooks
+	NewMultiStarnameHooks = types.NewMultiStarnameHooks
+	// RegisterInvariants aliases keeper.RegisterInvariants
+	RegisterInvariants = keeper.RegisterInvariants
 )
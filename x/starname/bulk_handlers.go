@@ -0,0 +1,239 @@
+package starname
+
+import (
+	"strconv"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/iov-one/iovns/x/starname/controllers/account"
+	"github.com/iov-one/iovns/x/starname/controllers/domain"
+	"github.com/iov-one/iovns/x/starname/controllers/fees"
+	"github.com/iov-one/iovns/x/starname/keeper"
+	"github.com/iov-one/iovns/x/starname/keeper/executor"
+	"github.com/iov-one/iovns/x/starname/types"
+)
+
+// handlerMsgTransferAccounts transfers a set of accounts of a closed domain to a new owner
+func handlerMsgTransferAccounts(ctx sdk.Context, k keeper.Keeper, msg *types.MsgTransferAccounts) (*sdk.Result, error) {
+	domainCtrl, err := bulkDomainController(ctx, k, msg.Domain, msg.Owner)
+	if err != nil {
+		return nil, err
+	}
+	d := domainCtrl.Domain()
+	accounts := make([]types.Account, len(msg.Names))
+	names := make(map[string]struct{}, len(msg.Names))
+	for i, name := range msg.Names {
+		// names which differ only before canonicalization pass ValidateBasic
+		if _, ok := names[name]; ok {
+			return nil, sdkerrors.Wrapf(types.ErrInvalidRequest, "duplicate account %s", name)
+		}
+		names[name] = struct{}{}
+		accountCtrl := account.NewController(ctx, k, msg.Domain, name).
+			WithDomainController(domain.NewController(ctx, k, msg.Domain).WithDomain(d))
+		if err := accountCtrl.
+			MustExist().
+			NotExpired().
+			TransferableBy(msg.Owner).
+			ResettableBy(msg.Owner, msg.Reset).
+			NotOfferedBy(msg.Owner).
+			Validate(); err != nil {
+			return nil, err
+		}
+		accounts[i] = accountCtrl.Account()
+	}
+	// collect fees
+	fee := bulkFee(ctx, k, d, &types.MsgTransferAccount{Domain: msg.Domain}, len(accounts))
+	if err := k.CollectFees(ctx, msg, fee); err != nil {
+		return nil, sdkerrors.Wrap(err, "unable to collect fees")
+	}
+	// transfer accounts
+	for _, a := range accounts {
+		executor.NewAccount(ctx, k, a).Transfer(msg.NewOwner, msg.Reset)
+	}
+	// success
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			sdk.EventTypeMessage,
+			append([]sdk.Attribute{
+				sdk.NewAttribute(sdk.AttributeKeyModule, types.ModuleName),
+				sdk.NewAttribute(sdk.AttributeKeySender, msg.Owner.String()),
+				sdk.NewAttribute(sdk.AttributeKeyAction, msg.Type()),
+				sdk.NewAttribute(types.AttributeKeyDomainName, msg.Domain),
+				sdk.NewAttribute(types.AttributeKeyTransferAccountNewOwner, msg.NewOwner.String()),
+				sdk.NewAttribute(types.AttributeKeyTransferAccountReset, strconv.FormatBool(msg.Reset)),
+				sdk.NewAttribute(types.AttributeKeyOwner, msg.Owner.String()),
+			}, accountNameAttributes(accounts)...)...,
+		),
+	)
+	return &sdk.Result{
+		Events: ctx.EventManager().Events(),
+	}, nil
+}
+
+// handlerMsgDeleteAccountsByOwner deletes the accounts of a closed domain owned by an address
+func handlerMsgDeleteAccountsByOwner(ctx sdk.Context, k keeper.Keeper, msg *types.MsgDeleteAccountsByOwner) (*sdk.Result, error) {
+	domainCtrl, err := bulkDomainController(ctx, k, msg.Domain, msg.Owner)
+	if err != nil {
+		return nil, err
+	}
+	d := domainCtrl.Domain()
+	var accounts []types.Account
+	filter := k.AccountStore(ctx).Filter(&types.Account{Domain: msg.Domain, Owner: msg.AccountOwner})
+	for ; filter.Valid() && len(accounts) < types.BulkAccountsMax; filter.Next() {
+		a := new(types.Account)
+		filter.Read(a)
+		// the empty account is deleted with its domain
+		if *a.Name == types.EmptyAccountName {
+			continue
+		}
+		accountCtrl := account.NewController(ctx, k, msg.Domain, *a.Name).
+			WithAccount(*a).
+			WithDomainController(domain.NewController(ctx, k, msg.Domain).WithDomain(d))
+		if err := accountCtrl.DeletableBy(msg.Owner).NotOfferedBy(msg.Owner).Validate(); err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, *a)
+	}
+	if len(accounts) == 0 {
+		return nil, sdkerrors.Wrapf(types.ErrAccountDoesNotExist, "no account owned by %s in domain %s", msg.AccountOwner, msg.Domain)
+	}
+	// collect fees
+	fee := bulkFee(ctx, k, d, &types.MsgDeleteAccount{Domain: msg.Domain}, len(accounts))
+	if err := k.CollectFees(ctx, msg, fee); err != nil {
+		return nil, sdkerrors.Wrap(err, "unable to collect fees")
+	}
+	// delete accounts
+	for _, a := range accounts {
+		executor.NewAccount(ctx, k, a).Delete()
+	}
+	// success
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			sdk.EventTypeMessage,
+			append([]sdk.Attribute{
+				sdk.NewAttribute(sdk.AttributeKeyModule, types.ModuleName),
+				sdk.NewAttribute(sdk.AttributeKeySender, msg.Owner.String()),
+				sdk.NewAttribute(sdk.AttributeKeyAction, msg.Type()),
+				sdk.NewAttribute(types.AttributeKeyDomainName, msg.Domain),
+				sdk.NewAttribute(types.AttributeKeyOwner, msg.Owner.String()),
+			}, accountNameAttributes(accounts)...)...,
+		),
+	)
+	return &sdk.Result{
+		Events: ctx.EventManager().Events(),
+	}, nil
+}
+
+// handlerMsgSetAccountsResource sets a resource on the accounts of a closed domain
+func handlerMsgSetAccountsResource(ctx sdk.Context, k keeper.Keeper, msg *types.MsgSetAccountsResource) (*sdk.Result, error) {
+	domainCtrl, err := bulkDomainController(ctx, k, msg.Domain, msg.Owner)
+	if err != nil {
+		return nil, err
+	}
+	d := domainCtrl.Domain()
+	if err := account.NewController(ctx, k, msg.Domain, "").
+		ValidResources([]types.Resource{msg.Resource}).
+		Validate(); err != nil {
+		return nil, err
+	}
+	var accounts []types.Account
+	var resources [][]types.Resource
+	// only the accounts which do not have the resource yet count towards the limit, so that
+	// sending the msg again updates the next accounts
+	filter := k.AccountStore(ctx).Filter(&types.Account{Domain: msg.Domain})
+	for ; filter.Valid() && len(accounts) < types.BulkAccountsMax; filter.Next() {
+		a := new(types.Account)
+		filter.Read(a)
+		updated, changed := withResource(a.Resources, msg.Resource)
+		if !changed {
+			continue
+		}
+		if err := account.NewController(ctx, k, msg.Domain, *a.Name).
+			WithAccount(*a).
+			ResourceLimitNotExceeded(updated).
+			Validate(); err != nil {
+			return nil, sdkerrors.Wrapf(err, "account %s", types.AccountStarname(a.Domain, *a.Name))
+		}
+		accounts = append(accounts, *a)
+		resources = append(resources, updated)
+	}
+	if len(accounts) == 0 {
+		return nil, sdkerrors.Wrapf(types.ErrInvalidRequest, "all the accounts of domain %s already have the resource", msg.Domain)
+	}
+	// collect fees
+	fee := bulkFee(ctx, k, d, &types.MsgReplaceAccountResources{Domain: msg.Domain}, len(accounts))
+	if err := k.CollectFees(ctx, msg, fee); err != nil {
+		return nil, sdkerrors.Wrap(err, "unable to collect fees")
+	}
+	// replace accounts resources
+	for i, a := range accounts {
+		executor.NewAccount(ctx, k, a).ReplaceResources(resources[i])
+	}
+	// success
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			sdk.EventTypeMessage,
+			append([]sdk.Attribute{
+				sdk.NewAttribute(sdk.AttributeKeyModule, types.ModuleName),
+				sdk.NewAttribute(sdk.AttributeKeySender, msg.Owner.String()),
+				sdk.NewAttribute(sdk.AttributeKeyAction, msg.Type()),
+				sdk.NewAttribute(types.AttributeKeyDomainName, msg.Domain),
+				sdk.NewAttribute(types.AttributeKeyOwner, msg.Owner.String()),
+			}, accountNameAttributes(accounts)...)...,
+		),
+	)
+	return &sdk.Result{
+		Events: ctx.EventManager().Events(),
+	}, nil
+}
+
+// bulkDomainController returns the controller of the domain after checking
+// that it is a closed domain, not expired, administered by admin
+func bulkDomainController(ctx sdk.Context, k keeper.Keeper, name string, admin sdk.AccAddress) (*domain.Domain, error) {
+	domainCtrl := domain.NewController(ctx, k, name)
+	if err := domainCtrl.
+		MustExist().
+		Type(types.ClosedDomain).
+		Admin(admin).
+		NotExpired().
+		Validate(); err != nil {
+		return nil, err
+	}
+	return domainCtrl, nil
+}
+
+// bulkFee returns the fee of msg, the msg changing a single account, applied to count accounts
+func bulkFee(ctx sdk.Context, k keeper.Keeper, d types.Domain, msg sdk.Msg, count int) sdk.Coin {
+	fee := fees.NewController(ctx, k, d).GetFee(msg)
+	return sdk.NewCoin(fee.Denom, fee.Amount.MulRaw(int64(count)))
+}
+
+// withResource returns the resources with the provided one, which replaces the resource
+// with the same URI if any, and whether they changed
+func withResource(resources []types.Resource, resource types.Resource) ([]types.Resource, bool) {
+	updated := make([]types.Resource, 0, len(resources)+1)
+	replaced := false
+	for _, r := range resources {
+		if r.URI == resource.URI {
+			if r.Resource == resource.Resource {
+				return resources, false
+			}
+			r = resource
+			replaced = true
+		}
+		updated = append(updated, r)
+	}
+	if !replaced {
+		updated = append(updated, resource)
+	}
+	return updated, true
+}
+
+// accountNameAttributes returns an account name event attribute for each account
+func accountNameAttributes(accounts []types.Account) []sdk.Attribute {
+	attributes := make([]sdk.Attribute, len(accounts))
+	for i, a := range accounts {
+		attributes[i] = sdk.NewAttribute(types.AttributeKeyAccountName, *a.Name)
+	}
+	return attributes
+}
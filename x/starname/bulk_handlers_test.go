@@ -0,0 +1,323 @@
+package starname
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/iov-one/iovns/pkg/utils"
+	"github.com/iov-one/iovns/x/configuration"
+	"github.com/iov-one/iovns/x/starname/keeper"
+	"github.com/iov-one/iovns/x/starname/keeper/executor"
+	"github.com/iov-one/iovns/x/starname/types"
+)
+
+func Test_handlerBulkAccounts(t *testing.T) {
+	// createDomain creates a domain administered by alice with the accounts
+	// one and two owned by bob and the account three owned by charlie
+	createDomain := func(typ types.DomainType) func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+		return func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+			keeper.GetConfigSetter(k.ConfigurationKeeper).SetConfig(ctx, configuration.Config{
+				DomainGracePeriod:  24 * time.Hour,
+				AccountGracePeriod: 24 * time.Hour,
+				ResourcesMax:       2,
+				ValidURI:           "^[a-z0-9:]+$",
+				ValidResource:      "^[a-z0-9]+$",
+			})
+			executor.NewDomain(ctx, k, types.Domain{
+				Name:       "test",
+				Admin:      keeper.AliceKey,
+				ValidUntil: utils.TimeToSeconds(ctx.BlockTime().Add(100 * time.Hour)),
+				Type:       typ,
+			}).Create()
+			owners := map[string]sdk.AccAddress{"one": keeper.BobKey, "two": keeper.BobKey, "three": keeper.CharlieKey}
+			for name, owner := range owners {
+				executor.NewAccount(ctx, k, types.Account{
+					Domain:     "test",
+					Name:       utils.StrPtr(name),
+					Owner:      owner,
+					ValidUntil: utils.TimeToSeconds(ctx.BlockTime().Add(100 * time.Hour)),
+					Resources:  []types.Resource{{URI: "asset:one", Resource: "one"}},
+				}).Create()
+			}
+		}
+	}
+	getAccount := func(k keeper.Keeper, ctx sdk.Context, name string) (types.Account, bool) {
+		account := new(types.Account)
+		ok := k.AccountStore(ctx).Read((&types.Account{Domain: "test", Name: utils.StrPtr(name)}).PrimaryKey(), account)
+		return *account, ok
+	}
+	cases := map[string]keeper.SubTest{
+		"success transfer accounts": {
+			BeforeTest: createDomain(types.ClosedDomain),
+			Test: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				_, err := handlerMsgTransferAccounts(ctx, k, &types.MsgTransferAccounts{
+					Domain:   "test",
+					Names:    []string{"one", "three"},
+					Owner:    keeper.AliceKey,
+					NewOwner: keeper.CharlieKey,
+					Reset:    true,
+				})
+				if err != nil {
+					t.Fatalf("handlerMsgTransferAccounts() got error: %s", err)
+				}
+			},
+			AfterTest: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				for _, name := range []string{"one", "three"} {
+					account, _ := getAccount(k, ctx, name)
+					if !account.Owner.Equals(keeper.CharlieKey) {
+						t.Fatalf("account %s: unexpected owner: %s", name, account.Owner)
+					}
+					if len(account.Resources) != 0 {
+						t.Fatalf("account %s: resources not reset", name)
+					}
+				}
+				account, _ := getAccount(k, ctx, "two")
+				if !account.Owner.Equals(keeper.BobKey) {
+					t.Fatalf("account two: unexpected owner: %s", account.Owner)
+				}
+			},
+		},
+		"fail transfer accounts of open domain": {
+			BeforeTest: createDomain(types.OpenDomain),
+			Test: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				_, err := handlerMsgTransferAccounts(ctx, k, &types.MsgTransferAccounts{
+					Domain:   "test",
+					Names:    []string{"one"},
+					Owner:    keeper.AliceKey,
+					NewOwner: keeper.CharlieKey,
+				})
+				if !errors.Is(err, types.ErrInvalidDomainType) {
+					t.Fatalf("handlerMsgTransferAccounts() expected error: %s, got: %s", types.ErrInvalidDomainType, err)
+				}
+			},
+		},
+		"fail transfer accounts not admin": {
+			BeforeTest: createDomain(types.ClosedDomain),
+			Test: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				_, err := handlerMsgTransferAccounts(ctx, k, &types.MsgTransferAccounts{
+					Domain:   "test",
+					Names:    []string{"one"},
+					Owner:    keeper.BobKey,
+					NewOwner: keeper.CharlieKey,
+				})
+				if !errors.Is(err, types.ErrUnauthorized) {
+					t.Fatalf("handlerMsgTransferAccounts() expected error: %s, got: %s", types.ErrUnauthorized, err)
+				}
+			},
+		},
+		"fail transfer accounts duplicate canonical names": {
+			BeforeTest: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				createDomain(types.ClosedDomain)(t, k, ctx, mocks)
+				executor.NewAccount(ctx, k, types.Account{
+					Domain:     "test",
+					Name:       utils.StrPtr("caf\u00e9"),
+					Owner:      keeper.BobKey,
+					ValidUntil: utils.TimeToSeconds(ctx.BlockTime().Add(100 * time.Hour)),
+				}).Create()
+			},
+			Test: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				msg := &types.MsgTransferAccounts{
+					Domain:   "test",
+					Names:    []string{"caf\u00e9", "cafe\u0301"},
+					Owner:    keeper.AliceKey,
+					NewOwner: keeper.CharlieKey,
+				}
+				if err := msg.ValidateBasic(); err != nil {
+					t.Fatalf("ValidateBasic() got error: %s", err)
+				}
+				_, err := NewHandler(k)(ctx, msg)
+				if !errors.Is(err, types.ErrInvalidRequest) {
+					t.Fatalf("handlerMsgTransferAccounts() expected error: %s, got: %s", types.ErrInvalidRequest, err)
+				}
+			},
+		},
+		"fail transfer accounts missing account": {
+			BeforeTest: createDomain(types.ClosedDomain),
+			Test: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				_, err := handlerMsgTransferAccounts(ctx, k, &types.MsgTransferAccounts{
+					Domain:   "test",
+					Names:    []string{"one", "missing"},
+					Owner:    keeper.AliceKey,
+					NewOwner: keeper.CharlieKey,
+				})
+				if !errors.Is(err, types.ErrAccountDoesNotExist) {
+					t.Fatalf("handlerMsgTransferAccounts() expected error: %s, got: %s", types.ErrAccountDoesNotExist, err)
+				}
+			},
+			AfterTest: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				account, _ := getAccount(k, ctx, "one")
+				if !account.Owner.Equals(keeper.BobKey) {
+					t.Fatalf("account one: unexpected owner: %s", account.Owner)
+				}
+			},
+		},
+		"success delete accounts by owner": {
+			BeforeTest: createDomain(types.ClosedDomain),
+			Test: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				_, err := handlerMsgDeleteAccountsByOwner(ctx, k, &types.MsgDeleteAccountsByOwner{
+					Domain:       "test",
+					Owner:        keeper.AliceKey,
+					AccountOwner: keeper.BobKey,
+				})
+				if err != nil {
+					t.Fatalf("handlerMsgDeleteAccountsByOwner() got error: %s", err)
+				}
+			},
+			AfterTest: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				for _, name := range []string{"one", "two"} {
+					if _, ok := getAccount(k, ctx, name); ok {
+						t.Fatalf("account %s not deleted", name)
+					}
+				}
+				if _, ok := getAccount(k, ctx, "three"); !ok {
+					t.Fatal("account three deleted")
+				}
+				if _, ok := getAccount(k, ctx, types.EmptyAccountName); !ok {
+					t.Fatal("empty account deleted")
+				}
+				if count := k.GetAccountCount(ctx, "test"); count != 1 {
+					t.Fatalf("unexpected account count: %d", count)
+				}
+			},
+		},
+		"fail delete accounts by owner no account": {
+			BeforeTest: createDomain(types.ClosedDomain),
+			Test: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				_, err := handlerMsgDeleteAccountsByOwner(ctx, k, &types.MsgDeleteAccountsByOwner{
+					Domain:       "test",
+					Owner:        keeper.AliceKey,
+					AccountOwner: keeper.AliceKey,
+				})
+				if !errors.Is(err, types.ErrAccountDoesNotExist) {
+					t.Fatalf("handlerMsgDeleteAccountsByOwner() expected error: %s, got: %s", types.ErrAccountDoesNotExist, err)
+				}
+			},
+		},
+		"success set accounts resource append": {
+			BeforeTest: createDomain(types.ClosedDomain),
+			Test: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				_, err := handlerMsgSetAccountsResource(ctx, k, &types.MsgSetAccountsResource{
+					Domain:   "test",
+					Owner:    keeper.AliceKey,
+					Resource: types.Resource{URI: "asset:two", Resource: "two"},
+				})
+				if err != nil {
+					t.Fatalf("handlerMsgSetAccountsResource() got error: %s", err)
+				}
+			},
+			AfterTest: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				for _, name := range []string{"one", "two", "three"} {
+					account, _ := getAccount(k, ctx, name)
+					if len(account.Resources) != 2 || account.Resources[1].URI != "asset:two" {
+						t.Fatalf("account %s: unexpected resources: %+v", name, account.Resources)
+					}
+				}
+			},
+		},
+		"success set accounts resource replace": {
+			BeforeTest: createDomain(types.ClosedDomain),
+			Test: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				_, err := handlerMsgSetAccountsResource(ctx, k, &types.MsgSetAccountsResource{
+					Domain:   "test",
+					Owner:    keeper.AliceKey,
+					Resource: types.Resource{URI: "asset:one", Resource: "replaced"},
+				})
+				if err != nil {
+					t.Fatalf("handlerMsgSetAccountsResource() got error: %s", err)
+				}
+			},
+			AfterTest: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				account, _ := getAccount(k, ctx, "one")
+				if len(account.Resources) != 1 || account.Resources[0].Resource != "replaced" {
+					t.Fatalf("unexpected resources: %+v", account.Resources)
+				}
+			},
+		},
+		"fail set accounts resource already set": {
+			BeforeTest: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				createDomain(types.ClosedDomain)(t, k, ctx, mocks)
+				// the empty account must also have the resource
+				account, _ := getAccount(k, ctx, types.EmptyAccountName)
+				executor.NewAccount(ctx, k, account).ReplaceResources([]types.Resource{{URI: "asset:one", Resource: "one"}})
+			},
+			Test: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				_, err := handlerMsgSetAccountsResource(ctx, k, &types.MsgSetAccountsResource{
+					Domain:   "test",
+					Owner:    keeper.AliceKey,
+					Resource: types.Resource{URI: "asset:one", Resource: "one"},
+				})
+				if !errors.Is(err, types.ErrInvalidRequest) {
+					t.Fatalf("handlerMsgSetAccountsResource() expected error: %s, got: %s", types.ErrInvalidRequest, err)
+				}
+			},
+		},
+		"success set accounts resource sent again updates the remaining accounts": {
+			BeforeTest: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				createDomain(types.ClosedDomain)(t, k, ctx, mocks)
+				// with the empty account and the accounts one, two and three the domain has 2*BulkAccountsMax accounts
+				for i := 4; i < 2*types.BulkAccountsMax; i++ {
+					executor.NewAccount(ctx, k, types.Account{
+						Domain:     "test",
+						Name:       utils.StrPtr(fmt.Sprintf("a%03d", i)),
+						Owner:      keeper.BobKey,
+						ValidUntil: utils.TimeToSeconds(ctx.BlockTime().Add(100 * time.Hour)),
+					}).Create()
+				}
+			},
+			Test: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				msg := &types.MsgSetAccountsResource{
+					Domain:   "test",
+					Owner:    keeper.AliceKey,
+					Resource: types.Resource{URI: "asset:two", Resource: "two"},
+				}
+				for i := 0; i < 2; i++ {
+					if _, err := handlerMsgSetAccountsResource(ctx, k, msg); err != nil {
+						t.Fatalf("handlerMsgSetAccountsResource() got error: %s", err)
+					}
+				}
+				_, err := handlerMsgSetAccountsResource(ctx, k, msg)
+				if !errors.Is(err, types.ErrInvalidRequest) {
+					t.Fatalf("handlerMsgSetAccountsResource() expected error: %s, got: %s", types.ErrInvalidRequest, err)
+				}
+			},
+			AfterTest: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				accounts := 0
+				filter := k.AccountStore(ctx).Filter(&types.Account{Domain: "test"})
+				for ; filter.Valid(); filter.Next() {
+					accounts++
+					account := new(types.Account)
+					filter.Read(account)
+					updated := false
+					for _, r := range account.Resources {
+						if r == (types.Resource{URI: "asset:two", Resource: "two"}) {
+							updated = true
+						}
+					}
+					if !updated {
+						t.Fatalf("account %s: resource not set", *account.Name)
+					}
+				}
+				if accounts != 2*types.BulkAccountsMax {
+					t.Fatalf("unexpected number of accounts: %d", accounts)
+				}
+			},
+		},
+		"fail set accounts resource invalid resource": {
+			BeforeTest: createDomain(types.ClosedDomain),
+			Test: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				_, err := handlerMsgSetAccountsResource(ctx, k, &types.MsgSetAccountsResource{
+					Domain:   "test",
+					Owner:    keeper.AliceKey,
+					Resource: types.Resource{URI: "INVALID", Resource: "one"},
+				})
+				if !errors.Is(err, types.ErrInvalidResource) {
+					t.Fatalf("handlerMsgSetAccountsResource() expected error: %s, got: %s", types.ErrInvalidResource, err)
+				}
+			},
+		},
+	}
+	keeper.RunTests(t, cases)
+}
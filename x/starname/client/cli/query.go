@@ -32,6 +32,12 @@ func GetQueryCmd(moduleQueryPath string, cdc *codec.Codec) *cobra.Command {
 			getQueryOwnerAccount(moduleQueryPath, cdc),
 			getQueryOwnerDomain(moduleQueryPath, cdc),
 			getQueryResourcesAccount(moduleQueryPath, cdc),
+			getQueryStarnameHistory(moduleQueryPath, cdc),
+			getQueryRegistrationPolicy(moduleQueryPath, cdc),
+			getQueryInviteCode(moduleQueryPath, cdc),
+			getQueryTransferOffers(moduleQueryPath, cdc),
+			getQueryTransferOffer(moduleQueryPath, cdc),
+			getQuerySponsorships(moduleQueryPath, cdc),
 		)...,
 	)
 	return domainQueryCmd
@@ -290,3 +296,234 @@ func getQueryResourcesAccount(modulePath string, cdc *codec.Codec) *cobra.Comman
 	// return cmd
 	return cmd
 }
+
+func getQueryStarnameHistory(modulePath string, cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "history",
+		Short: "get the history of changes of a domain or an account",
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			// get flags
+			starname, err := cmd.Flags().GetString("starname")
+			if err != nil {
+				return err
+			}
+			rpp, err := cmd.Flags().GetInt("rpp")
+			if err != nil {
+				return err
+			}
+			offset, err := cmd.Flags().GetInt("offset")
+			if err != nil {
+				return err
+			}
+			// get query & validate
+			q := keeper.QueryStarnameHistory{
+				Starname:       starname,
+				ResultsPerPage: rpp,
+				Offset:         offset,
+			}
+			if err = q.Validate(); err != nil {
+				return err
+			}
+			// get query path
+			path := fmt.Sprintf("custom/%s/%s", modulePath, q.QueryPath())
+			return processQueryCmd(cdc, path, q, new(keeper.QueryStarnameHistoryResponse))
+		},
+	}
+	// add flags
+	cmd.Flags().String("starname", "", "the domain name or the account in name*domain format")
+	cmd.Flags().Int("offset", 1, "the page offset")
+	cmd.Flags().Int("rpp", 100, "results per page")
+	// return cmd
+	return cmd
+}
+
+func getQueryRegistrationPolicy(modulePath string, cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "registration-policy",
+		Short: "get the account registration policy of a closed domain",
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			// get flags
+			domain, err := cmd.Flags().GetString("domain")
+			if err != nil {
+				return err
+			}
+			// get query & validate
+			q := keeper.QueryRegistrationPolicy{Domain: domain}
+			if err = q.Validate(); err != nil {
+				return err
+			}
+			// get query path
+			path := fmt.Sprintf("custom/%s/%s", modulePath, q.QueryPath())
+			return processQueryCmd(cdc, path, q, new(keeper.QueryRegistrationPolicyResponse))
+		},
+	}
+	// add flags
+	cmd.Flags().String("domain", "", "the domain name")
+	// return cmd
+	return cmd
+}
+
+func getQueryInviteCode(modulePath string, cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "invite-code",
+		Short: "check if an invite code of a domain was already used",
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			// get flags
+			domain, err := cmd.Flags().GetString("domain")
+			if err != nil {
+				return err
+			}
+			code, err := cmd.Flags().GetString("code")
+			if err != nil {
+				return err
+			}
+			// get query & validate
+			q := keeper.QueryInviteCode{Domain: domain, Code: code}
+			if err = q.Validate(); err != nil {
+				return err
+			}
+			// get query path
+			path := fmt.Sprintf("custom/%s/%s", modulePath, q.QueryPath())
+			return processQueryCmd(cdc, path, q, new(keeper.QueryInviteCodeResponse))
+		},
+	}
+	// add flags
+	cmd.Flags().String("domain", "", "the domain name")
+	cmd.Flags().String("code", "", "the invite code")
+	// return cmd
+	return cmd
+}
+
+func getQueryTransferOffers(modulePath string, cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "transfer-offers",
+		Short: "get the pending transfer offers made by an owner or received by a recipient",
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			// get flags
+			var addrs [2]sdk.AccAddress
+			for i, flag := range []string{"owner", "recipient"} {
+				str, err := cmd.Flags().GetString(flag)
+				if err != nil {
+					return err
+				}
+				if str == "" {
+					continue
+				}
+				if addrs[i], err = sdk.AccAddressFromBech32(str); err != nil {
+					return err
+				}
+			}
+			rpp, err := cmd.Flags().GetInt("rpp")
+			if err != nil {
+				return err
+			}
+			offset, err := cmd.Flags().GetInt("offset")
+			if err != nil {
+				return err
+			}
+			// get query & validate
+			q := keeper.QueryTransferOffers{
+				Owner:          addrs[0],
+				Recipient:      addrs[1],
+				ResultsPerPage: rpp,
+				Offset:         offset,
+			}
+			if err = q.Validate(); err != nil {
+				return err
+			}
+			// get query path
+			path := fmt.Sprintf("custom/%s/%s", modulePath, q.QueryPath())
+			return processQueryCmd(cdc, path, q, new(keeper.QueryTransferOffersResponse))
+		},
+	}
+	// add flags
+	cmd.Flags().String("owner", "", "bech32 address of the owner who made the offers")
+	cmd.Flags().String("recipient", "", "bech32 address of the recipient of the offers")
+	cmd.Flags().Int("offset", 1, "page number")
+	cmd.Flags().Int("rpp", 100, "results per page")
+	// return cmd
+	return cmd
+}
+
+func getQueryTransferOffer(modulePath string, cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "transfer-offer",
+		Short: "get a transfer offer by its id or the pending transfer offer of a domain or an account",
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			// get flags
+			id, err := cmd.Flags().GetUint64("id")
+			if err != nil {
+				return err
+			}
+			starname, err := cmd.Flags().GetString("starname")
+			if err != nil {
+				return err
+			}
+			// get query & validate
+			q := keeper.QueryTransferOffer{ID: id, Starname: starname}
+			if err = q.Validate(); err != nil {
+				return err
+			}
+			// get query path
+			path := fmt.Sprintf("custom/%s/%s", modulePath, q.QueryPath())
+			return processQueryCmd(cdc, path, q, new(keeper.QueryTransferOfferResponse))
+		},
+	}
+	// add flags
+	cmd.Flags().Uint64("id", 0, "the id of the transfer offer")
+	cmd.Flags().String("starname", "", "the domain name or the account in name*domain format")
+	// return cmd
+	return cmd
+}
+
+func getQuerySponsorships(modulePath string, cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sponsorships",
+		Short: "get the renewal sponsorships of a sponsor or of a domain or an account",
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			// get flags
+			sponsorStr, err := cmd.Flags().GetString("sponsor")
+			if err != nil {
+				return err
+			}
+			var sponsor sdk.AccAddress
+			if sponsorStr != "" {
+				if sponsor, err = sdk.AccAddressFromBech32(sponsorStr); err != nil {
+					return err
+				}
+			}
+			starname, err := cmd.Flags().GetString("starname")
+			if err != nil {
+				return err
+			}
+			rpp, err := cmd.Flags().GetInt("rpp")
+			if err != nil {
+				return err
+			}
+			offset, err := cmd.Flags().GetInt("offset")
+			if err != nil {
+				return err
+			}
+			// get query & validate
+			q := keeper.QuerySponsorships{
+				Sponsor:        sponsor,
+				Starname:       starname,
+				ResultsPerPage: rpp,
+				Offset:         offset,
+			}
+			if err = q.Validate(); err != nil {
+				return err
+			}
+			// get query path
+			path := fmt.Sprintf("custom/%s/%s", modulePath, q.QueryPath())
+			return processQueryCmd(cdc, path, q, new(keeper.QuerySponsorshipsResponse))
+		},
+	}
+	// add flags
+	cmd.Flags().String("sponsor", "", "bech32 address of the sponsor")
+	cmd.Flags().String("starname", "", "the domain name or the account in name*domain format")
+	cmd.Flags().Int("offset", 1, "page number")
+	cmd.Flags().Int("rpp", 100, "results per page")
+	// return cmd
+	return cmd
+}
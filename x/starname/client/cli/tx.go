@@ -45,6 +45,19 @@ func GetTxCmd(storeKey string, cdc *codec.Codec) *cobra.Command {
 		getCmdDelAccountCerts(cdc),
 		getCmdRegisterAccount(cdc),
 		getCmdSetAccountMetadata(cdc),
+		getCmdSetRegistrationPolicy(cdc),
+		getCmdSignRegistrationCredential(cdc),
+		getCmdOfferDomainTransfer(cdc),
+		getCmdOfferAccountTransfer(cdc),
+		getCmdAcceptTransferOffer(cdc),
+		getCmdRejectTransferOffer(cdc),
+		getCmdCancelTransferOffer(cdc),
+		getCmdDepositSponsorship(cdc),
+		getCmdWithdrawSponsorship(cdc),
+		getCmdTransferAccounts(cdc),
+		getCmdDeleteAccountsByOwner(cdc),
+		getCmdSetAccountsResource(cdc),
+		getCmdSetAccountValidUntil(cdc),
 	)...)
 	return domainTxCmd
 }
@@ -667,6 +680,25 @@ func getCmdRegisterAccount(cdc *codec.Codec) *cobra.Command {
 					return
 				}
 			}
+			credentialFile, err := cmd.Flags().GetString("credential-file")
+			if err != nil {
+				return err
+			}
+			var credential *types.RegistrationCredential
+			if credentialFile != "" {
+				b, err := ioutil.ReadFile(credentialFile)
+				if err != nil {
+					return err
+				}
+				credential = new(types.RegistrationCredential)
+				if err := cdc.UnmarshalJSON(b, credential); err != nil {
+					return sdkerrors.Wrapf(types.ErrInvalidCredential, "err: %s", err)
+				}
+			}
+			validUntil, err := cmd.Flags().GetInt64("valid-until")
+			if err != nil {
+				return err
+			}
 			// build msg
 			msg := &types.MsgRegisterAccount{
 				Domain:       domain,
@@ -675,6 +707,8 @@ func getCmdRegisterAccount(cdc *codec.Codec) *cobra.Command {
 				Registerer:   cliCtx.GetFromAddress(),
 				FeePayerAddr: feePayer,
 				Broker:       broker,
+				Credential:   credential,
+				ValidUntil:   validUntil,
 			}
 			// check if valid
 			if err = msg.ValidateBasic(); err != nil {
@@ -689,6 +723,8 @@ func getCmdRegisterAccount(cdc *codec.Codec) *cobra.Command {
 	cmd.Flags().String("owner", "", "the address of the owner, if no owner provided signer is the owner")
 	cmd.Flags().String("fee-payer", "", "address of the fee payer, optional")
 	cmd.Flags().String("broker", "", "address of the broker, optional")
+	cmd.Flags().String("credential-file", "", "path of the registration credential file in json format, optional")
+	cmd.Flags().Int64("valid-until", 0, "the unix timestamp at which the account expires, only the admin of a closed domain can set it, optional")
 	return cmd
 }
 
@@ -814,3 +850,617 @@ func getCmdSetAccountMetadata(cdc *codec.Codec) *cobra.Command {
 	// return cmd
 	return cmd
 }
+
+// parseAddresses parses a list of bech32 addresses
+func parseAddresses(strs []string) ([]sdk.AccAddress, error) {
+	addrs := make([]sdk.AccAddress, len(strs))
+	for i, str := range strs {
+		addr, err := sdk.AccAddressFromBech32(str)
+		if err != nil {
+			return nil, err
+		}
+		addrs[i] = addr
+	}
+	return addrs, nil
+}
+
+func getCmdSetRegistrationPolicy(cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "set-registration-policy",
+		Short: "set the account registration policy of a closed domain",
+		Long:  "set the account registration policy of a closed domain, an empty policy removes the existing one",
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+			inBuf := bufio.NewReader(cmd.InOrStdin())
+			txBuilder := auth.NewTxBuilderFromCLI(inBuf).WithTxEncoder(utils.GetTxEncoder(cdc))
+			// get flags
+			domain, err := cmd.Flags().GetString("domain")
+			if err != nil {
+				return
+			}
+			allowStrs, err := cmd.Flags().GetStringSlice("allow")
+			if err != nil {
+				return
+			}
+			allowList, err := parseAddresses(allowStrs)
+			if err != nil {
+				return
+			}
+			issuerStrs, err := cmd.Flags().GetStringSlice("issuer")
+			if err != nil {
+				return
+			}
+			issuers, err := parseAddresses(issuerStrs)
+			if err != nil {
+				return
+			}
+			inviteCodes, err := cmd.Flags().GetBool("invite-codes")
+			if err != nil {
+				return
+			}
+			feePayerStr, err := cmd.Flags().GetString("fee-payer")
+			if err != nil {
+				return err
+			}
+			var feePayer sdk.AccAddress
+			if feePayerStr != "" {
+				feePayer, err = sdk.AccAddressFromBech32(feePayerStr)
+				if err != nil {
+					return
+				}
+			}
+			// build msg
+			msg := &types.MsgSetRegistrationPolicy{
+				Domain:             domain,
+				Owner:              cliCtx.GetFromAddress(),
+				AllowList:          allowList,
+				CertificateIssuers: issuers,
+				InviteCodes:        inviteCodes,
+				FeePayerAddr:       feePayer,
+			}
+			// check if valid
+			if err = msg.ValidateBasic(); err != nil {
+				return err
+			}
+			// broadcast request
+			return utils.GenerateOrBroadcastMsgs(cliCtx, txBuilder, []sdk.Msg{msg})
+		},
+	}
+	// add flags
+	cmd.Flags().String("domain", "", "the closed domain name")
+	cmd.Flags().StringSlice("allow", nil, "comma separated addresses allowed to register accounts")
+	cmd.Flags().StringSlice("issuer", nil, "comma separated addresses of the trusted certificate issuers")
+	cmd.Flags().Bool("invite-codes", false, "accept one-time invite codes signed by the domain admin")
+	cmd.Flags().String("fee-payer", "", "address of the fee payer, optional")
+	return cmd
+}
+
+// getCmdSignRegistrationCredential signs a registration credential offline,
+// the output can be given to register-account through the credential-file flag
+func getCmdSignRegistrationCredential(cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sign-registration-credential",
+		Short: "sign a certificate or an invite code which allows to register an account in a closed domain",
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+			inBuf := bufio.NewReader(cmd.InOrStdin())
+			txBuilder := auth.NewTxBuilderFromCLI(inBuf)
+			// get flags
+			typ, err := cmd.Flags().GetString("type")
+			if err != nil {
+				return
+			}
+			domain, err := cmd.Flags().GetString("domain")
+			if err != nil {
+				return
+			}
+			registererStr, err := cmd.Flags().GetString("registerer")
+			if err != nil {
+				return
+			}
+			registerer, err := sdk.AccAddressFromBech32(registererStr)
+			if err != nil {
+				return
+			}
+			code, err := cmd.Flags().GetString("code")
+			if err != nil {
+				return
+			}
+			if cliCtx.ChainID == "" {
+				return fmt.Errorf("the chain id is required")
+			}
+			credential := types.RegistrationCredential{
+				Type: types.CredentialType(typ),
+				Code: code,
+			}
+			signBytes := types.CredentialSignBytes(cliCtx.ChainID, credential.Type, domain, registerer, code)
+			credential.Signature, credential.PubKey, err = txBuilder.Keybase().Sign(cliCtx.GetFromName(), "", signBytes)
+			if err != nil {
+				return
+			}
+			if err = credential.ValidateBasic(); err != nil {
+				return err
+			}
+			if err = credential.Verify(cliCtx.ChainID, domain, registerer); err != nil {
+				return err
+			}
+			return cliCtx.PrintOutput(credential)
+		},
+	}
+	// add flags
+	cmd.Flags().String("type", string(types.InviteCredential), "the credential type, either certificate or invite")
+	cmd.Flags().String("domain", "", "the closed domain name")
+	cmd.Flags().String("registerer", "", "the address allowed to register the account")
+	cmd.Flags().String("code", "", "the one-time invite code, required by invites")
+	return cmd
+}
+
+// getFeePayer parses the optional fee-payer flag
+func getFeePayer(cmd *cobra.Command) (sdk.AccAddress, error) {
+	feePayerStr, err := cmd.Flags().GetString("fee-payer")
+	if err != nil || feePayerStr == "" {
+		return nil, err
+	}
+	return sdk.AccAddressFromBech32(feePayerStr)
+}
+
+// getOfferTerms parses the recipient, price and expiry flags of transfer offers
+func getOfferTerms(cmd *cobra.Command) (recipient sdk.AccAddress, price sdk.Coins, expiry int64, err error) {
+	recipientStr, err := cmd.Flags().GetString("recipient")
+	if err != nil {
+		return
+	}
+	recipient, err = sdk.AccAddressFromBech32(recipientStr)
+	if err != nil {
+		return
+	}
+	priceStr, err := cmd.Flags().GetString("price")
+	if err != nil {
+		return
+	}
+	price, err = sdk.ParseCoins(priceStr)
+	if err != nil {
+		return
+	}
+	expiry, err = cmd.Flags().GetInt64("expiry")
+	return
+}
+
+// addOfferTermsFlags adds the flags parsed by getOfferTerms
+func addOfferTermsFlags(cmd *cobra.Command) {
+	cmd.Flags().String("recipient", "", "the recipient address in bech32 format")
+	cmd.Flags().String("price", "", "the amount the recipient pays on accept, optional")
+	cmd.Flags().Int64("expiry", 0, "the unix timestamp after which the offer cannot be accepted, optional")
+	cmd.Flags().String("fee-payer", "", "address of the fee payer, optional")
+}
+
+func getCmdOfferDomainTransfer(cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "offer-domain-transfer",
+		Short: "offer the transfer of a domain, which is applied once accepted by the recipient",
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+			inBuf := bufio.NewReader(cmd.InOrStdin())
+			txBuilder := auth.NewTxBuilderFromCLI(inBuf).WithTxEncoder(utils.GetTxEncoder(cdc))
+			// get flags
+			domain, err := cmd.Flags().GetString("domain")
+			if err != nil {
+				return
+			}
+			transferFlag, err := cmd.Flags().GetInt("transfer-flag")
+			if err != nil {
+				return
+			}
+			recipient, price, expiry, err := getOfferTerms(cmd)
+			if err != nil {
+				return
+			}
+			feePayer, err := getFeePayer(cmd)
+			if err != nil {
+				return
+			}
+			// build msg
+			msg := &types.MsgOfferDomainTransfer{
+				Domain:       domain,
+				Owner:        cliCtx.GetFromAddress(),
+				Recipient:    recipient,
+				TransferFlag: types.TransferFlag(transferFlag),
+				Price:        price,
+				Expiry:       expiry,
+				FeePayerAddr: feePayer,
+			}
+			// check if valid
+			if err = msg.ValidateBasic(); err != nil {
+				return err
+			}
+			// broadcast request
+			return utils.GenerateOrBroadcastMsgs(cliCtx, txBuilder, []sdk.Msg{msg})
+		},
+	}
+	// add flags
+	cmd.Flags().String("domain", "", "the domain name to offer")
+	cmd.Flags().Int("transfer-flag", types.TransferResetNone, "transfer flags for a domain")
+	addOfferTermsFlags(cmd)
+	return cmd
+}
+
+func getCmdOfferAccountTransfer(cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "offer-account-transfer",
+		Short: "offer the transfer of an account, which is applied once accepted by the recipient",
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+			inBuf := bufio.NewReader(cmd.InOrStdin())
+			txBuilder := auth.NewTxBuilderFromCLI(inBuf).WithTxEncoder(utils.GetTxEncoder(cdc))
+			// get flags
+			domain, err := cmd.Flags().GetString("domain")
+			if err != nil {
+				return
+			}
+			name, err := cmd.Flags().GetString("name")
+			if err != nil {
+				return
+			}
+			reset, err := cmd.Flags().GetBool("reset")
+			if err != nil {
+				return
+			}
+			recipient, price, expiry, err := getOfferTerms(cmd)
+			if err != nil {
+				return
+			}
+			feePayer, err := getFeePayer(cmd)
+			if err != nil {
+				return
+			}
+			// build msg
+			msg := &types.MsgOfferAccountTransfer{
+				Domain:       domain,
+				Name:         name,
+				Owner:        cliCtx.GetFromAddress(),
+				Recipient:    recipient,
+				Reset:        reset,
+				Price:        price,
+				Expiry:       expiry,
+				FeePayerAddr: feePayer,
+			}
+			// check if valid
+			if err = msg.ValidateBasic(); err != nil {
+				return err
+			}
+			// broadcast request
+			return utils.GenerateOrBroadcastMsgs(cliCtx, txBuilder, []sdk.Msg{msg})
+		},
+	}
+	// add flags
+	cmd.Flags().String("domain", "", "the domain name of the account")
+	cmd.Flags().String("name", "", "the name of the account to offer")
+	cmd.Flags().Bool("reset", false, "true: reset the account content on transfer")
+	addOfferTermsFlags(cmd)
+	return cmd
+}
+
+// getCmdTransferOfferAnswer builds the commands used to accept, reject and cancel
+// transfer offers, which are identified by their id and signed by the from address
+func getCmdTransferOfferAnswer(cdc *codec.Codec, use, short string, newMsg func(id uint64, signer, feePayer sdk.AccAddress) sdk.Msg) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   use,
+		Short: short,
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+			inBuf := bufio.NewReader(cmd.InOrStdin())
+			txBuilder := auth.NewTxBuilderFromCLI(inBuf).WithTxEncoder(utils.GetTxEncoder(cdc))
+			// get flags
+			id, err := cmd.Flags().GetUint64("id")
+			if err != nil {
+				return
+			}
+			feePayer, err := getFeePayer(cmd)
+			if err != nil {
+				return
+			}
+			// build msg
+			msg := newMsg(id, cliCtx.GetFromAddress(), feePayer)
+			// check if valid
+			if err = msg.ValidateBasic(); err != nil {
+				return err
+			}
+			// broadcast request
+			return utils.GenerateOrBroadcastMsgs(cliCtx, txBuilder, []sdk.Msg{msg})
+		},
+	}
+	// add flags
+	cmd.Flags().Uint64("id", 0, "the id of the transfer offer")
+	cmd.Flags().String("fee-payer", "", "address of the fee payer, optional")
+	return cmd
+}
+
+func getCmdAcceptTransferOffer(cdc *codec.Codec) *cobra.Command {
+	return getCmdTransferOfferAnswer(cdc, "accept-transfer-offer", "accept a transfer offer, paying its price if any",
+		func(id uint64, signer, feePayer sdk.AccAddress) sdk.Msg {
+			return &types.MsgAcceptTransferOffer{ID: id, Recipient: signer, FeePayerAddr: feePayer}
+		})
+}
+
+func getCmdRejectTransferOffer(cdc *codec.Codec) *cobra.Command {
+	return getCmdTransferOfferAnswer(cdc, "reject-transfer-offer", "reject a transfer offer",
+		func(id uint64, signer, feePayer sdk.AccAddress) sdk.Msg {
+			return &types.MsgRejectTransferOffer{ID: id, Recipient: signer, FeePayerAddr: feePayer}
+		})
+}
+
+func getCmdCancelTransferOffer(cdc *codec.Codec) *cobra.Command {
+	return getCmdTransferOfferAnswer(cdc, "cancel-transfer-offer", "cancel a transfer offer made by the signer",
+		func(id uint64, signer, feePayer sdk.AccAddress) sdk.Msg {
+			return &types.MsgCancelTransferOffer{ID: id, Owner: signer, FeePayerAddr: feePayer}
+		})
+}
+
+// getCmdSponsorship builds the commands used to deposit into and withdraw from
+// the renewal sponsorship of a domain or an account made by the from address
+func getCmdSponsorship(cdc *codec.Codec, use, short string, newMsg func(domain, name string, sponsor sdk.AccAddress, amount sdk.Coins, feePayer sdk.AccAddress) sdk.Msg) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   use,
+		Short: short,
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+			inBuf := bufio.NewReader(cmd.InOrStdin())
+			txBuilder := auth.NewTxBuilderFromCLI(inBuf).WithTxEncoder(utils.GetTxEncoder(cdc))
+			// get flags
+			domain, err := cmd.Flags().GetString("domain")
+			if err != nil {
+				return
+			}
+			name, err := cmd.Flags().GetString("name")
+			if err != nil {
+				return
+			}
+			amountStr, err := cmd.Flags().GetString("amount")
+			if err != nil {
+				return
+			}
+			amount, err := sdk.ParseCoins(amountStr)
+			if err != nil {
+				return
+			}
+			feePayer, err := getFeePayer(cmd)
+			if err != nil {
+				return
+			}
+			// build msg
+			msg := newMsg(domain, name, cliCtx.GetFromAddress(), amount, feePayer)
+			// check if valid
+			if err = msg.ValidateBasic(); err != nil {
+				return err
+			}
+			// broadcast request
+			return utils.GenerateOrBroadcastMsgs(cliCtx, txBuilder, []sdk.Msg{msg})
+		},
+	}
+	// add flags
+	cmd.Flags().String("domain", "", "the domain name sponsored, or the domain of the account sponsored")
+	cmd.Flags().String("name", "", "the name of the account sponsored, empty to sponsor the domain")
+	cmd.Flags().String("fee-payer", "", "address of the fee payer, optional")
+	return cmd
+}
+
+func getCmdDepositSponsorship(cdc *codec.Codec) *cobra.Command {
+	cmd := getCmdSponsorship(cdc, "deposit-sponsorship", "deposit coins which renew a domain or an account before it expires",
+		func(domain, name string, sponsor sdk.AccAddress, amount sdk.Coins, feePayer sdk.AccAddress) sdk.Msg {
+			return &types.MsgDepositSponsorship{Domain: domain, Name: name, Sponsor: sponsor, Amount: amount, FeePayerAddr: feePayer}
+		})
+	cmd.Flags().String("amount", "", "the amount deposited")
+	return cmd
+}
+
+func getCmdWithdrawSponsorship(cdc *codec.Codec) *cobra.Command {
+	cmd := getCmdSponsorship(cdc, "withdraw-sponsorship", "withdraw coins from the renewal sponsorship of a domain or an account",
+		func(domain, name string, sponsor sdk.AccAddress, amount sdk.Coins, feePayer sdk.AccAddress) sdk.Msg {
+			return &types.MsgWithdrawSponsorship{Domain: domain, Name: name, Sponsor: sponsor, Amount: amount, FeePayerAddr: feePayer}
+		})
+	cmd.Flags().String("amount", "", "the amount withdrawn, the whole balance if empty")
+	return cmd
+}
+
+func getCmdTransferAccounts(cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "transfer-accounts",
+		Short: "transfer a set of accounts of a closed domain to a new owner",
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+			inBuf := bufio.NewReader(cmd.InOrStdin())
+			txBuilder := auth.NewTxBuilderFromCLI(inBuf).WithTxEncoder(utils.GetTxEncoder(cdc))
+			// get flags
+			domain, err := cmd.Flags().GetString("domain")
+			if err != nil {
+				return
+			}
+			names, err := cmd.Flags().GetStringSlice("names")
+			if err != nil {
+				return
+			}
+			newOwner, err := cmd.Flags().GetString("new-owner")
+			if err != nil {
+				return
+			}
+			newOwnerAddr, err := sdk.AccAddressFromBech32(newOwner)
+			if err != nil {
+				return
+			}
+			reset, err := cmd.Flags().GetBool("reset")
+			if err != nil {
+				return
+			}
+			feePayer, err := getFeePayer(cmd)
+			if err != nil {
+				return
+			}
+			// build msg
+			msg := &types.MsgTransferAccounts{
+				Domain:       domain,
+				Names:        names,
+				Owner:        cliCtx.GetFromAddress(),
+				NewOwner:     newOwnerAddr,
+				Reset:        reset,
+				FeePayerAddr: feePayer,
+			}
+			// check if valid
+			if err = msg.ValidateBasic(); err != nil {
+				return err
+			}
+			// broadcast request
+			return utils.GenerateOrBroadcastMsgs(cliCtx, txBuilder, []sdk.Msg{msg})
+		},
+	}
+	// add flags
+	cmd.Flags().String("domain", "", "the closed domain name")
+	cmd.Flags().StringSlice("names", nil, fmt.Sprintf("comma separated names of the accounts to transfer, at most %d", types.BulkAccountsMax))
+	cmd.Flags().String("new-owner", "", "the new owner address in bech32 format")
+	cmd.Flags().Bool("reset", false, "reset the accounts content")
+	cmd.Flags().String("fee-payer", "", "address of the fee payer, optional")
+	return cmd
+}
+
+func getCmdDeleteAccountsByOwner(cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "delete-accounts-by-owner",
+		Short: fmt.Sprintf("delete up to %d accounts of a closed domain owned by an address", types.BulkAccountsMax),
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+			inBuf := bufio.NewReader(cmd.InOrStdin())
+			txBuilder := auth.NewTxBuilderFromCLI(inBuf).WithTxEncoder(utils.GetTxEncoder(cdc))
+			// get flags
+			domain, err := cmd.Flags().GetString("domain")
+			if err != nil {
+				return
+			}
+			accountOwner, err := cmd.Flags().GetString("account-owner")
+			if err != nil {
+				return
+			}
+			accountOwnerAddr, err := sdk.AccAddressFromBech32(accountOwner)
+			if err != nil {
+				return
+			}
+			feePayer, err := getFeePayer(cmd)
+			if err != nil {
+				return
+			}
+			// build msg
+			msg := &types.MsgDeleteAccountsByOwner{
+				Domain:       domain,
+				Owner:        cliCtx.GetFromAddress(),
+				AccountOwner: accountOwnerAddr,
+				FeePayerAddr: feePayer,
+			}
+			// check if valid
+			if err = msg.ValidateBasic(); err != nil {
+				return err
+			}
+			// broadcast request
+			return utils.GenerateOrBroadcastMsgs(cliCtx, txBuilder, []sdk.Msg{msg})
+		},
+	}
+	// add flags
+	cmd.Flags().String("domain", "", "the closed domain name")
+	cmd.Flags().String("account-owner", "", "the owner of the accounts to delete in bech32 format")
+	cmd.Flags().String("fee-payer", "", "address of the fee payer, optional")
+	return cmd
+}
+
+func getCmdSetAccountsResource(cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "set-accounts-resource",
+		Short: fmt.Sprintf("set a resource on up to %d accounts of a closed domain which do not have it yet", types.BulkAccountsMax),
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+			inBuf := bufio.NewReader(cmd.InOrStdin())
+			txBuilder := auth.NewTxBuilderFromCLI(inBuf).WithTxEncoder(utils.GetTxEncoder(cdc))
+			// get flags
+			domain, err := cmd.Flags().GetString("domain")
+			if err != nil {
+				return
+			}
+			uri, err := cmd.Flags().GetString("uri")
+			if err != nil {
+				return
+			}
+			resource, err := cmd.Flags().GetString("resource")
+			if err != nil {
+				return
+			}
+			feePayer, err := getFeePayer(cmd)
+			if err != nil {
+				return
+			}
+			// build msg
+			msg := &types.MsgSetAccountsResource{
+				Domain:       domain,
+				Owner:        cliCtx.GetFromAddress(),
+				Resource:     types.Resource{URI: uri, Resource: resource},
+				FeePayerAddr: feePayer,
+			}
+			// check if valid
+			if err = msg.ValidateBasic(); err != nil {
+				return err
+			}
+			// broadcast request
+			return utils.GenerateOrBroadcastMsgs(cliCtx, txBuilder, []sdk.Msg{msg})
+		},
+	}
+	// add flags
+	cmd.Flags().String("domain", "", "the closed domain name")
+	cmd.Flags().String("uri", "", "the URI of the resource")
+	cmd.Flags().String("resource", "", "the resource")
+	cmd.Flags().String("fee-payer", "", "address of the fee payer, optional")
+	return cmd
+}
+
+func getCmdSetAccountValidUntil(cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "set-account-valid-until",
+		Short: "set the expiration of an account of a closed domain",
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+			inBuf := bufio.NewReader(cmd.InOrStdin())
+			txBuilder := auth.NewTxBuilderFromCLI(inBuf).WithTxEncoder(utils.GetTxEncoder(cdc))
+			// get flags
+			domain, err := cmd.Flags().GetString("domain")
+			if err != nil {
+				return
+			}
+			name, err := cmd.Flags().GetString("name")
+			if err != nil {
+				return
+			}
+			validUntil, err := cmd.Flags().GetInt64("valid-until")
+			if err != nil {
+				return
+			}
+			feePayer, err := getFeePayer(cmd)
+			if err != nil {
+				return
+			}
+			// build msg
+			msg := &types.MsgSetAccountValidUntil{
+				Domain:       domain,
+				Name:         name,
+				Owner:        cliCtx.GetFromAddress(),
+				ValidUntil:   validUntil,
+				FeePayerAddr: feePayer,
+			}
+			// check if valid
+			if err = msg.ValidateBasic(); err != nil {
+				return err
+			}
+			// broadcast request
+			return utils.GenerateOrBroadcastMsgs(cliCtx, txBuilder, []sdk.Msg{msg})
+		},
+	}
+	// add flags
+	cmd.Flags().String("domain", "", "the closed domain name")
+	cmd.Flags().String("name", "", "the name of the account")
+	cmd.Flags().Int64("valid-until", 0, "the unix timestamp at which the account expires, zero makes the account follow the domain expiration")
+	cmd.Flags().String("fee-payer", "", "address of the fee payer, optional")
+	return cmd
+}
@@ -0,0 +1,106 @@
+package proof
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/cosmos/cosmos-sdk/client/context"
+	"github.com/cosmos/cosmos-sdk/codec"
+	"github.com/cosmos/cosmos-sdk/store/rootmulti"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/iov-one/iovns/x/starname/keeper"
+	"github.com/iov-one/iovns/x/starname/types"
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/crypto/merkle"
+)
+
+// AccountWithProof is the verifiable resolution of an account,
+// it contains the raw value saved in the store alongside its merkle proof
+type AccountWithProof struct {
+	// Domain is the domain of the account
+	Domain string `json:"domain"`
+	// Name is the name of the account
+	Name string `json:"name"`
+	// Height is the height at which the store was queried
+	Height int64 `json:"height"`
+	// Key is the raw key of the account in the starname store
+	Key []byte `json:"key"`
+	// Value is the raw value of the account, empty if the account does not exist
+	Value []byte `json:"value"`
+	// Account is the decoded value, it is informative only and must not be trusted before verification
+	Account *types.Account `json:"account"`
+	// Proof is the merkle proof of Value, or of its absence, up to the app hash
+	Proof *merkle.Proof `json:"proof"`
+}
+
+// QueryAccount queries the store identified by storeName for the raw account
+// identified by domain and name asking the node to include the merkle proof
+func QueryAccount(cliCtx context.CLIContext, storeName string, domain, name string) (AccountWithProof, error) {
+	key := keeper.AccountKey(domain, name)
+	resp, err := cliCtx.QueryABCI(abci.RequestQuery{
+		Path:   fmt.Sprintf("/store/%s/key", storeName),
+		Data:   key,
+		Height: cliCtx.Height,
+		Prove:  true,
+	})
+	if err != nil {
+		return AccountWithProof{}, err
+	}
+	res := AccountWithProof{
+		Domain: domain,
+		Name:   name,
+		Height: resp.Height,
+		Key:    key,
+		Value:  resp.Value,
+		Proof:  resp.Proof,
+	}
+	if len(resp.Value) != 0 {
+		res.Account, err = decodeAccount(cliCtx.Codec, resp.Value)
+		if err != nil {
+			return AccountWithProof{}, err
+		}
+	}
+	return res, nil
+}
+
+// VerifyAccount verifies the provided account resolution against the trusted app hash,
+// storeName is the name of the starname module store. If the proof is valid it returns the
+// account decoded from the proven value, or types.ErrAccountDoesNotExist if the proof
+// proves the absence of the account.
+func VerifyAccount(cdc *codec.Codec, storeName string, appHash []byte, res AccountWithProof) (*types.Account, error) {
+	if res.Proof == nil {
+		return nil, sdkerrors.Wrap(types.ErrInvalidProof, "missing proof")
+	}
+	// ensure the proof refers to the requested account
+	if !bytes.Equal(res.Key, keeper.AccountKey(res.Domain, res.Name)) {
+		return nil, sdkerrors.Wrapf(types.ErrInvalidProof, "key does not match account %s", types.AccountStarname(res.Domain, res.Name))
+	}
+	kp := merkle.KeyPath{}.
+		AppendKey([]byte(storeName), merkle.KeyEncodingURL).
+		AppendKey(res.Key, merkle.KeyEncodingURL)
+	prt := rootmulti.DefaultProofRuntime()
+	// verify absence
+	if len(res.Value) == 0 {
+		if err := prt.VerifyAbsence(res.Proof, appHash, kp.String()); err != nil {
+			return nil, sdkerrors.Wrap(types.ErrInvalidProof, err.Error())
+		}
+		return nil, sdkerrors.Wrapf(types.ErrAccountDoesNotExist, "%s", types.AccountStarname(res.Domain, res.Name))
+	}
+	// verify existence
+	if err := prt.VerifyValue(res.Proof, appHash, kp.String(), res.Value); err != nil {
+		return nil, sdkerrors.Wrap(types.ErrInvalidProof, err.Error())
+	}
+	return decodeAccount(cdc, res.Value)
+}
+
+// decodeAccount decodes a raw account value as saved by crud.Store
+func decodeAccount(cdc *codec.Codec, value []byte) (account *types.Account, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = sdkerrors.Wrapf(sdkerrors.ErrJSONUnmarshal, "unable to decode account: %v", r)
+		}
+	}()
+	account = new(types.Account)
+	account.UnmarshalCRUD(cdc, value)
+	return account, nil
+}
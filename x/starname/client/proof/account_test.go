@@ -0,0 +1,92 @@
+package proof
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/store"
+	"github.com/cosmos/cosmos-sdk/store/rootmulti"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/iov-one/iovns/pkg/utils"
+	"github.com/iov-one/iovns/x/starname/keeper"
+	"github.com/iov-one/iovns/x/starname/types"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/libs/log"
+	db "github.com/tendermint/tm-db"
+)
+
+func TestVerifyAccount(t *testing.T) {
+	cdc := keeper.NewTestCodec()
+	mdb := db.NewMemDB()
+	ms := store.NewCommitMultiStore(mdb)
+	key := sdk.NewKVStoreKey(types.DomainStoreKey)
+	ms.MountStoreWithDB(key, sdk.StoreTypeIAVL, mdb)
+	require.Nil(t, ms.LoadLatestVersion())
+	k := keeper.NewKeeper(cdc, key, nil, nil, nil)
+	ctx := sdk.NewContext(ms, abci.Header{Time: time.Now()}, false, log.NewNopLogger())
+	// save an account and commit
+	k.AccountStore(ctx).Create(&types.Account{
+		Domain:     "test",
+		Name:       utils.StrPtr("alice"),
+		Owner:      sdk.AccAddress("alice"),
+		ValidUntil: 100,
+	})
+	commit := ms.Commit()
+	query := func(domain, name string) AccountWithProof {
+		accKey := keeper.AccountKey(domain, name)
+		resp := ms.(*rootmulti.Store).Query(abci.RequestQuery{
+			Path:   fmt.Sprintf("/%s/key", types.DomainStoreKey),
+			Data:   accKey,
+			Height: commit.Version,
+			Prove:  true,
+		})
+		require.Equal(t, uint32(0), resp.Code, resp.Log)
+		return AccountWithProof{
+			Domain: domain,
+			Name:   name,
+			Height: resp.Height,
+			Key:    accKey,
+			Value:  resp.Value,
+			Proof:  resp.Proof,
+		}
+	}
+	t.Run("success", func(t *testing.T) {
+		account, err := VerifyAccount(cdc, types.DomainStoreKey, commit.Hash, query("test", "alice"))
+		require.NoError(t, err)
+		require.Equal(t, "alice", *account.Name)
+		require.Equal(t, sdk.AccAddress("alice"), account.Owner)
+		require.Equal(t, int64(100), account.ValidUntil)
+	})
+	t.Run("absence", func(t *testing.T) {
+		_, err := VerifyAccount(cdc, types.DomainStoreKey, commit.Hash, query("test", "bob"))
+		if !errors.Is(err, types.ErrAccountDoesNotExist) {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+	t.Run("tampered value", func(t *testing.T) {
+		res := query("test", "alice")
+		res.Value = append([]byte{}, res.Value...)
+		res.Value[len(res.Value)-1]++
+		_, err := VerifyAccount(cdc, types.DomainStoreKey, commit.Hash, res)
+		if !errors.Is(err, types.ErrInvalidProof) {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+	t.Run("wrong app hash", func(t *testing.T) {
+		_, err := VerifyAccount(cdc, types.DomainStoreKey, []byte("invalid"), query("test", "alice"))
+		if !errors.Is(err, types.ErrInvalidProof) {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+	t.Run("mismatched key", func(t *testing.T) {
+		res := query("test", "alice")
+		res.Name = "bob"
+		_, err := VerifyAccount(cdc, types.DomainStoreKey, commit.Hash, res)
+		if !errors.Is(err, types.ErrInvalidProof) {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+}
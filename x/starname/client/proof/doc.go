@@ -0,0 +1,10 @@
+/*
+Package proof contains helpers that light clients can use to verify
+the resolution of an account against a trusted application hash
+instead of trusting the node that served the response.
+
+The app hash of height H is committed in the header of height H+1,
+so a client resolving an account at height H must obtain the app hash
+from a verified header at height H+1.
+*/
+package proof
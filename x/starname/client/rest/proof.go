@@ -0,0 +1,53 @@
+package rest
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/cosmos/cosmos-sdk/client/context"
+	"github.com/cosmos/cosmos-sdk/types/rest"
+	"github.com/gorilla/mux"
+	"github.com/iov-one/iovns/pkg/queries"
+	"github.com/iov-one/iovns/x/starname/client/proof"
+	"github.com/iov-one/iovns/x/starname/keeper"
+)
+
+// resolveAccountWithProofHandler resolves an account reading its raw value
+// from the store and returns it alongside the merkle proof, which clients
+// can verify against a trusted app hash using the proof package
+func resolveAccountWithProofHandler(cliCtx context.CLIContext, storeName string) http.HandlerFunc {
+	return func(writer http.ResponseWriter, request *http.Request) {
+		cliCtx, ok := rest.ParseQueryHeightOrReturnBadRequest(writer, cliCtx, request)
+		if !ok {
+			return
+		}
+		b, err := ioutil.ReadAll(request.Body)
+		if err != nil {
+			rest.WriteErrorResponse(writer, http.StatusInternalServerError, err.Error())
+			return
+		}
+		query := new(keeper.QueryResolveAccount)
+		if err = queries.DefaultQueryDecode(b, query); err != nil {
+			rest.WriteErrorResponse(writer, http.StatusBadRequest, err.Error())
+			return
+		}
+		if err = query.Validate(); err != nil {
+			rest.WriteErrorResponse(writer, http.StatusBadRequest, err.Error())
+			return
+		}
+		res, err := proof.QueryAccount(cliCtx, storeName, query.Domain, query.Name)
+		if err != nil {
+			rest.WriteErrorResponse(writer, http.StatusBadRequest, err.Error())
+			return
+		}
+		cliCtx = cliCtx.WithHeight(res.Height)
+		rest.PostProcessResponse(writer, cliCtx, res)
+	}
+}
+
+// registerProofRoutes registers the routes that return merkle proofs
+func registerProofRoutes(cliCtx context.CLIContext, r *mux.Router, storeName string) {
+	path := fmt.Sprintf("/%s/query/resolveWithProof", storeName)
+	r.HandleFunc(path, resolveAccountWithProofHandler(cliCtx, storeName)).Methods("POST")
+}
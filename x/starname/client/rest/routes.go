@@ -27,6 +27,18 @@ var txRoutesList = map[string]func(cliContext context.CLIContext) http.HandlerFu
 	"transferAccount":         transferAccountHandler,
 	"transferDomain":          transferDomainHandler,
 	"setAccountMetadata":      setAccountMetadataHandler,
+	"setRegistrationPolicy":   setRegistrationPolicyHandler,
+	"offerDomainTransfer":     offerDomainTransferHandler,
+	"offerAccountTransfer":    offerAccountTransferHandler,
+	"acceptTransferOffer":     acceptTransferOfferHandler,
+	"rejectTransferOffer":     rejectTransferOfferHandler,
+	"cancelTransferOffer":     cancelTransferOfferHandler,
+	"depositSponsorship":      depositSponsorshipHandler,
+	"withdrawSponsorship":     withdrawSponsorshipHandler,
+	"transferAccounts":        transferAccountsHandler,
+	"deleteAccountsByOwner":   deleteAccountsByOwnerHandler,
+	"setAccountsResource":     setAccountsResourceHandler,
+	"setAccountValidUntil":    setAccountValidUntilHandler,
 }
 
 // registerTxRoutes registers all the transaction routes to the router
@@ -104,4 +116,6 @@ func RegisterRoutes(cliContext context.CLIContext, r *mux.Router, storeName stri
 	registerTxRoutes(cliContext, r, storeName)
 	// register query routes
 	registerQueryRoutes(cliContext, r, storeName, queries)
+	// register proof routes
+	registerProofRoutes(cliContext, r, storeName)
 }
@@ -229,3 +229,207 @@ func setAccountMetadataHandler(cliCtx context.CLIContext) http.HandlerFunc {
 		handleTxRequest(cliCtx, req.BaseReq, req.Message, writer)
 	}
 }
+
+// setRegistrationPolicy is the request model for setRegistrationPolicyHandler
+type setRegistrationPolicy struct {
+	BaseReq rest.BaseReq                    `json:"base_req"`
+	Message *types.MsgSetRegistrationPolicy `json:"message"`
+}
+
+// setRegistrationPolicyHandler builds the transaction to sign to set the registration policy of a closed domain
+func setRegistrationPolicyHandler(cliCtx context.CLIContext) http.HandlerFunc {
+	return func(writer http.ResponseWriter, request *http.Request) {
+		var req setRegistrationPolicy
+		if !rest.ReadRESTReq(writer, request, cliCtx.Codec, &req) {
+			return
+		}
+		handleTxRequest(cliCtx, req.BaseReq, req.Message, writer)
+	}
+}
+
+// offerDomainTransfer is the request model for offerDomainTransferHandler
+type offerDomainTransfer struct {
+	BaseReq rest.BaseReq                  `json:"base_req"`
+	Message *types.MsgOfferDomainTransfer `json:"message"`
+}
+
+// offerDomainTransferHandler builds the transaction to sign to offer the transfer of a domain
+func offerDomainTransferHandler(cliCtx context.CLIContext) http.HandlerFunc {
+	return func(writer http.ResponseWriter, request *http.Request) {
+		var req offerDomainTransfer
+		if !rest.ReadRESTReq(writer, request, cliCtx.Codec, &req) {
+			return
+		}
+		handleTxRequest(cliCtx, req.BaseReq, req.Message, writer)
+	}
+}
+
+// offerAccountTransfer is the request model for offerAccountTransferHandler
+type offerAccountTransfer struct {
+	BaseReq rest.BaseReq                   `json:"base_req"`
+	Message *types.MsgOfferAccountTransfer `json:"message"`
+}
+
+// offerAccountTransferHandler builds the transaction to sign to offer the transfer of an account
+func offerAccountTransferHandler(cliCtx context.CLIContext) http.HandlerFunc {
+	return func(writer http.ResponseWriter, request *http.Request) {
+		var req offerAccountTransfer
+		if !rest.ReadRESTReq(writer, request, cliCtx.Codec, &req) {
+			return
+		}
+		handleTxRequest(cliCtx, req.BaseReq, req.Message, writer)
+	}
+}
+
+// acceptTransferOffer is the request model for acceptTransferOfferHandler
+type acceptTransferOffer struct {
+	BaseReq rest.BaseReq                  `json:"base_req"`
+	Message *types.MsgAcceptTransferOffer `json:"message"`
+}
+
+// acceptTransferOfferHandler builds the transaction to sign to accept a transfer offer
+func acceptTransferOfferHandler(cliCtx context.CLIContext) http.HandlerFunc {
+	return func(writer http.ResponseWriter, request *http.Request) {
+		var req acceptTransferOffer
+		if !rest.ReadRESTReq(writer, request, cliCtx.Codec, &req) {
+			return
+		}
+		handleTxRequest(cliCtx, req.BaseReq, req.Message, writer)
+	}
+}
+
+// rejectTransferOffer is the request model for rejectTransferOfferHandler
+type rejectTransferOffer struct {
+	BaseReq rest.BaseReq                  `json:"base_req"`
+	Message *types.MsgRejectTransferOffer `json:"message"`
+}
+
+// rejectTransferOfferHandler builds the transaction to sign to reject a transfer offer
+func rejectTransferOfferHandler(cliCtx context.CLIContext) http.HandlerFunc {
+	return func(writer http.ResponseWriter, request *http.Request) {
+		var req rejectTransferOffer
+		if !rest.ReadRESTReq(writer, request, cliCtx.Codec, &req) {
+			return
+		}
+		handleTxRequest(cliCtx, req.BaseReq, req.Message, writer)
+	}
+}
+
+// cancelTransferOffer is the request model for cancelTransferOfferHandler
+type cancelTransferOffer struct {
+	BaseReq rest.BaseReq                  `json:"base_req"`
+	Message *types.MsgCancelTransferOffer `json:"message"`
+}
+
+// cancelTransferOfferHandler builds the transaction to sign to cancel a transfer offer
+func cancelTransferOfferHandler(cliCtx context.CLIContext) http.HandlerFunc {
+	return func(writer http.ResponseWriter, request *http.Request) {
+		var req cancelTransferOffer
+		if !rest.ReadRESTReq(writer, request, cliCtx.Codec, &req) {
+			return
+		}
+		handleTxRequest(cliCtx, req.BaseReq, req.Message, writer)
+	}
+}
+
+// depositSponsorship is the request model for depositSponsorshipHandler
+type depositSponsorship struct {
+	BaseReq rest.BaseReq                 `json:"base_req"`
+	Message *types.MsgDepositSponsorship `json:"message"`
+}
+
+// depositSponsorshipHandler builds the transaction to sign to deposit coins into a renewal sponsorship
+func depositSponsorshipHandler(cliCtx context.CLIContext) http.HandlerFunc {
+	return func(writer http.ResponseWriter, request *http.Request) {
+		var req depositSponsorship
+		if !rest.ReadRESTReq(writer, request, cliCtx.Codec, &req) {
+			return
+		}
+		handleTxRequest(cliCtx, req.BaseReq, req.Message, writer)
+	}
+}
+
+// withdrawSponsorship is the request model for withdrawSponsorshipHandler
+type withdrawSponsorship struct {
+	BaseReq rest.BaseReq                  `json:"base_req"`
+	Message *types.MsgWithdrawSponsorship `json:"message"`
+}
+
+// withdrawSponsorshipHandler builds the transaction to sign to withdraw coins from a renewal sponsorship
+func withdrawSponsorshipHandler(cliCtx context.CLIContext) http.HandlerFunc {
+	return func(writer http.ResponseWriter, request *http.Request) {
+		var req withdrawSponsorship
+		if !rest.ReadRESTReq(writer, request, cliCtx.Codec, &req) {
+			return
+		}
+		handleTxRequest(cliCtx, req.BaseReq, req.Message, writer)
+	}
+}
+
+// transferAccounts is the request model for transferAccountsHandler
+type transferAccounts struct {
+	BaseReq rest.BaseReq               `json:"base_req"`
+	Message *types.MsgTransferAccounts `json:"message"`
+}
+
+// transferAccountsHandler builds the transaction to sign to transfer a set of accounts of a closed domain
+func transferAccountsHandler(cliCtx context.CLIContext) http.HandlerFunc {
+	return func(writer http.ResponseWriter, request *http.Request) {
+		var req transferAccounts
+		if !rest.ReadRESTReq(writer, request, cliCtx.Codec, &req) {
+			return
+		}
+		handleTxRequest(cliCtx, req.BaseReq, req.Message, writer)
+	}
+}
+
+// deleteAccountsByOwner is the request model for deleteAccountsByOwnerHandler
+type deleteAccountsByOwner struct {
+	BaseReq rest.BaseReq                    `json:"base_req"`
+	Message *types.MsgDeleteAccountsByOwner `json:"message"`
+}
+
+// deleteAccountsByOwnerHandler builds the transaction to sign to delete the accounts of a closed domain owned by an address
+func deleteAccountsByOwnerHandler(cliCtx context.CLIContext) http.HandlerFunc {
+	return func(writer http.ResponseWriter, request *http.Request) {
+		var req deleteAccountsByOwner
+		if !rest.ReadRESTReq(writer, request, cliCtx.Codec, &req) {
+			return
+		}
+		handleTxRequest(cliCtx, req.BaseReq, req.Message, writer)
+	}
+}
+
+// setAccountsResource is the request model for setAccountsResourceHandler
+type setAccountsResource struct {
+	BaseReq rest.BaseReq                  `json:"base_req"`
+	Message *types.MsgSetAccountsResource `json:"message"`
+}
+
+// setAccountsResourceHandler builds the transaction to sign to set a resource on the accounts of a closed domain
+func setAccountsResourceHandler(cliCtx context.CLIContext) http.HandlerFunc {
+	return func(writer http.ResponseWriter, request *http.Request) {
+		var req setAccountsResource
+		if !rest.ReadRESTReq(writer, request, cliCtx.Codec, &req) {
+			return
+		}
+		handleTxRequest(cliCtx, req.BaseReq, req.Message, writer)
+	}
+}
+
+// setAccountValidUntil is the request model for setAccountValidUntilHandler
+type setAccountValidUntil struct {
+	BaseReq rest.BaseReq                   `json:"base_req"`
+	Message *types.MsgSetAccountValidUntil `json:"message"`
+}
+
+// setAccountValidUntilHandler builds the transaction to sign to set the expiration of an account of a closed domain
+func setAccountValidUntilHandler(cliCtx context.CLIContext) http.HandlerFunc {
+	return func(writer http.ResponseWriter, request *http.Request) {
+		var req setAccountValidUntil
+		if !rest.ReadRESTReq(writer, request, cliCtx.Codec, &req) {
+			return
+		}
+		handleTxRequest(cliCtx, req.BaseReq, req.Message, writer)
+	}
+}
@@ -3,6 +3,7 @@ package account
 import (
 	"bytes"
 	crud "github.com/iov-one/cosmos-sdk-crud/pkg/crud"
+	"github.com/iov-one/iovns/pkg/idn"
 	"github.com/iov-one/iovns/pkg/utils"
 	"regexp"
 	"time"
@@ -31,11 +32,16 @@ type Account struct {
 	name, domain string
 	account      *types.Account
 	conf         *configuration.Config
+	reserved     *configuration.ReservedNames
 
 	ctx        sdk.Context
 	k          keeper.Keeper
 	store      crud.Store
 	domainCtrl *domain.Domain
+	credential *types.RegistrationCredential
+	policy     *types.RegistrationPolicy
+	// inviteCode is the invite code which allowed the registration, if any
+	inviteCode string
 }
 
 // Validate verifies the account against the order of provided controllers
@@ -64,10 +70,20 @@ func (a *Account) MustNotExist() *Account {
 	return a
 }
 
-// ValidName asserts the account name is valid
-func (a *Account) ValidName() *Account {
+// ValidName asserts the account name is valid and
+// is not reserved for someone other than the claimant
+func (a *Account) ValidName(claimant sdk.AccAddress) *Account {
 	a.validators = append(a.validators, func(ctrl *Account) error {
-		return a.validName()
+		return ctrl.validName(claimant)
+	})
+	return a
+}
+
+// NotConfusable asserts the account name is not visually confusable
+// with the name of another existing account in the same domain
+func (a *Account) NotConfusable() *Account {
+	a.validators = append(a.validators, func(ctrl *Account) error {
+		return ctrl.notConfusable()
 	})
 	return a
 }
@@ -80,6 +96,15 @@ func (a *Account) NotExpired() *Account {
 	return a
 }
 
+// ValidExpiration asserts that validUntil can be set as the expiration of an account of the
+// domain, which must be closed, validUntil must be in the future and not after the domain expiration
+func (a *Account) ValidExpiration(validUntil int64) *Account {
+	a.validators = append(a.validators, func(ctrl *Account) error {
+		return ctrl.validExpiration(validUntil)
+	})
+	return a
+}
+
 // Renewable asserts that the account is renewable
 func (a *Account) Renewable() *Account {
 	a.validators = append(a.validators, func(ctrl *Account) error {
@@ -153,6 +178,15 @@ func (a *Account) TransferableBy(addr sdk.AccAddress) *Account {
 	return a
 }
 
+// NotOfferedBy checks that the account has no pending transfer offer made by the provided
+// address, the offered account is locked until the offer is accepted, rejected, cancelled or expires
+func (a *Account) NotOfferedBy(addr sdk.AccAddress) *Account {
+	a.validators = append(a.validators, func(ctrl *Account) error {
+		return ctrl.notOfferedBy(addr)
+	})
+	return a
+}
+
 // ResettableBy checks if the account attributes resettable by the provided address
 func (a *Account) ResettableBy(addr sdk.AccAddress, reset bool) *Account {
 	a.validators = append(a.validators, func(ctrl *Account) error {
@@ -217,6 +251,26 @@ func (a *Account) WithConfiguration(cfg configuration.Config) *Account {
 	return a
 }
 
+// WithCredential allows to specify the registration credential
+// checked by RegistrableBy against the domain registration policy
+func (a *Account) WithCredential(credential *types.RegistrationCredential) *Account {
+	a.credential = credential
+	return a
+}
+
+// WithReservedNames allows to specify cached reserved names, only
+// the entries matching the name are taken into account
+func (a *Account) WithReservedNames(reserved configuration.ReservedNames) *Account {
+	a.reserved = &reserved
+	return a
+}
+
+// WithRegistrationPolicy allows to specify a cached domain registration policy
+func (a *Account) WithRegistrationPolicy(policy types.RegistrationPolicy) *Account {
+	a.policy = &policy
+	return a
+}
+
 // WithAccount allows to specify a cached account
 func (a *Account) WithAccount(acc types.Account) *Account {
 	a.account = &acc
@@ -263,6 +317,27 @@ func (a *Account) mustNotExist() error {
 	return sdkerrors.Wrapf(types.ErrAccountExists, "account %s already exists in domain %s", a.name, a.domain)
 }
 
+// requireReservedNames updates the reserved names matching the name
+// if they are not already set, and caches them after
+func (a *Account) requireReservedNames() {
+	if a.reserved != nil {
+		return
+	}
+	reserved := a.k.ConfigurationKeeper.GetReservedNamesMatching(a.ctx, a.name)
+	a.reserved = &reserved
+}
+
+// requireRegistrationPolicy updates the domain registration policy
+// if it is not already set, and caches it after, domains without
+// a registration policy are treated as having an empty one
+func (a *Account) requireRegistrationPolicy() {
+	if a.policy != nil {
+		return
+	}
+	policy, _ := a.k.GetRegistrationPolicy(a.ctx, a.domain)
+	a.policy = &policy
+}
+
 // requireConfiguration updates the configuration
 // if it is not already set, and caches it after
 func (a *Account) requireConfiguration() {
@@ -273,12 +348,36 @@ func (a *Account) requireConfiguration() {
 	a.conf = &conf
 }
 
-// validName is the unexported function used by ValidAccountName
-func (a *Account) validName() error {
+// validName is the unexported function used by ValidName
+func (a *Account) validName(claimant sdk.AccAddress) error {
+	if !idn.IsCanonical(a.name) {
+		return sdkerrors.Wrapf(types.ErrInvalidAccountName, "not in canonical form: %q", a.name)
+	}
 	a.requireConfiguration()
 	if !regexp.MustCompile(a.conf.ValidAccountName).MatchString(a.name) {
 		return sdkerrors.Wrapf(types.ErrInvalidAccountName, "invalid name: %s", a.name)
 	}
+	if err := idn.ValidateScripts(a.name); err != nil {
+		return sdkerrors.Wrap(types.ErrInvalidAccountName, err.Error())
+	}
+	a.requireReservedNames()
+	if reserved, ok := a.reserved.Blocking(a.name, claimant); ok {
+		return sdkerrors.Wrapf(types.ErrInvalidAccountName, "%s is reserved by %s", a.name, reserved.Name)
+	}
+	return nil
+}
+
+// notConfusable is the unexported function used by NotConfusable
+func (a *Account) notConfusable() error {
+	filter := a.store.Filter(&types.AccountSkeletonFilter{Domain: a.domain, Name: a.name})
+	for ; filter.Valid(); filter.Next() {
+		account := new(types.Account)
+		filter.Read(account)
+		if *account.Name == a.name {
+			continue
+		}
+		return sdkerrors.Wrapf(types.ErrInvalidAccountName, "%s is confusable with account %s", a.name, *account.Name)
+	}
 	return nil
 }
 
@@ -290,20 +389,55 @@ func (a *Account) notExpired() error {
 	if err := a.requireDomain(); err != nil {
 		panic("validation check is not allowed on a non existing domain")
 	}
-	switch a.domainCtrl.Domain().Type {
-	// if domain is closed type then skip the expiration validation checks
-	case types.ClosedDomain:
+	// accounts of closed domains without an explicit expiration follow the domain
+	if a.domainCtrl.Domain().Type == types.ClosedDomain && !a.hasExpiration() {
 		return nil
 	}
 	// check if account has expired
-	expireTime := utils.SecondsToTime(a.account.ValidUntil)
-	if !expireTime.Before(a.ctx.BlockTime()) {
+	if !a.expiration().Before(a.ctx.BlockTime()) {
 		return nil
 	}
 	// if it has expired return error
 	return sdkerrors.Wrapf(types.ErrAccountExpired, "account %s in domain %s has expired", a.name, a.domain)
 }
 
+// hasExpiration tells if the account has an explicit expiration, accounts of closed
+// domains set to zero or to types.MaxValidUntil follow the domain expiration
+func (a *Account) hasExpiration() bool {
+	return a.account.ValidUntil != 0 && a.account.ValidUntil != types.MaxValidUntil
+}
+
+// expiration returns the expiration time of the account, which is capped at the domain
+// expiration for the accounts of closed domains with an explicit expiration
+func (a *Account) expiration() time.Time {
+	expireTime := utils.SecondsToTime(a.account.ValidUntil)
+	if !a.hasExpiration() {
+		return expireTime
+	}
+	if d := a.domainCtrl.Domain(); d.Type == types.ClosedDomain && d.ValidUntil < a.account.ValidUntil {
+		return utils.SecondsToTime(d.ValidUntil)
+	}
+	return expireTime
+}
+
+// validExpiration is the unexported function used by ValidExpiration
+func (a *Account) validExpiration(validUntil int64) error {
+	if err := a.requireDomain(); err != nil {
+		panic("validation check is not allowed on a non existing domain")
+	}
+	d := a.domainCtrl.Domain()
+	if d.Type != types.ClosedDomain {
+		return sdkerrors.Wrapf(types.ErrInvalidDomainType, "account expiration can be set only in closed domains")
+	}
+	if !utils.SecondsToTime(validUntil).After(a.ctx.BlockTime()) {
+		return sdkerrors.Wrapf(types.ErrInvalidAccountValidUntil, "account expiration %d is not in the future", validUntil)
+	}
+	if validUntil > d.ValidUntil {
+		return sdkerrors.Wrapf(types.ErrInvalidAccountValidUntil, "account expiration %d is after domain %s expiration %d", validUntil, d.Name, d.ValidUntil)
+	}
+	return nil
+}
+
 func (a *Account) renewable() error {
 	if err := a.requireAccount(); err != nil {
 		panic("validation check is not allowed on a non existing account")
@@ -459,6 +593,15 @@ func (a *Account) transferableBy(addr sdk.AccAddress) error {
 	return nil
 }
 
+// notOfferedBy is the unexported function used by NotOfferedBy
+func (a *Account) notOfferedBy(addr sdk.AccAddress) error {
+	offer, ok := a.k.GetStarnameTransferOffer(a.ctx, types.AccountStarname(a.domain, a.name))
+	if !ok || !offer.Owner.Equals(addr) || offer.Expired(a.ctx.BlockTime().Unix()) {
+		return nil
+	}
+	return sdkerrors.Wrapf(types.ErrStarnameOffered, "account %s in domain %s is locked by the transfer offer %d", a.name, a.domain, offer.ID)
+}
+
 func (a *Account) resettableBy(addr sdk.AccAddress, reset bool) error {
 	if err := a.requireDomain(); err != nil {
 		panic("validation check not allowed on a non existing domain")
@@ -484,10 +627,12 @@ func (a *Account) gracePeriodFinished() error {
 	if err := a.requireAccount(); err != nil {
 		panic("condition check not allowed on non existing account ")
 	}
+	if err := a.requireDomain(); err != nil {
+		panic("condition check not allowed on non existing domain")
+	}
 	// get grace period and expiration time
 	gracePeriod := a.conf.AccountGracePeriod
-	expireTime := utils.SecondsToTime(a.account.ValidUntil)
-	if a.ctx.BlockTime().After(expireTime.Add(gracePeriod)) {
+	if a.ctx.BlockTime().After(a.expiration().Add(gracePeriod)) {
 		return nil
 	}
 	return sdkerrors.Wrapf(types.ErrAccountGracePeriodNotFinished, "account %s grace period has not finished", *a.account.Name)
@@ -523,15 +668,63 @@ func (a *Account) registrableBy(addr sdk.AccAddress) error {
 	// check domain type
 	switch a.domainCtrl.Domain().Type {
 	// if domain is closed then the registerer must be domain owner
+	// or must be allowed by the domain registration policy
 	case types.ClosedDomain:
-		return a.domainCtrl.
+		err := a.domainCtrl.
 			Admin(addr).
 			Validate()
+		if err == nil {
+			return nil
+		}
+		a.requireRegistrationPolicy()
+		if a.policy.Empty() {
+			return err
+		}
+		return a.allowedByPolicy(*a.policy, addr, err)
 	default:
 		return nil
 	}
 }
 
+// allowedByPolicy checks if the registration policy allows addr to register accounts
+// in the domain, adminErr is returned if addr provided no allowed credential
+func (a *Account) allowedByPolicy(policy types.RegistrationPolicy, addr sdk.AccAddress, adminErr error) error {
+	if policy.Allowed(addr) {
+		return nil
+	}
+	if a.credential == nil {
+		return adminErr
+	}
+	if err := a.credential.Verify(a.ctx.ChainID(), a.domain, addr); err != nil {
+		return err
+	}
+	issuer := a.credential.Issuer()
+	switch a.credential.Type {
+	case types.CertificateCredential:
+		if !policy.TrustsIssuer(issuer) {
+			return sdkerrors.Wrapf(types.ErrUnauthorized, "%s is not a certificate issuer of domain %s", issuer, a.domain)
+		}
+	case types.InviteCredential:
+		if !policy.InviteCodes {
+			return sdkerrors.Wrapf(types.ErrUnauthorized, "domain %s does not accept invite codes", a.domain)
+		}
+		if !a.domainCtrl.Domain().Admin.Equals(issuer) {
+			return sdkerrors.Wrapf(types.ErrUnauthorized, "invite code was not signed by the admin of domain %s", a.domain)
+		}
+		if a.k.IsInviteCodeUsed(a.ctx, a.domain, a.credential.Code) {
+			return sdkerrors.Wrapf(types.ErrInviteCodeUsed, "%s", a.credential.Code)
+		}
+		a.inviteCode = a.credential.Code
+	}
+	return nil
+}
+
+// InviteCode returns the invite code which allowed RegistrableBy to succeed,
+// it is empty if the registration was not allowed by an invite code
+func (a *Account) InviteCode() string {
+	return a.inviteCode
+}
+
 // Account returns the cached account, if the account existence
 // was not asserted before, it panics.
 func (a *Account) Account() types.Account {
@@ -10,6 +10,8 @@ import (
 	"github.com/iov-one/iovns/x/starname/keeper"
 	"github.com/iov-one/iovns/x/starname/keeper/executor"
 	"github.com/iov-one/iovns/x/starname/types"
+	"github.com/tendermint/tendermint/crypto"
+	"github.com/tendermint/tendermint/crypto/secp256k1"
 	"testing"
 	"time"
 )
@@ -242,10 +244,10 @@ func TestAccount_notExpired(t *testing.T) {
 			t.Fatalf("want error: %s, got: %s", types.ErrAccountExpired, err)
 		}
 	})
-	t.Run("success account expired but in closed domain", func(t *testing.T) {
+	t.Run("success account without expiration in closed domain", func(t *testing.T) {
 		acc := (&Account{
 			account: &types.Account{
-				ValidUntil: 1,
+				ValidUntil: types.MaxValidUntil,
 			},
 			ctx: sdk.Context{}.WithBlockTime(time.Unix(20, 0)),
 		}).WithDomainController(closedDomain)
@@ -254,6 +256,80 @@ func TestAccount_notExpired(t *testing.T) {
 			t.Fatalf("got error: %s", err)
 		}
 	})
+	t.Run("expired account with expiration in closed domain", func(t *testing.T) {
+		acc := (&Account{
+			account: &types.Account{
+				ValidUntil: 1,
+			},
+			ctx: sdk.Context{}.WithBlockTime(time.Unix(20, 0)),
+		}).WithDomainController(closedDomain)
+		err := acc.NotExpired().Validate()
+		if !errors.Is(err, types.ErrAccountExpired) {
+			t.Fatalf("want error: %s, got: %s", types.ErrAccountExpired, err)
+		}
+	})
+	t.Run("expiration capped at closed domain expiration", func(t *testing.T) {
+		acc := (&Account{
+			account: &types.Account{
+				ValidUntil: 30,
+			},
+			ctx: sdk.Context{}.WithBlockTime(time.Unix(20, 0)),
+		}).WithDomainController((&domain.Domain{}).WithDomain(types.Domain{
+			Type:       types.ClosedDomain,
+			ValidUntil: 10,
+		}))
+		err := acc.NotExpired().Validate()
+		if !errors.Is(err, types.ErrAccountExpired) {
+			t.Fatalf("want error: %s, got: %s", types.ErrAccountExpired, err)
+		}
+	})
+}
+
+func TestAccount_validExpiration(t *testing.T) {
+	ctx := sdk.Context{}.WithBlockTime(time.Unix(20, 0))
+	closedDomain := (&domain.Domain{}).WithDomain(types.Domain{
+		Name:       "test",
+		Type:       types.ClosedDomain,
+		ValidUntil: 100,
+	})
+	cases := map[string]struct {
+		Domain     *domain.Domain
+		ValidUntil int64
+		Err        error
+	}{
+		"success": {
+			Domain:     closedDomain,
+			ValidUntil: 50,
+		},
+		"success domain expiration": {
+			Domain:     closedDomain,
+			ValidUntil: 100,
+		},
+		"fail open domain": {
+			Domain:     (&domain.Domain{}).WithDomain(types.Domain{Type: types.OpenDomain, ValidUntil: 100}),
+			ValidUntil: 50,
+			Err:        types.ErrInvalidDomainType,
+		},
+		"fail in the past": {
+			Domain:     closedDomain,
+			ValidUntil: 20,
+			Err:        types.ErrInvalidAccountValidUntil,
+		},
+		"fail after domain expiration": {
+			Domain:     closedDomain,
+			ValidUntil: 101,
+			Err:        types.ErrInvalidAccountValidUntil,
+		},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			acc := (&Account{ctx: ctx}).WithDomainController(c.Domain)
+			err := acc.ValidExpiration(c.ValidUntil).Validate()
+			if !errors.Is(err, c.Err) {
+				t.Fatalf("want error: %v, got: %v", c.Err, err)
+			}
+		})
+	}
 }
 
 func TestAccount_ownedBy(t *testing.T) {
@@ -285,7 +361,7 @@ func TestAccount_validName(t *testing.T) {
 			account: &types.Account{Name: utils.StrPtr("valid")},
 			conf:    &configuration.Config{ValidAccountName: "^(.*?)?"},
 		}
-		err := acc.ValidName().Validate()
+		err := acc.WithReservedNames(nil).ValidName(nil).Validate()
 		if err != nil {
 			t.Fatalf("got error: %s", err)
 		}
@@ -295,13 +371,69 @@ func TestAccount_validName(t *testing.T) {
 			name: "not valid",
 			conf: &configuration.Config{ValidAccountName: "$^"},
 		}
-		err := acc.ValidName().Validate()
+		err := acc.ValidName(nil).Validate()
 		if !errors.Is(err, types.ErrInvalidAccountName) {
 			t.Fatalf("unexpected error: %s, wanted: %s", err, types.ErrInvalidAccountName)
 		}
 	})
 }
 
+func TestAccount_validNameUnicode(t *testing.T) {
+	cases := map[string]struct {
+		name    string
+		wantErr bool
+	}{
+		"canonical":         {name: "no\u00ebl"},
+		"single script":     {name: "пример"},
+		"not canonical":     {name: "noe\u0308l", wantErr: true},
+		"mixed scripts":     {name: "pаypal", wantErr: true},
+		"invalid utf8 name": {name: string([]byte{0xff}), wantErr: true},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			acc := &Account{
+				name: c.name,
+				conf: &configuration.Config{ValidAccountName: keeper.RegexMatchAll},
+			}
+			err := acc.WithReservedNames(nil).ValidName(nil).Validate()
+			if c.wantErr && !errors.Is(err, types.ErrInvalidAccountName) {
+				t.Fatalf("want err: %s, got: %v", types.ErrInvalidAccountName, err)
+			}
+			if !c.wantErr && err != nil {
+				t.Fatalf("got error: %s", err)
+			}
+		})
+	}
+}
+
+func TestAccount_notConfusable(t *testing.T) {
+	k, ctx, _ := keeper.NewTestKeeper(t, true)
+	as := k.AccountStore(ctx)
+	as.Create(&types.Account{Domain: "test", Name: utils.StrPtr("paypal"), Owner: keeper.AliceKey})
+	as.Create(&types.Account{Domain: "other", Name: utils.StrPtr("coop"), Owner: keeper.AliceKey})
+	cases := map[string]struct {
+		domain, name string
+		wantErr      bool
+	}{
+		"not confusable":              {domain: "test", name: "alice"},
+		"same name":                   {domain: "test", name: "paypal"},
+		"confusable":                  {domain: "test", name: "pаypаl", wantErr: true},
+		"confusable in other domain":  {domain: "test", name: "\u0441\u043e\u043e\u0440"},
+		"skeleton prefix of existing": {domain: "test", name: "pаy"},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := NewController(ctx, k, c.domain, c.name).NotConfusable().Validate()
+			if c.wantErr && !errors.Is(err, types.ErrInvalidAccountName) {
+				t.Fatalf("want err: %s, got: %v", types.ErrInvalidAccountName, err)
+			}
+			if !c.wantErr && err != nil {
+				t.Fatalf("got error: %s", err)
+			}
+		})
+	}
+}
+
 func TestAccountRegistrableBy(t *testing.T) {
 	closedDomain := (&domain.Domain{}).WithDomain(types.Domain{
 		Type:  types.ClosedDomain,
@@ -318,7 +450,7 @@ func TestAccountRegistrableBy(t *testing.T) {
 		}
 	})
 	t.Run("fail in closed domain", func(t *testing.T) {
-		acc := (&Account{}).WithDomainController(closedDomain)
+		acc := (&Account{}).WithDomainController(closedDomain).WithRegistrationPolicy(types.RegistrationPolicy{})
 		err := acc.RegistrableBy(keeper.BobKey).Validate()
 		if !errors.Is(err, types.ErrUnauthorized) {
 			t.Fatalf("want: %s, got: %s", types.ErrUnauthorized, err)
@@ -332,3 +464,126 @@ func TestAccountRegistrableBy(t *testing.T) {
 		}
 	})
 }
+
+func TestAccountRegistrableByPolicy(t *testing.T) {
+	adminKey := secp256k1.GenPrivKey()
+	issuerKey := secp256k1.GenPrivKey()
+	otherKey := secp256k1.GenPrivKey()
+	admin := sdk.AccAddress(adminKey.PubKey().Address())
+	registerer := keeper.BobKey
+	const chainID = "iovns-test"
+	signOn := func(chainID string, key crypto.PrivKey, typ types.CredentialType, registerer sdk.AccAddress, code string) *types.RegistrationCredential {
+		sig, err := key.Sign(types.CredentialSignBytes(chainID, typ, "closed", registerer, code))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &types.RegistrationCredential{Type: typ, Code: code, PubKey: key.PubKey(), Signature: sig}
+	}
+	sign := func(key crypto.PrivKey, typ types.CredentialType, registerer sdk.AccAddress, code string) *types.RegistrationCredential {
+		return signOn(chainID, key, typ, registerer, code)
+	}
+	policy := types.RegistrationPolicy{
+		Domain:             "closed",
+		AllowList:          []sdk.AccAddress{keeper.CharlieKey},
+		CertificateIssuers: []sdk.AccAddress{sdk.AccAddress(issuerKey.PubKey().Address())},
+		InviteCodes:        true,
+	}
+	cases := map[string]struct {
+		policy     types.RegistrationPolicy
+		registerer sdk.AccAddress
+		credential *types.RegistrationCredential
+		usedCode   string
+		wantErr    error
+		wantCode   string
+	}{
+		"success allow list": {
+			policy:     policy,
+			registerer: keeper.CharlieKey,
+		},
+		"success certificate": {
+			policy:     policy,
+			registerer: registerer,
+			credential: sign(issuerKey, types.CertificateCredential, registerer, ""),
+		},
+		"success invite code": {
+			policy:     policy,
+			registerer: registerer,
+			credential: sign(adminKey, types.InviteCredential, registerer, "welcome"),
+			wantCode:   "welcome",
+		},
+		"fail no credential": {
+			policy:     policy,
+			registerer: registerer,
+			wantErr:    types.ErrUnauthorized,
+		},
+		"fail certificate untrusted issuer": {
+			policy:     policy,
+			registerer: registerer,
+			credential: sign(otherKey, types.CertificateCredential, registerer, ""),
+			wantErr:    types.ErrUnauthorized,
+		},
+		"fail certificate of another registerer": {
+			policy:     policy,
+			registerer: registerer,
+			credential: sign(issuerKey, types.CertificateCredential, keeper.CharlieKey, ""),
+			wantErr:    types.ErrInvalidCredential,
+		},
+		"fail invite code of another registerer": {
+			policy:     policy,
+			registerer: registerer,
+			credential: sign(adminKey, types.InviteCredential, keeper.CharlieKey, "welcome"),
+			wantErr:    types.ErrInvalidCredential,
+		},
+		"fail certificate of another chain": {
+			policy:     policy,
+			registerer: registerer,
+			credential: signOn("other-chain", issuerKey, types.CertificateCredential, registerer, ""),
+			wantErr:    types.ErrInvalidCredential,
+		},
+		"fail invite code not signed by admin": {
+			policy:     policy,
+			registerer: registerer,
+			credential: sign(otherKey, types.InviteCredential, registerer, "welcome"),
+			wantErr:    types.ErrUnauthorized,
+		},
+		"fail invite code already used": {
+			policy:     policy,
+			registerer: registerer,
+			credential: sign(adminKey, types.InviteCredential, registerer, "welcome"),
+			usedCode:   "welcome",
+			wantErr:    types.ErrInviteCodeUsed,
+		},
+		"fail invite codes disabled": {
+			policy:     types.RegistrationPolicy{Domain: "closed", AllowList: policy.AllowList},
+			registerer: registerer,
+			credential: sign(adminKey, types.InviteCredential, registerer, "welcome"),
+			wantErr:    types.ErrUnauthorized,
+		},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			k, ctx, _ := keeper.NewTestKeeper(t, true)
+			ctx = ctx.WithChainID(chainID)
+			d := types.Domain{
+				Name:       "closed",
+				Admin:      admin,
+				ValidUntil: time.Now().Add(100 * time.Hour).Unix(),
+				Type:       types.ClosedDomain,
+			}
+			ex := executor.NewDomain(ctx, k, d)
+			ex.Create()
+			ex.SetRegistrationPolicy(c.policy)
+			if c.usedCode != "" {
+				ex.UseInviteCode(c.usedCode)
+			}
+			acc := NewController(ctx, k, "closed", "test").WithCredential(c.credential)
+			err := acc.RegistrableBy(c.registerer).Validate()
+			if !errors.Is(err, c.wantErr) {
+				t.Fatalf("want: %v, got: %v", c.wantErr, err)
+			}
+			if acc.InviteCode() != c.wantCode {
+				t.Fatalf("want invite code: %q, got: %q", c.wantCode, acc.InviteCode())
+			}
+		})
+	}
+}
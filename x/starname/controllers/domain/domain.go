@@ -2,6 +2,7 @@ package domain
 
 import (
 	crud "github.com/iov-one/cosmos-sdk-crud/pkg/crud"
+	"github.com/iov-one/iovns/pkg/idn"
 	"github.com/iov-one/iovns/pkg/utils"
 	"regexp"
 	"time"
@@ -23,6 +24,7 @@ type Domain struct {
 	ctx        sdk.Context
 	domain     *types.Domain
 	conf       *configuration.Config
+	reserved   *configuration.ReservedNames
 	k          keeper.Keeper
 	store      crud.Store
 }
@@ -46,6 +48,13 @@ func (c *Domain) WithConfiguration(cfg configuration.Config) *Domain {
 	return c
 }
 
+// WithReservedNames allows to specify cached reserved names, only
+// the entries matching the name are taken into account
+func (c *Domain) WithReservedNames(reserved configuration.ReservedNames) *Domain {
+	c.reserved = &reserved
+	return c
+}
+
 // WithDomain creates a domain controller with a cached domain
 func (c *Domain) WithDomain(dom types.Domain) *Domain {
 	c.domain = &dom
@@ -105,10 +114,20 @@ func (c *Domain) MustNotExist() *Domain {
 	return c
 }
 
-// ValidAccountName checks if the name of the domain is valid
-func (c *Domain) ValidName() *Domain {
+// ValidName checks if the name of the domain is valid and
+// is not reserved for someone other than the claimant
+func (c *Domain) ValidName(claimant sdk.AccAddress) *Domain {
+	c.validators = append(c.validators, func(controller *Domain) error {
+		return controller.validName(claimant)
+	})
+	return c
+}
+
+// NotConfusable checks that the domain name is not visually
+// confusable with the name of another existing domain
+func (c *Domain) NotConfusable() *Domain {
 	c.validators = append(c.validators, func(controller *Domain) error {
-		return controller.validName()
+		return controller.notConfusable()
 	})
 	return c
 }
@@ -137,6 +156,15 @@ func (c *Domain) Renewable() *Domain {
 	return c
 }
 
+// NotOfferedBy checks that neither the domain nor its accounts have a pending transfer
+// offer made by the provided address, which could not be honoured after the operation
+func (c *Domain) NotOfferedBy(addr sdk.AccAddress) *Domain {
+	c.validators = append(c.validators, func(controller *Domain) error {
+		return controller.notOfferedBy(addr)
+	})
+	return c
+}
+
 // expired returns nil if domain expired, otherwise ErrDomainNotExpired
 func (c *Domain) expired() error {
 	// assert domain exists
@@ -238,7 +266,11 @@ func (c *Domain) mustNotExist() error {
 }
 
 // validName checks if the name of the domain is valid
-func (c *Domain) validName() error {
+func (c *Domain) validName(claimant sdk.AccAddress) error {
+	// assert domain name is in its canonical form
+	if !idn.IsCanonical(c.domainName) {
+		return sdkerrors.Wrapf(types.ErrInvalidDomainName, "not in canonical form: %q", c.domainName)
+	}
 	// require configuration
 	c.requireConfiguration()
 	// get valid domain regexp
@@ -247,10 +279,43 @@ func (c *Domain) validName() error {
 	if !validator.MatchString(c.domainName) {
 		return sdkerrors.Wrap(types.ErrInvalidDomainName, c.domainName)
 	}
+	// assert domain name does not mix scripts
+	if err := idn.ValidateScripts(c.domainName); err != nil {
+		return sdkerrors.Wrap(types.ErrInvalidDomainName, err.Error())
+	}
+	// assert domain name is not reserved
+	c.requireReservedNames()
+	if reserved, ok := c.reserved.Blocking(c.domainName, claimant); ok {
+		return sdkerrors.Wrapf(types.ErrInvalidDomainName, "%s is reserved by %s", c.domainName, reserved.Name)
+	}
 	// success
 	return nil
 }
 
+// notConfusable checks that no other domain is confusable with the domain name
+func (c *Domain) notConfusable() error {
+	filter := c.store.Filter(&types.DomainSkeletonFilter{Name: c.domainName})
+	for ; filter.Valid(); filter.Next() {
+		domain := new(types.Domain)
+		filter.Read(domain)
+		if domain.Name == c.domainName {
+			continue
+		}
+		return sdkerrors.Wrapf(types.ErrInvalidDomainName, "%s is confusable with domain %s", c.domainName, domain.Name)
+	}
+	return nil
+}
+
+// requireReservedNames updates the reserved names matching the name
+// if they are not already set, and caches them after
+func (c *Domain) requireReservedNames() {
+	if c.reserved != nil {
+		return
+	}
+	reserved := c.k.ConfigurationKeeper.GetReservedNamesMatching(c.ctx, c.domainName)
+	c.reserved = &reserved
+}
+
 // requireConfiguration updates the configuration
 // if it is not already set, and caches it after
 func (c *Domain) requireConfiguration() {
@@ -297,6 +362,15 @@ func (c *Domain) transferable(flag types.TransferFlag) error {
 	}
 }
 
+// notOfferedBy is the unexported function used by NotOfferedBy
+func (c *Domain) notOfferedBy(addr sdk.AccAddress) error {
+	offer, ok := c.k.GetDomainPendingTransferOffer(c.ctx, c.domainName, addr)
+	if !ok {
+		return nil
+	}
+	return sdkerrors.Wrapf(types.ErrStarnameOffered, "%s is locked by the transfer offer %d of %s", c.domainName, offer.ID, offer.Starname())
+}
+
 func (c *Domain) renewable() error {
 	c.requireConfiguration()
 	if err := c.requireDomain(); err != nil {
@@ -283,7 +283,7 @@ func TestDomain_validName(t *testing.T) {
 			},
 			Test: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
 				ctrl := NewController(ctx, k, "test")
-				err := ctrl.validName()
+				err := ctrl.validName(nil)
 				if err != nil {
 					t.Fatalf("got error: %s", err)
 				}
@@ -304,16 +304,90 @@ func TestDomain_validName(t *testing.T) {
 			},
 			Test: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
 				ctrl := NewController(ctx, k, "test")
-				err := ctrl.validName()
+				err := ctrl.validName(nil)
 				if !errors.Is(err, types.ErrInvalidDomainName) {
 					t.Fatalf("want err: %s, got: %s", types.ErrInvalidDomainName, err)
 				}
 			},
 		},
+		"mixed scripts": {
+			BeforeTest: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				setConfig := keeper.GetConfigSetter(k.ConfigurationKeeper).SetConfig
+				setConfig(ctx, configuration.Config{
+					ValidDomainName: keeper.RegexMatchAll,
+				})
+			},
+			Test: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				err := NewController(ctx, k, "pаypal").validName(nil)
+				if !errors.Is(err, types.ErrInvalidDomainName) {
+					t.Fatalf("want err: %s, got: %s", types.ErrInvalidDomainName, err)
+				}
+			},
+		},
+		"not canonical": {
+			BeforeTest: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				setConfig := keeper.GetConfigSetter(k.ConfigurationKeeper).SetConfig
+				setConfig(ctx, configuration.Config{
+					ValidDomainName: keeper.RegexMatchAll,
+				})
+			},
+			Test: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				err := NewController(ctx, k, "cafe\u0301").validName(nil)
+				if !errors.Is(err, types.ErrInvalidDomainName) {
+					t.Fatalf("want err: %s, got: %s", types.ErrInvalidDomainName, err)
+				}
+			},
+		},
+		"reserved name": {
+			BeforeTest: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				setter := keeper.GetConfigSetter(k.ConfigurationKeeper)
+				setter.SetConfig(ctx, configuration.Config{
+					ValidDomainName: keeper.RegexMatchAll,
+				})
+				setter.SetReservedName(ctx, configuration.ReservedName{
+					Name:     "test",
+					Claimant: keeper.AliceKey,
+				})
+			},
+			Test: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				err := NewController(ctx, k, "test").validName(keeper.BobKey)
+				if !errors.Is(err, types.ErrInvalidDomainName) {
+					t.Fatalf("want err: %s, got: %s", types.ErrInvalidDomainName, err)
+				}
+				if err := NewController(ctx, k, "test").validName(keeper.AliceKey); err != nil {
+					t.Fatalf("claimant got error: %s", err)
+				}
+			},
+		},
 	}
 	keeper.RunTests(t, cases)
 }
 
+func TestDomain_notConfusable(t *testing.T) {
+	k, ctx, _ := keeper.NewTestKeeper(t, true)
+	k.DomainStore(ctx).Create(&types.Domain{Name: "paypal", Admin: keeper.AliceKey})
+	cases := map[string]struct {
+		name    string
+		wantErr bool
+	}{
+		"not confusable": {name: "iov"},
+		"same name":      {name: "paypal"},
+		"confusable":     {name: "pаypаl", wantErr: true},
+		"fullwidth":      {name: "ｐａｙｐａｌ", wantErr: true},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := NewController(ctx, k, c.name).NotConfusable().Validate()
+			if c.wantErr && !errors.Is(err, types.ErrInvalidDomainName) {
+				t.Fatalf("want err: %s, got: %v", types.ErrInvalidDomainName, err)
+			}
+			if !c.wantErr && err != nil {
+				t.Fatalf("got error: %s", err)
+			}
+		})
+	}
+}
+
 func TestDomain_Renewable(t *testing.T) {
 	k, ctx, _ := keeper.NewTestKeeper(t, true)
 	ctx = ctx.WithBlockTime(time.Unix(1, 0))
@@ -69,14 +69,11 @@ func (f feeApplier) renewDomain() sdk.Dec {
 	if f.domain.Type == types.OpenDomain {
 		return f.moduleFees.RenewDomainOpen
 	}
-	var accountN int64
-	as := f.k.AccountStore(f.ctx)
-	filter := as.Filter(&types.Account{Domain: f.domain.Name})
-	for ; filter.Valid(); filter.Next() {
-		accountN++
+	accountN := sdk.NewIntFromUint64(f.k.GetAccountCount(f.ctx, f.domain.Name))
+	fee := f.moduleFees.RenewDomainClosedBase.Add(f.moduleFees.RenewDomainClosedPerAccount.MulInt(accountN))
+	if fee.GT(f.moduleFees.RenewDomainClosedMax) {
+		return f.moduleFees.RenewDomainClosedMax
 	}
-	fee := f.moduleFees.RegisterAccountClosed
-	fee = fee.MulInt64(accountN)
 	return fee
 }
 
@@ -132,7 +129,7 @@ func (f feeApplier) defaultFee() sdk.Dec {
 
 func (f feeApplier) getFeeParam(msg sdk.Msg) sdk.Dec {
 	switch msg.(type) {
-	case *types.MsgTransferDomain:
+	case *types.MsgTransferDomain, *types.MsgOfferDomainTransfer:
 		return f.transferDomain()
 	case *types.MsgRegisterDomain:
 		return f.registerDomain()
@@ -140,9 +137,9 @@ func (f feeApplier) getFeeParam(msg sdk.Msg) sdk.Dec {
 		return f.renewDomain()
 	case *types.MsgRegisterAccount:
 		return f.registerAccount()
-	case *types.MsgTransferAccount:
+	case *types.MsgTransferAccount, *types.MsgOfferAccountTransfer:
 		return f.transferAccount()
-	case *types.MsgRenewAccount:
+	case *types.MsgRenewAccount, *types.MsgSetAccountValidUntil:
 		return f.renewAccount()
 	case *types.MsgReplaceAccountResources:
 		return f.replaceResources()
@@ -7,6 +7,7 @@ import (
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/iov-one/iovns/x/configuration"
 	"github.com/iov-one/iovns/x/starname/keeper"
+	"github.com/iov-one/iovns/x/starname/keeper/executor"
 	"github.com/iov-one/iovns/x/starname/types"
 )
 
@@ -42,6 +43,9 @@ func Test_FeeApplier(t *testing.T) {
 		TransferDomainClosed:         sdk.NewDec(34),
 		TransferDomainOpen:           sdk.NewDec(36),
 		RenewDomainOpen:              sdk.NewDec(28),
+		RenewDomainClosedBase:        sdk.NewDec(4),
+		RenewDomainClosedPerAccount:  sdk.NewDec(2),
+		RenewDomainClosedMax:         sdk.NewDec(10),
 	}
 	cases := map[string]struct {
 		Msg         sdk.Msg
@@ -163,7 +167,12 @@ func Test_FeeApplier(t *testing.T) {
 		"renew domain closed": {
 			Msg:         &types.MsgRenewDomain{},
 			Domain:      types.Domain{Type: types.ClosedDomain, Name: "renew"},
-			ExpectedFee: sdk.NewDec(6), // it's three accounts-> "", "1", "2"; so 4/2*3=6
+			ExpectedFee: sdk.NewDec(4), // it's two accounts besides the empty one -> "1", "2"; so (4+2*2)/2=4
+		},
+		"renew domain closed capped": {
+			Msg:         &types.MsgRenewDomain{},
+			Domain:      types.Domain{Type: types.ClosedDomain, Name: "capped"},
+			ExpectedFee: sdk.NewDec(5), // it's four accounts so (4+2*4)/2=6, capped at 10/2=5
 		},
 		"default fee unknown message": {
 			Msg:         &keeper.DullMsg{},
@@ -171,17 +180,17 @@ func Test_FeeApplier(t *testing.T) {
 		},
 		"use default fee": {
 			Msg:         &types.MsgRenewDomain{},
-			Domain:      types.Domain{Type: types.ClosedDomain, Name: "not exists"}, // since it does not exist, the fee is 0
-			ExpectedFee: sdk.NewDec(1),
+			Domain:      types.Domain{Type: types.ClosedDomain, Name: "not exists"}, // since it does not exist, the fee is the base one
+			ExpectedFee: sdk.NewDec(2),
 		},
 	}
 	k, ctx, _ := keeper.NewTestKeeper(t, true)
-	ds := k.DomainStore(ctx)
-	as := k.AccountStore(ctx)
-	ds.Create(&types.Domain{Name: "renew", Admin: keeper.AliceKey})
-	as.Create(&types.Account{Domain: "renew", Name: utils.StrPtr(types.EmptyAccountName), Owner: keeper.AliceKey}) // TODO in the future this might be removed
-	as.Create(&types.Account{Domain: "renew", Name: utils.StrPtr("1"), Owner: keeper.AliceKey})
-	as.Create(&types.Account{Domain: "renew", Name: utils.StrPtr("2"), Owner: keeper.AliceKey})
+	for domain, accounts := range map[string][]string{"renew": {"1", "2"}, "capped": {"1", "2", "3", "4"}} {
+		executor.NewDomain(ctx, k, types.Domain{Name: domain, Admin: keeper.AliceKey, Type: types.ClosedDomain}).Create()
+		for _, name := range accounts {
+			executor.NewAccount(ctx, k, types.Account{Domain: domain, Name: utils.StrPtr(name), Owner: keeper.AliceKey}).Create()
+		}
+	}
 
 	k.ConfigurationKeeper.(keeper.ConfigurationSetter).SetFees(ctx, &fee)
 	for name, c := range cases {
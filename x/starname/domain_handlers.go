@@ -18,6 +18,7 @@ func handlerMsgDeleteDomain(ctx sdk.Context, k keeper.Keeper, msg *types.MsgDele
 	if err := ctrl.
 		MustExist().
 		DeletableBy(msg.Owner).
+		NotOfferedBy(msg.Owner).
 		Validate(); err != nil {
 		return nil, err
 	}
@@ -51,7 +52,8 @@ func handleMsgRegisterDomain(ctx sdk.Context, k Keeper, msg *types.MsgRegisterDo
 	ctrl := domain.NewController(ctx, k, msg.Name)
 	err = ctrl.
 		MustNotExist().
-		ValidName().
+		ValidName(msg.Admin).
+		NotConfusable().
 		Validate()
 	if err != nil {
 		return nil, err
@@ -133,6 +135,7 @@ func handlerMsgTransferDomain(ctx sdk.Context, k keeper.Keeper, msg *types.MsgTr
 		Admin(msg.Owner).
 		NotExpired().
 		Transferable(msg.TransferFlag).
+		NotOfferedBy(msg.Owner).
 		Validate()
 	if err != nil {
 		return nil, err
@@ -163,3 +166,38 @@ func handlerMsgTransferDomain(ctx sdk.Context, k keeper.Keeper, msg *types.MsgTr
 		Events: ctx.EventManager().Events(),
 	}, nil
 }
+
+// handlerMsgSetRegistrationPolicy sets the policy which defines who can register accounts in a closed domain
+func handlerMsgSetRegistrationPolicy(ctx sdk.Context, k keeper.Keeper, msg *types.MsgSetRegistrationPolicy) (*sdk.Result, error) {
+	ctrl := domain.NewController(ctx, k, msg.Domain)
+	if err := ctrl.
+		MustExist().
+		Type(types.ClosedDomain).
+		Admin(msg.Owner).
+		NotExpired().
+		Validate(); err != nil {
+		return nil, err
+	}
+	feeCtrl := fees.NewController(ctx, k, ctrl.Domain())
+	fee := feeCtrl.GetFee(msg)
+	// collect fees
+	if err := k.CollectFees(ctx, msg, fee); err != nil {
+		return nil, sdkerrors.Wrap(err, "unable to collect fees")
+	}
+	// replace the registration policy
+	executor.NewDomain(ctx, k, ctrl.Domain()).SetRegistrationPolicy(msg.Policy())
+	// success
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			sdk.EventTypeMessage,
+			sdk.NewAttribute(sdk.AttributeKeyModule, types.ModuleName),
+			sdk.NewAttribute(sdk.AttributeKeySender, msg.Owner.String()),
+			sdk.NewAttribute(sdk.AttributeKeyAction, msg.Type()),
+			sdk.NewAttribute(types.AttributeKeyDomainName, msg.Domain),
+			sdk.NewAttribute(types.AttributeKeyInviteCodes, fmt.Sprintf("%t", msg.InviteCodes)),
+		),
+	)
+	return &sdk.Result{
+		Events: ctx.EventManager().Events(),
+	}, nil
+}
@@ -525,6 +525,53 @@ func TestHandleMsgRegisterDomain(t *testing.T) {
 				}
 			},
 		},
+		"success name stored in canonical form": {
+			BeforeTest: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				keeper.GetConfigSetter(k.ConfigurationKeeper).SetConfig(ctx, configuration.Config{
+					Configurer:      keeper.AliceKey,
+					ValidDomainName: "^(.*?)?",
+				})
+			},
+			Test: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				_, err := NewHandler(k)(ctx, &types.MsgRegisterDomain{
+					Name:       "cafe\u0301",
+					Admin:      keeper.AliceKey,
+					DomainType: types.OpenDomain,
+				})
+				if err != nil {
+					t.Fatalf("handleMsgRegisterDomain() got error: %s", err)
+				}
+			},
+			AfterTest: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				exists := k.DomainStore(ctx).Read((&types.Domain{Name: "caf\u00e9"}).PrimaryKey(), new(types.Domain))
+				if !exists {
+					t.Fatalf("handleMsgRegisterDomain() could not find domain in canonical form")
+				}
+			},
+		},
+		"fail domain confusable with existing one": {
+			BeforeTest: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				keeper.GetConfigSetter(k.ConfigurationKeeper).SetConfig(ctx, configuration.Config{
+					Configurer:      keeper.AliceKey,
+					ValidDomainName: "^(.*?)?",
+				})
+				executor.NewDomain(ctx, k, types.Domain{
+					Name:  "coop",
+					Admin: keeper.BobKey,
+					Type:  types.OpenDomain,
+				}).Create()
+			},
+			Test: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				_, err := handleMsgRegisterDomain(ctx, k, &types.MsgRegisterDomain{
+					Name:       "\u0441\u043e\u043e\u0440",
+					Admin:      keeper.AliceKey,
+					DomainType: types.OpenDomain,
+				})
+				if !errors.Is(err, types.ErrInvalidDomainName) {
+					t.Fatalf("handleMsgRegisterDomain() expected: %s got: %s", types.ErrInvalidDomainName, err)
+				}
+			},
+		},
 		"fail domain name exists": {
 			BeforeTest: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
 				executor.NewDomain(ctx, k, types.Domain{
@@ -785,3 +832,111 @@ func Test_handlerMsgTransferDomain(t *testing.T) {
 
 	keeper.RunTests(t, cases)
 }
+
+func Test_handlerMsgSetRegistrationPolicy(t *testing.T) {
+	createDomain := func(domainType types.DomainType) func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+		return func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+			executor.NewDomain(ctx, k, types.Domain{
+				Name:       "test",
+				Admin:      keeper.AliceKey,
+				ValidUntil: time.Now().Add(100000 * time.Hour).Unix(),
+				Type:       domainType,
+			}).Create()
+		}
+	}
+	cases := map[string]keeper.SubTest{
+		"success": {
+			BeforeTest: createDomain(types.ClosedDomain),
+			Test: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				_, err := handlerMsgSetRegistrationPolicy(ctx, k, &types.MsgSetRegistrationPolicy{
+					Domain:      "test",
+					Owner:       keeper.AliceKey,
+					AllowList:   []sdk.AccAddress{keeper.BobKey},
+					InviteCodes: true,
+				})
+				if err != nil {
+					t.Fatalf("handlerMsgSetRegistrationPolicy() got error: %s", err)
+				}
+			},
+			AfterTest: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				policy, ok := k.GetRegistrationPolicy(ctx, "test")
+				if !ok {
+					t.Fatal("registration policy not found")
+				}
+				if !policy.Allowed(keeper.BobKey) || !policy.InviteCodes {
+					t.Fatalf("unexpected registration policy: %+v", policy)
+				}
+			},
+		},
+		"success empty policy removes the existing one": {
+			BeforeTest: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				createDomain(types.ClosedDomain)(t, k, ctx, mocks)
+				k.SetRegistrationPolicy(ctx, types.RegistrationPolicy{Domain: "test", InviteCodes: true})
+			},
+			Test: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				_, err := handlerMsgSetRegistrationPolicy(ctx, k, &types.MsgSetRegistrationPolicy{
+					Domain: "test",
+					Owner:  keeper.AliceKey,
+				})
+				if err != nil {
+					t.Fatalf("handlerMsgSetRegistrationPolicy() got error: %s", err)
+				}
+			},
+			AfterTest: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				if _, ok := k.GetRegistrationPolicy(ctx, "test"); ok {
+					t.Fatal("registration policy should not exist")
+				}
+			},
+		},
+		"fail not admin": {
+			BeforeTest: createDomain(types.ClosedDomain),
+			Test: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				_, err := handlerMsgSetRegistrationPolicy(ctx, k, &types.MsgSetRegistrationPolicy{
+					Domain:      "test",
+					Owner:       keeper.BobKey,
+					InviteCodes: true,
+				})
+				if !errors.Is(err, types.ErrUnauthorized) {
+					t.Fatalf("handlerMsgSetRegistrationPolicy() want: %s, got: %s", types.ErrUnauthorized, err)
+				}
+			},
+		},
+		"fail open domain": {
+			BeforeTest: createDomain(types.OpenDomain),
+			Test: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				_, err := handlerMsgSetRegistrationPolicy(ctx, k, &types.MsgSetRegistrationPolicy{
+					Domain:      "test",
+					Owner:       keeper.AliceKey,
+					InviteCodes: true,
+				})
+				if !errors.Is(err, types.ErrInvalidDomainType) {
+					t.Fatalf("handlerMsgSetRegistrationPolicy() want: %s, got: %s", types.ErrInvalidDomainType, err)
+				}
+			},
+		},
+		"success domain transfer removes the policy": {
+			BeforeTest: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				createDomain(types.ClosedDomain)(t, k, ctx, mocks)
+				k.SetRegistrationPolicy(ctx, types.RegistrationPolicy{Domain: "test", InviteCodes: true})
+			},
+			Test: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				_, err := handlerMsgTransferDomain(ctx, k, &types.MsgTransferDomain{
+					Domain:       "test",
+					Owner:        keeper.AliceKey,
+					NewAdmin:     keeper.BobKey,
+					TransferFlag: types.TransferOwned,
+				})
+				if err != nil {
+					t.Fatalf("handlerMsgTransferDomain() got error: %s", err)
+				}
+			},
+			AfterTest: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				if _, ok := k.GetRegistrationPolicy(ctx, "test"); ok {
+					t.Fatal("registration policy should not exist")
+				}
+			},
+		},
+	}
+
+	keeper.RunTests(t, cases)
+}
@@ -1,9 +1,15 @@
 package starname
 
 import (
+	"crypto/sha256"
 	"fmt"
+	"regexp"
+
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
 	crud "github.com/iov-one/cosmos-sdk-crud/pkg/crud/types"
+	"github.com/iov-one/iovns/pkg/idn"
+	"github.com/iov-one/iovns/x/configuration"
 	"github.com/iov-one/iovns/x/starname/types"
 )
 
@@ -13,6 +19,28 @@ type GenesisState struct {
 	Domains []types.Domain `json:"domains"`
 	// AccountRecords contains the records of registered accounts
 	Accounts []types.Account `json:"accounts"`
+	// RegistrationPolicies contains the account registration policies of closed domains
+	RegistrationPolicies []types.RegistrationPolicy `json:"registration_policies,omitempty"`
+	// UsedInviteCodes contains the hashes of the invite codes already used in closed domains
+	UsedInviteCodes []UsedInviteCode `json:"used_invite_codes,omitempty"`
+	// TransferOffers contains the pending transfer offers of domains and accounts
+	TransferOffers []types.TransferOffer `json:"transfer_offers,omitempty"`
+	// LastTransferOfferID is the ID of the last transfer offer created
+	LastTransferOfferID uint64 `json:"last_transfer_offer_id,omitempty"`
+	// Sponsorships contains the renewal sponsorships of domains and accounts
+	Sponsorships []types.Sponsorship `json:"sponsorships,omitempty"`
+	// History contains the history records of domains and accounts
+	History []types.HistoryRecord `json:"history,omitempty"`
+	// LastHistoryID is the ID of the last history record
+	LastHistoryID uint64 `json:"last_history_id,omitempty"`
+}
+
+// UsedInviteCode is the genesis record of an invite code already used in a domain
+type UsedInviteCode struct {
+	// Domain is the name of the domain the invite code belongs to
+	Domain string `json:"domain"`
+	// CodeHash is the hash of the invite code
+	CodeHash []byte `json:"code_hash"`
 }
 
 // NewGenesisState builds a genesis state including the domains provided
@@ -20,20 +48,248 @@ func NewGenesisState(domains []types.Domain, accounts []types.Account) GenesisSt
 	return GenesisState{Domains: domains, Accounts: accounts}
 }
 
-// ValidateGenesis validates a genesis state
-// checking for domain validity and no domain name repetitions
+// ValidateGenesis validates a genesis state checking for domain and account
+// validity, name repetitions, orphan accounts and domains without empty account,
+// the names are checked against the configuration by the app ValidateGenesis
 func ValidateGenesis(data GenesisState) error {
-	namesSet := make(map[string]struct{}, len(data.Domains))
+	if errs := GenesisErrors(data, nil); len(errs) != 0 {
+		return errs[0]
+	}
+	return nil
+}
+
+// ValidateGenesisWithConfig validates a genesis state like ValidateGenesis does
+// and also checks names and account limits against the provided configuration
+func ValidateGenesisWithConfig(data GenesisState, conf configuration.Config) error {
+	if errs := GenesisErrors(data, &conf); len(errs) != 0 {
+		return errs[0]
+	}
+	return nil
+}
+
+// GenesisErrors returns all the issues found in the genesis state,
+// if conf is not nil domains and accounts are checked against it too
+func GenesisErrors(data GenesisState, conf *configuration.Config) []error {
+	var errs []error
+	var v *genesisValidator
+	if conf != nil {
+		var err error
+		v, err = newGenesisValidator(*conf)
+		if err != nil {
+			return []error{err}
+		}
+	}
+	// check domains
+	domains := make(map[string]struct{}, len(data.Domains))
 	for _, domain := range data.Domains {
-		if _, ok := namesSet[domain.Name]; ok {
-			return fmt.Errorf("domain name %s declared twice", domain.Name)
+		if _, ok := domains[domain.Name]; ok {
+			errs = append(errs, sdkerrors.Wrapf(types.ErrDomainAlreadyExists, "domain name %s declared twice", domain.Name))
+			continue
 		}
-		namesSet[domain.Name] = struct{}{}
+		domains[domain.Name] = struct{}{}
 		if err := validateDomain(domain); err != nil {
-			return err
+			errs = append(errs, err)
+			continue
+		}
+		if v == nil {
+			continue
+		}
+		if err := v.domain(domain); err != nil {
+			errs = append(errs, err)
 		}
 	}
-	return nil
+	// check accounts
+	accounts := make(map[string]struct{}, len(data.Accounts))
+	emptyAccounts := make(map[string]struct{}, len(data.Domains))
+	for _, account := range data.Accounts {
+		if err := validateAccount(account); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		starname := types.AccountStarname(account.Domain, *account.Name)
+		if _, ok := accounts[starname]; ok {
+			errs = append(errs, sdkerrors.Wrapf(types.ErrAccountExists, "account %s declared twice", starname))
+			continue
+		}
+		accounts[starname] = struct{}{}
+		if _, ok := domains[account.Domain]; !ok {
+			errs = append(errs, sdkerrors.Wrapf(types.ErrDomainDoesNotExist, "domain %s of account %s", account.Domain, starname))
+			continue
+		}
+		if *account.Name == "" {
+			emptyAccounts[account.Domain] = struct{}{}
+		}
+		if v == nil {
+			continue
+		}
+		if err := v.account(account); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	// check every domain has its empty account
+	for _, domain := range data.Domains {
+		if _, ok := emptyAccounts[domain.Name]; !ok {
+			errs = append(errs, sdkerrors.Wrapf(types.ErrAccountDoesNotExist, "empty account of domain %s", domain.Name))
+			// report missing empty accounts once per domain name
+			emptyAccounts[domain.Name] = struct{}{}
+		}
+	}
+	errs = append(errs, registrationErrors(data)...)
+	errs = append(errs, transferOfferErrors(data)...)
+	errs = append(errs, sponsorshipErrors(data)...)
+	errs = append(errs, historyErrors(data)...)
+	return errs
+}
+
+// historyErrors returns the issues found in the history records, which
+// can refer to deleted domains and accounts
+func historyErrors(data GenesisState) []error {
+	var errs []error
+	ids := make(map[uint64]struct{}, len(data.History))
+	for _, record := range data.History {
+		if err := record.Validate(); err != nil {
+			errs = append(errs, sdkerrors.Wrapf(err, "history record %d", record.ID))
+			continue
+		}
+		if record.ID == 0 || record.ID > data.LastHistoryID {
+			errs = append(errs, sdkerrors.Wrapf(types.ErrInvalidHistoryRecord, "id %d is not in range 1-%d", record.ID, data.LastHistoryID))
+			continue
+		}
+		if _, ok := ids[record.ID]; ok {
+			errs = append(errs, sdkerrors.Wrapf(types.ErrInvalidHistoryRecord, "id %d declared twice", record.ID))
+			continue
+		}
+		ids[record.ID] = struct{}{}
+	}
+	return errs
+}
+
+// sponsorshipErrors returns the issues found in the sponsorships, which must refer to
+// existing domains or to existing accounts of open domains, one per sponsor and starname
+func sponsorshipErrors(data GenesisState) []error {
+	var errs []error
+	sponsorable := make(map[string]bool, len(data.Domains)+len(data.Accounts))
+	open := make(map[string]bool, len(data.Domains))
+	for _, domain := range data.Domains {
+		sponsorable[domain.Name] = true
+		open[domain.Name] = domain.Type == types.OpenDomain
+	}
+	for _, account := range data.Accounts {
+		if account.Name != nil && *account.Name != types.EmptyAccountName {
+			sponsorable[types.AccountStarname(account.Domain, *account.Name)] = open[account.Domain]
+		}
+	}
+	keys := make(map[string]struct{}, len(data.Sponsorships))
+	for _, sponsorship := range data.Sponsorships {
+		starname := sponsorship.Starname()
+		if err := sponsorship.Validate(); err != nil {
+			errs = append(errs, sdkerrors.Wrapf(err, "sponsorship of %s", starname))
+			continue
+		}
+		key := string(sponsorship.PrimaryKey().Key())
+		if _, ok := keys[key]; ok {
+			errs = append(errs, sdkerrors.Wrapf(types.ErrInvalidSponsorship, "sponsorship of %s by %s declared twice", starname, sponsorship.Sponsor))
+			continue
+		}
+		keys[key] = struct{}{}
+		if !sponsorable[starname] {
+			errs = append(errs, sdkerrors.Wrapf(types.ErrInvalidSponsorship, "sponsorship of %s which does not exist or cannot be renewed", starname))
+		}
+	}
+	return errs
+}
+
+// transferOfferErrors returns the issues found in the transfer offers, which must
+// be made by the owner allowed to transfer existing domains or accounts, one per starname
+func transferOfferErrors(data GenesisState) []error {
+	var errs []error
+	owners := make(map[string]sdk.AccAddress, len(data.Domains)+len(data.Accounts))
+	closed := make(map[string]bool, len(data.Domains))
+	for _, domain := range data.Domains {
+		owners[domain.Name] = domain.Admin
+		closed[domain.Name] = domain.Type == types.ClosedDomain
+	}
+	for _, account := range data.Accounts {
+		if account.Name == nil {
+			continue
+		}
+		// accounts of closed domains are offered by the domain admin
+		owner := account.Owner
+		if closed[account.Domain] {
+			owner = owners[account.Domain]
+		}
+		owners[types.AccountStarname(account.Domain, *account.Name)] = owner
+	}
+	ids := make(map[uint64]struct{}, len(data.TransferOffers))
+	starnames := make(map[string]struct{}, len(data.TransferOffers))
+	for _, offer := range data.TransferOffers {
+		if err := offer.Validate(); err != nil {
+			errs = append(errs, sdkerrors.Wrapf(err, "transfer offer %d", offer.ID))
+			continue
+		}
+		if offer.ID == 0 || offer.ID > data.LastTransferOfferID {
+			errs = append(errs, sdkerrors.Wrapf(types.ErrInvalidTransferOffer, "id %d is not in range 1-%d", offer.ID, data.LastTransferOfferID))
+			continue
+		}
+		if _, ok := ids[offer.ID]; ok {
+			errs = append(errs, sdkerrors.Wrapf(types.ErrInvalidTransferOffer, "id %d declared twice", offer.ID))
+			continue
+		}
+		ids[offer.ID] = struct{}{}
+		starname := offer.Starname()
+		if _, ok := starnames[starname]; ok {
+			errs = append(errs, sdkerrors.Wrapf(types.ErrTransferOfferExists, "transfer offer %d of %s", offer.ID, starname))
+			continue
+		}
+		starnames[starname] = struct{}{}
+		owner, ok := owners[starname]
+		if !ok || !owner.Equals(offer.Owner) {
+			errs = append(errs, sdkerrors.Wrapf(types.ErrInvalidTransferOffer, "transfer offer %d of %s which does not exist or is not owned by %s", offer.ID, starname, offer.Owner))
+		}
+	}
+	return errs
+}
+
+// registrationErrors returns the issues found in the registration policies
+// and in the used invite codes, which can belong only to closed domains
+func registrationErrors(data GenesisState) []error {
+	var errs []error
+	closed := make(map[string]bool, len(data.Domains))
+	for _, domain := range data.Domains {
+		closed[domain.Name] = domain.Type == types.ClosedDomain
+	}
+	policies := make(map[string]struct{}, len(data.RegistrationPolicies))
+	for _, policy := range data.RegistrationPolicies {
+		if err := policy.Validate(); err != nil {
+			errs = append(errs, sdkerrors.Wrapf(err, "registration policy of domain %s", policy.Domain))
+			continue
+		}
+		if _, ok := policies[policy.Domain]; ok {
+			errs = append(errs, sdkerrors.Wrapf(types.ErrInvalidDomainName, "registration policy of domain %s declared twice", policy.Domain))
+			continue
+		}
+		policies[policy.Domain] = struct{}{}
+		if isClosed, ok := closed[policy.Domain]; !ok || !isClosed {
+			errs = append(errs, sdkerrors.Wrapf(types.ErrInvalidDomainType, "registration policy of domain %s which does not exist or is not closed", policy.Domain))
+		}
+	}
+	codes := make(map[string]struct{}, len(data.UsedInviteCodes))
+	for _, code := range data.UsedInviteCodes {
+		if len(code.CodeHash) != sha256.Size {
+			errs = append(errs, sdkerrors.Wrapf(types.ErrInvalidCredential, "invalid invite code hash %X in domain %s", code.CodeHash, code.Domain))
+			continue
+		}
+		key := fmt.Sprintf("%s/%X", code.Domain, code.CodeHash)
+		if _, ok := codes[key]; ok {
+			errs = append(errs, sdkerrors.Wrapf(types.ErrInviteCodeUsed, "invite code hash %X of domain %s declared twice", code.CodeHash, code.Domain))
+			continue
+		}
+		codes[key] = struct{}{}
+		if isClosed, ok := closed[code.Domain]; !ok || !isClosed {
+			errs = append(errs, sdkerrors.Wrapf(types.ErrInvalidDomainType, "invite code of domain %s which does not exist or is not closed", code.Domain))
+		}
+	}
+	return errs
 }
 
 // DefaultGenesisState creates an empty genesis state for the domain module
@@ -53,7 +309,38 @@ func InitGenesis(ctx sdk.Context, keeper Keeper, data GenesisState) {
 	as := keeper.AccountStore(ctx)
 	for _, account := range data.Accounts {
 		as.Create(&account)
+		if *account.Name != types.EmptyAccountName {
+			keeper.IncreaseAccountCount(ctx, account.Domain)
+		}
+	}
+	// insert registration policies and used invite codes
+	for _, policy := range data.RegistrationPolicies {
+		keeper.SetRegistrationPolicy(ctx, policy)
+	}
+	for _, code := range data.UsedInviteCodes {
+		keeper.SetInviteCodeUsed(ctx, code.Domain, code.CodeHash)
+	}
+	// insert transfer offers
+	for _, offer := range data.TransferOffers {
+		keeper.SetTransferOffer(ctx, offer)
+	}
+	if data.LastTransferOfferID != 0 {
+		keeper.SetTransferOfferSequence(ctx, data.LastTransferOfferID)
 	}
+	// insert sponsorships
+	for _, sponsorship := range data.Sponsorships {
+		keeper.SetSponsorship(ctx, sponsorship)
+	}
+	// insert history records
+	for _, record := range data.History {
+		keeper.SetHistoryRecord(ctx, record)
+	}
+	if data.LastHistoryID != 0 {
+		keeper.SetHistorySequence(ctx, data.LastHistoryID)
+	}
+	// genesis state is always in the latest format
+	migrations := keeper.Migrations()
+	migrations.SetVersion(ctx, migrations.LatestVersion())
 }
 
 // ExportGenesis saves the state of the domain module
@@ -74,14 +361,144 @@ func ExportGenesis(ctx sdk.Context, k Keeper) GenesisState {
 		accounts = append(accounts, *account)
 		return true
 	})
+	var policies []types.RegistrationPolicy
+	var codes []UsedInviteCode
+	k.IterateRegistrationPolicies(ctx, func(policy types.RegistrationPolicy) bool {
+		policies = append(policies, policy)
+		return true
+	})
+	for _, domain := range domains {
+		k.IterateUsedInviteCodes(ctx, domain.Name, func(codeHash []byte) bool {
+			codes = append(codes, UsedInviteCode{Domain: domain.Name, CodeHash: codeHash})
+			return true
+		})
+	}
+	var offers []types.TransferOffer
+	k.IterateTransferOffers(ctx, func(offer types.TransferOffer) bool {
+		offers = append(offers, offer)
+		return true
+	})
+	var sponsorships []types.Sponsorship
+	k.IterateSponsorships(ctx, func(sponsorship types.Sponsorship) bool {
+		sponsorships = append(sponsorships, sponsorship)
+		return true
+	})
+	var history []types.HistoryRecord
+	k.IterateHistory(ctx, func(record types.HistoryRecord) bool {
+		history = append(history, record)
+		return true
+	})
 	return GenesisState{
-		Domains:  domains,
-		Accounts: accounts,
+		Domains:              domains,
+		Accounts:             accounts,
+		RegistrationPolicies: policies,
+		UsedInviteCodes:      codes,
+		TransferOffers:       offers,
+		LastTransferOfferID:  k.GetTransferOfferSequence(ctx),
+		Sponsorships:         sponsorships,
+		History:              history,
+		LastHistoryID:        k.GetHistorySequence(ctx),
 	}
 }
 
 // validateDomain checks if a domain is valid or not
 func validateDomain(d types.Domain) error {
-	// TODO fill
+	if d.Name == "" {
+		return sdkerrors.Wrap(types.ErrInvalidDomainName, "empty")
+	}
+	if !idn.IsCanonical(d.Name) {
+		return sdkerrors.Wrapf(types.ErrInvalidDomainName, "domain %q is not in canonical form", d.Name)
+	}
+	if d.Admin.Empty() {
+		return sdkerrors.Wrapf(types.ErrInvalidOwner, "empty admin for domain %s", d.Name)
+	}
+	if err := types.ValidateDomainType(d.Type); err != nil {
+		return sdkerrors.Wrapf(err, "domain %s", d.Name)
+	}
+	return nil
+}
+
+// validateAccount checks if an account is valid or not
+func validateAccount(a types.Account) error {
+	if a.Domain == "" {
+		return sdkerrors.Wrap(types.ErrInvalidDomainName, "empty account domain")
+	}
+	if a.Name == nil {
+		return sdkerrors.Wrapf(types.ErrInvalidAccountName, "nil account name in domain %s", a.Domain)
+	}
+	if !idn.IsCanonical(a.Domain) || !idn.IsCanonical(*a.Name) {
+		return sdkerrors.Wrapf(types.ErrInvalidAccountName, "account %q is not in canonical form", types.AccountStarname(a.Domain, *a.Name))
+	}
+	if a.Owner.Empty() {
+		return sdkerrors.Wrapf(types.ErrInvalidOwner, "empty owner for account %s", types.AccountStarname(a.Domain, *a.Name))
+	}
+	return nil
+}
+
+// genesisValidator checks domains and accounts against the configuration
+type genesisValidator struct {
+	conf          configuration.Config
+	validDomain   *regexp.Regexp
+	validAccount  *regexp.Regexp
+	validURI      *regexp.Regexp
+	validResource *regexp.Regexp
+}
+
+func newGenesisValidator(conf configuration.Config) (*genesisValidator, error) {
+	if err := conf.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+	return &genesisValidator{
+		conf:          conf,
+		validDomain:   regexp.MustCompile(conf.ValidDomainName),
+		validAccount:  regexp.MustCompile(conf.ValidAccountName),
+		validURI:      regexp.MustCompile(conf.ValidURI),
+		validResource: regexp.MustCompile(conf.ValidResource),
+	}, nil
+}
+
+// domain checks the domain name against the configuration
+func (v *genesisValidator) domain(d types.Domain) error {
+	if !v.validDomain.MatchString(d.Name) {
+		return sdkerrors.Wrap(types.ErrInvalidDomainName, d.Name)
+	}
+	return nil
+}
+
+// account checks the account name, resources, certificates
+// and metadata against the configuration
+func (v *genesisValidator) account(a types.Account) error {
+	starname := types.AccountStarname(a.Domain, *a.Name)
+	// empty accounts do not need to match the account name regexp
+	if *a.Name != "" && !v.validAccount.MatchString(*a.Name) {
+		return sdkerrors.Wrap(types.ErrInvalidAccountName, starname)
+	}
+	if uint32(len(a.Resources)) > v.conf.ResourcesMax {
+		return sdkerrors.Wrapf(types.ErrResourceLimitExceeded, "account %s has %d resources, limit: %d", starname, len(a.Resources), v.conf.ResourcesMax)
+	}
+	uris := make(map[string]struct{}, len(a.Resources))
+	for _, resource := range a.Resources {
+		if _, ok := uris[resource.URI]; ok {
+			return sdkerrors.Wrapf(types.ErrInvalidResource, "duplicate URI %s in account %s", resource.URI, starname)
+		}
+		uris[resource.URI] = struct{}{}
+		if !v.validURI.MatchString(resource.URI) {
+			return sdkerrors.Wrapf(types.ErrInvalidResource, "%s is not a valid URI in account %s", resource.URI, starname)
+		}
+		if !v.validResource.MatchString(resource.Resource) {
+			return sdkerrors.Wrapf(types.ErrInvalidResource, "%s is not a valid resource in account %s", resource.Resource, starname)
+		}
+	}
+	if uint32(len(a.Certificates)) > v.conf.CertificateCountMax {
+		return sdkerrors.Wrapf(types.ErrCertificateLimitReached, "account %s has %d certificates, limit: %d", starname, len(a.Certificates), v.conf.CertificateCountMax)
+	}
+	for _, cert := range a.Certificates {
+		if uint64(len(cert)) > v.conf.CertificateSizeMax {
+			return sdkerrors.Wrapf(types.ErrCertificateSizeExceeded, "account %s, max certificate size %d exceeded", starname, v.conf.CertificateSizeMax)
+		}
+	}
+	if uint64(len(a.MetadataURI)) > v.conf.MetadataSizeMax {
+		return sdkerrors.Wrapf(types.ErrMetadataSizeExceeded, "account %s, max metadata size %d exceeded", starname, v.conf.MetadataSizeMax)
+	}
 	return nil
 }
@@ -2,9 +2,13 @@ package starname
 
 import (
 	"encoding/json"
+	"errors"
 	"testing"
+	"time"
 
+	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/iov-one/iovns/pkg/utils"
+	"github.com/iov-one/iovns/x/configuration"
 	"github.com/iov-one/iovns/x/starname/keeper"
 	"github.com/iov-one/iovns/x/starname/keeper/executor"
 	"github.com/iov-one/iovns/x/starname/types"
@@ -35,3 +39,264 @@ func TestExportGenesis(t *testing.T) {
 		t.Fatal("unexpected genesis state")
 	}
 }
+
+func TestValidateGenesis(t *testing.T) {
+	conf := configuration.Config{
+		Configurer:          keeper.AliceKey,
+		ValidDomainName:     "^[a-z]{4,16}$",
+		ValidAccountName:    "^[a-z]{1,16}$",
+		ValidURI:            "^[a-z:]+$",
+		ValidResource:       "^[a-z]+$",
+		ResourcesMax:        1,
+		CertificateSizeMax:  4,
+		CertificateCountMax: 1,
+		MetadataSizeMax:     4,
+	}
+	domain := types.Domain{Name: "test", Admin: keeper.AliceKey, ValidUntil: 100, Type: types.OpenDomain}
+	emptyAccount := types.Account{Domain: "test", Name: utils.StrPtr(""), Owner: keeper.AliceKey, ValidUntil: 100}
+	closedDomain := types.Domain{Name: "closed", Admin: keeper.AliceKey, ValidUntil: 100, Type: types.ClosedDomain}
+	closedEmptyAccount := types.Account{Domain: "closed", Name: utils.StrPtr(""), Owner: keeper.AliceKey, ValidUntil: 100}
+	account := func(name string) types.Account {
+		return types.Account{Domain: "test", Name: utils.StrPtr(name), Owner: keeper.BobKey, ValidUntil: 100}
+	}
+	cases := map[string]struct {
+		Genesis    GenesisState
+		WithConfig bool
+		Err        error
+	}{
+		"success": {
+			Genesis:    NewGenesisState([]types.Domain{domain}, []types.Account{emptyAccount, account("bob")}),
+			WithConfig: true,
+		},
+		"duplicate domain": {
+			Genesis: NewGenesisState([]types.Domain{domain, domain}, []types.Account{emptyAccount}),
+			Err:     types.ErrDomainAlreadyExists,
+		},
+		"invalid domain type": {
+			Genesis: NewGenesisState([]types.Domain{{Name: "test", Admin: keeper.AliceKey, Type: "invalid"}}, []types.Account{emptyAccount}),
+			Err:     types.ErrInvalidDomainType,
+		},
+		"missing empty account": {
+			Genesis: NewGenesisState([]types.Domain{domain}, []types.Account{account("bob")}),
+			Err:     types.ErrAccountDoesNotExist,
+		},
+		"orphan account": {
+			Genesis: NewGenesisState(nil, []types.Account{{Domain: "none", Name: utils.StrPtr("bob"), Owner: keeper.BobKey}}),
+			Err:     types.ErrDomainDoesNotExist,
+		},
+		"duplicate account": {
+			Genesis: NewGenesisState([]types.Domain{domain}, []types.Account{emptyAccount, account("bob"), account("bob")}),
+			Err:     types.ErrAccountExists,
+		},
+		"nil account name": {
+			Genesis: NewGenesisState([]types.Domain{domain}, []types.Account{{Domain: "test", Owner: keeper.BobKey}}),
+			Err:     types.ErrInvalidAccountName,
+		},
+		"invalid domain name": {
+			Genesis: NewGenesisState(
+				[]types.Domain{{Name: "t", Admin: keeper.AliceKey, Type: types.OpenDomain}},
+				[]types.Account{{Domain: "t", Name: utils.StrPtr(""), Owner: keeper.AliceKey}},
+			),
+			WithConfig: true,
+			Err:        types.ErrInvalidDomainName,
+		},
+		"account name not in canonical form": {
+			Genesis: NewGenesisState([]types.Domain{domain}, []types.Account{emptyAccount, account("noe\u0308l")}),
+			Err:     types.ErrInvalidAccountName,
+		},
+		"invalid account name": {
+			Genesis:    NewGenesisState([]types.Domain{domain}, []types.Account{emptyAccount, account("B0B")}),
+			WithConfig: true,
+			Err:        types.ErrInvalidAccountName,
+		},
+		"resource limit exceeded": {
+			Genesis: NewGenesisState([]types.Domain{domain}, []types.Account{emptyAccount, func() types.Account {
+				acc := account("bob")
+				acc.Resources = []types.Resource{{URI: "a:b", Resource: "a"}, {URI: "c:d", Resource: "c"}}
+				return acc
+			}()}),
+			WithConfig: true,
+			Err:        types.ErrResourceLimitExceeded,
+		},
+		"certificate size exceeded": {
+			Genesis: NewGenesisState([]types.Domain{domain}, []types.Account{emptyAccount, func() types.Account {
+				acc := account("bob")
+				acc.Certificates = []types.Certificate{[]byte("toolong")}
+				return acc
+			}()}),
+			WithConfig: true,
+			Err:        types.ErrCertificateSizeExceeded,
+		},
+		"success registration policy": {
+			Genesis: GenesisState{
+				Domains:              []types.Domain{closedDomain},
+				Accounts:             []types.Account{closedEmptyAccount},
+				RegistrationPolicies: []types.RegistrationPolicy{{Domain: "closed", AllowList: []sdk.AccAddress{keeper.BobKey}}},
+				UsedInviteCodes:      []UsedInviteCode{{Domain: "closed", CodeHash: keeper.InviteCodeHash("code")}},
+			},
+		},
+		"registration policy of open domain": {
+			Genesis: GenesisState{
+				Domains:              []types.Domain{domain},
+				Accounts:             []types.Account{emptyAccount},
+				RegistrationPolicies: []types.RegistrationPolicy{{Domain: "test", InviteCodes: true}},
+			},
+			Err: types.ErrInvalidDomainType,
+		},
+		"invalid invite code hash": {
+			Genesis: GenesisState{
+				Domains:         []types.Domain{closedDomain},
+				Accounts:        []types.Account{closedEmptyAccount},
+				UsedInviteCodes: []UsedInviteCode{{Domain: "closed", CodeHash: []byte("code")}},
+			},
+			Err: types.ErrInvalidCredential,
+		},
+		"success transfer offer": {
+			Genesis: GenesisState{
+				Domains:             []types.Domain{domain},
+				Accounts:            []types.Account{emptyAccount, account("bob")},
+				TransferOffers:      []types.TransferOffer{{ID: 2, Domain: "test", Name: utils.StrPtr("bob"), Owner: keeper.BobKey, Recipient: keeper.CharlieKey}},
+				LastTransferOfferID: 2,
+			},
+		},
+		"transfer offer id out of range": {
+			Genesis: GenesisState{
+				Domains:             []types.Domain{domain},
+				Accounts:            []types.Account{emptyAccount},
+				TransferOffers:      []types.TransferOffer{{ID: 3, Domain: "test", Owner: keeper.AliceKey, Recipient: keeper.CharlieKey}},
+				LastTransferOfferID: 2,
+			},
+			Err: types.ErrInvalidTransferOffer,
+		},
+		"transfer offer not made by the owner": {
+			Genesis: GenesisState{
+				Domains:             []types.Domain{domain},
+				Accounts:            []types.Account{emptyAccount, account("bob")},
+				TransferOffers:      []types.TransferOffer{{ID: 1, Domain: "test", Name: utils.StrPtr("bob"), Owner: keeper.AliceKey, Recipient: keeper.CharlieKey}},
+				LastTransferOfferID: 1,
+			},
+			Err: types.ErrInvalidTransferOffer,
+		},
+		"transfer offer declared twice for a starname": {
+			Genesis: GenesisState{
+				Domains:  []types.Domain{domain},
+				Accounts: []types.Account{emptyAccount},
+				TransferOffers: []types.TransferOffer{
+					{ID: 1, Domain: "test", Owner: keeper.AliceKey, Recipient: keeper.CharlieKey},
+					{ID: 2, Domain: "test", Owner: keeper.AliceKey, Recipient: keeper.BobKey},
+				},
+				LastTransferOfferID: 2,
+			},
+			Err: types.ErrTransferOfferExists,
+		},
+		"success sponsorship": {
+			Genesis: GenesisState{
+				Domains:  []types.Domain{domain},
+				Accounts: []types.Account{emptyAccount, account("bob")},
+				Sponsorships: []types.Sponsorship{
+					{Domain: "test", Sponsor: keeper.CharlieKey, Balance: sdk.NewCoins(sdk.NewInt64Coin("tiov", 10))},
+					{Domain: "test", Name: "bob", Sponsor: keeper.CharlieKey, Balance: sdk.NewCoins(sdk.NewInt64Coin("tiov", 10))},
+				},
+			},
+		},
+		"sponsorship of account in closed domain": {
+			Genesis: GenesisState{
+				Domains:      []types.Domain{closedDomain},
+				Accounts:     []types.Account{closedEmptyAccount, {Domain: "closed", Name: utils.StrPtr("bob"), Owner: keeper.BobKey, ValidUntil: 100}},
+				Sponsorships: []types.Sponsorship{{Domain: "closed", Name: "bob", Sponsor: keeper.CharlieKey, Balance: sdk.NewCoins(sdk.NewInt64Coin("tiov", 10))}},
+			},
+			Err: types.ErrInvalidSponsorship,
+		},
+		"sponsorship declared twice": {
+			Genesis: GenesisState{
+				Domains:  []types.Domain{domain},
+				Accounts: []types.Account{emptyAccount},
+				Sponsorships: []types.Sponsorship{
+					{Domain: "test", Sponsor: keeper.CharlieKey, Balance: sdk.NewCoins(sdk.NewInt64Coin("tiov", 10))},
+					{Domain: "test", Sponsor: keeper.CharlieKey, Balance: sdk.NewCoins(sdk.NewInt64Coin("tiov", 5))},
+				},
+			},
+			Err: types.ErrInvalidSponsorship,
+		},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			var err error
+			if c.WithConfig {
+				err = ValidateGenesisWithConfig(c.Genesis, conf)
+			} else {
+				err = ValidateGenesis(c.Genesis)
+			}
+			if !errors.Is(err, c.Err) {
+				t.Fatalf("expected error: %v, got: %v", c.Err, err)
+			}
+		})
+	}
+}
+
+func TestGenesisErrors(t *testing.T) {
+	genesis := NewGenesisState(
+		[]types.Domain{{Name: "test", Admin: keeper.AliceKey, Type: types.OpenDomain}},
+		[]types.Account{
+			{Domain: "none", Name: utils.StrPtr("bob"), Owner: keeper.BobKey},
+			{Domain: "test", Name: utils.StrPtr("bob"), Owner: keeper.BobKey},
+		},
+	)
+	// orphan account and missing empty account are both reported
+	if errs := GenesisErrors(genesis, nil); len(errs) != 2 {
+		t.Fatalf("expected 2 errors, got: %v", errs)
+	}
+}
+
+func TestTransferOffersGenesis(t *testing.T) {
+	k, ctx, _ := keeper.NewTestKeeper(t, true)
+	offer := types.TransferOffer{ID: 4, Domain: "test", Owner: keeper.AliceKey, Recipient: keeper.BobKey, Expiry: 100}
+	InitGenesis(ctx, k, GenesisState{
+		Domains:             []types.Domain{{Name: "test", Admin: keeper.AliceKey, ValidUntil: 100, Type: types.OpenDomain}},
+		Accounts:            []types.Account{{Domain: "test", Name: utils.StrPtr(""), Owner: keeper.AliceKey, ValidUntil: 100}},
+		TransferOffers:      []types.TransferOffer{offer},
+		LastTransferOfferID: 5,
+	})
+	exported := ExportGenesis(ctx, k)
+	if exported.LastTransferOfferID != 5 {
+		t.Fatalf("unexpected last transfer offer id: %d", exported.LastTransferOfferID)
+	}
+	if len(exported.TransferOffers) != 1 || exported.TransferOffers[0].ID != offer.ID {
+		t.Fatalf("unexpected transfer offers: %+v", exported.TransferOffers)
+	}
+	// the expiry of imported offers is queued
+	if expired := k.DeleteExpiredTransferOffers(ctx.WithBlockTime(time.Unix(100, 0))); len(expired) != 1 {
+		t.Fatalf("expected 1 expired transfer offer, got: %d", len(expired))
+	}
+}
+
+func TestHistoryGenesis(t *testing.T) {
+	k, ctx, _ := keeper.NewTestKeeper(t, true)
+	keeper.GetConfigSetter(k.ConfigurationKeeper).SetConfig(ctx, configuration.Config{HistoryRetention: time.Hour})
+	record := types.HistoryRecord{ID: 3, Starname: "test", Domain: "test", Height: 1, Time: 100, Action: types.HistoryDelete, Owner: keeper.AliceKey}
+	state := GenesisState{
+		History:       []types.HistoryRecord{record},
+		LastHistoryID: 3,
+	}
+	if err := ValidateGenesis(state); err != nil {
+		t.Fatalf("ValidateGenesis() got error: %s", err)
+	}
+	InitGenesis(ctx, k, state)
+	exported := ExportGenesis(ctx, k)
+	if exported.LastHistoryID != 3 {
+		t.Fatalf("unexpected last history id: %d", exported.LastHistoryID)
+	}
+	if len(exported.History) != 1 || exported.History[0].ID != record.ID {
+		t.Fatalf("unexpected history: %+v", exported.History)
+	}
+	// the imported sequence is used by the next records
+	k.RecordHistory(ctx, types.NewDomainHistoryRecord(types.HistoryCreate, types.Domain{Name: "test", Admin: keeper.BobKey}))
+	if id := k.GetHistorySequence(ctx); id != 4 {
+		t.Fatalf("unexpected history sequence: %d", id)
+	}
+	// records out of the sequence range are rejected
+	state.LastHistoryID = 2
+	if err := ValidateGenesis(state); !errors.Is(err, types.ErrInvalidHistoryRecord) {
+		t.Fatalf("ValidateGenesis() expected error: %s, got: %s", types.ErrInvalidHistoryRecord, err)
+	}
+}
@@ -13,6 +13,10 @@ import (
 func NewHandler(k Keeper) sdk.Handler {
 	f := func(ctx sdk.Context, msg sdk.Msg) (*sdk.Result, error) {
 		ctx = ctx.WithEventManager(sdk.NewEventManager())
+		// names are handled in their canonical form
+		if msg, ok := msg.(types.MsgWithStarname); ok {
+			msg.Canonicalize()
+		}
 		switch msg := msg.(type) {
 		// domain handlers
 		case *types.MsgRegisterDomain:
@@ -23,11 +27,15 @@ func NewHandler(k Keeper) sdk.Handler {
 			return handlerMsgDeleteDomain(ctx, k, msg)
 		case *types.MsgTransferDomain:
 			return handlerMsgTransferDomain(ctx, k, msg)
+		case *types.MsgSetRegistrationPolicy:
+			return handlerMsgSetRegistrationPolicy(ctx, k, msg)
 		// account handlers
 		case *types.MsgRegisterAccount:
 			return handleMsgRegisterAccount(ctx, k, msg)
 		case *types.MsgRenewAccount:
 			return handlerMsgRenewAccount(ctx, k, msg)
+		case *types.MsgSetAccountValidUntil:
+			return handlerMsgSetAccountValidUntil(ctx, k, msg)
 		case *types.MsgAddAccountCertificates:
 			return handlerMsgAddAccountCertificates(ctx, k, msg)
 		case *types.MsgDeleteAccountCertificate:
@@ -40,6 +48,29 @@ func NewHandler(k Keeper) sdk.Handler {
 			return handlerMsgTransferAccount(ctx, k, msg)
 		case *types.MsgReplaceAccountMetadata:
 			return handlerMsgReplaceAccountMetadata(ctx, k, msg)
+		// transfer offer handlers
+		case *types.MsgOfferDomainTransfer:
+			return handlerMsgOfferDomainTransfer(ctx, k, msg)
+		case *types.MsgOfferAccountTransfer:
+			return handlerMsgOfferAccountTransfer(ctx, k, msg)
+		case *types.MsgAcceptTransferOffer:
+			return handlerMsgAcceptTransferOffer(ctx, k, msg)
+		case *types.MsgRejectTransferOffer:
+			return handlerMsgRejectTransferOffer(ctx, k, msg)
+		case *types.MsgCancelTransferOffer:
+			return handlerMsgCancelTransferOffer(ctx, k, msg)
+		// sponsorship handlers
+		case *types.MsgDepositSponsorship:
+			return handlerMsgDepositSponsorship(ctx, k, msg)
+		case *types.MsgWithdrawSponsorship:
+			return handlerMsgWithdrawSponsorship(ctx, k, msg)
+		// bulk handlers
+		case *types.MsgTransferAccounts:
+			return handlerMsgTransferAccounts(ctx, k, msg)
+		case *types.MsgDeleteAccountsByOwner:
+			return handlerMsgDeleteAccountsByOwner(ctx, k, msg)
+		case *types.MsgSetAccountsResource:
+			return handlerMsgSetAccountsResource(ctx, k, msg)
 		default:
 			return nil, sdkerrors.Wrap(sdkerrors.ErrUnknownRequest, fmt.Sprintf("unregonized request: %T", msg))
 		}
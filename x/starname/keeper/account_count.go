@@ -0,0 +1,72 @@
+package keeper
+
+import (
+	"encoding/binary"
+
+	"github.com/cosmos/cosmos-sdk/store/prefix"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/iov-one/iovns/x/starname/types"
+)
+
+// AccountCountStorePrefix is the prefix of the store counting the accounts of each domain
+var AccountCountStorePrefix = []byte{0xC}
+
+// accountCountStore returns the store of the account counters, keyed by domain name
+func (k Keeper) accountCountStore(ctx sdk.Context) prefix.Store {
+	return prefix.NewStore(ctx.KVStore(k.StoreKey), AccountCountStorePrefix)
+}
+
+// GetAccountCount returns the number of accounts registered in the domain, the empty account excluded
+func (k Keeper) GetAccountCount(ctx sdk.Context, domain string) uint64 {
+	b := k.accountCountStore(ctx).Get([]byte(domain))
+	if b == nil {
+		return 0
+	}
+	return binary.BigEndian.Uint64(b)
+}
+
+// SetAccountCount sets the number of accounts registered in the domain, a zero count is removed
+func (k Keeper) SetAccountCount(ctx sdk.Context, domain string, count uint64) {
+	if count == 0 {
+		k.accountCountStore(ctx).Delete([]byte(domain))
+		return
+	}
+	k.accountCountStore(ctx).Set([]byte(domain), sdk.Uint64ToBigEndian(count))
+}
+
+// IncreaseAccountCount increments the number of accounts registered in the domain
+func (k Keeper) IncreaseAccountCount(ctx sdk.Context, domain string) {
+	k.SetAccountCount(ctx, domain, k.GetAccountCount(ctx, domain)+1)
+}
+
+// DecreaseAccountCount decrements the number of accounts registered in the domain
+func (k Keeper) DecreaseAccountCount(ctx sdk.Context, domain string) {
+	count := k.GetAccountCount(ctx, domain)
+	if count == 0 {
+		panic("cannot decrease the account count of domain " + domain + " below zero")
+	}
+	k.SetAccountCount(ctx, domain, count-1)
+}
+
+// IterateAccountCounts iterates over the account counters of the domains
+// until do returns false
+func (k Keeper) IterateAccountCounts(ctx sdk.Context, do func(domain string, count uint64) bool) {
+	it := k.accountCountStore(ctx).Iterator(nil, nil)
+	defer it.Close()
+	for ; it.Valid(); it.Next() {
+		if !do(string(it.Key()), binary.BigEndian.Uint64(it.Value())) {
+			return
+		}
+	}
+}
+
+// countAccounts returns the number of accounts registered in each domain, the empty accounts excluded
+func (k Keeper) countAccounts(ctx sdk.Context) map[string]uint64 {
+	counts := make(map[string]uint64)
+	k.iterateAccounts(ctx, func(account types.Account) {
+		if *account.Name != types.EmptyAccountName {
+			counts[account.Domain]++
+		}
+	})
+	return counts
+}
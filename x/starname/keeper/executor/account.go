@@ -32,6 +32,7 @@ func (a *Account) Transfer(newOwner sdk.AccAddress, reset bool) {
 	}
 	// apply account changes
 	// update owner
+	oldOwner := a.account.Owner
 	a.account.Owner = newOwner
 	// if reset is required then clear the account
 	if reset {
@@ -41,6 +42,10 @@ func (a *Account) Transfer(newOwner sdk.AccAddress, reset bool) {
 	}
 	// apply changes
 	a.store.Update(a.account)
+	a.k.RecordHistory(a.ctx, types.NewAccountHistoryRecord(types.HistoryTransfer, *a.account))
+	// pending offers were made by the old owner
+	a.k.DeleteStarnameTransferOffers(a.ctx, types.AccountStarname(a.account.Domain, *a.account.Name))
+	a.k.AfterAccountTransferred(a.ctx, *a.account, oldOwner)
 }
 
 // UpdateMetadata updates account's metadata
@@ -50,6 +55,8 @@ func (a *Account) UpdateMetadata(newMetadata string) {
 	}
 	a.account.MetadataURI = newMetadata
 	a.store.Update(a.account)
+	a.k.RecordHistory(a.ctx, types.NewAccountHistoryRecord(types.HistoryUpdateMetadata, *a.account))
+	a.k.AfterAccountUpdated(a.ctx, *a.account)
 }
 
 // ReplaceResources replaces account's resources
@@ -59,6 +66,8 @@ func (a *Account) ReplaceResources(newTargets []types.Resource) {
 	}
 	a.account.Resources = newTargets
 	a.store.Update(a.account)
+	a.k.RecordHistory(a.ctx, types.NewAccountHistoryRecord(types.HistoryReplaceResources, *a.account))
+	a.k.AfterAccountUpdated(a.ctx, *a.account)
 }
 
 // Renew renews an account
@@ -72,6 +81,20 @@ func (a *Account) Renew() {
 	)
 	// update account in kv store
 	a.store.Update(a.account)
+	a.k.RecordHistory(a.ctx, types.NewAccountHistoryRecord(types.HistoryRenew, *a.account))
+	a.k.AfterAccountRenewed(a.ctx, *a.account)
+}
+
+// SetValidUntil sets the expiration of an account
+func (a *Account) SetValidUntil(validUntil int64) {
+	if a.account == nil {
+		panic("cannot set the expiration of a non specified account")
+	}
+	a.account.ValidUntil = validUntil
+	// update account in kv store
+	a.store.Update(a.account)
+	a.k.RecordHistory(a.ctx, types.NewAccountHistoryRecord(types.HistorySetValidUntil, *a.account))
+	a.k.AfterAccountValidUntilSet(a.ctx, *a.account)
 }
 
 // Create creates an account
@@ -80,6 +103,11 @@ func (a *Account) Create() {
 		panic("cannot create a non specified account")
 	}
 	a.store.Create(a.account)
+	if *a.account.Name != types.EmptyAccountName {
+		a.k.IncreaseAccountCount(a.ctx, a.account.Domain)
+	}
+	a.k.RecordHistory(a.ctx, types.NewAccountHistoryRecord(types.HistoryCreate, *a.account))
+	a.k.AfterAccountCreated(a.ctx, *a.account)
 }
 
 // Delete deletes the account
@@ -87,7 +115,13 @@ func (a *Account) Delete() {
 	if a.account == nil {
 		panic("cannot delete a non specified account")
 	}
+	a.k.BeforeAccountDeleted(a.ctx, *a.account)
 	a.store.Delete(a.account.PrimaryKey())
+	if *a.account.Name != types.EmptyAccountName {
+		a.k.DecreaseAccountCount(a.ctx, a.account.Domain)
+	}
+	a.k.RecordHistory(a.ctx, types.NewAccountHistoryRecord(types.HistoryDelete, *a.account))
+	a.k.DeleteStarnameTransferOffers(a.ctx, types.AccountStarname(a.account.Domain, *a.account.Name))
 }
 
 // DeleteCertificate deletes the certificate of the account at the provided index
@@ -97,6 +131,7 @@ func (a *Account) DeleteCertificate(index int) {
 	}
 	a.account.Certificates = append(a.account.Certificates[:index], a.account.Certificates[index+1:]...)
 	a.store.Update(a.account)
+	a.k.AfterAccountUpdated(a.ctx, *a.account)
 }
 
 // AddCertificate adds a certificate to the account
@@ -106,6 +141,7 @@ func (a *Account) AddCertificate(cert []byte) {
 	}
 	a.account.Certificates = append(a.account.Certificates, cert)
 	a.store.Update(a.account)
+	a.k.AfterAccountUpdated(a.ctx, *a.account)
 }
 
 // State returns the current state of the account
@@ -1,10 +1,15 @@
 package executor
 
 import (
-	"github.com/iov-one/iovns/x/starname/keeper"
-	"github.com/iov-one/iovns/x/starname/types"
 	"reflect"
 	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/iov-one/iovns/pkg/utils"
+	"github.com/iov-one/iovns/x/configuration"
+	"github.com/iov-one/iovns/x/starname/keeper"
+	"github.com/iov-one/iovns/x/starname/types"
 )
 
 func TestAccount_AddCertificate(t *testing.T) {
@@ -30,6 +35,9 @@ func TestAccount_Create(t *testing.T) {
 	if !reflect.DeepEqual(*got, acc) {
 		t.Fatal("unexpected result")
 	}
+	if count := testKeeper.GetAccountCount(testCtx, acc.Domain); count != 1 {
+		t.Fatalf("unexpected account count: %d", count)
+	}
 }
 
 func TestAccount_DeleteCertificate(t *testing.T) {
@@ -134,4 +142,72 @@ func TestAccount_Delete(t *testing.T) {
 	if found {
 		t.Fatal("account was not deleted")
 	}
+	if count := testKeeper.GetAccountCount(testCtx, testAccount.Domain); count != 0 {
+		t.Fatalf("unexpected account count: %d", count)
+	}
+}
+
+func TestAccount_History(t *testing.T) {
+	// a keeper of its own, so that the history holds the records of this test only
+	k, ctx, _ := keeper.NewTestKeeper(t, false)
+	keeper.GetConfigSetter(k.ConfigurationKeeper).SetConfig(ctx, configuration.Config{AccountRenewalPeriod: time.Hour, HistoryRetention: time.Hour})
+	now := time.Unix(1600000000, 0)
+	ctx = ctx.WithBlockHeight(7).WithBlockTime(now)
+	account := types.Account{
+		Domain:      "test",
+		Name:        utils.StrPtr("alice"),
+		Owner:       aliceKey,
+		ValidUntil:  now.Add(time.Hour).Unix(),
+		Resources:   []types.Resource{{URI: "uri", Resource: "resource"}},
+		MetadataURI: "metadata",
+	}
+	resources := []types.Resource{{URI: "new uri", Resource: "new resource"}}
+	ex := NewAccount(ctx, k, account)
+	ex.Create()
+	ex.Transfer(bobKey, true)
+	ex.UpdateMetadata("new metadata")
+	ex.ReplaceResources(resources)
+	ex.Renew()
+	ex.SetValidUntil(now.Add(time.Minute).Unix())
+	ex.Delete()
+
+	record := func(id uint64, action types.HistoryAction, owner sdk.AccAddress, validUntil int64, resources []types.Resource, metadata string) types.HistoryRecord {
+		return types.HistoryRecord{
+			ID:          id,
+			Starname:    "alice*test",
+			Domain:      "test",
+			Height:      7,
+			Time:        now.Unix(),
+			Action:      action,
+			Owner:       owner,
+			ValidUntil:  validUntil,
+			Resources:   resources,
+			MetadataURI: metadata,
+		}
+	}
+	want := []types.HistoryRecord{
+		record(1, types.HistoryCreate, aliceKey, now.Add(time.Hour).Unix(), account.Resources, "metadata"),
+		// the transfer resets the account
+		record(2, types.HistoryTransfer, bobKey, now.Add(time.Hour).Unix(), nil, ""),
+		record(3, types.HistoryUpdateMetadata, bobKey, now.Add(time.Hour).Unix(), nil, "new metadata"),
+		record(4, types.HistoryReplaceResources, bobKey, now.Add(time.Hour).Unix(), resources, "new metadata"),
+		record(5, types.HistoryRenew, bobKey, now.Add(2*time.Hour).Unix(), resources, "new metadata"),
+		record(6, types.HistorySetValidUntil, bobKey, now.Add(time.Minute).Unix(), resources, "new metadata"),
+		record(7, types.HistoryDelete, bobKey, now.Add(time.Minute).Unix(), resources, "new metadata"),
+	}
+	filter := k.HistoryStore(ctx).Filter(&types.HistoryRecord{Starname: "alice*test"})
+	var got []types.HistoryRecord
+	for ; filter.Valid(); filter.Next() {
+		record := new(types.HistoryRecord)
+		filter.Read(record)
+		got = append(got, *record)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("want %d records, got %d: %+v", len(want), len(got), got)
+	}
+	for i := range want {
+		if !reflect.DeepEqual(got[i], want[i]) {
+			t.Fatalf("record %d:\nwant: %+v\ngot:  %+v", i, want[i], got[i])
+		}
+	}
 }
@@ -37,6 +37,8 @@ func (d *Domain) Renew(accValidUntil ...int64) {
 	if len(accValidUntil) != 0 {
 		d.domain.ValidUntil = accValidUntil[0]
 		d.domains.Update(d.domain)
+		d.k.RecordHistory(d.ctx, types.NewDomainHistoryRecord(types.HistoryRenew, *d.domain))
+		d.k.AfterDomainRenewed(d.ctx, *d.domain)
 		return
 	}
 	// get configuration
@@ -47,12 +49,16 @@ func (d *Domain) Renew(accValidUntil ...int64) {
 	)
 	// set domain
 	d.domains.Update(d.domain)
+	d.k.RecordHistory(d.ctx, types.NewDomainHistoryRecord(types.HistoryRenew, *d.domain))
+	d.k.AfterDomainRenewed(d.ctx, *d.domain)
 	// update empty account
 	account := new(types.Account)
 	fltr := d.accounts.Filter(&types.Account{Domain: d.domain.Name, Name: utils.StrPtr(types.EmptyAccountName)})
 	fltr.Read(account)
 	account.ValidUntil = d.domain.ValidUntil
 	fltr.Update(account)
+	d.k.RecordHistory(d.ctx, types.NewAccountHistoryRecord(types.HistoryRenew, *account))
+	d.k.AfterAccountRenewed(d.ctx, *account)
 }
 
 // Delete deletes a domain from the kvstore
@@ -60,11 +66,21 @@ func (d *Domain) Delete() {
 	if d.domain == nil {
 		panic("cannot execute delete state change on non present domain")
 	}
+	d.k.BeforeDomainDeleted(d.ctx, *d.domain)
 	filter := d.accounts.Filter(&types.Account{Domain: d.domain.Name})
 	for ; filter.Valid(); filter.Next() {
+		acc := new(types.Account)
+		filter.Read(acc)
+		d.k.BeforeAccountDeleted(d.ctx, *acc)
 		filter.Delete()
+		d.k.RecordHistory(d.ctx, types.NewAccountHistoryRecord(types.HistoryDelete, *acc))
 	}
+	d.k.SetAccountCount(d.ctx, d.domain.Name, 0)
 	d.domains.Delete(d.domain.PrimaryKey())
+	d.k.RecordHistory(d.ctx, types.NewDomainHistoryRecord(types.HistoryDelete, *d.domain))
+	d.deleteRegistrationPolicy()
+	d.k.DeleteUsedInviteCodes(d.ctx, d.domain.Name)
+	d.k.DeleteDomainTransferOffers(d.ctx, d.domain.Name)
 }
 
 // Transfer transfers a domain given a flag and an owner
@@ -77,6 +93,16 @@ func (d *Domain) Transfer(flag types.TransferFlag, newOwner sdk.AccAddress) {
 	var oldOwner = d.domain.Admin // cache it for future uses
 	d.domain.Admin = newOwner
 	d.domains.Update(d.domain)
+	d.k.RecordHistory(d.ctx, types.NewDomainHistoryRecord(types.HistoryTransfer, *d.domain))
+	d.k.AfterDomainTransferred(d.ctx, *d.domain, oldOwner)
+	// the registration policy and the pending offers were defined by the old admin,
+	// who is the one offering the accounts of closed domains
+	d.deleteRegistrationPolicy()
+	if d.domain.Type == types.ClosedDomain {
+		d.k.DeleteDomainTransferOffers(d.ctx, d.domain.Name)
+	} else {
+		d.k.DeleteStarnameTransferOffers(d.ctx, d.domain.Name)
+	}
 	// transfer empty account
 	filter := d.accounts.Filter(&types.Account{Domain: d.domain.Name, Name: utils.StrPtr(types.EmptyAccountName)})
 	emptyAccount := new(types.Account)
@@ -121,6 +147,7 @@ func (d *Domain) Create() {
 		panic("cannot create non specified domain")
 	}
 	d.domains.Create(d.domain)
+	d.k.RecordHistory(d.ctx, types.NewDomainHistoryRecord(types.HistoryCreate, *d.domain))
 	emptyAccount := &types.Account{
 		Domain:       d.domain.Name,
 		Name:         utils.StrPtr(types.EmptyAccountName),
@@ -132,4 +159,34 @@ func (d *Domain) Create() {
 		MetadataURI:  "",
 	}
 	d.accounts.Create(emptyAccount)
+	d.k.RecordHistory(d.ctx, types.NewAccountHistoryRecord(types.HistoryCreate, *emptyAccount))
+	d.k.AfterDomainCreated(d.ctx, *d.domain)
+	d.k.AfterAccountCreated(d.ctx, *emptyAccount)
+}
+
+// SetRegistrationPolicy sets the registration policy of the domain,
+// an empty policy removes the existing one
+func (d *Domain) SetRegistrationPolicy(policy types.RegistrationPolicy) {
+	if d.domain == nil {
+		panic("cannot set registration policy of non specified domain")
+	}
+	policy.Domain = d.domain.Name
+	if policy.Empty() {
+		d.deleteRegistrationPolicy()
+		return
+	}
+	d.k.SetRegistrationPolicy(d.ctx, policy)
+}
+
+// UseInviteCode records the usage of an invite code of the domain
+func (d *Domain) UseInviteCode(code string) {
+	if d.domain == nil {
+		panic("cannot use invite code of non specified domain")
+	}
+	d.k.SetInviteCodeUsed(d.ctx, d.domain.Name, keeper.InviteCodeHash(code))
+}
+
+// deleteRegistrationPolicy removes the registration policy of the domain, if any
+func (d *Domain) deleteRegistrationPolicy() {
+	d.k.DeleteRegistrationPolicy(d.ctx, d.domain.Name)
 }
@@ -163,4 +163,15 @@ func TestDomain_Delete(t *testing.T) {
 			t.Fatal("domain was not deleted")
 		}
 	})
+	t.Run("transfer offers are deleted", func(t *testing.T) {
+		testCtx, _ := testCtx.CacheContext()
+		domainOffer := testKeeper.CreateTransferOffer(testCtx, types.TransferOffer{Domain: testDomain.Name, Owner: testDomain.Admin, Recipient: aliceKey})
+		accountOffer := testKeeper.CreateTransferOffer(testCtx, types.TransferOffer{Domain: testDomain.Name, Name: testAccount.Name, Owner: testAccount.Owner, Recipient: bobKey})
+		NewDomain(testCtx, testKeeper, testDomain).Delete()
+		for _, offer := range []types.TransferOffer{domainOffer, accountOffer} {
+			if _, ok := testKeeper.GetTransferOffer(testCtx, offer.ID); ok {
+				t.Fatalf("transfer offer %d was not deleted", offer.ID)
+			}
+		}
+	})
 }
@@ -0,0 +1,169 @@
+package executor
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/iov-one/iovns/pkg/utils"
+	"github.com/iov-one/iovns/x/configuration"
+	"github.com/iov-one/iovns/x/starname/keeper"
+	"github.com/iov-one/iovns/x/starname/types"
+)
+
+// hooksRecorder records the hooks called as hook name and starname
+type hooksRecorder struct {
+	calls []string
+}
+
+func (h *hooksRecorder) record(hook, starname string) {
+	h.calls = append(h.calls, hook+" "+starname)
+}
+
+func (h *hooksRecorder) AfterDomainCreated(_ sdk.Context, domain types.Domain) {
+	h.record("AfterDomainCreated", domain.Name)
+}
+
+func (h *hooksRecorder) AfterDomainRenewed(_ sdk.Context, domain types.Domain) {
+	h.record("AfterDomainRenewed", domain.Name)
+}
+
+func (h *hooksRecorder) AfterDomainTransferred(_ sdk.Context, domain types.Domain, _ sdk.AccAddress) {
+	h.record("AfterDomainTransferred", domain.Name)
+}
+
+func (h *hooksRecorder) BeforeDomainDeleted(_ sdk.Context, domain types.Domain) {
+	h.record("BeforeDomainDeleted", domain.Name)
+}
+
+func (h *hooksRecorder) AfterAccountCreated(_ sdk.Context, account types.Account) {
+	h.record("AfterAccountCreated", types.AccountStarname(account.Domain, *account.Name))
+}
+
+func (h *hooksRecorder) AfterAccountRenewed(_ sdk.Context, account types.Account) {
+	h.record("AfterAccountRenewed", types.AccountStarname(account.Domain, *account.Name))
+}
+
+func (h *hooksRecorder) AfterAccountValidUntilSet(_ sdk.Context, account types.Account) {
+	h.record("AfterAccountValidUntilSet", types.AccountStarname(account.Domain, *account.Name))
+}
+
+func (h *hooksRecorder) AfterAccountTransferred(_ sdk.Context, account types.Account, _ sdk.AccAddress) {
+	h.record("AfterAccountTransferred", types.AccountStarname(account.Domain, *account.Name))
+}
+
+func (h *hooksRecorder) AfterAccountUpdated(_ sdk.Context, account types.Account) {
+	h.record("AfterAccountUpdated", types.AccountStarname(account.Domain, *account.Name))
+}
+
+func (h *hooksRecorder) BeforeAccountDeleted(_ sdk.Context, account types.Account) {
+	h.record("BeforeAccountDeleted", types.AccountStarname(account.Domain, *account.Name))
+}
+
+func TestHooks(t *testing.T) {
+	init := func() (keeper.Keeper, sdk.Context, *hooksRecorder) {
+		k, ctx, _ := keeper.NewTestKeeper(t, false)
+		keeper.GetConfigSetter(k.ConfigurationKeeper).SetConfig(ctx, configuration.Config{AccountRenewalPeriod: time.Second})
+		recorder := new(hooksRecorder)
+		k.SetHooks(types.NewMultiStarnameHooks(recorder))
+		domain := types.Domain{Name: "test", Admin: keeper.BobKey, Type: types.OpenDomain}
+		NewDomain(ctx, k, domain).Create()
+		NewAccount(ctx, k, types.Account{Domain: "test", Name: utils.StrPtr("1"), Owner: keeper.BobKey}).Create()
+		recorder.calls = nil
+		return k, ctx, recorder
+	}
+	domain := types.Domain{Name: "test", Admin: keeper.BobKey, Type: types.OpenDomain}
+	account := types.Account{Domain: "test", Name: utils.StrPtr("1"), Owner: keeper.BobKey}
+	cases := map[string]struct {
+		do    func(k keeper.Keeper, ctx sdk.Context)
+		calls []string
+	}{
+		"create domain": {
+			do: func(k keeper.Keeper, ctx sdk.Context) {
+				NewDomain(ctx, k, types.Domain{Name: "other", Admin: keeper.AliceKey}).Create()
+			},
+			calls: []string{"AfterDomainCreated other", "AfterAccountCreated *other"},
+		},
+		"renew domain": {
+			do: func(k keeper.Keeper, ctx sdk.Context) {
+				NewDomain(ctx, k, domain).Renew()
+			},
+			calls: []string{"AfterDomainRenewed test", "AfterAccountRenewed *test"},
+		},
+		"transfer domain owned accounts": {
+			do: func(k keeper.Keeper, ctx sdk.Context) {
+				NewDomain(ctx, k, domain).Transfer(types.TransferOwned, keeper.AliceKey)
+			},
+			calls: []string{"AfterDomainTransferred test", "AfterAccountTransferred *test", "AfterAccountTransferred 1*test"},
+		},
+		"transfer domain flushing accounts": {
+			do: func(k keeper.Keeper, ctx sdk.Context) {
+				NewDomain(ctx, k, domain).Transfer(types.TransferFlush, keeper.AliceKey)
+			},
+			calls: []string{
+				"AfterDomainTransferred test",
+				"AfterAccountTransferred *test",
+				"AfterAccountTransferred *test",
+				"BeforeAccountDeleted 1*test",
+			},
+		},
+		"delete domain": {
+			do: func(k keeper.Keeper, ctx sdk.Context) {
+				NewDomain(ctx, k, domain).Delete()
+			},
+			calls: []string{"BeforeDomainDeleted test", "BeforeAccountDeleted *test", "BeforeAccountDeleted 1*test"},
+		},
+		"update account": {
+			do: func(k keeper.Keeper, ctx sdk.Context) {
+				ex := NewAccount(ctx, k, account)
+				ex.ReplaceResources([]types.Resource{{URI: "uri", Resource: "res"}})
+				ex.UpdateMetadata("metadata")
+				ex.AddCertificate([]byte("cert"))
+				ex.DeleteCertificate(0)
+			},
+			calls: []string{
+				"AfterAccountUpdated 1*test",
+				"AfterAccountUpdated 1*test",
+				"AfterAccountUpdated 1*test",
+				"AfterAccountUpdated 1*test",
+			},
+		},
+		"renew, transfer and delete account": {
+			do: func(k keeper.Keeper, ctx sdk.Context) {
+				ex := NewAccount(ctx, k, account)
+				ex.Renew()
+				ex.Transfer(keeper.AliceKey, true)
+				ex.Delete()
+			},
+			calls: []string{"AfterAccountRenewed 1*test", "AfterAccountTransferred 1*test", "BeforeAccountDeleted 1*test"},
+		},
+		"set account valid until": {
+			do: func(k keeper.Keeper, ctx sdk.Context) {
+				// clearing the expiration is not a renewal
+				NewAccount(ctx, k, account).SetValidUntil(0)
+			},
+			calls: []string{"AfterAccountValidUntilSet 1*test"},
+		},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			k, ctx, recorder := init()
+			c.do(k, ctx)
+			if !reflect.DeepEqual(recorder.calls, c.calls) {
+				t.Fatalf("unexpected hooks calls\nwant: %v\ngot:  %v", c.calls, recorder.calls)
+			}
+		})
+	}
+}
+
+func TestKeeper_SetHooksTwice(t *testing.T) {
+	k, _, _ := keeper.NewTestKeeper(t, false)
+	k.SetHooks(types.NewMultiStarnameHooks())
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatal("setting hooks twice should panic")
+		}
+	}()
+	k.SetHooks(types.NewMultiStarnameHooks())
+}
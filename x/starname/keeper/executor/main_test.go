@@ -68,6 +68,7 @@ func newTest() error {
 	testCtx = sdk.NewContext(ms, tmtypes.Header{Time: time.Now()}, true, log.NewNopLogger())
 	testKeeper = keeper.NewKeeper(testCdc, testKey, mockConfig, nil, nil)
 	testKeeper.AccountStore(testCtx).Create(&testAccount)
+	testKeeper.SetAccountCount(testCtx, testAccount.Domain, 1)
 	testKeeper.DomainStore(testCtx).Create(&testDomain)
 	testKeeper.AccountStore(testCtx).Create(&types.Account{
 		Domain:      testDomain.Name,
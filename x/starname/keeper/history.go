@@ -0,0 +1,88 @@
+package keeper
+
+import (
+	"encoding/binary"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/iov-one/cosmos-sdk-crud/pkg/crud"
+	crudtypes "github.com/iov-one/cosmos-sdk-crud/pkg/crud/types"
+	"github.com/iov-one/iovns/x/starname/types"
+)
+
+// HistoryStorePrefix is the prefix of the history records store
+var HistoryStorePrefix = []byte{0x3}
+
+// HistorySequenceKey is the key used to store the last history record ID
+var HistorySequenceKey = []byte{0x4}
+
+// HistoryStore returns the crud.Store used to interact with history records
+func (k Keeper) HistoryStore(ctx sdk.Context) crud.Store {
+	return crud.NewStore(ctx, k.StoreKey, k.Cdc, HistoryStorePrefix)
+}
+
+// GetHistorySequence returns the last history record ID
+func (k Keeper) GetHistorySequence(ctx sdk.Context) uint64 {
+	b := ctx.KVStore(k.StoreKey).Get(HistorySequenceKey)
+	if b == nil {
+		return 0
+	}
+	return binary.BigEndian.Uint64(b)
+}
+
+// SetHistorySequence sets the last history record ID
+func (k Keeper) SetHistorySequence(ctx sdk.Context, id uint64) {
+	ctx.KVStore(k.StoreKey).Set(HistorySequenceKey, sdk.Uint64ToBigEndian(id))
+}
+
+// RecordHistory saves the provided record in the history index after assigning
+// it an ID and the current block height and time, records are saved only if
+// the history retention of the configuration is not zero
+func (k Keeper) RecordHistory(ctx sdk.Context, record types.HistoryRecord) {
+	if k.ConfigurationKeeper.GetConfiguration(ctx).HistoryRetention == 0 {
+		return
+	}
+	record.ID = k.GetHistorySequence(ctx) + 1
+	k.SetHistorySequence(ctx, record.ID)
+	record.Height = ctx.BlockHeight()
+	record.Time = ctx.BlockTime().Unix()
+	k.SetHistoryRecord(ctx, record)
+}
+
+// SetHistoryRecord saves the provided record with its ID, height and time
+func (k Keeper) SetHistoryRecord(ctx sdk.Context, record types.HistoryRecord) {
+	k.HistoryStore(ctx).Create(&record)
+}
+
+// IterateHistory calls do on each history record, from the oldest one
+func (k Keeper) IterateHistory(ctx sdk.Context, do func(record types.HistoryRecord) bool) {
+	store := k.HistoryStore(ctx)
+	store.IterateKeys(func(pk crudtypes.PrimaryKey) bool {
+		record := new(types.HistoryRecord)
+		store.Read(pk, record)
+		return do(*record)
+	})
+}
+
+// PruneHistory removes the history records older than the history retention
+// of the configuration and returns how many were removed, at most
+// types.HistoryPruneMax records are removed per call so the ones left
+// are removed by the next calls, it is called at the end of each block
+func (k Keeper) PruneHistory(ctx sdk.Context) int {
+	retention := k.ConfigurationKeeper.GetConfiguration(ctx).HistoryRetention
+	// when the history is disabled the records left are pruned too
+	before := ctx.BlockTime().Add(-retention).Unix()
+	var pruned []types.HistoryRecord
+	// record IDs follow the block time so the oldest records come first
+	k.IterateHistory(ctx, func(record types.HistoryRecord) bool {
+		if record.Time >= before {
+			return false
+		}
+		pruned = append(pruned, record)
+		return len(pruned) < types.HistoryPruneMax
+	})
+	store := k.HistoryStore(ctx)
+	for _, record := range pruned {
+		store.Delete(record.PrimaryKey())
+	}
+	return len(pruned)
+}
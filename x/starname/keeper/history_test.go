@@ -0,0 +1,63 @@
+package keeper
+
+import (
+	"testing"
+	"time"
+
+	"github.com/iov-one/iovns/x/configuration"
+	"github.com/iov-one/iovns/x/starname/types"
+)
+
+func TestKeeper_RecordHistory(t *testing.T) {
+	k, ctx, _ := NewTestKeeper(t, false)
+	record := types.NewDomainHistoryRecord(types.HistoryCreate, types.Domain{Name: "test", Admin: aliceAddr})
+	// the history is disabled without retention
+	k.RecordHistory(ctx, record)
+	if id := k.GetHistorySequence(ctx); id != 0 {
+		t.Fatalf("history recorded while disabled, last id: %d", id)
+	}
+	GetConfigSetter(k.ConfigurationKeeper).SetConfig(ctx, configuration.Config{HistoryRetention: time.Hour})
+	k.RecordHistory(ctx, record)
+	if id := k.GetHistorySequence(ctx); id != 1 {
+		t.Fatalf("unexpected last history id: %d", id)
+	}
+}
+
+func TestKeeper_PruneHistory(t *testing.T) {
+	k, ctx, _ := NewTestKeeper(t, false)
+	setRetention := func(retention time.Duration) {
+		GetConfigSetter(k.ConfigurationKeeper).SetConfig(ctx, configuration.Config{Configurer: aliceAddr, HistoryRetention: retention})
+	}
+	setRetention(time.Hour)
+	start := time.Unix(1600000000, 0)
+	// one record per minute
+	records := types.HistoryPruneMax + 60
+	for i := 0; i < records; i++ {
+		k.RecordHistory(ctx.WithBlockTime(start.Add(time.Duration(i)*time.Minute)), types.NewDomainHistoryRecord(types.HistoryRenew, types.Domain{Name: "test", Admin: aliceAddr}))
+	}
+	ids := func() []uint64 {
+		var ids []uint64
+		k.IterateHistory(ctx, func(record types.HistoryRecord) bool {
+			ids = append(ids, record.ID)
+			return true
+		})
+		return ids
+	}
+	now := start.Add(time.Duration(records) * time.Minute)
+	// the records older than an hour are pruned, at most HistoryPruneMax at once
+	if pruned := k.PruneHistory(ctx.WithBlockTime(now)); pruned != types.HistoryPruneMax {
+		t.Fatalf("unexpected number of pruned records: %d", pruned)
+	}
+	if pruned := k.PruneHistory(ctx.WithBlockTime(now)); pruned != 0 {
+		t.Fatalf("unexpected number of pruned records: %d", pruned)
+	}
+	if left := ids(); len(left) != 60 || left[0] != types.HistoryPruneMax+1 {
+		t.Fatalf("unexpected records left: %v", left)
+	}
+	// the records left are pruned once the history is disabled
+	setRetention(0)
+	k.PruneHistory(ctx.WithBlockTime(now))
+	if left := ids(); len(left) != 0 {
+		t.Fatalf("unexpected records left: %v", left)
+	}
+}
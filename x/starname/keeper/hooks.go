@@ -0,0 +1,84 @@
+package keeper
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/iov-one/iovns/x/starname/types"
+)
+
+// SetHooks sets the hooks called on domains and accounts state changes,
+// it panics if called twice, use types.MultiStarnameHooks to set many
+func (k *Keeper) SetHooks(hooks types.StarnameHooks) *Keeper {
+	if k.hooks != nil {
+		panic("cannot set starname hooks twice")
+	}
+	k.hooks = hooks
+	return k
+}
+
+// hooks wrap the calls to the registered hooks, if any, the ones changing
+// expiries also move the sponsored starnames in the sponsored renewals queue
+
+func (k Keeper) AfterDomainCreated(ctx sdk.Context, domain types.Domain) {
+	k.requeueSponsoredRenewal(ctx, domain.Name, domain.ValidUntil)
+	if k.hooks != nil {
+		k.hooks.AfterDomainCreated(ctx, domain)
+	}
+}
+
+func (k Keeper) AfterDomainRenewed(ctx sdk.Context, domain types.Domain) {
+	k.requeueSponsoredRenewal(ctx, domain.Name, domain.ValidUntil)
+	if k.hooks != nil {
+		k.hooks.AfterDomainRenewed(ctx, domain)
+	}
+}
+
+func (k Keeper) AfterDomainTransferred(ctx sdk.Context, domain types.Domain, oldAdmin sdk.AccAddress) {
+	if k.hooks != nil {
+		k.hooks.AfterDomainTransferred(ctx, domain, oldAdmin)
+	}
+}
+
+func (k Keeper) BeforeDomainDeleted(ctx sdk.Context, domain types.Domain) {
+	if k.hooks != nil {
+		k.hooks.BeforeDomainDeleted(ctx, domain)
+	}
+}
+
+func (k Keeper) AfterAccountCreated(ctx sdk.Context, account types.Account) {
+	k.requeueSponsoredRenewal(ctx, types.AccountStarname(account.Domain, *account.Name), account.ValidUntil)
+	if k.hooks != nil {
+		k.hooks.AfterAccountCreated(ctx, account)
+	}
+}
+
+func (k Keeper) AfterAccountRenewed(ctx sdk.Context, account types.Account) {
+	k.requeueSponsoredRenewal(ctx, types.AccountStarname(account.Domain, *account.Name), account.ValidUntil)
+	if k.hooks != nil {
+		k.hooks.AfterAccountRenewed(ctx, account)
+	}
+}
+
+func (k Keeper) AfterAccountValidUntilSet(ctx sdk.Context, account types.Account) {
+	k.requeueSponsoredRenewal(ctx, types.AccountStarname(account.Domain, *account.Name), account.ValidUntil)
+	if k.hooks != nil {
+		k.hooks.AfterAccountValidUntilSet(ctx, account)
+	}
+}
+
+func (k Keeper) AfterAccountTransferred(ctx sdk.Context, account types.Account, oldOwner sdk.AccAddress) {
+	if k.hooks != nil {
+		k.hooks.AfterAccountTransferred(ctx, account, oldOwner)
+	}
+}
+
+func (k Keeper) AfterAccountUpdated(ctx sdk.Context, account types.Account) {
+	if k.hooks != nil {
+		k.hooks.AfterAccountUpdated(ctx, account)
+	}
+}
+
+func (k Keeper) BeforeAccountDeleted(ctx sdk.Context, account types.Account) {
+	if k.hooks != nil {
+		k.hooks.BeforeAccountDeleted(ctx, account)
+	}
+}
@@ -0,0 +1,287 @@
+package keeper
+
+import (
+	"fmt"
+	"sort"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	crud "github.com/iov-one/cosmos-sdk-crud/pkg/crud/types"
+	"github.com/iov-one/iovns/x/starname/types"
+)
+
+// RegisterInvariants registers the starname module invariants
+func RegisterInvariants(ir sdk.InvariantRegistry, k Keeper) {
+	ir.RegisterRoute(types.ModuleName, "account-domain", AccountDomainInvariant(k))
+	ir.RegisterRoute(types.ModuleName, "domain-empty-account", DomainEmptyAccountInvariant(k))
+	ir.RegisterRoute(types.ModuleName, "closed-domain-account-expiration", ClosedDomainAccountExpirationInvariant(k))
+	ir.RegisterRoute(types.ModuleName, "secondary-indexes", SecondaryIndexesInvariant(k))
+	ir.RegisterRoute(types.ModuleName, "configuration-limits", ConfigurationLimitsInvariant(k))
+	ir.RegisterRoute(types.ModuleName, "transfer-offer-owner", TransferOfferOwnerInvariant(k))
+	ir.RegisterRoute(types.ModuleName, "account-count", AccountCountInvariant(k))
+}
+
+// AllInvariants runs all the invariants of the starname module
+func AllInvariants(k Keeper) sdk.Invariant {
+	return func(ctx sdk.Context) (string, bool) {
+		var msg string
+		var broken bool
+		for _, inv := range []sdk.Invariant{
+			AccountDomainInvariant(k),
+			DomainEmptyAccountInvariant(k),
+			ClosedDomainAccountExpirationInvariant(k),
+			SecondaryIndexesInvariant(k),
+			ConfigurationLimitsInvariant(k),
+			TransferOfferOwnerInvariant(k),
+			AccountCountInvariant(k),
+		} {
+			res, stop := inv(ctx)
+			msg += res
+			broken = broken || stop
+		}
+		return msg, broken
+	}
+}
+
+// AccountDomainInvariant checks that every account references an existing domain
+func AccountDomainInvariant(k Keeper) sdk.Invariant {
+	return func(ctx sdk.Context) (string, bool) {
+		var msg string
+		var count int
+		ds := k.DomainStore(ctx)
+		k.iterateAccounts(ctx, func(account types.Account) {
+			if !ds.Read((&types.Domain{Name: account.Domain}).PrimaryKey(), new(types.Domain)) {
+				count++
+				msg += fmt.Sprintf("\taccount %s references missing domain %s\n", types.AccountStarname(account.Domain, *account.Name), account.Domain)
+			}
+		})
+		return sdk.FormatInvariant(types.ModuleName, "account-domain",
+			fmt.Sprintf("amount of accounts without domain found %d\n%s", count, msg)), count != 0
+	}
+}
+
+// DomainEmptyAccountInvariant checks that every domain has its empty account
+func DomainEmptyAccountInvariant(k Keeper) sdk.Invariant {
+	return func(ctx sdk.Context) (string, bool) {
+		var msg string
+		var count int
+		as := k.AccountStore(ctx)
+		k.iterateDomains(ctx, func(domain types.Domain) {
+			empty := ""
+			if !as.Read((&types.Account{Domain: domain.Name, Name: &empty}).PrimaryKey(), new(types.Account)) {
+				count++
+				msg += fmt.Sprintf("\tdomain %s has no empty account\n", domain.Name)
+			}
+		})
+		return sdk.FormatInvariant(types.ModuleName, "domain-empty-account",
+			fmt.Sprintf("amount of domains without empty account found %d\n%s", count, msg)), count != 0
+	}
+}
+
+// ClosedDomainAccountExpirationInvariant checks that accounts in closed domains do not
+// outlive their domain, accounts set to types.MaxValidUntil follow the domain expiration
+func ClosedDomainAccountExpirationInvariant(k Keeper) sdk.Invariant {
+	return func(ctx sdk.Context) (string, bool) {
+		var msg string
+		var count int
+		as := k.AccountStore(ctx)
+		k.iterateDomains(ctx, func(domain types.Domain) {
+			if domain.Type != types.ClosedDomain {
+				return
+			}
+			filter := as.Filter(&types.Account{Domain: domain.Name})
+			for ; filter.Valid(); filter.Next() {
+				account := new(types.Account)
+				if !safeRead(filter, account) {
+					continue
+				}
+				if account.ValidUntil == types.MaxValidUntil || account.ValidUntil <= domain.ValidUntil {
+					continue
+				}
+				count++
+				msg += fmt.Sprintf("\taccount %s expires at %d after its closed domain expiration %d\n",
+					types.AccountStarname(account.Domain, *account.Name), account.ValidUntil, domain.ValidUntil)
+			}
+		})
+		return sdk.FormatInvariant(types.ModuleName, "closed-domain-account-expiration",
+			fmt.Sprintf("amount of closed domain accounts outliving their domain found %d\n%s", count, msg)), count != 0
+	}
+}
+
+// SecondaryIndexesInvariant checks that the secondary indexes of domains and accounts match
+// the primary records: every object is indexed by each of its secondary keys and every index
+// entry belongs to an existing object holding that secondary key, each keyspace is read once
+func SecondaryIndexesInvariant(k Keeper) sdk.Invariant {
+	return func(ctx sdk.Context) (string, bool) {
+		var msg string
+		var count int
+		// check compares the index entries of the store with the ones expected from its objects
+		check := func(storePrefix []byte, expected map[string]string) {
+			prefix := append(append([]byte{}, storePrefix...), CrudIndexPrefix...)
+			it := sdk.KVStorePrefixIterator(ctx.KVStore(k.StoreKey), prefix)
+			for ; it.Valid(); it.Next() {
+				key := string(it.Key()[len(prefix):])
+				if _, ok := expected[key]; !ok {
+					count++
+					msg += fmt.Sprintf("	index %x does not belong to an object holding it\n", key)
+					continue
+				}
+				delete(expected, key)
+			}
+			it.Close()
+			missing := make([]string, 0, len(expected))
+			for key := range expected {
+				missing = append(missing, key)
+			}
+			sort.Strings(missing)
+			for _, key := range missing {
+				count++
+				msg += fmt.Sprintf("	%s is not indexed by %x\n", expected[key], key)
+			}
+		}
+		domains := make(map[string]string)
+		k.iterateDomains(ctx, func(domain types.Domain) {
+			addIndexKeys(domains, &domain, fmt.Sprintf("domain %s", domain.Name))
+		})
+		check(DomainStorePrefix, domains)
+		accounts := make(map[string]string)
+		k.iterateAccounts(ctx, func(account types.Account) {
+			addIndexKeys(accounts, &account, fmt.Sprintf("account %s", types.AccountStarname(account.Domain, *account.Name)))
+		})
+		check(AccountStorePrefix, accounts)
+		return sdk.FormatInvariant(types.ModuleName, "secondary-indexes",
+			fmt.Sprintf("amount of index inconsistencies found %d\n%s", count, msg)), count != 0
+	}
+}
+
+// ConfigurationLimitsInvariant checks that the resources and certificates
+// of every account respect the limits defined in the configuration
+func ConfigurationLimitsInvariant(k Keeper) sdk.Invariant {
+	return func(ctx sdk.Context) (string, bool) {
+		var msg string
+		var count int
+		conf := k.ConfigurationKeeper.GetConfiguration(ctx)
+		k.iterateAccounts(ctx, func(account types.Account) {
+			starname := types.AccountStarname(account.Domain, *account.Name)
+			if uint32(len(account.Resources)) > conf.ResourcesMax {
+				count++
+				msg += fmt.Sprintf("\taccount %s has %d resources, limit: %d\n", starname, len(account.Resources), conf.ResourcesMax)
+			}
+			if uint32(len(account.Certificates)) > conf.CertificateCountMax {
+				count++
+				msg += fmt.Sprintf("\taccount %s has %d certificates, limit: %d\n", starname, len(account.Certificates), conf.CertificateCountMax)
+			}
+			for i, cert := range account.Certificates {
+				if uint64(len(cert)) > conf.CertificateSizeMax {
+					count++
+					msg += fmt.Sprintf("\taccount %s certificate %d has size %d, limit: %d\n", starname, i, len(cert), conf.CertificateSizeMax)
+				}
+			}
+		})
+		return sdk.FormatInvariant(types.ModuleName, "configuration-limits",
+			fmt.Sprintf("amount of configuration limits violations found %d\n%s", count, msg)), count != 0
+	}
+}
+
+// TransferOfferOwnerInvariant checks that every pending transfer offer refers to an existing
+// domain or account transferable by the offer owner, stale offers must be removed on transfers and deletions
+func TransferOfferOwnerInvariant(k Keeper) sdk.Invariant {
+	return func(ctx sdk.Context) (string, bool) {
+		var msg string
+		var count int
+		ds := k.DomainStore(ctx)
+		as := k.AccountStore(ctx)
+		k.IterateTransferOffers(ctx, func(offer types.TransferOffer) bool {
+			var owner sdk.AccAddress
+			domain := new(types.Domain)
+			if ds.Read((&types.Domain{Name: offer.Domain}).PrimaryKey(), domain) {
+				owner = domain.Admin
+			}
+			if !offer.IsDomainOffer() {
+				account := new(types.Account)
+				switch {
+				case !as.Read((&types.Account{Domain: offer.Domain, Name: offer.Name}).PrimaryKey(), account):
+					owner = nil
+				// accounts of closed domains are offered by the domain admin
+				case domain.Type != types.ClosedDomain:
+					owner = account.Owner
+				}
+			}
+			if owner.Empty() || !owner.Equals(offer.Owner) {
+				count++
+				msg += fmt.Sprintf("\ttransfer offer %d of %s is not made by its current owner\n", offer.ID, offer.Starname())
+			}
+			return true
+		})
+		return sdk.FormatInvariant(types.ModuleName, "transfer-offer-owner",
+			fmt.Sprintf("amount of stale transfer offers found %d\n%s", count, msg)), count != 0
+	}
+}
+
+// AccountCountInvariant checks that the account counters of the domains, used to compute
+// the renewal fee of closed domains, match the number of accounts registered in them
+func AccountCountInvariant(k Keeper) sdk.Invariant {
+	return func(ctx sdk.Context) (string, bool) {
+		var msg string
+		var count int
+		expected := k.countAccounts(ctx)
+		k.IterateAccountCounts(ctx, func(domain string, n uint64) bool {
+			if _, ok := expected[domain]; !ok {
+				count++
+				msg += fmt.Sprintf("\tdomain %s counts %d accounts, expected 0\n", domain, n)
+			}
+			return true
+		})
+		k.iterateDomains(ctx, func(domain types.Domain) {
+			if n := k.GetAccountCount(ctx, domain.Name); n != expected[domain.Name] {
+				count++
+				msg += fmt.Sprintf("\tdomain %s counts %d accounts, expected %d\n", domain.Name, n, expected[domain.Name])
+			}
+		})
+		return sdk.FormatInvariant(types.ModuleName, "account-count",
+			fmt.Sprintf("amount of wrong account counters found %d\n%s", count, msg)), count != 0
+	}
+}
+
+// iterateDomains calls do on every domain in the store
+func (k Keeper) iterateDomains(ctx sdk.Context, do func(domain types.Domain)) {
+	ds := k.DomainStore(ctx)
+	ds.IterateKeys(func(pk crud.PrimaryKey) bool {
+		domain := new(types.Domain)
+		ds.Read(pk, domain)
+		do(*domain)
+		return true
+	})
+}
+
+// iterateAccounts calls do on every account in the store
+func (k Keeper) iterateAccounts(ctx sdk.Context, do func(account types.Account)) {
+	as := k.AccountStore(ctx)
+	as.IterateKeys(func(pk crud.PrimaryKey) bool {
+		account := new(types.Account)
+		as.Read(pk, account)
+		do(*account)
+		return true
+	})
+}
+
+// addIndexKeys adds to keys the index entries of the object, as saved by crud.Store
+// under the index prefix, mapped to the name of the object
+func addIndexKeys(keys map[string]string, o crud.Object, name string) {
+	pk := o.PrimaryKey().Key()
+	for _, sk := range o.SecondaryKeys() {
+		key := append([]byte{sk.Prefix()}, sk.Key()...)
+		keys[string(append(key, pk...))] = name
+	}
+}
+
+// safeRead reads the current object of the filter, it returns
+// false if the index points to an object which does not exist
+func safeRead(filter crud.Filter, o crud.Object) (ok bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			ok = false
+		}
+	}()
+	filter.Read(o)
+	return true
+}
@@ -0,0 +1,102 @@
+package keeper
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/iov-one/iovns/pkg/utils"
+	"github.com/iov-one/iovns/x/configuration"
+	"github.com/iov-one/iovns/x/starname/types"
+)
+
+func TestInvariants(t *testing.T) {
+	// setup creates a closed domain with its empty account and an account
+	setup := func(t *testing.T) (Keeper, sdk.Context) {
+		k, ctx, _ := NewTestKeeper(t, false)
+		k.ConfigurationKeeper.(configuration.Keeper).SetConfig(ctx, configuration.Config{
+			ResourcesMax:        1,
+			CertificateCountMax: 1,
+			CertificateSizeMax:  4,
+		})
+		k.DomainStore(ctx).Create(&types.Domain{Name: "test", Admin: aliceAddr, ValidUntil: 100, Type: types.ClosedDomain})
+		as := k.AccountStore(ctx)
+		as.Create(&types.Account{Domain: "test", Name: utils.StrPtr(""), Owner: aliceAddr, ValidUntil: 100})
+		as.Create(&types.Account{
+			Domain:     "test",
+			Name:       utils.StrPtr("bob"),
+			Owner:      bobAddr,
+			ValidUntil: types.MaxValidUntil,
+			Resources:  []types.Resource{{URI: "uri", Resource: "res"}},
+		})
+		k.SetAccountCount(ctx, "test", 1)
+		// accounts of closed domains are offered by the domain admin
+		k.CreateTransferOffer(ctx, types.TransferOffer{Domain: "test", Name: utils.StrPtr("bob"), Owner: aliceAddr, Recipient: bobAddr})
+		return k, ctx
+	}
+	cases := map[string]struct {
+		Invariant  func(k Keeper) sdk.Invariant
+		BreakState func(t *testing.T, ctx sdk.Context, k Keeper)
+	}{
+		"account domain": {
+			Invariant: AccountDomainInvariant,
+			BreakState: func(t *testing.T, ctx sdk.Context, k Keeper) {
+				k.AccountStore(ctx).Create(&types.Account{Domain: "none", Name: utils.StrPtr("bob"), Owner: bobAddr})
+			},
+		},
+		"domain empty account": {
+			Invariant: DomainEmptyAccountInvariant,
+			BreakState: func(t *testing.T, ctx sdk.Context, k Keeper) {
+				k.AccountStore(ctx).Delete((&types.Account{Domain: "test", Name: utils.StrPtr("")}).PrimaryKey())
+			},
+		},
+		"closed domain account expiration": {
+			Invariant: ClosedDomainAccountExpirationInvariant,
+			BreakState: func(t *testing.T, ctx sdk.Context, k Keeper) {
+				k.AccountStore(ctx).Update(&types.Account{Domain: "test", Name: utils.StrPtr("bob"), Owner: bobAddr, ValidUntil: 101})
+			},
+		},
+		"secondary indexes": {
+			Invariant: SecondaryIndexesInvariant,
+			BreakState: func(t *testing.T, ctx sdk.Context, k Keeper) {
+				// overwrite the raw account without updating its indexes
+				account := &types.Account{Domain: "test", Name: utils.StrPtr("bob"), Owner: aliceAddr}
+				ctx.KVStore(k.StoreKey).Set(AccountKey("test", "bob"), k.Cdc.MustMarshalBinaryBare(account.MarshalCRUD()))
+			},
+		},
+		"transfer offer owner": {
+			Invariant: TransferOfferOwnerInvariant,
+			BreakState: func(t *testing.T, ctx sdk.Context, k Keeper) {
+				k.CreateTransferOffer(ctx, types.TransferOffer{Domain: "test", Owner: bobAddr, Recipient: aliceAddr})
+			},
+		},
+		"account count": {
+			Invariant: AccountCountInvariant,
+			BreakState: func(t *testing.T, ctx sdk.Context, k Keeper) {
+				k.IncreaseAccountCount(ctx, "test")
+			},
+		},
+		"configuration limits": {
+			Invariant: ConfigurationLimitsInvariant,
+			BreakState: func(t *testing.T, ctx sdk.Context, k Keeper) {
+				k.AccountStore(ctx).Update(&types.Account{
+					Domain:       "test",
+					Name:         utils.StrPtr("bob"),
+					Owner:        bobAddr,
+					Certificates: []types.Certificate{[]byte("toolong")},
+				})
+			},
+		},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			k, ctx := setup(t)
+			if msg, broken := AllInvariants(k)(ctx); broken {
+				t.Fatalf("unexpected broken invariant: %s", msg)
+			}
+			c.BreakState(t, ctx, k)
+			if _, broken := c.Invariant(k)(ctx); !broken {
+				t.Fatal("expected broken invariant")
+			}
+		})
+	}
+}
@@ -20,9 +20,11 @@ type ParamSubspace interface {
 
 // SupplyKeeper defines the behaviour
 // of the supply keeper used to collect
-// and then distribute the fees
+// and then distribute the fees, and to
+// pay the price of transfer offers
 type SupplyKeeper interface {
 	SendCoinsFromAccountToModule(ctx sdk.Context, addr sdk.AccAddress, moduleName string, coins sdk.Coins) error
+	SendCoinsFromModuleToAccount(ctx sdk.Context, moduleName string, addr sdk.AccAddress, coins sdk.Coins) error
 }
 
 // ConfigurationKeeper defines the behaviour of the configuration state checks
@@ -40,6 +42,8 @@ type ConfigurationKeeper interface {
 	GetDomainRenewDuration(ctx sdk.Context) time.Duration
 	// GetDomainGracePeriod returns the grace period duration
 	GetDomainGracePeriod(ctx sdk.Context) time.Duration
+	// GetReservedNamesMatching returns the entries of the reserved names registry which reserve the name
+	GetReservedNamesMatching(ctx sdk.Context, name string) configuration.ReservedNames
 }
 
 // Keeper of the domain store
@@ -52,6 +56,8 @@ type Keeper struct {
 	StoreKey   sdk.StoreKey // contains the store key for the domain module
 	Cdc        *codec.Codec
 	paramspace ParamSubspace
+	// hooks are called on domains and accounts state changes
+	hooks types.StarnameHooks
 }
 
 // NewKeeper creates aliceAddr domain keeper
@@ -66,15 +72,48 @@ func NewKeeper(cdc *codec.Codec, storeKey sdk.StoreKey, configKeeper Configurati
 	return keeper
 }
 
+// AccountStorePrefix is the prefix of the account objects store
+var AccountStorePrefix = []byte{0x1}
+
+// DomainStorePrefix is the prefix of the domain objects store
+var DomainStorePrefix = []byte{0x2}
+
+// CrudObjectPrefix is the prefix crud.Store uses to save objects,
+// as opposed to the prefixes used to save their indexes
+var CrudObjectPrefix = []byte{0x0}
+
+// CrudIndexPrefix is the prefix crud.Store uses to save the pointers from
+// secondary keys to primary keys, which are saved as prefix|secondary key|primary key
+var CrudIndexPrefix = []byte{0x1}
+
 // AccountStore returns the crud.Store used to interact with account objects
 func (k Keeper) AccountStore(ctx sdk.Context) crud.Store {
-	store := crud.NewStore(ctx, k.StoreKey, k.Cdc, []byte{0x1})
+	store := crud.NewStore(ctx, k.StoreKey, k.Cdc, AccountStorePrefix)
 	return store
 }
 
 // DomainStore returns the crud.Store used to interact with domain objects
 func (k Keeper) DomainStore(ctx sdk.Context) crud.Store {
-	return crud.NewStore(ctx, k.StoreKey, k.Cdc, []byte{0x2})
+	return crud.NewStore(ctx, k.StoreKey, k.Cdc, DomainStorePrefix)
+}
+
+// AccountKey returns the raw key under which the account
+// identified by domain and name is saved in the module's KVStore,
+// it can be used to query the store directly and obtain merkle proofs
+func AccountKey(domain, name string) []byte {
+	pk := (&types.Account{Domain: domain, Name: &name}).PrimaryKey()
+	key := append([]byte{}, AccountStorePrefix...)
+	key = append(key, CrudObjectPrefix...)
+	return append(key, pk.Key()...)
+}
+
+// DomainKey returns the raw key under which the domain
+// identified by name is saved in the module's KVStore
+func DomainKey(name string) []byte {
+	pk := (&types.Domain{Name: name}).PrimaryKey()
+	key := append([]byte{}, DomainStorePrefix...)
+	key = append(key, CrudObjectPrefix...)
+	return append(key, pk.Key()...)
 }
 
 // Logger returns aliceAddr module-specific logger.
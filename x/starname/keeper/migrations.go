@@ -0,0 +1,164 @@
+package keeper
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	crud "github.com/iov-one/cosmos-sdk-crud/pkg/crud/types"
+	"github.com/iov-one/iovns/pkg/idn"
+	"github.com/iov-one/iovns/pkg/migration"
+	"github.com/iov-one/iovns/pkg/utils"
+	"github.com/iov-one/iovns/x/starname/types"
+)
+
+// StoreVersionKey is the key used to store the version of the module's store
+var StoreVersionKey = []byte{0x5}
+
+// Migrations returns the manager of the starname store migrations,
+// new migrations must be appended to the list with the next version
+func (k Keeper) Migrations() migration.Manager {
+	return migration.NewManager(types.ModuleName, k.StoreKey, StoreVersionKey,
+		migration.Migration{
+			Version:     1,
+			Description: "rewrite domains and accounts and rebuild their indexes",
+			Migrate:     k.migrateRewriteObjects,
+		},
+		migration.Migration{
+			Version:     2,
+			Description: "store domain and account names in their canonical form and index their skeletons",
+			Migrate:     k.migrateCanonicalNames,
+		},
+		migration.Migration{
+			Version:     3,
+			Description: "count the accounts registered in each domain",
+			Migrate:     k.migrateAccountCounts,
+		},
+		migration.Migration{
+			Version:     4,
+			Description: "make the accounts of closed domains follow the domain expiration",
+			Migrate:     k.migrateClosedDomainAccountExpirations,
+		},
+	)
+}
+
+// migrateRewriteObjects rewrites domains and accounts in place rebuilding their indexes
+func (k Keeper) migrateRewriteObjects(ctx sdk.Context) error {
+	err := migration.RewriteObjects(ctx, k.StoreKey, k.Cdc, DomainStorePrefix, func(value []byte) (crud.Object, error) {
+		domain := new(types.Domain)
+		if err := k.Cdc.UnmarshalBinaryBare(value, domain); err != nil {
+			return nil, err
+		}
+		return domain, nil
+	})
+	if err != nil {
+		return err
+	}
+	return migration.RewriteObjects(ctx, k.StoreKey, k.Cdc, AccountStorePrefix, func(value []byte) (o crud.Object, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("unable to decode account: %v", r)
+			}
+		}()
+		account := new(types.Account)
+		account.UnmarshalCRUD(k.Cdc, value)
+		return account, nil
+	})
+}
+
+// migrateCanonicalNames rewrites domains and accounts using their canonical names,
+// the rewrite also creates the skeleton indexes used to detect confusable names,
+// it fails without changing the store if two names have the same canonical form
+func (k Keeper) migrateCanonicalNames(ctx sdk.Context) error {
+	if collisions := k.canonicalNameCollisions(ctx); len(collisions) != 0 {
+		return fmt.Errorf("names with the same canonical form must be resolved first: %s", strings.Join(collisions, "; "))
+	}
+	err := migration.RewriteObjects(ctx, k.StoreKey, k.Cdc, DomainStorePrefix, func(value []byte) (crud.Object, error) {
+		domain := new(types.Domain)
+		if err := k.Cdc.UnmarshalBinaryBare(value, domain); err != nil {
+			return nil, err
+		}
+		domain.Name = idn.Canonical(domain.Name)
+		return domain, nil
+	})
+	if err != nil {
+		return err
+	}
+	return migration.RewriteObjects(ctx, k.StoreKey, k.Cdc, AccountStorePrefix, func(value []byte) (o crud.Object, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("unable to decode account: %v", r)
+			}
+		}()
+		account := new(types.Account)
+		account.UnmarshalCRUD(k.Cdc, value)
+		account.Domain = idn.Canonical(account.Domain)
+		if account.Name != nil {
+			account.Name = utils.StrPtr(idn.Canonical(*account.Name))
+		}
+		return account, nil
+	})
+}
+
+// canonicalNameCollisions returns the sorted list of the domains and accounts whose
+// names have the same canonical form, each entry lists the colliding stored names
+func (k Keeper) canonicalNameCollisions(ctx sdk.Context) []string {
+	names := make(map[string][]string)
+	k.iterateDomains(ctx, func(domain types.Domain) {
+		canonical := idn.Canonical(domain.Name)
+		names[canonical] = append(names[canonical], fmt.Sprintf("%+q", domain.Name))
+	})
+	k.iterateAccounts(ctx, func(account types.Account) {
+		canonical := types.AccountStarname(idn.Canonical(account.Domain), idn.Canonical(*account.Name))
+		names[canonical] = append(names[canonical], fmt.Sprintf("%+q", types.AccountStarname(account.Domain, *account.Name)))
+	})
+	var collisions []string
+	for canonical, stored := range names {
+		if len(stored) > 1 {
+			sort.Strings(stored)
+			collisions = append(collisions, fmt.Sprintf("%+q: %s", canonical, strings.Join(stored, ", ")))
+		}
+	}
+	sort.Strings(collisions)
+	return collisions
+}
+
+// migrateAccountCounts sets the account counter of every domain with registered accounts
+func (k Keeper) migrateAccountCounts(ctx sdk.Context) error {
+	counts := k.countAccounts(ctx)
+	domains := make([]string, 0, len(counts))
+	for domain := range counts {
+		domains = append(domains, domain)
+	}
+	sort.Strings(domains)
+	for _, domain := range domains {
+		k.SetAccountCount(ctx, domain, counts[domain])
+	}
+	return nil
+}
+
+// migrateClosedDomainAccountExpirations sets the expiration of the accounts of closed domains to
+// types.MaxValidUntil, the expiration of those accounts was ignored before admins could set it
+func (k Keeper) migrateClosedDomainAccountExpirations(ctx sdk.Context) error {
+	var closed []string
+	k.iterateDomains(ctx, func(domain types.Domain) {
+		if domain.Type == types.ClosedDomain {
+			closed = append(closed, domain.Name)
+		}
+	})
+	as := k.AccountStore(ctx)
+	for _, domain := range closed {
+		filter := as.Filter(&types.Account{Domain: domain})
+		for ; filter.Valid(); filter.Next() {
+			account := new(types.Account)
+			filter.Read(account)
+			if *account.Name == types.EmptyAccountName || account.ValidUntil == types.MaxValidUntil {
+				continue
+			}
+			account.ValidUntil = types.MaxValidUntil
+			filter.Update(account)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,358 @@
+package keeper
+
+import (
+	"encoding/binary"
+	"strings"
+
+	"github.com/cosmos/cosmos-sdk/store/prefix"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/iov-one/cosmos-sdk-crud/pkg/crud"
+	crudtypes "github.com/iov-one/cosmos-sdk-crud/pkg/crud/types"
+	"github.com/iov-one/iovns/pkg/idn"
+	"github.com/iov-one/iovns/pkg/queries"
+	"github.com/iov-one/iovns/x/starname/types"
+	abci "github.com/tendermint/tendermint/abci/types"
+)
+
+// TransferOfferStorePrefix is the prefix of the transfer offers store
+var TransferOfferStorePrefix = []byte{0x8}
+
+// TransferOfferSequenceKey is the key used to store the last transfer offer ID
+var TransferOfferSequenceKey = []byte{0x9}
+
+// TransferOfferQueuePrefix is the prefix of the queue of the transfer offers
+// with an expiry, keys are the big endian expiry followed by the offer ID
+var TransferOfferQueuePrefix = []byte{0xA}
+
+// TransferOfferStore returns the crud.Store used to interact with transfer offers
+func (k Keeper) TransferOfferStore(ctx sdk.Context) crud.Store {
+	return crud.NewStore(ctx, k.StoreKey, k.Cdc, TransferOfferStorePrefix)
+}
+
+// transferOfferQueue returns the store of the transfer offers expiry queue
+func (k Keeper) transferOfferQueue(ctx sdk.Context) prefix.Store {
+	return prefix.NewStore(ctx.KVStore(k.StoreKey), TransferOfferQueuePrefix)
+}
+
+// transferOfferQueueKey returns the expiry queue key of the offer
+func transferOfferQueueKey(offer types.TransferOffer) []byte {
+	return append(sdk.Uint64ToBigEndian(uint64(offer.Expiry)), sdk.Uint64ToBigEndian(offer.ID)...)
+}
+
+// GetTransferOfferSequence returns the last transfer offer ID
+func (k Keeper) GetTransferOfferSequence(ctx sdk.Context) uint64 {
+	b := ctx.KVStore(k.StoreKey).Get(TransferOfferSequenceKey)
+	if b == nil {
+		return 0
+	}
+	return binary.BigEndian.Uint64(b)
+}
+
+// SetTransferOfferSequence sets the last transfer offer ID
+func (k Keeper) SetTransferOfferSequence(ctx sdk.Context, id uint64) {
+	ctx.KVStore(k.StoreKey).Set(TransferOfferSequenceKey, sdk.Uint64ToBigEndian(id))
+}
+
+// CreateTransferOffer saves the provided offer after assigning it an ID
+func (k Keeper) CreateTransferOffer(ctx sdk.Context, offer types.TransferOffer) types.TransferOffer {
+	offer.ID = k.GetTransferOfferSequence(ctx) + 1
+	k.SetTransferOfferSequence(ctx, offer.ID)
+	k.SetTransferOffer(ctx, offer)
+	return offer
+}
+
+// SetTransferOffer saves the provided offer with its ID and queues its expiry, if any
+func (k Keeper) SetTransferOffer(ctx sdk.Context, offer types.TransferOffer) {
+	k.TransferOfferStore(ctx).Create(&offer)
+	if offer.Expiry != 0 {
+		k.transferOfferQueue(ctx).Set(transferOfferQueueKey(offer), []byte{0x1})
+	}
+}
+
+// GetTransferOffer returns the transfer offer with the provided ID, if any
+func (k Keeper) GetTransferOffer(ctx sdk.Context, id uint64) (types.TransferOffer, bool) {
+	offer := new(types.TransferOffer)
+	if !k.TransferOfferStore(ctx).Read((&types.TransferOffer{ID: id}).PrimaryKey(), offer) {
+		return types.TransferOffer{}, false
+	}
+	return *offer, true
+}
+
+// GetStarnameTransferOffer returns the pending transfer offer of a domain or an account, if any
+func (k Keeper) GetStarnameTransferOffer(ctx sdk.Context, starname string) (types.TransferOffer, bool) {
+	filter := k.TransferOfferStore(ctx).Filter(&types.TransferOfferStarnameFilter{Starname: starname})
+	if !filter.Valid() {
+		return types.TransferOffer{}, false
+	}
+	offer := new(types.TransferOffer)
+	filter.Read(offer)
+	return *offer, true
+}
+
+// GetDomainPendingTransferOffer returns a transfer offer made by owner on the domain,
+// or on one of its accounts, which can still be accepted, if any
+func (k Keeper) GetDomainPendingTransferOffer(ctx sdk.Context, domain string, owner sdk.AccAddress) (types.TransferOffer, bool) {
+	now := ctx.BlockTime().Unix()
+	filter := k.TransferOfferStore(ctx).Filter(&types.TransferOfferDomainFilter{Domain: domain})
+	for ; filter.Valid(); filter.Next() {
+		offer := new(types.TransferOffer)
+		filter.Read(offer)
+		if offer.Owner.Equals(owner) && !offer.Expired(now) {
+			return *offer, true
+		}
+	}
+	return types.TransferOffer{}, false
+}
+
+// DeleteTransferOffer removes the offer and its expiry from the queue
+func (k Keeper) DeleteTransferOffer(ctx sdk.Context, offer types.TransferOffer) {
+	k.TransferOfferStore(ctx).Delete(offer.PrimaryKey())
+	if offer.Expiry != 0 {
+		k.transferOfferQueue(ctx).Delete(transferOfferQueueKey(offer))
+	}
+}
+
+// DeleteStarnameTransferOffers removes the offers of a domain or an account,
+// it is used when their owner changes or when they are deleted
+func (k Keeper) DeleteStarnameTransferOffers(ctx sdk.Context, starname string) {
+	k.deleteFilteredTransferOffers(ctx, &types.TransferOfferStarnameFilter{Starname: starname})
+}
+
+// DeleteDomainTransferOffers removes the offers of a domain and of all its accounts
+func (k Keeper) DeleteDomainTransferOffers(ctx sdk.Context, domain string) {
+	k.deleteFilteredTransferOffers(ctx, &types.TransferOfferDomainFilter{Domain: domain})
+}
+
+// deleteFilteredTransferOffers removes the offers matching the filter
+func (k Keeper) deleteFilteredTransferOffers(ctx sdk.Context, f crudtypes.Object) {
+	var offers []types.TransferOffer
+	filter := k.TransferOfferStore(ctx).Filter(f)
+	for ; filter.Valid(); filter.Next() {
+		offer := new(types.TransferOffer)
+		filter.Read(offer)
+		offers = append(offers, *offer)
+	}
+	for _, offer := range offers {
+		k.DeleteTransferOffer(ctx, offer)
+	}
+}
+
+// IterateTransferOffers calls do on each transfer offer
+func (k Keeper) IterateTransferOffers(ctx sdk.Context, do func(offer types.TransferOffer) bool) {
+	store := k.TransferOfferStore(ctx)
+	store.IterateKeys(func(pk crudtypes.PrimaryKey) bool {
+		offer := new(types.TransferOffer)
+		store.Read(pk, offer)
+		return do(*offer)
+	})
+}
+
+// PayTransferOffer sends the price of the offer from its recipient to its owner,
+// the coins go through the module account so that the supply keeper tracks them
+func (k Keeper) PayTransferOffer(ctx sdk.Context, offer types.TransferOffer) error {
+	if offer.Price.Empty() {
+		return nil
+	}
+	if err := k.SupplyKeeper.SendCoinsFromAccountToModule(ctx, offer.Recipient, types.ModuleName, offer.Price); err != nil {
+		return err
+	}
+	return k.SupplyKeeper.SendCoinsFromModuleToAccount(ctx, types.ModuleName, offer.Owner, offer.Price)
+}
+
+// DeleteExpiredTransferOffers removes the offers expired at the current block time
+// and returns them, it is called at the end of each block
+func (k Keeper) DeleteExpiredTransferOffers(ctx sdk.Context) []types.TransferOffer {
+	queue := k.transferOfferQueue(ctx)
+	// offers expire when the block time reaches their expiry
+	end := sdk.Uint64ToBigEndian(uint64(ctx.BlockTime().Unix()) + 1)
+	var ids []uint64
+	it := queue.Iterator(nil, end)
+	for ; it.Valid(); it.Next() {
+		ids = append(ids, binary.BigEndian.Uint64(it.Key()[8:]))
+	}
+	it.Close()
+	expired := make([]types.TransferOffer, 0, len(ids))
+	for _, id := range ids {
+		offer, ok := k.GetTransferOffer(ctx, id)
+		if !ok {
+			continue
+		}
+		k.DeleteTransferOffer(ctx, offer)
+		expired = append(expired, offer)
+	}
+	return expired
+}
+
+// QueryTransferOffers is the request model used to get the pending
+// transfer offers made by an owner or received by a recipient
+type QueryTransferOffers struct {
+	// Owner is the address of the owner who made the offers, optional
+	Owner sdk.AccAddress `json:"owner"`
+	// Recipient is the address of the recipient of the offers, optional
+	Recipient sdk.AccAddress `json:"recipient"`
+	// ResultsPerPage is the number of results displayed in a page
+	ResultsPerPage int `json:"results_per_page"`
+	// Offset is the page number
+	Offset int `json:"offset"`
+}
+
+// Use is a placeholder
+func (q *QueryTransferOffers) Use() string {
+	return "transfer-offers"
+}
+
+// Description is a placeholder
+func (q *QueryTransferOffers) Description() string {
+	return "gets the pending transfer offers made by an owner or received by a recipient"
+}
+
+// Handler implements the local queryHandler
+func (q *QueryTransferOffers) Handler() QueryHandlerFunc {
+	return queryTransferOffersHandler
+}
+
+// QueryPath implements queries.QueryHandler
+func (q *QueryTransferOffers) QueryPath() string {
+	return "transferOffers"
+}
+
+// Validate implements queries.QueryHandler
+func (q *QueryTransferOffers) Validate() error {
+	if q.Owner.Empty() && q.Recipient.Empty() {
+		return sdkerrors.Wrapf(types.ErrInvalidRequest, "either owner or recipient must be provided")
+	}
+	if q.ResultsPerPage == 0 {
+		q.ResultsPerPage = 100
+	}
+	if q.Offset == 0 {
+		q.Offset = 1
+	}
+	return nil
+}
+
+// QueryTransferOffersResponse is the response
+// returned by the QueryTransferOffers query
+type QueryTransferOffersResponse struct {
+	// Offers contains the pending transfer offers
+	Offers []types.TransferOffer `json:"offers"`
+}
+
+// queryTransferOffersHandler returns the pending transfer offers of an owner or of a recipient
+func queryTransferOffersHandler(ctx sdk.Context, _ []string, req abci.RequestQuery, k Keeper) ([]byte, error) {
+	q := new(QueryTransferOffers)
+	err := queries.DefaultQueryDecode(req.Data, q)
+	if err != nil {
+		return nil, sdkerrors.Wrapf(sdkerrors.ErrJSONUnmarshal, err.Error())
+	}
+	// validate
+	if err := q.Validate(); err != nil {
+		return nil, err
+	}
+	// calculate index range
+	indexStart := q.ResultsPerPage*q.Offset - q.ResultsPerPage // start index
+	indexEnd := indexStart + q.ResultsPerPage - 1              // index end
+	i := 0
+	// iterate offers
+	offers := make([]types.TransferOffer, 0, q.ResultsPerPage)
+	filter := k.TransferOfferStore(ctx).Filter(&types.TransferOffer{Owner: q.Owner, Recipient: q.Recipient})
+	for {
+		if !filter.Valid() {
+			break
+		}
+		if i >= indexStart {
+			offer := new(types.TransferOffer)
+			filter.Read(offer)
+			offers = append(offers, *offer)
+		}
+		if i == indexEnd {
+			break
+		}
+		filter.Next()
+		i++
+	}
+	// return response
+	b, err := queries.DefaultQueryEncode(QueryTransferOffersResponse{Offers: offers})
+	if err != nil {
+		return nil, sdkerrors.Wrapf(sdkerrors.ErrJSONMarshal, err.Error())
+	}
+	return b, nil
+}
+
+// QueryTransferOffer is the request model used to get a transfer offer
+// by its ID or the pending transfer offer of a domain or an account
+type QueryTransferOffer struct {
+	// ID is the ID of the transfer offer
+	ID uint64 `json:"id"`
+	// Starname is either a domain name or an account in name*domain format
+	Starname string `json:"starname"`
+}
+
+// Use is a placeholder
+func (q *QueryTransferOffer) Use() string {
+	return "transfer-offer"
+}
+
+// Description is a placeholder
+func (q *QueryTransferOffer) Description() string {
+	return "gets a transfer offer by its id or the pending transfer offer of a domain or an account"
+}
+
+// Handler implements the local queryHandler
+func (q *QueryTransferOffer) Handler() QueryHandlerFunc {
+	return queryTransferOfferHandler
+}
+
+// QueryPath implements queries.QueryHandler
+func (q *QueryTransferOffer) QueryPath() string {
+	return "transferOffer"
+}
+
+// Validate implements queries.QueryHandler
+func (q *QueryTransferOffer) Validate() error {
+	if (q.ID == 0) == (q.Starname == "") {
+		return sdkerrors.Wrapf(types.ErrInvalidRequest, "either id or starname must be provided")
+	}
+	if strings.Count(q.Starname, types.StarnameSeparator) > 1 {
+		return types.ErrStarnameMultipleSeparator
+	}
+	q.Starname = idn.Canonical(q.Starname)
+	return nil
+}
+
+// QueryTransferOfferResponse is the response
+// returned by the QueryTransferOffer query
+type QueryTransferOfferResponse struct {
+	// Offer is the transfer offer
+	Offer types.TransferOffer `json:"offer"`
+}
+
+// queryTransferOfferHandler returns a transfer offer
+func queryTransferOfferHandler(ctx sdk.Context, _ []string, req abci.RequestQuery, k Keeper) ([]byte, error) {
+	q := new(QueryTransferOffer)
+	err := queries.DefaultQueryDecode(req.Data, q)
+	if err != nil {
+		return nil, sdkerrors.Wrapf(sdkerrors.ErrJSONUnmarshal, err.Error())
+	}
+	// validate
+	if err := q.Validate(); err != nil {
+		return nil, err
+	}
+	var offer types.TransferOffer
+	var ok bool
+	switch {
+	case q.ID != 0:
+		offer, ok = k.GetTransferOffer(ctx, q.ID)
+	default:
+		offer, ok = k.GetStarnameTransferOffer(ctx, q.Starname)
+	}
+	if !ok {
+		return nil, sdkerrors.Wrapf(types.ErrTransferOfferDoesNotExist, "not found: %d%s", q.ID, q.Starname)
+	}
+	// return response
+	b, err := queries.DefaultQueryEncode(QueryTransferOfferResponse{Offer: offer})
+	if err != nil {
+		return nil, sdkerrors.Wrapf(sdkerrors.ErrJSONMarshal, err.Error())
+	}
+	return b, nil
+}
@@ -3,6 +3,7 @@ package keeper
 import (
 	"fmt"
 	crud "github.com/iov-one/cosmos-sdk-crud/pkg/crud"
+	"github.com/iov-one/iovns/pkg/idn"
 	"github.com/iov-one/iovns/pkg/queries"
 	"github.com/iov-one/iovns/pkg/utils"
 	"strings"
@@ -25,6 +26,12 @@ func AvailableQueries() []queries.QueryHandler {
 		&QueryAccountsWithOwner{},
 		&QueryDomainsWithOwner{},
 		&QueryResolveResource{},
+		&QueryStarnameHistory{},
+		&QueryRegistrationPolicy{},
+		&QueryInviteCode{},
+		&QueryTransferOffers{},
+		&QueryTransferOffer{},
+		&QuerySponsorships{},
 	}
 	return qrs
 }
@@ -102,6 +109,7 @@ func (q *QueryAccountsInDomain) Validate() error {
 	if q.Domain == "" {
 		return sdkerrors.Wrapf(types.ErrInvalidDomainName, "empty")
 	}
+	q.Domain = idn.Canonical(q.Domain)
 	// if results per page is unset then use default
 	if q.ResultsPerPage <= 0 {
 		q.ResultsPerPage = 100
@@ -409,6 +417,9 @@ func (q *QueryResolveAccount) Validate() error {
 		q.Domain = sname[1]
 		q.Starname = ""
 	}
+	// resolve the canonical form of the names
+	q.Domain = idn.Canonical(q.Domain)
+	q.Name = idn.Canonical(q.Name)
 
 	if q.Domain == "" {
 		return sdkerrors.Wrapf(types.ErrInvalidDomainName, "empty")
@@ -486,6 +497,7 @@ func (q *QueryResolveDomain) Validate() error {
 	if q.Name == "" {
 		return sdkerrors.Wrapf(types.ErrInvalidDomainName, "empty")
 	}
+	q.Name = idn.Canonical(q.Name)
 	return nil
 }
 
@@ -606,3 +618,101 @@ func queryResourceAccountHandler(ctx sdk.Context, _ []string, req abci.RequestQu
 	}
 	return b, nil
 }
+
+// QueryStarnameHistory is the request model used to
+// query the changes applied to a domain or an account
+type QueryStarnameHistory struct {
+	// Starname is either a domain name or an account in name*domain format
+	Starname string `json:"starname"`
+	// ResultsPerPage is the number of results displayed in a page
+	ResultsPerPage int `json:"results_per_page"`
+	// Offset is the page number
+	Offset int `json:"offset"`
+}
+
+// Use is a placeholder
+func (q *QueryStarnameHistory) Use() string {
+	return "history"
+}
+
+// Description is a placeholder
+func (q *QueryStarnameHistory) Description() string {
+	return "gets the history of changes of a domain or an account"
+}
+
+// Handler implements the local queryHandler
+func (q *QueryStarnameHistory) Handler() QueryHandlerFunc {
+	return queryStarnameHistoryHandler
+}
+
+// QueryPath implements queries.QueryHandler
+func (q *QueryStarnameHistory) QueryPath() string {
+	return "history"
+}
+
+// Validate implements queries.QueryHandler
+func (q *QueryStarnameHistory) Validate() error {
+	if q.Starname == "" {
+		return sdkerrors.Wrapf(types.ErrInvalidDomainName, "empty")
+	}
+	if strings.Count(q.Starname, types.StarnameSeparator) > 1 {
+		return types.ErrStarnameMultipleSeparator
+	}
+	q.Starname = idn.Canonical(q.Starname)
+	if q.ResultsPerPage == 0 {
+		q.ResultsPerPage = 100
+	}
+	if q.Offset == 0 {
+		q.Offset = 1
+	}
+	return nil
+}
+
+// QueryStarnameHistoryResponse is the response
+// returned by the QueryStarnameHistory query
+type QueryStarnameHistoryResponse struct {
+	// Records contains the history records
+	// sorted from the oldest to the newest
+	Records []types.HistoryRecord `json:"records"`
+}
+
+// queryStarnameHistoryHandler returns the history records of a domain or an account
+func queryStarnameHistoryHandler(ctx sdk.Context, _ []string, req abci.RequestQuery, k Keeper) ([]byte, error) {
+	q := new(QueryStarnameHistory)
+	err := queries.DefaultQueryDecode(req.Data, q)
+	if err != nil {
+		return nil, sdkerrors.Wrapf(sdkerrors.ErrJSONUnmarshal, err.Error())
+	}
+	// validate
+	if err := q.Validate(); err != nil {
+		return nil, err
+	}
+	// calculate index range
+	indexStart := q.ResultsPerPage*q.Offset - q.ResultsPerPage // start index
+	indexEnd := indexStart + q.ResultsPerPage - 1              // index end
+	i := 0
+	// iterate records
+	records := make([]types.HistoryRecord, 0, q.ResultsPerPage)
+	filter := k.HistoryStore(ctx).Filter(&types.HistoryRecord{Starname: q.Starname})
+	for {
+		if !filter.Valid() {
+			break
+		}
+		if i >= indexStart {
+			record := new(types.HistoryRecord)
+			filter.Read(record)
+			records = append(records, *record)
+		}
+		if i == indexEnd {
+			break
+		}
+		filter.Next()
+		i++
+	}
+	// return response
+	b, err := queries.DefaultQueryEncode(QueryStarnameHistoryResponse{Records: records})
+	if err != nil {
+		return nil, sdkerrors.Wrapf(sdkerrors.ErrJSONMarshal, err.Error())
+	}
+	return b, nil
+}
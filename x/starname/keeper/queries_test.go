@@ -6,6 +6,7 @@ import (
 	"time"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/iov-one/iovns/x/configuration"
 	"github.com/iov-one/iovns/x/starname/types"
 )
 
@@ -160,6 +161,25 @@ func Test_queryResolveAccountHandler(t *testing.T) {
 				Owner:  bobAddr,
 			}},
 		},
+		"success equivalent starname": {
+			BeforeTest: func(t *testing.T, ctx sdk.Context, k Keeper) {
+				k.AccountStore(ctx).Create(&types.Account{
+					Domain: "caf\u00e9",
+					Name:   utils.StrPtr("no\u00ebl"),
+					Owner:  bobAddr,
+				})
+			},
+			Request: &QueryResolveAccount{
+				Starname: "noe\u0308l*cafe\u0301",
+			},
+			Handler: queryResolveAccountHandler,
+			WantErr: nil,
+			PtrExpectedResponse: &QueryResolveAccountResponse{Account: types.Account{
+				Domain: "caf\u00e9",
+				Name:   utils.StrPtr("no\u00ebl"),
+				Owner:  bobAddr,
+			}},
+		},
 		"failure provide only one param starname": {
 			Request: &QueryResolveAccount{
 				Domain:   "test",
@@ -218,6 +238,18 @@ func Test_queryResolveDomainHandler(t *testing.T) {
 			WantErr:             nil,
 			PtrExpectedResponse: &QueryResolveDomainResponse{Domain: types.Domain{Name: "test", Admin: bobAddr}},
 		},
+		"success equivalent name": {
+			BeforeTest: func(t *testing.T, ctx sdk.Context, k Keeper) {
+				k.DomainStore(ctx).Create(&types.Domain{
+					Name:  "caf\u00e9",
+					Admin: bobAddr,
+				})
+			},
+			Request:             &QueryResolveDomain{Name: "cafe\u0301"},
+			Handler:             queryResolveDomainHandler,
+			WantErr:             nil,
+			PtrExpectedResponse: &QueryResolveDomainResponse{Domain: types.Domain{Name: "caf\u00e9", Admin: bobAddr}},
+		},
 	}
 
 	runQueryTests(t, testCases)
@@ -283,3 +315,182 @@ func Test_queryResourceAccountsHandler(t *testing.T) {
 
 	runQueryTests(t, testCases)
 }
+
+func Test_queryStarnameHistoryHandler(t *testing.T) {
+	enableHistory := func(ctx sdk.Context, k Keeper) {
+		GetConfigSetter(k.ConfigurationKeeper).SetConfig(ctx, configuration.Config{HistoryRetention: time.Hour})
+	}
+	testCases := map[string]subTest{
+		"success account": {
+			BeforeTest: func(t *testing.T, ctx sdk.Context, k Keeper) {
+				enableHistory(ctx, k)
+				ctx = ctx.WithBlockHeight(10).WithBlockTime(time.Unix(100, 0))
+				account := types.Account{Domain: "test", Name: utils.StrPtr("1"), Owner: aliceAddr}
+				k.RecordHistory(ctx, types.NewAccountHistoryRecord(types.HistoryCreate, account))
+				k.RecordHistory(ctx, types.NewDomainHistoryRecord(types.HistoryCreate, types.Domain{Name: "test", Admin: aliceAddr}))
+				account.Owner = bobAddr
+				ctx = ctx.WithBlockHeight(11).WithBlockTime(time.Unix(200, 0))
+				k.RecordHistory(ctx, types.NewAccountHistoryRecord(types.HistoryTransfer, account))
+			},
+			Request: &QueryStarnameHistory{
+				Starname: "1*test",
+			},
+			Handler: queryStarnameHistoryHandler,
+			WantErr: nil,
+			PtrExpectedResponse: &QueryStarnameHistoryResponse{
+				Records: []types.HistoryRecord{
+					{
+						ID:       1,
+						Starname: "1*test",
+						Domain:   "test",
+						Height:   10,
+						Time:     100,
+						Action:   types.HistoryCreate,
+						Owner:    aliceAddr,
+					},
+					{
+						ID:       3,
+						Starname: "1*test",
+						Domain:   "test",
+						Height:   11,
+						Time:     200,
+						Action:   types.HistoryTransfer,
+						Owner:    bobAddr,
+					},
+				},
+			},
+		},
+		"success domain with paging": {
+			BeforeTest: func(t *testing.T, ctx sdk.Context, k Keeper) {
+				enableHistory(ctx, k)
+				ctx = ctx.WithBlockHeight(10).WithBlockTime(time.Unix(100, 0))
+				domain := types.Domain{Name: "test", Admin: aliceAddr}
+				k.RecordHistory(ctx, types.NewDomainHistoryRecord(types.HistoryCreate, domain))
+				domain.Admin = bobAddr
+				k.RecordHistory(ctx, types.NewDomainHistoryRecord(types.HistoryTransfer, domain))
+			},
+			Request: &QueryStarnameHistory{
+				Starname:       "test",
+				ResultsPerPage: 1,
+				Offset:         2,
+			},
+			Handler: queryStarnameHistoryHandler,
+			WantErr: nil,
+			PtrExpectedResponse: &QueryStarnameHistoryResponse{
+				Records: []types.HistoryRecord{
+					{
+						ID:       2,
+						Starname: "test",
+						Domain:   "test",
+						Height:   10,
+						Time:     100,
+						Action:   types.HistoryTransfer,
+						Owner:    bobAddr,
+					},
+				},
+			},
+		},
+		"multiple separators": {
+			Request: &QueryStarnameHistory{
+				Starname: "1*test*test",
+			},
+			Handler: queryStarnameHistoryHandler,
+			WantErr: types.ErrStarnameMultipleSeparator,
+		},
+	}
+
+	runQueryTests(t, testCases)
+}
+
+func Test_queryTransferOffersHandler(t *testing.T) {
+	createOffers := func(t *testing.T, ctx sdk.Context, k Keeper) {
+		k.CreateTransferOffer(ctx, types.TransferOffer{Domain: "test", Owner: aliceAddr, Recipient: bobAddr})
+		k.CreateTransferOffer(ctx, types.TransferOffer{Domain: "test", Name: utils.StrPtr("1"), Owner: bobAddr, Recipient: aliceAddr})
+		k.CreateTransferOffer(ctx, types.TransferOffer{Domain: "other", Owner: aliceAddr, Recipient: bobAddr, Expiry: 100})
+	}
+	testCases := map[string]subTest{
+		"success owner": {
+			BeforeTest: createOffers,
+			Request: &QueryTransferOffers{
+				Owner: aliceAddr,
+			},
+			Handler: queryTransferOffersHandler,
+			WantErr: nil,
+			PtrExpectedResponse: &QueryTransferOffersResponse{
+				Offers: []types.TransferOffer{
+					{ID: 1, Domain: "test", Owner: aliceAddr, Recipient: bobAddr},
+					{ID: 3, Domain: "other", Owner: aliceAddr, Recipient: bobAddr, Expiry: 100},
+				},
+			},
+		},
+		"success recipient with paging": {
+			BeforeTest: createOffers,
+			Request: &QueryTransferOffers{
+				Recipient:      bobAddr,
+				ResultsPerPage: 1,
+				Offset:         2,
+			},
+			Handler: queryTransferOffersHandler,
+			WantErr: nil,
+			PtrExpectedResponse: &QueryTransferOffersResponse{
+				Offers: []types.TransferOffer{
+					{ID: 3, Domain: "other", Owner: aliceAddr, Recipient: bobAddr, Expiry: 100},
+				},
+			},
+		},
+		"missing owner and recipient": {
+			Request: &QueryTransferOffers{},
+			Handler: queryTransferOffersHandler,
+			WantErr: types.ErrInvalidRequest,
+		},
+	}
+	runQueryTests(t, testCases)
+}
+
+func Test_queryTransferOfferHandler(t *testing.T) {
+	createOffers := func(t *testing.T, ctx sdk.Context, k Keeper) {
+		k.CreateTransferOffer(ctx, types.TransferOffer{Domain: "test", Owner: aliceAddr, Recipient: bobAddr})
+		k.CreateTransferOffer(ctx, types.TransferOffer{Domain: "test", Name: utils.StrPtr("1"), Owner: bobAddr, Recipient: aliceAddr})
+	}
+	testCases := map[string]subTest{
+		"success id": {
+			BeforeTest: createOffers,
+			Request: &QueryTransferOffer{
+				ID: 1,
+			},
+			Handler: queryTransferOfferHandler,
+			WantErr: nil,
+			PtrExpectedResponse: &QueryTransferOfferResponse{
+				Offer: types.TransferOffer{ID: 1, Domain: "test", Owner: aliceAddr, Recipient: bobAddr},
+			},
+		},
+		"success starname": {
+			BeforeTest: createOffers,
+			Request: &QueryTransferOffer{
+				Starname: "1*test",
+			},
+			Handler: queryTransferOfferHandler,
+			WantErr: nil,
+			PtrExpectedResponse: &QueryTransferOfferResponse{
+				Offer: types.TransferOffer{ID: 2, Domain: "test", Name: utils.StrPtr("1"), Owner: bobAddr, Recipient: aliceAddr},
+			},
+		},
+		"offer does not exist": {
+			BeforeTest: createOffers,
+			Request: &QueryTransferOffer{
+				Starname: "2*test",
+			},
+			Handler: queryTransferOfferHandler,
+			WantErr: types.ErrTransferOfferDoesNotExist,
+		},
+		"both id and starname": {
+			Request: &QueryTransferOffer{
+				ID:       1,
+				Starname: "test",
+			},
+			Handler: queryTransferOfferHandler,
+			WantErr: types.ErrInvalidRequest,
+		},
+	}
+	runQueryTests(t, testCases)
+}
@@ -0,0 +1,243 @@
+package keeper
+
+import (
+	"crypto/sha256"
+
+	"github.com/cosmos/cosmos-sdk/store/prefix"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/iov-one/iovns/pkg/idn"
+	"github.com/iov-one/iovns/pkg/queries"
+	"github.com/iov-one/iovns/x/starname/types"
+	abci "github.com/tendermint/tendermint/abci/types"
+)
+
+// RegistrationPolicyStorePrefix is the prefix of the registration policies store
+var RegistrationPolicyStorePrefix = []byte{0x6}
+
+// InviteCodeStorePrefix is the prefix of the used invite codes store
+var InviteCodeStorePrefix = []byte{0x7}
+
+// registrationPolicyStore returns the store of the registration policies, keyed by domain name
+func (k Keeper) registrationPolicyStore(ctx sdk.Context) prefix.Store {
+	return prefix.NewStore(ctx.KVStore(k.StoreKey), RegistrationPolicyStorePrefix)
+}
+
+// GetRegistrationPolicy returns the registration policy of the domain, if any
+func (k Keeper) GetRegistrationPolicy(ctx sdk.Context, domain string) (types.RegistrationPolicy, bool) {
+	b := k.registrationPolicyStore(ctx).Get([]byte(domain))
+	if b == nil {
+		return types.RegistrationPolicy{}, false
+	}
+	var policy types.RegistrationPolicy
+	k.Cdc.MustUnmarshalBinaryBare(b, &policy)
+	return policy, true
+}
+
+// SetRegistrationPolicy saves the registration policy of its domain
+func (k Keeper) SetRegistrationPolicy(ctx sdk.Context, policy types.RegistrationPolicy) {
+	k.registrationPolicyStore(ctx).Set([]byte(policy.Domain), k.Cdc.MustMarshalBinaryBare(policy))
+}
+
+// DeleteRegistrationPolicy removes the registration policy of the domain
+func (k Keeper) DeleteRegistrationPolicy(ctx sdk.Context, domain string) {
+	k.registrationPolicyStore(ctx).Delete([]byte(domain))
+}
+
+// IterateRegistrationPolicies calls do on each registration policy
+func (k Keeper) IterateRegistrationPolicies(ctx sdk.Context, do func(policy types.RegistrationPolicy) bool) {
+	it := k.registrationPolicyStore(ctx).Iterator(nil, nil)
+	defer it.Close()
+	for ; it.Valid(); it.Next() {
+		var policy types.RegistrationPolicy
+		k.Cdc.MustUnmarshalBinaryBare(it.Value(), &policy)
+		if !do(policy) {
+			return
+		}
+	}
+}
+
+// inviteCodeDomainPrefix returns the prefix of the used invite codes of a domain,
+// domain names are hashed so that no domain prefix contains another
+func inviteCodeDomainPrefix(domain string) []byte {
+	h := sha256.Sum256([]byte(domain))
+	return h[:]
+}
+
+// inviteCodeStore returns the store of the used invite codes of a domain
+func (k Keeper) inviteCodeStore(ctx sdk.Context, domain string) prefix.Store {
+	store := prefix.NewStore(ctx.KVStore(k.StoreKey), InviteCodeStorePrefix)
+	return prefix.NewStore(store, inviteCodeDomainPrefix(domain))
+}
+
+// InviteCodeHash returns the hash used to record the usage of an invite code
+func InviteCodeHash(code string) []byte {
+	h := sha256.Sum256([]byte(code))
+	return h[:]
+}
+
+// IsInviteCodeUsed checks if the invite code of the domain was already used
+func (k Keeper) IsInviteCodeUsed(ctx sdk.Context, domain, code string) bool {
+	return k.inviteCodeStore(ctx, domain).Has(InviteCodeHash(code))
+}
+
+// SetInviteCodeUsed records the usage of the invite code hash in the domain
+func (k Keeper) SetInviteCodeUsed(ctx sdk.Context, domain string, codeHash []byte) {
+	k.inviteCodeStore(ctx, domain).Set(codeHash, []byte{0x1})
+}
+
+// IterateUsedInviteCodes calls do on the hash of each used invite code of the domain
+func (k Keeper) IterateUsedInviteCodes(ctx sdk.Context, domain string, do func(codeHash []byte) bool) {
+	it := k.inviteCodeStore(ctx, domain).Iterator(nil, nil)
+	defer it.Close()
+	for ; it.Valid(); it.Next() {
+		if !do(it.Key()) {
+			return
+		}
+	}
+}
+
+// DeleteUsedInviteCodes removes the used invite codes of the domain
+func (k Keeper) DeleteUsedInviteCodes(ctx sdk.Context, domain string) {
+	var hashes [][]byte
+	k.IterateUsedInviteCodes(ctx, domain, func(codeHash []byte) bool {
+		hashes = append(hashes, codeHash)
+		return true
+	})
+	store := k.inviteCodeStore(ctx, domain)
+	for _, h := range hashes {
+		store.Delete(h)
+	}
+}
+
+// QueryRegistrationPolicy is the request model
+// used to get the registration policy of a closed domain
+type QueryRegistrationPolicy struct {
+	// Domain is the name of the domain
+	Domain string `json:"domain"`
+}
+
+// Use is a placeholder
+func (q *QueryRegistrationPolicy) Use() string {
+	return "registration-policy"
+}
+
+// Description is a placeholder
+func (q *QueryRegistrationPolicy) Description() string {
+	return "gets the account registration policy of a closed domain"
+}
+
+// Handler implements the local queryHandler
+func (q *QueryRegistrationPolicy) Handler() QueryHandlerFunc {
+	return queryRegistrationPolicyHandler
+}
+
+// QueryPath implements queries.QueryHandler
+func (q *QueryRegistrationPolicy) QueryPath() string {
+	return "registrationPolicy"
+}
+
+// Validate implements queries.QueryHandler
+func (q *QueryRegistrationPolicy) Validate() error {
+	if q.Domain == "" {
+		return sdkerrors.Wrapf(types.ErrInvalidDomainName, "empty")
+	}
+	q.Domain = idn.Canonical(q.Domain)
+	return nil
+}
+
+// QueryRegistrationPolicyResponse is the response
+// returned by the QueryRegistrationPolicy query
+type QueryRegistrationPolicyResponse struct {
+	// Policy is the registration policy of the domain
+	Policy types.RegistrationPolicy `json:"policy"`
+}
+
+// queryRegistrationPolicyHandler returns the registration policy of a domain
+func queryRegistrationPolicyHandler(ctx sdk.Context, _ []string, req abci.RequestQuery, k Keeper) ([]byte, error) {
+	q := new(QueryRegistrationPolicy)
+	err := queries.DefaultQueryDecode(req.Data, q)
+	if err != nil {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrJSONUnmarshal, err.Error())
+	}
+	if err = q.Validate(); err != nil {
+		return nil, err
+	}
+	if !k.DomainStore(ctx).Read((&types.Domain{Name: q.Domain}).PrimaryKey(), new(types.Domain)) {
+		return nil, sdkerrors.Wrapf(types.ErrDomainDoesNotExist, "not found: %s", q.Domain)
+	}
+	policy, ok := k.GetRegistrationPolicy(ctx, q.Domain)
+	if !ok {
+		return nil, sdkerrors.Wrapf(types.ErrRegistrationPolicyDoesNotExist, "not found: %s", q.Domain)
+	}
+	respBytes, err := queries.DefaultQueryEncode(QueryRegistrationPolicyResponse{Policy: policy})
+	if err != nil {
+		return nil, sdkerrors.Wrapf(sdkerrors.ErrJSONMarshal, err.Error())
+	}
+	return respBytes, nil
+}
+
+// QueryInviteCode is the request model
+// used to check if an invite code of a domain was already used
+type QueryInviteCode struct {
+	// Domain is the name of the domain
+	Domain string `json:"domain"`
+	// Code is the invite code
+	Code string `json:"code"`
+}
+
+// Use is a placeholder
+func (q *QueryInviteCode) Use() string {
+	return "invite-code"
+}
+
+// Description is a placeholder
+func (q *QueryInviteCode) Description() string {
+	return "checks if an invite code of a domain was already used"
+}
+
+// Handler implements the local queryHandler
+func (q *QueryInviteCode) Handler() QueryHandlerFunc {
+	return queryInviteCodeHandler
+}
+
+// QueryPath implements queries.QueryHandler
+func (q *QueryInviteCode) QueryPath() string {
+	return "inviteCode"
+}
+
+// Validate implements queries.QueryHandler
+func (q *QueryInviteCode) Validate() error {
+	if q.Domain == "" {
+		return sdkerrors.Wrapf(types.ErrInvalidDomainName, "empty")
+	}
+	q.Domain = idn.Canonical(q.Domain)
+	if q.Code == "" {
+		return sdkerrors.Wrapf(types.ErrInvalidCredential, "empty invite code")
+	}
+	return nil
+}
+
+// QueryInviteCodeResponse is the response
+// returned by the QueryInviteCode query
+type QueryInviteCodeResponse struct {
+	// Used is true if the invite code was already used
+	Used bool `json:"used"`
+}
+
+// queryInviteCodeHandler checks if an invite code was used
+func queryInviteCodeHandler(ctx sdk.Context, _ []string, req abci.RequestQuery, k Keeper) ([]byte, error) {
+	q := new(QueryInviteCode)
+	err := queries.DefaultQueryDecode(req.Data, q)
+	if err != nil {
+		return nil, sdkerrors.Wrap(sdkerrors.ErrJSONUnmarshal, err.Error())
+	}
+	if err = q.Validate(); err != nil {
+		return nil, err
+	}
+	respBytes, err := queries.DefaultQueryEncode(QueryInviteCodeResponse{Used: k.IsInviteCodeUsed(ctx, q.Domain, q.Code)})
+	if err != nil {
+		return nil, sdkerrors.Wrapf(sdkerrors.ErrJSONMarshal, err.Error())
+	}
+	return respBytes, nil
+}
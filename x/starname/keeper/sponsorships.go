@@ -0,0 +1,315 @@
+package keeper
+
+import (
+	"strings"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/store/prefix"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/cosmos-sdk/x/supply"
+	"github.com/iov-one/cosmos-sdk-crud/pkg/crud"
+	crudtypes "github.com/iov-one/cosmos-sdk-crud/pkg/crud/types"
+	"github.com/iov-one/iovns/pkg/idn"
+	"github.com/iov-one/iovns/pkg/queries"
+	"github.com/iov-one/iovns/x/starname/types"
+	abci "github.com/tendermint/tendermint/abci/types"
+)
+
+// SponsorshipStorePrefix is the prefix of the renewal sponsorships store
+var SponsorshipStorePrefix = []byte{0xB}
+
+// SponsoredRenewalQueuePrefix is the prefix of the queue of the sponsored starnames, keys
+// are the big endian time their renewal is due followed by the starname
+var SponsoredRenewalQueuePrefix = []byte{0xD}
+
+// SponsoredRenewalDuePrefix is the prefix of the time the renewal of
+// the queued starnames is due, keys are the starnames
+var SponsoredRenewalDuePrefix = []byte{0xE}
+
+// SponsorshipStore returns the crud.Store used to interact with renewal sponsorships
+func (k Keeper) SponsorshipStore(ctx sdk.Context) crud.Store {
+	return crud.NewStore(ctx, k.StoreKey, k.Cdc, SponsorshipStorePrefix)
+}
+
+// ModuleAddress returns the address of the module account,
+// which holds the coins deposited in the sponsorships
+func (k Keeper) ModuleAddress() sdk.AccAddress {
+	return supply.NewModuleAddress(types.ModuleName)
+}
+
+// GetSponsorship returns the sponsorship of the domain, or of the account if name is not empty, made by sponsor
+func (k Keeper) GetSponsorship(ctx sdk.Context, domain, name string, sponsor sdk.AccAddress) (types.Sponsorship, bool) {
+	sponsorship := new(types.Sponsorship)
+	if !k.SponsorshipStore(ctx).Read((&types.Sponsorship{Domain: domain, Name: name, Sponsor: sponsor}).PrimaryKey(), sponsorship) {
+		return types.Sponsorship{}, false
+	}
+	return *sponsorship, true
+}
+
+// SetSponsorship saves the provided sponsorship and queues the
+// renewal of its starname if it is not queued already
+func (k Keeper) SetSponsorship(ctx sdk.Context, sponsorship types.Sponsorship) {
+	store := k.SponsorshipStore(ctx)
+	if store.Read(sponsorship.PrimaryKey(), new(types.Sponsorship)) {
+		store.Update(&sponsorship)
+	} else {
+		store.Create(&sponsorship)
+	}
+	starname := sponsorship.Starname()
+	if k.sponsoredRenewalDue(ctx).Has([]byte(starname)) {
+		return
+	}
+	// starnames which do not exist are queued right away so that their sponsorships get closed
+	var validUntil int64
+	if sponsorship.IsDomainSponsorship() {
+		domain := new(types.Domain)
+		if k.DomainStore(ctx).Read((&types.Domain{Name: sponsorship.Domain}).PrimaryKey(), domain) {
+			validUntil = domain.ValidUntil
+		}
+	} else {
+		account := new(types.Account)
+		if k.AccountStore(ctx).Read((&types.Account{Domain: sponsorship.Domain, Name: &sponsorship.Name}).PrimaryKey(), account) {
+			validUntil = account.ValidUntil
+		}
+	}
+	k.QueueSponsoredRenewal(ctx, starname, SponsoredRenewalDueTime(validUntil))
+}
+
+// DeleteSponsorship removes the provided sponsorship
+func (k Keeper) DeleteSponsorship(ctx sdk.Context, sponsorship types.Sponsorship) {
+	k.SponsorshipStore(ctx).Delete(sponsorship.PrimaryKey())
+}
+
+// IterateSponsorships calls do on each sponsorship
+func (k Keeper) IterateSponsorships(ctx sdk.Context, do func(sponsorship types.Sponsorship) bool) {
+	store := k.SponsorshipStore(ctx)
+	store.IterateKeys(func(pk crudtypes.PrimaryKey) bool {
+		sponsorship := new(types.Sponsorship)
+		store.Read(pk, sponsorship)
+		return do(*sponsorship)
+	})
+}
+
+// GetStarnameSponsorships returns the sponsorships of a domain, or of an account if name is not empty
+func (k Keeper) GetStarnameSponsorships(ctx sdk.Context, domain, name string) []types.Sponsorship {
+	var sponsorships []types.Sponsorship
+	filter := k.SponsorshipStore(ctx).Filter(&types.Sponsorship{Domain: domain, Name: name})
+	for ; filter.Valid(); filter.Next() {
+		sponsorship := new(types.Sponsorship)
+		filter.Read(sponsorship)
+		sponsorships = append(sponsorships, *sponsorship)
+	}
+	return sponsorships
+}
+
+// sponsoredRenewalQueue returns the store of the sponsored renewals queue
+func (k Keeper) sponsoredRenewalQueue(ctx sdk.Context) prefix.Store {
+	return prefix.NewStore(ctx.KVStore(k.StoreKey), SponsoredRenewalQueuePrefix)
+}
+
+// sponsoredRenewalDue returns the store of the due time of the queued sponsored renewals
+func (k Keeper) sponsoredRenewalDue(ctx sdk.Context) prefix.Store {
+	return prefix.NewStore(ctx.KVStore(k.StoreKey), SponsoredRenewalDuePrefix)
+}
+
+// sponsoredRenewalQueueKey returns the sponsored renewals queue key of the starname
+func sponsoredRenewalQueueKey(due []byte, starname string) []byte {
+	return append(append([]byte{}, due...), starname...)
+}
+
+// SponsoredRenewalDueTime returns the time the renewal of
+// a starname expiring at validUntil enters the renewal window
+func SponsoredRenewalDueTime(validUntil int64) time.Time {
+	return time.Unix(validUntil, 0).Add(-types.SponsoredRenewalWindow)
+}
+
+// QueueSponsoredRenewal queues the renewal of the starname at the
+// provided time, replacing its previous position in the queue, if any
+func (k Keeper) QueueSponsoredRenewal(ctx sdk.Context, starname string, due time.Time) {
+	k.dequeueSponsoredRenewal(ctx, starname)
+	unix := due.Unix()
+	if unix < 0 {
+		unix = 0
+	}
+	b := sdk.Uint64ToBigEndian(uint64(unix))
+	k.sponsoredRenewalQueue(ctx).Set(sponsoredRenewalQueueKey(b, starname), []byte{0x1})
+	k.sponsoredRenewalDue(ctx).Set([]byte(starname), b)
+}
+
+// dequeueSponsoredRenewal removes the starname from the sponsored renewals queue
+func (k Keeper) dequeueSponsoredRenewal(ctx sdk.Context, starname string) {
+	dueStore := k.sponsoredRenewalDue(ctx)
+	due := dueStore.Get([]byte(starname))
+	if due == nil {
+		return
+	}
+	k.sponsoredRenewalQueue(ctx).Delete(sponsoredRenewalQueueKey(due, starname))
+	dueStore.Delete([]byte(starname))
+}
+
+// requeueSponsoredRenewal moves the starname in the sponsored renewals queue
+// after its expiry changed, starnames which are not queued are not sponsored
+func (k Keeper) requeueSponsoredRenewal(ctx sdk.Context, starname string, validUntil int64) {
+	if !k.sponsoredRenewalDue(ctx).Has([]byte(starname)) {
+		return
+	}
+	k.QueueSponsoredRenewal(ctx, starname, SponsoredRenewalDueTime(validUntil))
+}
+
+// DequeueDueSponsoredRenewals removes from the queue and returns
+// the starnames whose renewal is due at the current block time
+func (k Keeper) DequeueDueSponsoredRenewals(ctx sdk.Context) []string {
+	queue := k.sponsoredRenewalQueue(ctx)
+	end := sdk.Uint64ToBigEndian(uint64(ctx.BlockTime().Unix()) + 1)
+	iterator := queue.Iterator(nil, end)
+	var starnames []string
+	for ; iterator.Valid(); iterator.Next() {
+		starnames = append(starnames, string(iterator.Key()[8:]))
+	}
+	iterator.Close()
+	for _, starname := range starnames {
+		k.dequeueSponsoredRenewal(ctx, starname)
+	}
+	return starnames
+}
+
+// DepositSponsorship moves amount from the sponsor to the module
+// account and adds it to the balance of the sponsorship
+func (k Keeper) DepositSponsorship(ctx sdk.Context, sponsorship types.Sponsorship, amount sdk.Coins) (types.Sponsorship, error) {
+	if err := k.SupplyKeeper.SendCoinsFromAccountToModule(ctx, sponsorship.Sponsor, types.ModuleName, amount); err != nil {
+		return types.Sponsorship{}, err
+	}
+	sponsorship.Balance = sponsorship.Balance.Add(amount...)
+	k.SetSponsorship(ctx, sponsorship)
+	return sponsorship, nil
+}
+
+// WithdrawSponsorship sends amount from the module account back to the sponsor and removes
+// it from the balance of the sponsorship, which is deleted once its balance is zero
+func (k Keeper) WithdrawSponsorship(ctx sdk.Context, sponsorship types.Sponsorship, amount sdk.Coins) (types.Sponsorship, error) {
+	balance, negative := sponsorship.Balance.SafeSub(amount)
+	if negative {
+		return types.Sponsorship{}, sdkerrors.Wrapf(sdkerrors.ErrInsufficientFunds, "balance %s is less than %s", sponsorship.Balance, amount)
+	}
+	if !amount.Empty() {
+		if err := k.SupplyKeeper.SendCoinsFromModuleToAccount(ctx, types.ModuleName, sponsorship.Sponsor, amount); err != nil {
+			return types.Sponsorship{}, err
+		}
+	}
+	sponsorship.Balance = balance
+	if balance.IsZero() {
+		k.DeleteSponsorship(ctx, sponsorship)
+		return sponsorship, nil
+	}
+	k.SetSponsorship(ctx, sponsorship)
+	return sponsorship, nil
+}
+
+// QuerySponsorships is the request model used to get
+// the sponsorships of a sponsor or of a starname
+type QuerySponsorships struct {
+	// Sponsor is the address of the sponsor, optional
+	Sponsor sdk.AccAddress `json:"sponsor"`
+	// Starname is either a domain name or an account in name*domain format, optional
+	Starname string `json:"starname"`
+	// ResultsPerPage is the number of results displayed in a page
+	ResultsPerPage int `json:"results_per_page"`
+	// Offset is the page number
+	Offset int `json:"offset"`
+}
+
+// Use is a placeholder
+func (q *QuerySponsorships) Use() string {
+	return "sponsorships"
+}
+
+// Description is a placeholder
+func (q *QuerySponsorships) Description() string {
+	return "gets the renewal sponsorships of a sponsor or of a domain or an account"
+}
+
+// Handler implements the local queryHandler
+func (q *QuerySponsorships) Handler() QueryHandlerFunc {
+	return querySponsorshipsHandler
+}
+
+// QueryPath implements queries.QueryHandler
+func (q *QuerySponsorships) QueryPath() string {
+	return "sponsorships"
+}
+
+// Validate implements queries.QueryHandler
+func (q *QuerySponsorships) Validate() error {
+	if q.Sponsor.Empty() && q.Starname == "" {
+		return sdkerrors.Wrapf(types.ErrInvalidRequest, "either sponsor or starname must be provided")
+	}
+	if strings.Count(q.Starname, types.StarnameSeparator) > 1 {
+		return types.ErrStarnameMultipleSeparator
+	}
+	q.Starname = idn.Canonical(q.Starname)
+	if q.ResultsPerPage == 0 {
+		q.ResultsPerPage = 100
+	}
+	if q.Offset == 0 {
+		q.Offset = 1
+	}
+	return nil
+}
+
+// filter returns the sponsorship used to filter the store
+func (q *QuerySponsorships) filter() *types.Sponsorship {
+	filter := &types.Sponsorship{Domain: q.Starname, Sponsor: q.Sponsor}
+	if sname := strings.Split(q.Starname, types.StarnameSeparator); len(sname) == 2 {
+		filter.Name, filter.Domain = sname[0], sname[1]
+	}
+	return filter
+}
+
+// QuerySponsorshipsResponse is the response
+// returned by the QuerySponsorships query
+type QuerySponsorshipsResponse struct {
+	// Sponsorships contains the sponsorships found
+	Sponsorships []types.Sponsorship `json:"sponsorships"`
+}
+
+// querySponsorshipsHandler returns the sponsorships of a sponsor or of a starname
+func querySponsorshipsHandler(ctx sdk.Context, _ []string, req abci.RequestQuery, k Keeper) ([]byte, error) {
+	q := new(QuerySponsorships)
+	err := queries.DefaultQueryDecode(req.Data, q)
+	if err != nil {
+		return nil, sdkerrors.Wrapf(sdkerrors.ErrJSONUnmarshal, err.Error())
+	}
+	// validate
+	if err := q.Validate(); err != nil {
+		return nil, err
+	}
+	// calculate index range
+	indexStart := q.ResultsPerPage*q.Offset - q.ResultsPerPage // start index
+	indexEnd := indexStart + q.ResultsPerPage - 1              // index end
+	i := 0
+	// iterate sponsorships
+	sponsorships := make([]types.Sponsorship, 0, q.ResultsPerPage)
+	filter := k.SponsorshipStore(ctx).Filter(q.filter())
+	for {
+		if !filter.Valid() {
+			break
+		}
+		if i >= indexStart {
+			sponsorship := new(types.Sponsorship)
+			filter.Read(sponsorship)
+			sponsorships = append(sponsorships, *sponsorship)
+		}
+		if i == indexEnd {
+			break
+		}
+		filter.Next()
+		i++
+	}
+	// return response
+	b, err := queries.DefaultQueryEncode(QuerySponsorshipsResponse{Sponsorships: sponsorships})
+	if err != nil {
+		return nil, sdkerrors.Wrapf(sdkerrors.ErrJSONMarshal, err.Error())
+	}
+	return b, nil
+}
@@ -85,6 +85,8 @@ func NewTestKeeper(t testing.TB, isCheckTx bool) (Keeper, sdk.Context, *Mocks) {
 	confKeeper := configuration.NewKeeper(cdc, configurationStoreKey, subspace.NewSubspace(cdc, nil, nil, "test"))
 	// create context
 	ctx := sdk.NewContext(ms, tmtypes.Header{Time: time.Now()}, isCheckTx, log.NewNopLogger())
+	// set a configuration with the history disabled, tests set the one they need
+	confKeeper.SetConfig(ctx, configuration.Config{Configurer: AliceKey})
 	// create domain.Keeper
 	return NewKeeper(cdc, domainStoreKey, confKeeper, mocks.Supply.Mock(), nil), ctx, mocks
 }
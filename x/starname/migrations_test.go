@@ -0,0 +1,163 @@
+package starname
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/iov-one/iovns/x/starname/keeper"
+)
+
+// legacyDomain is a domain record as stored at store version 0, the legacy
+// types are frozen so that the fixtures keep the encoding of that version
+// whatever happens to types.Domain and types.Account
+type legacyDomain struct {
+	Name       string         `json:"name"`
+	Admin      sdk.AccAddress `json:"admin"`
+	ValidUntil int64          `json:"valid_until"`
+	Type       string         `json:"type"`
+	Broker     sdk.AccAddress `json:"broker"`
+}
+
+// legacyResource is a resource of a legacy account
+type legacyResource struct {
+	URI      string `json:"uri"`
+	Resource string `json:"resource"`
+}
+
+// legacyAccount is an account as stored at store version 0
+type legacyAccount struct {
+	Domain       string           `json:"domain"`
+	Name         *string          `json:"name"`
+	Owner        sdk.AccAddress   `json:"owner"`
+	ValidUntil   int64            `json:"valid_until"`
+	Resources    []legacyResource `json:"resources"`
+	Certificates [][]byte         `json:"certificates"`
+	Broker       sdk.AccAddress   `json:"broker"`
+	MetadataURI  string           `json:"metadata_uri"`
+}
+
+// legacyAccountRecord is the envelope of the stored accounts, which records
+// whether the name is nil since amino does not tell it apart from the empty name
+type legacyAccountRecord struct {
+	Underlying *legacyAccount
+	NameNil    bool
+}
+
+// loadLegacyGenesis writes the domains and accounts of the genesis file in the store
+// the way a store at version 0 holds them: records encoded with the legacy types
+// under their crud object keys, without indexes, account counts and store version
+func loadLegacyGenesis(t *testing.T, ctx sdk.Context, k Keeper, file string) {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var data struct {
+		Domains  []legacyDomain  `json:"domains"`
+		Accounts []legacyAccount `json:"accounts"`
+	}
+	if err = json.Unmarshal(b, &data); err != nil {
+		t.Fatal(err)
+	}
+	cdc := codec.New()
+	store := ctx.KVStore(k.StoreKey)
+	// domains were stored under 0x2 and accounts under 0x1, followed by the crud object prefix 0x0
+	for _, domain := range data.Domains {
+		store.Set(append([]byte{0x2, 0x0}, domain.Name...), cdc.MustMarshalBinaryBare(domain))
+	}
+	for i := range data.Accounts {
+		account := &data.Accounts[i]
+		key := append([]byte{0x1, 0x0}, account.Domain+"*"+*account.Name...)
+		store.Set(key, cdc.MustMarshalBinaryBare(legacyAccountRecord{Underlying: account, NameNil: account.Name == nil}))
+	}
+}
+
+// TestMigrations loads the genesis of each store version, migrates it to
+// the latest version and compares the result with the expected state
+func TestMigrations(t *testing.T) {
+	cases := map[string]struct {
+		Genesis  string
+		Expected string
+	}{
+		"v0 to latest": {
+			Genesis:  "v0.json",
+			Expected: "v1.json",
+		},
+		"names to canonical form": {
+			Genesis:  "decomposed_names.json",
+			Expected: "canonical_names.json",
+		},
+		"closed domain accounts follow the domain expiration": {
+			Genesis:  "closed_domain_expirations.json",
+			Expected: "closed_domain_expirations_migrated.json",
+		},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			k, ctx, _ := keeper.NewTestKeeper(t, false)
+			loadLegacyGenesis(t, ctx, k, filepath.Join("testdata", "migrations", c.Genesis))
+			migrations := k.Migrations()
+			if err := migrations.Migrate(ctx); err != nil {
+				t.Fatal(err)
+			}
+			if migrations.Version(ctx) != migrations.LatestVersion() {
+				t.Fatalf("unexpected store version: %d", migrations.Version(ctx))
+			}
+			// compare state
+			expected, err := ioutil.ReadFile(filepath.Join("testdata", "migrations", c.Expected))
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := json.Marshal(ExportGenesis(ctx, k))
+			if err != nil {
+				t.Fatal(err)
+			}
+			compacted := new(bytes.Buffer)
+			if err = json.Compact(compacted, expected); err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(compacted.Bytes(), got) {
+				t.Fatalf("unexpected state:\nwant: %s\ngot:  %s", compacted, got)
+			}
+			// check indexes were rebuilt and accounts counted
+			for _, invariant := range []sdk.Invariant{
+				keeper.AccountDomainInvariant(k),
+				keeper.DomainEmptyAccountInvariant(k),
+				keeper.SecondaryIndexesInvariant(k),
+				keeper.AccountCountInvariant(k),
+			} {
+				if msg, broken := invariant(ctx); broken {
+					t.Fatal(msg)
+				}
+			}
+		})
+	}
+}
+
+// TestMigrations_canonicalNameCollisions checks the migration to canonical names
+// reports the names which have the same canonical form and leaves the store untouched
+func TestMigrations_canonicalNameCollisions(t *testing.T) {
+	k, ctx, _ := keeper.NewTestKeeper(t, false)
+	loadLegacyGenesis(t, ctx, k, filepath.Join("testdata", "migrations", "colliding_names.json"))
+	migrations := k.Migrations()
+	err := migrations.Migrate(ctx)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	for _, collision := range []string{
+		`"caf\u00e9": "caf\u00e9", "cafe\u0301"`,
+		`"no\u00ebl*iov": "no\u00ebl*iov", "noe\u0308l*iov"`,
+	} {
+		if !strings.Contains(err.Error(), collision) {
+			t.Fatalf("collision %s not reported in: %s", collision, err)
+		}
+	}
+	if migrations.Version(ctx) != 0 {
+		t.Fatalf("unexpected store version: %d", migrations.Version(ctx))
+	}
+}
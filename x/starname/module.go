@@ -2,23 +2,28 @@ package starname
 
 import (
 	"encoding/json"
+	"math/rand"
 
 	"github.com/cosmos/cosmos-sdk/client/context"
 	"github.com/cosmos/cosmos-sdk/codec"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/types/module"
+	"github.com/cosmos/cosmos-sdk/x/simulation"
 	"github.com/gorilla/mux"
+	simutil "github.com/iov-one/iovns/pkg/simulation"
 	"github.com/iov-one/iovns/x/starname/client/cli"
 	"github.com/iov-one/iovns/x/starname/client/rest"
 	"github.com/iov-one/iovns/x/starname/keeper"
+	starnamesim "github.com/iov-one/iovns/x/starname/simulation"
 	"github.com/iov-one/iovns/x/starname/types"
 	"github.com/spf13/cobra"
 	abci "github.com/tendermint/tendermint/abci/types"
 )
 
 var (
-	_ module.AppModule      = AppModule{}
-	_ module.AppModuleBasic = AppModuleBasic{}
+	_ module.AppModule           = AppModule{}
+	_ module.AppModuleBasic      = AppModuleBasic{}
+	_ module.AppModuleSimulation = AppModule{}
 )
 
 type AppModuleBasic struct{}
@@ -62,23 +67,27 @@ func (AppModuleBasic) GetTxCmd(cdc *codec.Codec) *cobra.Command {
 }
 
 // NewAppModule creates a new AppModule Object
-func NewAppModule(k Keeper) AppModule {
+func NewAppModule(k Keeper, ak simutil.AccountKeeper) AppModule {
 	return AppModule{
 		AppModuleBasic: AppModuleBasic{},
 		keeper:         k,
+		accountKeeper:  ak,
 	}
 }
 
 type AppModule struct {
 	AppModuleBasic
-	keeper Keeper
+	keeper        Keeper
+	accountKeeper simutil.AccountKeeper
 }
 
 func (AppModule) Name() string {
 	return types.ModuleName
 }
 
-func (am AppModule) RegisterInvariants(ir sdk.InvariantRegistry) {}
+func (am AppModule) RegisterInvariants(ir sdk.InvariantRegistry) {
+	keeper.RegisterInvariants(ir, am.keeper)
+}
 
 func (am AppModule) Route() string {
 	return types.RouterKey
@@ -99,7 +108,8 @@ func (am AppModule) BeginBlock(_ sdk.Context, _ abci.RequestBeginBlock) {
 
 }
 
-func (am AppModule) EndBlock(sdk.Context, abci.RequestEndBlock) []abci.ValidatorUpdate {
+func (am AppModule) EndBlock(ctx sdk.Context, _ abci.RequestEndBlock) []abci.ValidatorUpdate {
+	EndBlocker(ctx, am.keeper)
 	return []abci.ValidatorUpdate{}
 }
 
@@ -114,3 +124,28 @@ func (am AppModule) ExportGenesis(ctx sdk.Context) json.RawMessage {
 	gs := ExportGenesis(ctx, am.keeper)
 	return types.ModuleCdc.MustMarshalJSON(gs)
 }
+
+// GenerateGenesisState creates a randomized GenesisState of the starname module
+func (AppModule) GenerateGenesisState(simState *module.SimulationState) {
+	starnamesim.RandomizedGenState(simState)
+}
+
+// ProposalContents doesn't return any content functions for governance proposals
+func (AppModule) ProposalContents(_ module.SimulationState) []simulation.WeightedProposalContent {
+	return nil
+}
+
+// RandomizedParams returns nil because the starname module has no simulated params
+func (AppModule) RandomizedParams(_ *rand.Rand) []simulation.ParamChange {
+	return nil
+}
+
+// RegisterStoreDecoder registers a decoder for the starname module store
+func (AppModule) RegisterStoreDecoder(sdr sdk.StoreDecoderRegistry) {
+	sdr[types.DomainStoreKey] = starnamesim.DecodeStore
+}
+
+// WeightedOperations returns the starname module operations with their respective weights
+func (am AppModule) WeightedOperations(simState module.SimulationState) []simulation.WeightedOperation {
+	return starnamesim.WeightedOperations(simState.AppParams, simState.Cdc, am.accountKeeper, am.keeper, NewHandler(am.keeper))
+}
@@ -0,0 +1,217 @@
+package starname
+
+import (
+	"fmt"
+	"strconv"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/iov-one/iovns/x/starname/controllers/account"
+	"github.com/iov-one/iovns/x/starname/controllers/domain"
+	"github.com/iov-one/iovns/x/starname/controllers/fees"
+	"github.com/iov-one/iovns/x/starname/keeper"
+	"github.com/iov-one/iovns/x/starname/keeper/executor"
+	"github.com/iov-one/iovns/x/starname/types"
+)
+
+// offerControllers runs on the offered domain or account the same checks as the immediate
+// transfer made by the offer owner, the account controller is nil for domain offers
+func offerControllers(ctx sdk.Context, k keeper.Keeper, offer types.TransferOffer) (*domain.Domain, *account.Account, error) {
+	domainCtrl := domain.NewController(ctx, k, offer.Domain)
+	if offer.IsDomainOffer() {
+		err := domainCtrl.
+			MustExist().
+			Admin(offer.Owner).
+			NotExpired().
+			Transferable(offer.TransferFlag).
+			Validate()
+		return domainCtrl, nil, err
+	}
+	if err := domainCtrl.MustExist().NotExpired().Validate(); err != nil {
+		return nil, nil, err
+	}
+	accountCtrl := account.NewController(ctx, k, offer.Domain, *offer.Name).
+		WithDomainController(domainCtrl)
+	err := accountCtrl.
+		MustExist().
+		NotExpired().
+		TransferableBy(offer.Owner).
+		ResettableBy(offer.Owner, offer.Reset).
+		Validate()
+	return domainCtrl, accountCtrl, err
+}
+
+// getTransferOffer returns the offer with the provided ID
+// if it exists and if addr is its recipient or its owner
+func getTransferOffer(ctx sdk.Context, k keeper.Keeper, id uint64, addr sdk.AccAddress, recipient bool) (types.TransferOffer, error) {
+	offer, ok := k.GetTransferOffer(ctx, id)
+	if !ok {
+		return types.TransferOffer{}, sdkerrors.Wrapf(types.ErrTransferOfferDoesNotExist, "not found: %d", id)
+	}
+	if recipient && !offer.Recipient.Equals(addr) {
+		return types.TransferOffer{}, sdkerrors.Wrapf(types.ErrUnauthorizedTransferOffer, "%s is not the recipient of offer %d", addr, id)
+	}
+	if !recipient && !offer.Owner.Equals(addr) {
+		return types.TransferOffer{}, sdkerrors.Wrapf(types.ErrUnauthorizedTransferOffer, "%s is not the owner of offer %d", addr, id)
+	}
+	return offer, nil
+}
+
+// createTransferOffer checks and saves a new transfer offer, it is shared by domain and account offers
+func createTransferOffer(ctx sdk.Context, k keeper.Keeper, msg types.MsgWithFeePayer, offer types.TransferOffer) (*sdk.Result, error) {
+	domainCtrl, _, err := offerControllers(ctx, k, offer)
+	if err != nil {
+		return nil, err
+	}
+	if offer.Expired(ctx.BlockTime().Unix()) {
+		return nil, sdkerrors.Wrapf(types.ErrTransferOfferExpired, "expiry %d is not after the block time", offer.Expiry)
+	}
+	if pending, ok := k.GetStarnameTransferOffer(ctx, offer.Starname()); ok {
+		return nil, sdkerrors.Wrapf(types.ErrTransferOfferExists, "offer %d of %s is pending", pending.ID, offer.Starname())
+	}
+	// collect fees
+	feeCtrl := fees.NewController(ctx, k, domainCtrl.Domain())
+	fee := feeCtrl.GetFee(msg)
+	if err := k.CollectFees(ctx, msg, fee); err != nil {
+		return nil, sdkerrors.Wrap(err, "unable to collect fees")
+	}
+	offer = k.CreateTransferOffer(ctx, offer)
+	// success
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			sdk.EventTypeMessage,
+			sdk.NewAttribute(sdk.AttributeKeyModule, types.ModuleName),
+			sdk.NewAttribute(sdk.AttributeKeySender, offer.Owner.String()),
+			sdk.NewAttribute(sdk.AttributeKeyAction, msg.Type()),
+			sdk.NewAttribute(types.AttributeKeyTransferOfferID, strconv.FormatUint(offer.ID, 10)),
+			sdk.NewAttribute(types.AttributeKeyDomainName, offer.Domain),
+			sdk.NewAttribute(types.AttributeKeyOwner, offer.Owner.String()),
+			sdk.NewAttribute(types.AttributeKeyTransferOfferRecipient, offer.Recipient.String()),
+			sdk.NewAttribute(types.AttributeKeyTransferOfferPrice, offer.Price.String()),
+			sdk.NewAttribute(types.AttributeKeyTransferOfferExpiry, strconv.FormatInt(offer.Expiry, 10)),
+		),
+	)
+	return &sdk.Result{
+		Data:   sdk.Uint64ToBigEndian(offer.ID),
+		Events: ctx.EventManager().Events(),
+	}, nil
+}
+
+// handlerMsgOfferDomainTransfer creates an offer to transfer a domain
+func handlerMsgOfferDomainTransfer(ctx sdk.Context, k keeper.Keeper, msg *types.MsgOfferDomainTransfer) (*sdk.Result, error) {
+	return createTransferOffer(ctx, k, msg, msg.Offer())
+}
+
+// handlerMsgOfferAccountTransfer creates an offer to transfer an account
+func handlerMsgOfferAccountTransfer(ctx sdk.Context, k keeper.Keeper, msg *types.MsgOfferAccountTransfer) (*sdk.Result, error) {
+	return createTransferOffer(ctx, k, msg, msg.Offer())
+}
+
+// handlerMsgAcceptTransferOffer applies the transfer of the offer after paying its price to the owner
+func handlerMsgAcceptTransferOffer(ctx sdk.Context, k keeper.Keeper, msg *types.MsgAcceptTransferOffer) (*sdk.Result, error) {
+	offer, err := getTransferOffer(ctx, k, msg.ID, msg.Recipient, true)
+	if err != nil {
+		return nil, err
+	}
+	if offer.Expired(ctx.BlockTime().Unix()) {
+		return nil, sdkerrors.Wrapf(types.ErrTransferOfferExpired, "offer %d expired at %d", offer.ID, offer.Expiry)
+	}
+	// the offered domain or account must still be transferable by the offer owner
+	domainCtrl, accountCtrl, err := offerControllers(ctx, k, offer)
+	if err != nil {
+		return nil, err
+	}
+	// collect fees
+	feeCtrl := fees.NewController(ctx, k, domainCtrl.Domain())
+	fee := feeCtrl.GetFee(msg)
+	if err := k.CollectFees(ctx, msg, fee); err != nil {
+		return nil, sdkerrors.Wrap(err, "unable to collect fees")
+	}
+	// pay the owner
+	if err := k.PayTransferOffer(ctx, offer); err != nil {
+		return nil, sdkerrors.Wrap(err, "unable to pay transfer offer price")
+	}
+	k.DeleteTransferOffer(ctx, offer)
+	// transfer domain or account
+	if offer.IsDomainOffer() {
+		executor.NewDomain(ctx, k, domainCtrl.Domain()).Transfer(offer.TransferFlag, offer.Recipient)
+	} else {
+		executor.NewAccount(ctx, k, accountCtrl.Account()).Transfer(offer.Recipient, offer.Reset)
+	}
+	// success
+	attributes := []sdk.Attribute{
+		sdk.NewAttribute(sdk.AttributeKeyModule, types.ModuleName),
+		sdk.NewAttribute(sdk.AttributeKeySender, msg.Recipient.String()),
+		sdk.NewAttribute(sdk.AttributeKeyAction, msg.Type()),
+		sdk.NewAttribute(types.AttributeKeyTransferOfferID, strconv.FormatUint(offer.ID, 10)),
+		sdk.NewAttribute(types.AttributeKeyDomainName, offer.Domain),
+		sdk.NewAttribute(types.AttributeKeyOwner, offer.Owner.String()),
+		sdk.NewAttribute(types.AttributeKeyTransferOfferPrice, offer.Price.String()),
+	}
+	if offer.IsDomainOffer() {
+		attributes = append(attributes,
+			sdk.NewAttribute(types.AttributeKeyTransferDomainNewOwner, offer.Recipient.String()),
+			sdk.NewAttribute(types.AttributeKeyTransferDomainFlag, fmt.Sprintf("%d", offer.TransferFlag)),
+		)
+	} else {
+		attributes = append(attributes,
+			sdk.NewAttribute(types.AttributeKeyAccountName, *offer.Name),
+			sdk.NewAttribute(types.AttributeKeyTransferAccountNewOwner, offer.Recipient.String()),
+			sdk.NewAttribute(types.AttributeKeyTransferAccountReset, strconv.FormatBool(offer.Reset)),
+		)
+	}
+	ctx.EventManager().EmitEvent(sdk.NewEvent(sdk.EventTypeMessage, attributes...))
+	return &sdk.Result{
+		Events: ctx.EventManager().Events(),
+	}, nil
+}
+
+// handlerMsgRejectTransferOffer removes an offer on behalf of its recipient
+func handlerMsgRejectTransferOffer(ctx sdk.Context, k keeper.Keeper, msg *types.MsgRejectTransferOffer) (*sdk.Result, error) {
+	offer, err := getTransferOffer(ctx, k, msg.ID, msg.Recipient, true)
+	if err != nil {
+		return nil, err
+	}
+	return removeTransferOffer(ctx, k, msg, offer, msg.Recipient)
+}
+
+// handlerMsgCancelTransferOffer removes an offer on behalf of its owner
+func handlerMsgCancelTransferOffer(ctx sdk.Context, k keeper.Keeper, msg *types.MsgCancelTransferOffer) (*sdk.Result, error) {
+	offer, err := getTransferOffer(ctx, k, msg.ID, msg.Owner, false)
+	if err != nil {
+		return nil, err
+	}
+	return removeTransferOffer(ctx, k, msg, offer, msg.Owner)
+}
+
+// removeTransferOffer collects the fees of msg and removes the offer
+func removeTransferOffer(ctx sdk.Context, k keeper.Keeper, msg types.MsgWithFeePayer, offer types.TransferOffer, sender sdk.AccAddress) (*sdk.Result, error) {
+	// offers are removed along with their domain so it exists
+	domainCtrl := domain.NewController(ctx, k, offer.Domain)
+	if err := domainCtrl.MustExist().Validate(); err != nil {
+		return nil, err
+	}
+	// collect fees
+	feeCtrl := fees.NewController(ctx, k, domainCtrl.Domain())
+	fee := feeCtrl.GetFee(msg)
+	if err := k.CollectFees(ctx, msg, fee); err != nil {
+		return nil, sdkerrors.Wrap(err, "unable to collect fees")
+	}
+	k.DeleteTransferOffer(ctx, offer)
+	// success
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			sdk.EventTypeMessage,
+			sdk.NewAttribute(sdk.AttributeKeyModule, types.ModuleName),
+			sdk.NewAttribute(sdk.AttributeKeySender, sender.String()),
+			sdk.NewAttribute(sdk.AttributeKeyAction, msg.Type()),
+			sdk.NewAttribute(types.AttributeKeyTransferOfferID, strconv.FormatUint(offer.ID, 10)),
+			sdk.NewAttribute(types.AttributeKeyDomainName, offer.Domain),
+			sdk.NewAttribute(types.AttributeKeyOwner, offer.Owner.String()),
+			sdk.NewAttribute(types.AttributeKeyTransferOfferRecipient, offer.Recipient.String()),
+		),
+	)
+	return &sdk.Result{
+		Events: ctx.EventManager().Events(),
+	}, nil
+}
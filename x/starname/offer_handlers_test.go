@@ -0,0 +1,492 @@
+package starname
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/iov-one/iovns/pkg/utils"
+	"github.com/iov-one/iovns/x/starname/keeper"
+	"github.com/iov-one/iovns/x/starname/keeper/executor"
+	"github.com/iov-one/iovns/x/starname/types"
+)
+
+func Test_handlerTransferOffers(t *testing.T) {
+	// createDomain creates an open domain owned by alice with an account owned by bob
+	createDomain := func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+		executor.NewDomain(ctx, k, types.Domain{
+			Name:       "test",
+			Admin:      keeper.AliceKey,
+			ValidUntil: utils.TimeToSeconds(ctx.BlockTime().Add(1000 * time.Hour)),
+			Type:       types.OpenDomain,
+		}).Create()
+		executor.NewAccount(ctx, k, types.Account{
+			Domain:     "test",
+			Name:       utils.StrPtr("test"),
+			Owner:      keeper.BobKey,
+			ValidUntil: utils.TimeToSeconds(ctx.BlockTime().Add(1000 * time.Hour)),
+		}).Create()
+	}
+	// offerDomain creates the offer of domain test from alice to charlie
+	offerDomain := func(t *testing.T, k keeper.Keeper, ctx sdk.Context, price sdk.Coins, expiry int64) {
+		_, err := handlerMsgOfferDomainTransfer(ctx, k, &types.MsgOfferDomainTransfer{
+			Domain:       "test",
+			Owner:        keeper.AliceKey,
+			Recipient:    keeper.CharlieKey,
+			TransferFlag: types.TransferResetNone,
+			Price:        price,
+			Expiry:       expiry,
+		})
+		if err != nil {
+			t.Fatalf("handlerMsgOfferDomainTransfer() got error: %s", err)
+		}
+	}
+	// offerAccount creates the offer of account test*test from bob to charlie
+	offerAccount := func(t *testing.T, k keeper.Keeper, ctx sdk.Context) {
+		_, err := handlerMsgOfferAccountTransfer(ctx, k, &types.MsgOfferAccountTransfer{
+			Domain:    "test",
+			Name:      "test",
+			Owner:     keeper.BobKey,
+			Recipient: keeper.CharlieKey,
+		})
+		if err != nil {
+			t.Fatalf("handlerMsgOfferAccountTransfer() got error: %s", err)
+		}
+	}
+	readDomain := func(k keeper.Keeper, ctx sdk.Context) types.Domain {
+		domain := new(types.Domain)
+		k.DomainStore(ctx).Read((&types.Domain{Name: "test"}).PrimaryKey(), domain)
+		return *domain
+	}
+	readAccount := func(k keeper.Keeper, ctx sdk.Context) types.Account {
+		account := new(types.Account)
+		k.AccountStore(ctx).Read((&types.Account{Domain: "test", Name: utils.StrPtr("test")}).PrimaryKey(), account)
+		return *account
+	}
+	price := sdk.NewCoins(sdk.NewCoin("testcoin", sdk.NewInt(10)))
+	cases := map[string]keeper.SubTest{
+		"success domain offer": {
+			BeforeTest: createDomain,
+			Test: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				offerDomain(t, k, ctx, price, 0)
+			},
+			AfterTest: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				offer, ok := k.GetStarnameTransferOffer(ctx, "test")
+				if !ok {
+					t.Fatal("transfer offer not found")
+				}
+				if offer.ID != 1 || !offer.Recipient.Equals(keeper.CharlieKey) || !offer.Price.IsEqual(price) {
+					t.Fatalf("unexpected transfer offer: %+v", offer)
+				}
+				// ownership is unchanged until accepted
+				if domain := readDomain(k, ctx); !domain.Admin.Equals(keeper.AliceKey) {
+					t.Fatalf("unexpected domain admin: %s", domain.Admin)
+				}
+			},
+		},
+		"fail offer by non admin": {
+			BeforeTest: createDomain,
+			Test: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				_, err := handlerMsgOfferDomainTransfer(ctx, k, &types.MsgOfferDomainTransfer{
+					Domain:    "test",
+					Owner:     keeper.BobKey,
+					Recipient: keeper.CharlieKey,
+				})
+				if !errors.Is(err, types.ErrUnauthorized) {
+					t.Fatalf("handlerMsgOfferDomainTransfer() want: %s, got: %s", types.ErrUnauthorized, err)
+				}
+			},
+		},
+		"fail offer already pending": {
+			BeforeTest: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				createDomain(t, k, ctx, mocks)
+				offerDomain(t, k, ctx, nil, 0)
+			},
+			Test: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				_, err := handlerMsgOfferDomainTransfer(ctx, k, &types.MsgOfferDomainTransfer{
+					Domain:       "test",
+					Owner:        keeper.AliceKey,
+					Recipient:    keeper.BobKey,
+					TransferFlag: types.TransferResetNone,
+				})
+				if !errors.Is(err, types.ErrTransferOfferExists) {
+					t.Fatalf("handlerMsgOfferDomainTransfer() want: %s, got: %s", types.ErrTransferOfferExists, err)
+				}
+			},
+		},
+		"fail offer expiry in the past": {
+			BeforeTest: createDomain,
+			Test: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				_, err := handlerMsgOfferDomainTransfer(ctx, k, &types.MsgOfferDomainTransfer{
+					Domain:       "test",
+					Owner:        keeper.AliceKey,
+					Recipient:    keeper.CharlieKey,
+					TransferFlag: types.TransferResetNone,
+					Expiry:       ctx.BlockTime().Unix(),
+				})
+				if !errors.Is(err, types.ErrTransferOfferExpired) {
+					t.Fatalf("handlerMsgOfferDomainTransfer() want: %s, got: %s", types.ErrTransferOfferExpired, err)
+				}
+			},
+		},
+		"success accept domain offer pays the owner": {
+			BeforeTest: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				createDomain(t, k, ctx, mocks)
+				offerDomain(t, k, ctx, price, 0)
+			},
+			Test: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				var paid, received bool
+				mocks.Supply.SetSendCoinsFromAccountToModule(func(ctx sdk.Context, addr sdk.AccAddress, moduleName string, coins sdk.Coins) error {
+					if moduleName == types.ModuleName {
+						paid = addr.Equals(keeper.CharlieKey) && coins.IsEqual(price)
+					}
+					return nil
+				})
+				mocks.Supply.SetSendCoinsFromModuleToAccount(func(ctx sdk.Context, moduleName string, addr sdk.AccAddress, coins sdk.Coins) error {
+					received = addr.Equals(keeper.AliceKey) && coins.IsEqual(price)
+					return nil
+				})
+				_, err := handlerMsgAcceptTransferOffer(ctx, k, &types.MsgAcceptTransferOffer{
+					ID:        1,
+					Recipient: keeper.CharlieKey,
+				})
+				if err != nil {
+					t.Fatalf("handlerMsgAcceptTransferOffer() got error: %s", err)
+				}
+				if !paid || !received {
+					t.Fatalf("price not moved from recipient to owner: paid %t, received %t", paid, received)
+				}
+			},
+			AfterTest: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				if domain := readDomain(k, ctx); !domain.Admin.Equals(keeper.CharlieKey) {
+					t.Fatalf("unexpected domain admin: %s", domain.Admin)
+				}
+				if _, ok := k.GetTransferOffer(ctx, 1); ok {
+					t.Fatal("transfer offer should not exist")
+				}
+			},
+		},
+		"success accept account offer": {
+			BeforeTest: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				createDomain(t, k, ctx, mocks)
+				offerAccount(t, k, ctx)
+			},
+			Test: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				_, err := handlerMsgAcceptTransferOffer(ctx, k, &types.MsgAcceptTransferOffer{
+					ID:        1,
+					Recipient: keeper.CharlieKey,
+				})
+				if err != nil {
+					t.Fatalf("handlerMsgAcceptTransferOffer() got error: %s", err)
+				}
+			},
+			AfterTest: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				if account := readAccount(k, ctx); !account.Owner.Equals(keeper.CharlieKey) {
+					t.Fatalf("unexpected account owner: %s", account.Owner)
+				}
+			},
+		},
+		"fail accept by non recipient": {
+			BeforeTest: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				createDomain(t, k, ctx, mocks)
+				offerAccount(t, k, ctx)
+			},
+			Test: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				_, err := handlerMsgAcceptTransferOffer(ctx, k, &types.MsgAcceptTransferOffer{
+					ID:        1,
+					Recipient: keeper.AliceKey,
+				})
+				if !errors.Is(err, types.ErrUnauthorizedTransferOffer) {
+					t.Fatalf("handlerMsgAcceptTransferOffer() want: %s, got: %s", types.ErrUnauthorizedTransferOffer, err)
+				}
+			},
+		},
+		"fail accept offer does not exist": {
+			BeforeTest: createDomain,
+			Test: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				_, err := handlerMsgAcceptTransferOffer(ctx, k, &types.MsgAcceptTransferOffer{
+					ID:        1,
+					Recipient: keeper.CharlieKey,
+				})
+				if !errors.Is(err, types.ErrTransferOfferDoesNotExist) {
+					t.Fatalf("handlerMsgAcceptTransferOffer() want: %s, got: %s", types.ErrTransferOfferDoesNotExist, err)
+				}
+			},
+		},
+		"fail accept expired offer": {
+			BeforeTestBlockTime: 1000,
+			BeforeTest: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				createDomain(t, k, ctx, mocks)
+				offerDomain(t, k, ctx, nil, 2000)
+			},
+			TestBlockTime: 2000,
+			Test: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				_, err := handlerMsgAcceptTransferOffer(ctx, k, &types.MsgAcceptTransferOffer{
+					ID:        1,
+					Recipient: keeper.CharlieKey,
+				})
+				if !errors.Is(err, types.ErrTransferOfferExpired) {
+					t.Fatalf("handlerMsgAcceptTransferOffer() want: %s, got: %s", types.ErrTransferOfferExpired, err)
+				}
+			},
+		},
+		"success reject": {
+			BeforeTest: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				createDomain(t, k, ctx, mocks)
+				offerDomain(t, k, ctx, nil, 0)
+			},
+			Test: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				_, err := handlerMsgRejectTransferOffer(ctx, k, &types.MsgRejectTransferOffer{
+					ID:        1,
+					Recipient: keeper.CharlieKey,
+				})
+				if err != nil {
+					t.Fatalf("handlerMsgRejectTransferOffer() got error: %s", err)
+				}
+			},
+			AfterTest: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				if _, ok := k.GetTransferOffer(ctx, 1); ok {
+					t.Fatal("transfer offer should not exist")
+				}
+				if domain := readDomain(k, ctx); !domain.Admin.Equals(keeper.AliceKey) {
+					t.Fatalf("unexpected domain admin: %s", domain.Admin)
+				}
+			},
+		},
+		"success cancel": {
+			BeforeTest: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				createDomain(t, k, ctx, mocks)
+				offerAccount(t, k, ctx)
+			},
+			Test: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				// the recipient cannot cancel
+				_, err := handlerMsgCancelTransferOffer(ctx, k, &types.MsgCancelTransferOffer{
+					ID:    1,
+					Owner: keeper.CharlieKey,
+				})
+				if !errors.Is(err, types.ErrUnauthorizedTransferOffer) {
+					t.Fatalf("handlerMsgCancelTransferOffer() want: %s, got: %s", types.ErrUnauthorizedTransferOffer, err)
+				}
+				_, err = handlerMsgCancelTransferOffer(ctx, k, &types.MsgCancelTransferOffer{
+					ID:    1,
+					Owner: keeper.BobKey,
+				})
+				if err != nil {
+					t.Fatalf("handlerMsgCancelTransferOffer() got error: %s", err)
+				}
+			},
+			AfterTest: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				if _, ok := k.GetStarnameTransferOffer(ctx, "test*test"); ok {
+					t.Fatal("transfer offer should not exist")
+				}
+			},
+		},
+		"fail offered account is locked": {
+			BeforeTest: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				createDomain(t, k, ctx, mocks)
+				offerAccount(t, k, ctx)
+			},
+			Test: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				_, err := handlerMsgTransferAccount(ctx, k, &types.MsgTransferAccount{
+					Domain:   "test",
+					Name:     "test",
+					Owner:    keeper.BobKey,
+					NewOwner: keeper.AliceKey,
+				})
+				if !errors.Is(err, types.ErrStarnameOffered) {
+					t.Fatalf("handlerMsgTransferAccount() want: %s, got: %s", types.ErrStarnameOffered, err)
+				}
+				_, err = handlerMsgDeleteAccount(ctx, k, &types.MsgDeleteAccount{
+					Domain: "test",
+					Name:   "test",
+					Owner:  keeper.BobKey,
+				})
+				if !errors.Is(err, types.ErrStarnameOffered) {
+					t.Fatalf("handlerMsgDeleteAccount() want: %s, got: %s", types.ErrStarnameOffered, err)
+				}
+			},
+			AfterTest: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				if _, ok := k.GetTransferOffer(ctx, 1); !ok {
+					t.Fatal("transfer offer not found")
+				}
+				if account := readAccount(k, ctx); !account.Owner.Equals(keeper.BobKey) {
+					t.Fatalf("unexpected account owner: %s", account.Owner)
+				}
+			},
+		},
+		"fail offered domain is locked": {
+			BeforeTest: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				createDomain(t, k, ctx, mocks)
+				offerDomain(t, k, ctx, nil, 0)
+			},
+			Test: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				_, err := handlerMsgTransferDomain(ctx, k, &types.MsgTransferDomain{
+					Domain:       "test",
+					Owner:        keeper.AliceKey,
+					NewAdmin:     keeper.BobKey,
+					TransferFlag: types.TransferResetNone,
+				})
+				if !errors.Is(err, types.ErrStarnameOffered) {
+					t.Fatalf("handlerMsgTransferDomain() want: %s, got: %s", types.ErrStarnameOffered, err)
+				}
+			},
+			AfterTest: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				if _, ok := k.GetTransferOffer(ctx, 1); !ok {
+					t.Fatal("transfer offer not found")
+				}
+				if domain := readDomain(k, ctx); !domain.Admin.Equals(keeper.AliceKey) {
+					t.Fatalf("unexpected domain admin: %s", domain.Admin)
+				}
+			},
+		},
+		"fail closed domain with an account offered by the admin is locked": {
+			BeforeTest: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				executor.NewDomain(ctx, k, types.Domain{
+					Name:       "test",
+					Admin:      keeper.AliceKey,
+					ValidUntil: utils.TimeToSeconds(ctx.BlockTime().Add(1000 * time.Hour)),
+					Type:       types.ClosedDomain,
+				}).Create()
+				executor.NewAccount(ctx, k, types.Account{
+					Domain: "test",
+					Name:   utils.StrPtr("test"),
+					Owner:  keeper.BobKey,
+				}).Create()
+				_, err := handlerMsgOfferAccountTransfer(ctx, k, &types.MsgOfferAccountTransfer{
+					Domain:    "test",
+					Name:      "test",
+					Owner:     keeper.AliceKey,
+					Recipient: keeper.CharlieKey,
+				})
+				if err != nil {
+					t.Fatalf("handlerMsgOfferAccountTransfer() got error: %s", err)
+				}
+			},
+			Test: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				_, err := handlerMsgDeleteDomain(ctx, k, &types.MsgDeleteDomain{
+					Domain: "test",
+					Owner:  keeper.AliceKey,
+				})
+				if !errors.Is(err, types.ErrStarnameOffered) {
+					t.Fatalf("handlerMsgDeleteDomain() want: %s, got: %s", types.ErrStarnameOffered, err)
+				}
+				_, err = handlerMsgTransferDomain(ctx, k, &types.MsgTransferDomain{
+					Domain:       "test",
+					Owner:        keeper.AliceKey,
+					NewAdmin:     keeper.BobKey,
+					TransferFlag: types.TransferFlush,
+				})
+				if !errors.Is(err, types.ErrStarnameOffered) {
+					t.Fatalf("handlerMsgTransferDomain() want: %s, got: %s", types.ErrStarnameOffered, err)
+				}
+				_, err = handlerMsgTransferAccounts(ctx, k, &types.MsgTransferAccounts{
+					Domain:   "test",
+					Names:    []string{"test"},
+					Owner:    keeper.AliceKey,
+					NewOwner: keeper.AliceKey,
+				})
+				if !errors.Is(err, types.ErrStarnameOffered) {
+					t.Fatalf("handlerMsgTransferAccounts() want: %s, got: %s", types.ErrStarnameOffered, err)
+				}
+				_, err = handlerMsgDeleteAccountsByOwner(ctx, k, &types.MsgDeleteAccountsByOwner{
+					Domain:       "test",
+					Owner:        keeper.AliceKey,
+					AccountOwner: keeper.BobKey,
+				})
+				if !errors.Is(err, types.ErrStarnameOffered) {
+					t.Fatalf("handlerMsgDeleteAccountsByOwner() want: %s, got: %s", types.ErrStarnameOffered, err)
+				}
+			},
+			AfterTest: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				if _, ok := k.GetTransferOffer(ctx, 1); !ok {
+					t.Fatal("transfer offer not found")
+				}
+				if account := readAccount(k, ctx); !account.Owner.Equals(keeper.BobKey) {
+					t.Fatalf("unexpected account owner: %s", account.Owner)
+				}
+			},
+		},
+		"success transfer after cancel": {
+			BeforeTest: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				createDomain(t, k, ctx, mocks)
+				offerAccount(t, k, ctx)
+				_, err := handlerMsgCancelTransferOffer(ctx, k, &types.MsgCancelTransferOffer{
+					ID:    1,
+					Owner: keeper.BobKey,
+				})
+				if err != nil {
+					t.Fatalf("handlerMsgCancelTransferOffer() got error: %s", err)
+				}
+			},
+			Test: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				_, err := handlerMsgTransferAccount(ctx, k, &types.MsgTransferAccount{
+					Domain:   "test",
+					Name:     "test",
+					Owner:    keeper.BobKey,
+					NewOwner: keeper.AliceKey,
+				})
+				if err != nil {
+					t.Fatalf("handlerMsgTransferAccount() got error: %s", err)
+				}
+			},
+			AfterTest: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				if account := readAccount(k, ctx); !account.Owner.Equals(keeper.AliceKey) {
+					t.Fatalf("unexpected account owner: %s", account.Owner)
+				}
+			},
+		},
+		"success immediate transfer removes expired offers": {
+			BeforeTestBlockTime: 1000,
+			BeforeTest: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				createDomain(t, k, ctx, mocks)
+				offerDomain(t, k, ctx, nil, 2000)
+			},
+			// the offer expired but the end blocker did not remove it yet
+			TestBlockTime: 2000,
+			Test: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				_, err := handlerMsgTransferDomain(ctx, k, &types.MsgTransferDomain{
+					Domain:       "test",
+					Owner:        keeper.AliceKey,
+					NewAdmin:     keeper.BobKey,
+					TransferFlag: types.TransferResetNone,
+				})
+				if err != nil {
+					t.Fatalf("handlerMsgTransferDomain() got error: %s", err)
+				}
+			},
+			AfterTest: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				if _, ok := k.GetTransferOffer(ctx, 1); ok {
+					t.Fatal("transfer offer should not exist")
+				}
+			},
+		},
+		"success end blocker removes expired offers": {
+			BeforeTestBlockTime: 1000,
+			BeforeTest: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				createDomain(t, k, ctx, mocks)
+				offerDomain(t, k, ctx, nil, 2000)
+				offerAccount(t, k, ctx)
+			},
+			TestBlockTime: 1999,
+			Test: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				EndBlocker(ctx, k)
+				if _, ok := k.GetTransferOffer(ctx, 1); !ok {
+					t.Fatal("transfer offer removed before its expiry")
+				}
+			},
+			AfterTestBlockTime: 2000,
+			AfterTest: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				EndBlocker(ctx, k)
+				if _, ok := k.GetTransferOffer(ctx, 1); ok {
+					t.Fatal("expired transfer offer should not exist")
+				}
+				// offers without expiry are kept
+				if _, ok := k.GetTransferOffer(ctx, 2); !ok {
+					t.Fatal("transfer offer without expiry not found")
+				}
+			},
+		},
+	}
+	keeper.RunTests(t, cases)
+}
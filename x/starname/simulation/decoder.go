@@ -0,0 +1,68 @@
+package simulation
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	"github.com/iov-one/iovns/x/starname/keeper"
+	"github.com/iov-one/iovns/x/starname/types"
+	tmkv "github.com/tendermint/tendermint/libs/kv"
+)
+
+// DecodeStore unmarshals the KVPair's values to the corresponding starname type,
+// index entries of the crud stores are returned as raw bytes
+func DecodeStore(cdc *codec.Codec, kvA, kvB tmkv.Pair) string {
+	prefix := kvA.Key[:1]
+	isObject := len(kvA.Key) > 1 && bytes.Equal(kvA.Key[1:2], keeper.CrudObjectPrefix)
+	switch {
+	case bytes.Equal(prefix, keeper.AccountStorePrefix) && isObject:
+		var accountA, accountB types.Account
+		accountA.UnmarshalCRUD(cdc, kvA.Value)
+		accountB.UnmarshalCRUD(cdc, kvB.Value)
+		// accounts are printed as JSON since their name is a pointer
+		return fmt.Sprintf("%s\n%s", cdc.MustMarshalJSON(accountA), cdc.MustMarshalJSON(accountB))
+	case bytes.Equal(prefix, keeper.DomainStorePrefix) && isObject:
+		var domainA, domainB types.Domain
+		cdc.MustUnmarshalBinaryBare(kvA.Value, &domainA)
+		cdc.MustUnmarshalBinaryBare(kvB.Value, &domainB)
+		return fmt.Sprintf("%v\n%v", domainA, domainB)
+	case bytes.Equal(prefix, keeper.HistoryStorePrefix) && isObject:
+		var recordA, recordB types.HistoryRecord
+		cdc.MustUnmarshalBinaryBare(kvA.Value, &recordA)
+		cdc.MustUnmarshalBinaryBare(kvB.Value, &recordB)
+		return fmt.Sprintf("%v\n%v", recordA, recordB)
+	case bytes.Equal(prefix, keeper.TransferOfferStorePrefix) && isObject:
+		var offerA, offerB types.TransferOffer
+		cdc.MustUnmarshalBinaryBare(kvA.Value, &offerA)
+		cdc.MustUnmarshalBinaryBare(kvB.Value, &offerB)
+		// offers are printed as JSON since their name is a pointer
+		return fmt.Sprintf("%s\n%s", cdc.MustMarshalJSON(offerA), cdc.MustMarshalJSON(offerB))
+	case bytes.Equal(prefix, keeper.SponsorshipStorePrefix) && isObject:
+		var sponsorshipA, sponsorshipB types.Sponsorship
+		cdc.MustUnmarshalBinaryBare(kvA.Value, &sponsorshipA)
+		cdc.MustUnmarshalBinaryBare(kvB.Value, &sponsorshipB)
+		return fmt.Sprintf("%v\n%v", sponsorshipA, sponsorshipB)
+	case bytes.Equal(prefix, keeper.RegistrationPolicyStorePrefix):
+		var policyA, policyB types.RegistrationPolicy
+		cdc.MustUnmarshalBinaryBare(kvA.Value, &policyA)
+		cdc.MustUnmarshalBinaryBare(kvB.Value, &policyB)
+		return fmt.Sprintf("%v\n%v", policyA, policyB)
+	case bytes.Equal(prefix, keeper.AccountStorePrefix),
+		bytes.Equal(prefix, keeper.DomainStorePrefix),
+		bytes.Equal(prefix, keeper.HistoryStorePrefix),
+		bytes.Equal(prefix, keeper.InviteCodeStorePrefix),
+		bytes.Equal(prefix, keeper.TransferOfferStorePrefix),
+		bytes.Equal(prefix, keeper.TransferOfferQueuePrefix),
+		bytes.Equal(prefix, keeper.SponsorshipStorePrefix):
+		return fmt.Sprintf("%X\n%X", kvA.Value, kvB.Value)
+	case bytes.Equal(prefix, keeper.AccountCountStorePrefix),
+		bytes.Equal(kvA.Key, keeper.HistorySequenceKey),
+		bytes.Equal(kvA.Key, keeper.StoreVersionKey),
+		bytes.Equal(kvA.Key, keeper.TransferOfferSequenceKey):
+		return fmt.Sprintf("%d\n%d", binary.BigEndian.Uint64(kvA.Value), binary.BigEndian.Uint64(kvB.Value))
+	default:
+		panic(fmt.Sprintf("invalid starname key %X", kvA.Key))
+	}
+}
@@ -0,0 +1,71 @@
+package simulation
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/iov-one/iovns/pkg/utils"
+	"github.com/iov-one/iovns/x/starname/keeper"
+	"github.com/iov-one/iovns/x/starname/types"
+	tmkv "github.com/tendermint/tendermint/libs/kv"
+)
+
+func TestDecodeStore(t *testing.T) {
+	cdc := codec.New()
+	owner := sdk.AccAddress("owner")
+	domain := types.Domain{Name: "test", Admin: owner, ValidUntil: 10, Type: types.ClosedDomain}
+	account := types.Account{Domain: "test", Name: utils.StrPtr(""), Owner: owner, ValidUntil: 10}
+	record := types.NewDomainHistoryRecord(types.HistoryCreate, domain)
+	sponsorship := types.Sponsorship{Domain: "test", Sponsor: owner, Balance: sdk.NewCoins(sdk.NewInt64Coin("tiov", 10))}
+	offer := types.TransferOffer{ID: 1, Domain: "test", Name: utils.StrPtr("acc"), Owner: owner, Recipient: sdk.AccAddress("recipient"), Expiry: 10}
+
+	key := func(prefix []byte, pk []byte) []byte {
+		return append(append(append([]byte{}, prefix...), keeper.CrudObjectPrefix...), pk...)
+	}
+	kvPairs := []tmkv.Pair{
+		{Key: key(keeper.DomainStorePrefix, domain.PrimaryKey().Key()), Value: cdc.MustMarshalBinaryBare(domain)},
+		{Key: key(keeper.AccountStorePrefix, account.PrimaryKey().Key()), Value: cdc.MustMarshalBinaryBare(account.MarshalCRUD())},
+		{Key: key(keeper.HistoryStorePrefix, []byte{0x1}), Value: cdc.MustMarshalBinaryBare(record)},
+		{Key: key(keeper.TransferOfferStorePrefix, offer.PrimaryKey().Key()), Value: cdc.MustMarshalBinaryBare(offer)},
+		{Key: key(keeper.SponsorshipStorePrefix, sponsorship.PrimaryKey().Key()), Value: cdc.MustMarshalBinaryBare(sponsorship)},
+		{Key: append(append([]byte{}, keeper.AccountStorePrefix...), 0x1, 0x2), Value: []byte{0xa}},
+		{Key: keeper.HistorySequenceKey, Value: sdk.Uint64ToBigEndian(3)},
+		{Key: keeper.StoreVersionKey, Value: sdk.Uint64ToBigEndian(1)},
+		{Key: keeper.TransferOfferSequenceKey, Value: sdk.Uint64ToBigEndian(1)},
+		{Key: append(append([]byte{}, keeper.AccountCountStorePrefix...), "test"...), Value: sdk.Uint64ToBigEndian(2)},
+		{Key: []byte{0x99}, Value: []byte{0x99}},
+	}
+	tests := []struct {
+		name        string
+		expectedLog string
+	}{
+		{"Domain", fmt.Sprintf("%v\n%v", domain, domain)},
+		{"Account", fmt.Sprintf("%s\n%s", cdc.MustMarshalJSON(account), cdc.MustMarshalJSON(account))},
+		{"HistoryRecord", fmt.Sprintf("%v\n%v", record, record)},
+		{"TransferOffer", fmt.Sprintf("%s\n%s", cdc.MustMarshalJSON(offer), cdc.MustMarshalJSON(offer))},
+		{"Sponsorship", fmt.Sprintf("%v\n%v", sponsorship, sponsorship)},
+		{"Index", "0A\n0A"},
+		{"HistorySequence", "3\n3"},
+		{"StoreVersion", "1\n1"},
+		{"TransferOfferSequence", "1\n1"},
+		{"AccountCount", "2\n2"},
+		{"other", ""},
+	}
+	for i, tt := range tests {
+		i, tt := i, tt
+		t.Run(tt.name, func(t *testing.T) {
+			if i == len(tests)-1 {
+				defer func() {
+					if r := recover(); r == nil {
+						t.Fatal("expected panic")
+					}
+				}()
+			}
+			if got := DecodeStore(cdc, kvPairs[i], kvPairs[i]); got != tt.expectedLog {
+				t.Fatalf("expected %q, got %q", tt.expectedLog, got)
+			}
+		})
+	}
+}
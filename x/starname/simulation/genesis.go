@@ -0,0 +1,141 @@
+package simulation
+
+import (
+	"fmt"
+	"math/rand"
+
+	"github.com/cosmos/cosmos-sdk/types/module"
+	"github.com/cosmos/cosmos-sdk/x/simulation"
+	simutil "github.com/iov-one/iovns/pkg/simulation"
+	"github.com/iov-one/iovns/pkg/utils"
+	configsim "github.com/iov-one/iovns/x/configuration/simulation"
+	conftypes "github.com/iov-one/iovns/x/configuration/types"
+	"github.com/iov-one/iovns/x/starname/types"
+)
+
+// GenesisState mirrors starname.GenesisState, which can not be imported here
+type GenesisState struct {
+	Domains  []types.Domain  `json:"domains"`
+	Accounts []types.Account `json:"accounts"`
+}
+
+// RandomResources returns at most max random resources matching the simulated configuration
+func RandomResources(r *rand.Rand, max uint32) []types.Resource {
+	if max == 0 {
+		return nil
+	}
+	n := r.Intn(int(max) + 1)
+	if n == 0 {
+		return nil
+	}
+	resources := make([]types.Resource, n)
+	for i := range resources {
+		// URIs are unique in the account
+		resources[i] = types.Resource{
+			URI:      fmt.Sprintf("asset:%d:%s", i, simutil.RandName(r, 2, 8)),
+			Resource: simulation.RandStringOfLength(r, simulation.RandIntBetween(r, 4, 32)),
+		}
+	}
+	return resources
+}
+
+// RandomCertificate returns a random certificate of size at most max
+func RandomCertificate(r *rand.Rand, max uint64) types.Certificate {
+	n := uint64(1)
+	if max > 1 {
+		n = uint64(simulation.RandIntBetween(r, 1, int(max)+1))
+	}
+	cert := make([]byte, n)
+	r.Read(cert)
+	return cert
+}
+
+// RandomMetadataURI returns a random metadata URI of size at most max
+func RandomMetadataURI(r *rand.Rand, max uint64) string {
+	if max == 0 {
+		return ""
+	}
+	n := uint64(simulation.RandIntBetween(r, 0, int(max)+1))
+	if n > 64 {
+		n = 64
+	}
+	return simulation.RandStringOfLength(r, int(n))
+}
+
+// RandomDomainType returns a random domain type
+func RandomDomainType(r *rand.Rand) types.DomainType {
+	if r.Intn(2) == 0 {
+		return types.OpenDomain
+	}
+	return types.ClosedDomain
+}
+
+// RandomizedGenState generates a random GenesisState for the starname module,
+// the configuration module genesis state must have been generated already
+func RandomizedGenState(simState *module.SimulationState) {
+	var confGenesis configsim.GenesisState
+	simState.Cdc.MustUnmarshalJSON(simState.GenState[conftypes.ModuleName], &confGenesis)
+	conf := confGenesis.Config
+
+	var genesis GenesisState
+	domainCount := simulation.RandIntBetween(simState.Rand, 1, 10)
+	domains := make(map[string]struct{}, domainCount)
+	for i := 0; i < domainCount; i++ {
+		name := simutil.RandName(simState.Rand, 4, 16)
+		if _, ok := domains[name]; ok {
+			continue
+		}
+		domains[name] = struct{}{}
+		admin, _ := simulation.RandomAcc(simState.Rand, simState.Accounts)
+		domain := types.Domain{
+			Name:       name,
+			Admin:      admin.Address,
+			ValidUntil: simState.GenTimestamp.Add(conf.DomainRenewalPeriod).Unix(),
+			Type:       RandomDomainType(simState.Rand),
+		}
+		genesis.Domains = append(genesis.Domains, domain)
+		genesis.Accounts = append(genesis.Accounts, types.Account{
+			Domain:     domain.Name,
+			Name:       utils.StrPtr(types.EmptyAccountName),
+			Owner:      domain.Admin,
+			ValidUntil: domain.ValidUntil,
+		})
+		genesis.Accounts = append(genesis.Accounts, randomAccounts(simState, conf, domain)...)
+	}
+	fmt.Printf("Selected randomly generated starname genesis with %d domains and %d accounts\n", len(genesis.Domains), len(genesis.Accounts))
+	simState.GenState[types.ModuleName] = simState.Cdc.MustMarshalJSON(genesis)
+}
+
+// randomAccounts generates random accounts in the provided domain
+func randomAccounts(simState *module.SimulationState, conf conftypes.Config, domain types.Domain) []types.Account {
+	r := simState.Rand
+	count := r.Intn(10)
+	names := make(map[string]struct{}, count)
+	accounts := make([]types.Account, 0, count)
+	for i := 0; i < count; i++ {
+		name := simutil.RandName(r, 1, 16)
+		if _, ok := names[name]; ok {
+			continue
+		}
+		names[name] = struct{}{}
+		owner, _ := simulation.RandomAcc(r, simState.Accounts)
+		validUntil := simState.GenTimestamp.Add(conf.AccountRenewalPeriod).Unix()
+		if domain.Type == types.ClosedDomain {
+			validUntil = types.MaxValidUntil
+		}
+		var certificates []types.Certificate
+		if conf.CertificateCountMax != 0 && r.Intn(2) == 0 {
+			certificates = []types.Certificate{RandomCertificate(r, conf.CertificateSizeMax)}
+		}
+		accounts = append(accounts, types.Account{
+			Domain:       domain.Name,
+			Name:         utils.StrPtr(name),
+			Owner:        owner.Address,
+			ValidUntil:   validUntil,
+			Resources:    RandomResources(r, conf.ResourcesMax),
+			Certificates: certificates,
+			MetadataURI:  RandomMetadataURI(r, conf.MetadataSizeMax),
+		})
+	}
+	return accounts
+}
@@ -0,0 +1,46 @@
+package simulation_test
+
+import (
+	"encoding/json"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	"github.com/cosmos/cosmos-sdk/types/module"
+	"github.com/cosmos/cosmos-sdk/x/simulation"
+	"github.com/iov-one/iovns/x/configuration"
+	configsim "github.com/iov-one/iovns/x/configuration/simulation"
+	"github.com/iov-one/iovns/x/starname"
+	starnamesim "github.com/iov-one/iovns/x/starname/simulation"
+)
+
+func TestRandomizedGenState(t *testing.T) {
+	cdc := codec.New()
+	for seed := int64(0); seed < 20; seed++ {
+		r := rand.New(rand.NewSource(seed))
+		simState := &module.SimulationState{
+			Cdc:          cdc,
+			Rand:         r,
+			GenState:     make(map[string]json.RawMessage),
+			Accounts:     simulation.RandomAccounts(r, 10),
+			GenTimestamp: time.Unix(1600000000, 0),
+		}
+		configsim.RandomizedGenState(simState)
+		starnamesim.RandomizedGenState(simState)
+
+		var confGenesis configuration.GenesisState
+		cdc.MustUnmarshalJSON(simState.GenState[configuration.ModuleName], &confGenesis)
+		if err := configuration.ValidateGenesis(confGenesis); err != nil {
+			t.Fatalf("seed %d: invalid configuration genesis: %s", seed, err)
+		}
+		var genesis starname.GenesisState
+		cdc.MustUnmarshalJSON(simState.GenState[starname.ModuleName], &genesis)
+		if len(genesis.Domains) == 0 {
+			t.Fatalf("seed %d: no domains generated", seed)
+		}
+		if err := starname.ValidateGenesisWithConfig(genesis, confGenesis.Config); err != nil {
+			t.Fatalf("seed %d: invalid starname genesis: %s", seed, err)
+		}
+	}
+}
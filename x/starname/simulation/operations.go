@@ -0,0 +1,644 @@
+package simulation
+
+import (
+	"fmt"
+	"math/rand"
+
+	"github.com/cosmos/cosmos-sdk/baseapp"
+	"github.com/cosmos/cosmos-sdk/codec"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/simulation"
+	crud "github.com/iov-one/cosmos-sdk-crud/pkg/crud/types"
+	simutil "github.com/iov-one/iovns/pkg/simulation"
+	"github.com/iov-one/iovns/x/starname/keeper"
+	"github.com/iov-one/iovns/x/starname/types"
+)
+
+// Simulation operation weights constants
+const (
+	OpWeightMsgRegisterDomain           = "op_weight_msg_register_domain"
+	OpWeightMsgRenewDomain              = "op_weight_msg_renew_domain"
+	OpWeightMsgTransferDomain           = "op_weight_msg_transfer_domain"
+	OpWeightMsgDeleteDomain             = "op_weight_msg_delete_domain"
+	OpWeightMsgRegisterAccount          = "op_weight_msg_register_account"
+	OpWeightMsgRenewAccount             = "op_weight_msg_renew_account"
+	OpWeightMsgTransferAccount          = "op_weight_msg_transfer_account"
+	OpWeightMsgDeleteAccount            = "op_weight_msg_delete_account"
+	OpWeightMsgReplaceAccountResources  = "op_weight_msg_replace_account_resources"
+	OpWeightMsgReplaceAccountMetadata   = "op_weight_msg_replace_account_metadata"
+	OpWeightMsgAddAccountCertificates   = "op_weight_msg_add_account_certificates"
+	OpWeightMsgDeleteAccountCertificate = "op_weight_msg_delete_account_certificate"
+	OpWeightMsgSetRegistrationPolicy    = "op_weight_msg_set_registration_policy"
+	OpWeightMsgOfferDomainTransfer      = "op_weight_msg_offer_domain_transfer"
+	OpWeightMsgOfferAccountTransfer     = "op_weight_msg_offer_account_transfer"
+	OpWeightMsgAcceptTransferOffer      = "op_weight_msg_accept_transfer_offer"
+	OpWeightMsgRejectTransferOffer      = "op_weight_msg_reject_transfer_offer"
+	OpWeightMsgCancelTransferOffer      = "op_weight_msg_cancel_transfer_offer"
+	OpWeightMsgDepositSponsorship       = "op_weight_msg_deposit_sponsorship"
+	OpWeightMsgWithdrawSponsorship      = "op_weight_msg_withdraw_sponsorship"
+	OpWeightMsgTransferAccounts         = "op_weight_msg_transfer_accounts"
+	OpWeightMsgDeleteAccountsByOwner    = "op_weight_msg_delete_accounts_by_owner"
+	OpWeightMsgSetAccountsResource      = "op_weight_msg_set_accounts_resource"
+	OpWeightMsgSetAccountValidUntil     = "op_weight_msg_set_account_valid_until"
+)
+
+// Default simulation operation weights
+const (
+	DefaultWeightMsgRegisterDomain           = 50
+	DefaultWeightMsgRenewDomain              = 20
+	DefaultWeightMsgTransferDomain           = 20
+	DefaultWeightMsgDeleteDomain             = 10
+	DefaultWeightMsgRegisterAccount          = 100
+	DefaultWeightMsgRenewAccount             = 30
+	DefaultWeightMsgTransferAccount          = 40
+	DefaultWeightMsgDeleteAccount            = 20
+	DefaultWeightMsgReplaceAccountResources  = 50
+	DefaultWeightMsgReplaceAccountMetadata   = 30
+	DefaultWeightMsgAddAccountCertificates   = 30
+	DefaultWeightMsgDeleteAccountCertificate = 20
+	DefaultWeightMsgSetRegistrationPolicy    = 20
+	DefaultWeightMsgOfferDomainTransfer      = 20
+	DefaultWeightMsgOfferAccountTransfer     = 30
+	DefaultWeightMsgAcceptTransferOffer      = 30
+	DefaultWeightMsgRejectTransferOffer      = 10
+	DefaultWeightMsgCancelTransferOffer      = 10
+	DefaultWeightMsgDepositSponsorship       = 30
+	DefaultWeightMsgWithdrawSponsorship      = 10
+	DefaultWeightMsgTransferAccounts         = 10
+	DefaultWeightMsgDeleteAccountsByOwner    = 10
+	DefaultWeightMsgSetAccountsResource      = 10
+	DefaultWeightMsgSetAccountValidUntil     = 20
+)
+
+// msgGenerator builds a random msg from the current state,
+// it returns false if no msg can be built
+type msgGenerator func(r *rand.Rand, ctx sdk.Context, accs []simulation.Account, k keeper.Keeper) (sdk.Msg, bool)
+
+// WeightedOperations returns all the operations from the module with their respective weights,
+// handler is the starname module handler used to skip msgs which would fail
+func WeightedOperations(appParams simulation.AppParams, cdc *codec.Codec, ak simutil.AccountKeeper, k keeper.Keeper, handler sdk.Handler) simulation.WeightedOperations {
+	ops := []struct {
+		key           string
+		defaultWeight int
+		gen           msgGenerator
+	}{
+		{OpWeightMsgRegisterDomain, DefaultWeightMsgRegisterDomain, genMsgRegisterDomain},
+		{OpWeightMsgRenewDomain, DefaultWeightMsgRenewDomain, genMsgRenewDomain},
+		{OpWeightMsgTransferDomain, DefaultWeightMsgTransferDomain, genMsgTransferDomain},
+		{OpWeightMsgDeleteDomain, DefaultWeightMsgDeleteDomain, genMsgDeleteDomain},
+		{OpWeightMsgRegisterAccount, DefaultWeightMsgRegisterAccount, genMsgRegisterAccount},
+		{OpWeightMsgRenewAccount, DefaultWeightMsgRenewAccount, genMsgRenewAccount},
+		{OpWeightMsgTransferAccount, DefaultWeightMsgTransferAccount, genMsgTransferAccount},
+		{OpWeightMsgDeleteAccount, DefaultWeightMsgDeleteAccount, genMsgDeleteAccount},
+		{OpWeightMsgReplaceAccountResources, DefaultWeightMsgReplaceAccountResources, genMsgReplaceAccountResources},
+		{OpWeightMsgReplaceAccountMetadata, DefaultWeightMsgReplaceAccountMetadata, genMsgReplaceAccountMetadata},
+		{OpWeightMsgAddAccountCertificates, DefaultWeightMsgAddAccountCertificates, genMsgAddAccountCertificates},
+		{OpWeightMsgDeleteAccountCertificate, DefaultWeightMsgDeleteAccountCertificate, genMsgDeleteAccountCertificate},
+		{OpWeightMsgSetRegistrationPolicy, DefaultWeightMsgSetRegistrationPolicy, genMsgSetRegistrationPolicy},
+		{OpWeightMsgOfferDomainTransfer, DefaultWeightMsgOfferDomainTransfer, genMsgOfferDomainTransfer},
+		{OpWeightMsgOfferAccountTransfer, DefaultWeightMsgOfferAccountTransfer, genMsgOfferAccountTransfer},
+		{OpWeightMsgAcceptTransferOffer, DefaultWeightMsgAcceptTransferOffer, genMsgAcceptTransferOffer},
+		{OpWeightMsgRejectTransferOffer, DefaultWeightMsgRejectTransferOffer, genMsgRejectTransferOffer},
+		{OpWeightMsgCancelTransferOffer, DefaultWeightMsgCancelTransferOffer, genMsgCancelTransferOffer},
+		{OpWeightMsgDepositSponsorship, DefaultWeightMsgDepositSponsorship, genMsgDepositSponsorship},
+		{OpWeightMsgWithdrawSponsorship, DefaultWeightMsgWithdrawSponsorship, genMsgWithdrawSponsorship},
+		{OpWeightMsgTransferAccounts, DefaultWeightMsgTransferAccounts, genMsgTransferAccounts},
+		{OpWeightMsgDeleteAccountsByOwner, DefaultWeightMsgDeleteAccountsByOwner, genMsgDeleteAccountsByOwner},
+		{OpWeightMsgSetAccountsResource, DefaultWeightMsgSetAccountsResource, genMsgSetAccountsResource},
+		{OpWeightMsgSetAccountValidUntil, DefaultWeightMsgSetAccountValidUntil, genMsgSetAccountValidUntil},
+	}
+	operations := make(simulation.WeightedOperations, len(ops))
+	for i, op := range ops {
+		var weight int
+		defaultWeight := op.defaultWeight
+		appParams.GetOrGenerate(cdc, op.key, &weight, nil,
+			func(_ *rand.Rand) { weight = defaultWeight },
+		)
+		operations[i] = simulation.NewWeightedOperation(weight, SimulateMsg(ak, k, handler, op.gen))
+	}
+	return operations
+}
+
+// SimulateMsg returns an operation delivering the msgs built by gen, msgs failing
+// their basic validation or their execution against the current state are skipped
+func SimulateMsg(ak simutil.AccountKeeper, k keeper.Keeper, handler sdk.Handler, gen msgGenerator) simulation.Operation {
+	return func(r *rand.Rand, app *baseapp.BaseApp, ctx sdk.Context, accs []simulation.Account, chainID string,
+	) (simulation.OperationMsg, []simulation.FutureOperation, error) {
+		msg, ok := gen(r, ctx, accs, k)
+		if !ok {
+			return simulation.NoOpMsg(types.ModuleName), nil, nil
+		}
+		if err := msg.ValidateBasic(); err != nil {
+			return simulation.NoOpMsg(types.ModuleName), nil, nil
+		}
+		// dry run the msg on a cached context to skip the ones refused by the handler
+		cacheCtx, _ := ctx.CacheContext()
+		if _, err := handler(cacheCtx, msg); err != nil {
+			return simulation.NoOpMsg(types.ModuleName), nil, nil
+		}
+		delivered, err := simutil.DeliverMsg(app, ctx, ak, accs, msg, chainID)
+		if err != nil || !delivered {
+			return simulation.NoOpMsg(types.ModuleName), nil, err
+		}
+		return simulation.NewOperationMsg(msg, true, ""), nil, nil
+	}
+}
+
+func genMsgRegisterDomain(r *rand.Rand, _ sdk.Context, accs []simulation.Account, _ keeper.Keeper) (sdk.Msg, bool) {
+	admin, _ := simulation.RandomAcc(r, accs)
+	return &types.MsgRegisterDomain{
+		Name:       simutil.RandName(r, 4, 16),
+		Admin:      admin.Address,
+		DomainType: RandomDomainType(r),
+	}, true
+}
+
+func genMsgRenewDomain(r *rand.Rand, ctx sdk.Context, accs []simulation.Account, k keeper.Keeper) (sdk.Msg, bool) {
+	domain, ok := randomDomain(r, ctx, k)
+	if !ok {
+		return nil, false
+	}
+	signer, _ := simulation.RandomAcc(r, accs)
+	return &types.MsgRenewDomain{
+		Domain: domain.Name,
+		Signer: signer.Address,
+	}, true
+}
+
+func genMsgTransferDomain(r *rand.Rand, ctx sdk.Context, accs []simulation.Account, k keeper.Keeper) (sdk.Msg, bool) {
+	domain, ok := randomDomain(r, ctx, k)
+	if !ok {
+		return nil, false
+	}
+	newAdmin, _ := simulation.RandomAcc(r, accs)
+	return &types.MsgTransferDomain{
+		Domain:       domain.Name,
+		Owner:        domain.Admin,
+		NewAdmin:     newAdmin.Address,
+		TransferFlag: types.TransferFlag(r.Intn(int(types.TransferAll) + 1)),
+	}, true
+}
+
+func genMsgDeleteDomain(r *rand.Rand, ctx sdk.Context, _ []simulation.Account, k keeper.Keeper) (sdk.Msg, bool) {
+	domain, ok := randomDomain(r, ctx, k)
+	if !ok {
+		return nil, false
+	}
+	return &types.MsgDeleteDomain{
+		Domain: domain.Name,
+		Owner:  domain.Admin,
+	}, true
+}
+
+func genMsgRegisterAccount(r *rand.Rand, ctx sdk.Context, accs []simulation.Account, k keeper.Keeper) (sdk.Msg, bool) {
+	domain, ok := randomDomain(r, ctx, k)
+	if !ok {
+		return nil, false
+	}
+	owner, _ := simulation.RandomAcc(r, accs)
+	registerer := owner.Address
+	var credential *types.RegistrationCredential
+	var validUntil int64
+	if domain.Type == types.ClosedDomain {
+		registerer = domain.Admin
+		validUntil = randomAccountValidUntil(r, ctx, domain)
+		// half of the time try to register through the registration policy, if any
+		if policy, ok := k.GetRegistrationPolicy(ctx, domain.Name); ok && r.Intn(2) == 0 {
+			registerer, credential = policyRegisterer(r, ctx, accs, domain, policy, owner.Address)
+			validUntil = 0
+		}
+	}
+	return &types.MsgRegisterAccount{
+		Domain:     domain.Name,
+		Name:       simutil.RandName(r, 1, 16),
+		Owner:      owner.Address,
+		Registerer: registerer,
+		Resources:  RandomResources(r, k.ConfigurationKeeper.GetConfiguration(ctx).ResourcesMax),
+		Credential: credential,
+		ValidUntil: validUntil,
+	}, true
+}
+
+// policyRegisterer returns a registerer allowed by the registration policy of the domain, the returned
+// credential is nil if the registerer is in the allow list or if no credential can be signed
+func policyRegisterer(r *rand.Rand, ctx sdk.Context, accs []simulation.Account, domain types.Domain, policy types.RegistrationPolicy, owner sdk.AccAddress) (sdk.AccAddress, *types.RegistrationCredential) {
+	switch {
+	case len(policy.AllowList) != 0 && r.Intn(2) == 0:
+		return policy.AllowList[r.Intn(len(policy.AllowList))], nil
+	case len(policy.CertificateIssuers) != 0 && r.Intn(2) == 0:
+		issuer, ok := simulation.FindAccount(accs, policy.CertificateIssuers[r.Intn(len(policy.CertificateIssuers))])
+		if !ok {
+			return owner, nil
+		}
+		return owner, signCredential(ctx, issuer, types.CertificateCredential, domain.Name, owner, "")
+	case policy.InviteCodes:
+		admin, ok := simulation.FindAccount(accs, domain.Admin)
+		if !ok {
+			return owner, nil
+		}
+		return owner, signCredential(ctx, admin, types.InviteCredential, domain.Name, owner, simutil.RandName(r, 8, 16))
+	default:
+		return owner, nil
+	}
+}
+
+// signCredential signs a registration credential with the key of the issuer
+func signCredential(ctx sdk.Context, issuer simulation.Account, typ types.CredentialType, domain string, registerer sdk.AccAddress, code string) *types.RegistrationCredential {
+	sig, err := issuer.PrivKey.Sign(types.CredentialSignBytes(ctx.ChainID(), typ, domain, registerer, code))
+	if err != nil {
+		panic(err)
+	}
+	return &types.RegistrationCredential{
+		Type:      typ,
+		Code:      code,
+		PubKey:    issuer.PubKey,
+		Signature: sig,
+	}
+}
+
+func genMsgRenewAccount(r *rand.Rand, ctx sdk.Context, accs []simulation.Account, k keeper.Keeper) (sdk.Msg, bool) {
+	account, ok := randomAccount(r, ctx, k)
+	if !ok {
+		return nil, false
+	}
+	signer, _ := simulation.RandomAcc(r, accs)
+	return &types.MsgRenewAccount{
+		Domain: account.Domain,
+		Name:   *account.Name,
+		Signer: signer.Address,
+	}, true
+}
+
+func genMsgTransferAccount(r *rand.Rand, ctx sdk.Context, accs []simulation.Account, k keeper.Keeper) (sdk.Msg, bool) {
+	account, ok := randomAccount(r, ctx, k)
+	if !ok {
+		return nil, false
+	}
+	newOwner, _ := simulation.RandomAcc(r, accs)
+	return &types.MsgTransferAccount{
+		Domain:   account.Domain,
+		Name:     *account.Name,
+		Owner:    accountManager(ctx, k, account),
+		NewOwner: newOwner.Address,
+		Reset:    r.Intn(2) == 0,
+	}, true
+}
+
+func genMsgDeleteAccount(r *rand.Rand, ctx sdk.Context, _ []simulation.Account, k keeper.Keeper) (sdk.Msg, bool) {
+	account, ok := randomAccount(r, ctx, k)
+	if !ok {
+		return nil, false
+	}
+	return &types.MsgDeleteAccount{
+		Domain: account.Domain,
+		Name:   *account.Name,
+		Owner:  accountManager(ctx, k, account),
+	}, true
+}
+
+func genMsgReplaceAccountResources(r *rand.Rand, ctx sdk.Context, _ []simulation.Account, k keeper.Keeper) (sdk.Msg, bool) {
+	account, ok := randomAccount(r, ctx, k)
+	if !ok {
+		return nil, false
+	}
+	return &types.MsgReplaceAccountResources{
+		Domain:       account.Domain,
+		Name:         *account.Name,
+		NewResources: RandomResources(r, k.ConfigurationKeeper.GetConfiguration(ctx).ResourcesMax),
+		Owner:        account.Owner,
+	}, true
+}
+
+func genMsgReplaceAccountMetadata(r *rand.Rand, ctx sdk.Context, _ []simulation.Account, k keeper.Keeper) (sdk.Msg, bool) {
+	account, ok := randomAccount(r, ctx, k)
+	if !ok {
+		return nil, false
+	}
+	return &types.MsgReplaceAccountMetadata{
+		Domain:         account.Domain,
+		Name:           *account.Name,
+		NewMetadataURI: RandomMetadataURI(r, k.ConfigurationKeeper.GetConfiguration(ctx).MetadataSizeMax),
+		Owner:          account.Owner,
+	}, true
+}
+
+func genMsgAddAccountCertificates(r *rand.Rand, ctx sdk.Context, _ []simulation.Account, k keeper.Keeper) (sdk.Msg, bool) {
+	account, ok := randomAccount(r, ctx, k)
+	if !ok {
+		return nil, false
+	}
+	return &types.MsgAddAccountCertificates{
+		Domain:         account.Domain,
+		Name:           *account.Name,
+		Owner:          account.Owner,
+		NewCertificate: RandomCertificate(r, k.ConfigurationKeeper.GetConfiguration(ctx).CertificateSizeMax),
+	}, true
+}
+
+func genMsgDeleteAccountCertificate(r *rand.Rand, ctx sdk.Context, _ []simulation.Account, k keeper.Keeper) (sdk.Msg, bool) {
+	account, ok := randomAccount(r, ctx, k)
+	if !ok || len(account.Certificates) == 0 {
+		return nil, false
+	}
+	return &types.MsgDeleteAccountCertificate{
+		Domain:            account.Domain,
+		Name:              *account.Name,
+		DeleteCertificate: account.Certificates[r.Intn(len(account.Certificates))],
+		Owner:             account.Owner,
+	}, true
+}
+
+// randomDomain returns a random domain from the store
+func genMsgSetRegistrationPolicy(r *rand.Rand, ctx sdk.Context, accs []simulation.Account, k keeper.Keeper) (sdk.Msg, bool) {
+	domain, ok := randomDomain(r, ctx, k)
+	if !ok || domain.Type != types.ClosedDomain {
+		return nil, false
+	}
+	return &types.MsgSetRegistrationPolicy{
+		Domain:             domain.Name,
+		Owner:              domain.Admin,
+		AllowList:          randomAddresses(r, accs, 3),
+		CertificateIssuers: randomAddresses(r, accs, 2),
+		InviteCodes:        r.Intn(2) == 0,
+	}, true
+}
+
+func genMsgOfferDomainTransfer(r *rand.Rand, ctx sdk.Context, accs []simulation.Account, k keeper.Keeper) (sdk.Msg, bool) {
+	domain, ok := randomDomain(r, ctx, k)
+	if !ok {
+		return nil, false
+	}
+	recipient, _ := simulation.RandomAcc(r, accs)
+	return &types.MsgOfferDomainTransfer{
+		Domain:       domain.Name,
+		Owner:        domain.Admin,
+		Recipient:    recipient.Address,
+		TransferFlag: types.TransferFlag(r.Intn(int(types.TransferAll) + 1)),
+		Expiry:       randomOfferExpiry(r, ctx),
+	}, true
+}
+
+func genMsgOfferAccountTransfer(r *rand.Rand, ctx sdk.Context, accs []simulation.Account, k keeper.Keeper) (sdk.Msg, bool) {
+	account, ok := randomAccount(r, ctx, k)
+	if !ok {
+		return nil, false
+	}
+	recipient, _ := simulation.RandomAcc(r, accs)
+	return &types.MsgOfferAccountTransfer{
+		Domain:    account.Domain,
+		Name:      *account.Name,
+		Owner:     accountManager(ctx, k, account),
+		Recipient: recipient.Address,
+		Reset:     r.Intn(2) == 0,
+		Expiry:    randomOfferExpiry(r, ctx),
+	}, true
+}
+
+func genMsgAcceptTransferOffer(r *rand.Rand, ctx sdk.Context, _ []simulation.Account, k keeper.Keeper) (sdk.Msg, bool) {
+	offer, ok := randomTransferOffer(r, ctx, k)
+	if !ok {
+		return nil, false
+	}
+	return &types.MsgAcceptTransferOffer{
+		ID:        offer.ID,
+		Recipient: offer.Recipient,
+	}, true
+}
+
+func genMsgRejectTransferOffer(r *rand.Rand, ctx sdk.Context, _ []simulation.Account, k keeper.Keeper) (sdk.Msg, bool) {
+	offer, ok := randomTransferOffer(r, ctx, k)
+	if !ok {
+		return nil, false
+	}
+	return &types.MsgRejectTransferOffer{
+		ID:        offer.ID,
+		Recipient: offer.Recipient,
+	}, true
+}
+
+func genMsgCancelTransferOffer(r *rand.Rand, ctx sdk.Context, _ []simulation.Account, k keeper.Keeper) (sdk.Msg, bool) {
+	offer, ok := randomTransferOffer(r, ctx, k)
+	if !ok {
+		return nil, false
+	}
+	return &types.MsgCancelTransferOffer{
+		ID:    offer.ID,
+		Owner: offer.Owner,
+	}, true
+}
+
+func genMsgDepositSponsorship(r *rand.Rand, ctx sdk.Context, accs []simulation.Account, k keeper.Keeper) (sdk.Msg, bool) {
+	sponsor, _ := simulation.RandomAcc(r, accs)
+	msg := &types.MsgDepositSponsorship{
+		Sponsor: sponsor.Address,
+		Amount:  sdk.NewCoins(sdk.NewInt64Coin(k.ConfigurationKeeper.GetFees(ctx).FeeCoinDenom, int64(simulation.RandIntBetween(r, 1, 1000)))),
+	}
+	if r.Intn(2) == 0 {
+		domain, ok := randomDomain(r, ctx, k)
+		if !ok {
+			return nil, false
+		}
+		msg.Domain = domain.Name
+		return msg, true
+	}
+	account, ok := randomAccount(r, ctx, k)
+	if !ok {
+		return nil, false
+	}
+	msg.Domain, msg.Name = account.Domain, *account.Name
+	return msg, true
+}
+
+func genMsgWithdrawSponsorship(r *rand.Rand, ctx sdk.Context, _ []simulation.Account, k keeper.Keeper) (sdk.Msg, bool) {
+	store := k.SponsorshipStore(ctx)
+	pk, ok := randomKey(r, store)
+	if !ok {
+		return nil, false
+	}
+	sponsorship := new(types.Sponsorship)
+	store.Read(pk, sponsorship)
+	msg := &types.MsgWithdrawSponsorship{
+		Domain:  sponsorship.Domain,
+		Name:    sponsorship.Name,
+		Sponsor: sponsorship.Sponsor,
+	}
+	// withdraw either the whole balance or a part of it
+	if r.Intn(2) == 0 && !sponsorship.Balance.Empty() {
+		coin := sponsorship.Balance[0]
+		msg.Amount = sdk.NewCoins(sdk.NewCoin(coin.Denom, simulation.RandomAmount(r, coin.Amount)))
+	}
+	return msg, true
+}
+
+func genMsgTransferAccounts(r *rand.Rand, ctx sdk.Context, accs []simulation.Account, k keeper.Keeper) (sdk.Msg, bool) {
+	domain, ok := randomClosedDomain(r, ctx, k)
+	if !ok {
+		return nil, false
+	}
+	// pick a random subset of the domain accounts
+	var names []string
+	filter := k.AccountStore(ctx).Filter(&types.Account{Domain: domain.Name})
+	for ; filter.Valid() && len(names) < types.BulkAccountsMax; filter.Next() {
+		account := new(types.Account)
+		filter.Read(account)
+		if *account.Name != types.EmptyAccountName && r.Intn(2) == 0 {
+			names = append(names, *account.Name)
+		}
+	}
+	if len(names) == 0 {
+		return nil, false
+	}
+	newOwner, _ := simulation.RandomAcc(r, accs)
+	return &types.MsgTransferAccounts{
+		Domain:   domain.Name,
+		Names:    names,
+		Owner:    domain.Admin,
+		NewOwner: newOwner.Address,
+		Reset:    r.Intn(2) == 0,
+	}, true
+}
+
+func genMsgDeleteAccountsByOwner(r *rand.Rand, ctx sdk.Context, _ []simulation.Account, k keeper.Keeper) (sdk.Msg, bool) {
+	account, ok := randomAccount(r, ctx, k)
+	if !ok {
+		return nil, false
+	}
+	return &types.MsgDeleteAccountsByOwner{
+		Domain:       account.Domain,
+		Owner:        accountManager(ctx, k, account),
+		AccountOwner: account.Owner,
+	}, true
+}
+
+func genMsgSetAccountsResource(r *rand.Rand, ctx sdk.Context, _ []simulation.Account, k keeper.Keeper) (sdk.Msg, bool) {
+	domain, ok := randomClosedDomain(r, ctx, k)
+	if !ok {
+		return nil, false
+	}
+	return &types.MsgSetAccountsResource{
+		Domain: domain.Name,
+		Owner:  domain.Admin,
+		Resource: types.Resource{
+			URI:      fmt.Sprintf("asset:%d:%s", r.Intn(3), simutil.RandName(r, 2, 8)),
+			Resource: simulation.RandStringOfLength(r, simulation.RandIntBetween(r, 4, 32)),
+		},
+	}, true
+}
+
+func genMsgSetAccountValidUntil(r *rand.Rand, ctx sdk.Context, _ []simulation.Account, k keeper.Keeper) (sdk.Msg, bool) {
+	account, ok := randomAccount(r, ctx, k)
+	if !ok {
+		return nil, false
+	}
+	domain := new(types.Domain)
+	if !k.DomainStore(ctx).Read((&types.Domain{Name: account.Domain}).PrimaryKey(), domain) || domain.Type != types.ClosedDomain {
+		return nil, false
+	}
+	return &types.MsgSetAccountValidUntil{
+		Domain:     account.Domain,
+		Name:       *account.Name,
+		Owner:      domain.Admin,
+		ValidUntil: randomAccountValidUntil(r, ctx, *domain),
+	}, true
+}
+
+// randomAccountValidUntil returns either no expiration or an expiration
+// between the block time and the expiration of the closed domain
+func randomAccountValidUntil(r *rand.Rand, ctx sdk.Context, domain types.Domain) int64 {
+	now := ctx.BlockTime().Unix()
+	if r.Intn(2) == 0 || domain.ValidUntil <= now+1 {
+		return 0
+	}
+	return now + 1 + r.Int63n(domain.ValidUntil-now)
+}
+
+// randomClosedDomain returns a random closed domain from the store
+func randomClosedDomain(r *rand.Rand, ctx sdk.Context, k keeper.Keeper) (types.Domain, bool) {
+	domain, ok := randomDomain(r, ctx, k)
+	if !ok || domain.Type != types.ClosedDomain {
+		return types.Domain{}, false
+	}
+	return domain, true
+}
+
+// randomOfferExpiry returns either no expiry or an expiry up to one hour after the block time
+func randomOfferExpiry(r *rand.Rand, ctx sdk.Context) int64 {
+	if r.Intn(2) == 0 {
+		return 0
+	}
+	return ctx.BlockTime().Unix() + 1 + r.Int63n(3600)
+}
+
+// randomTransferOffer returns a random pending transfer offer from the store
+func randomTransferOffer(r *rand.Rand, ctx sdk.Context, k keeper.Keeper) (types.TransferOffer, bool) {
+	os := k.TransferOfferStore(ctx)
+	pk, ok := randomKey(r, os)
+	if !ok {
+		return types.TransferOffer{}, false
+	}
+	offer := new(types.TransferOffer)
+	os.Read(pk, offer)
+	return *offer, true
+}
+
+// randomAddresses returns up to max distinct addresses of the simulation accounts
+func randomAddresses(r *rand.Rand, accs []simulation.Account, max int) []sdk.AccAddress {
+	n := r.Intn(max + 1)
+	if n > len(accs) {
+		n = len(accs)
+	}
+	addrs := make([]sdk.AccAddress, n)
+	for i, j := range r.Perm(len(accs))[:n] {
+		addrs[i] = accs[j].Address
+	}
+	return addrs
+}
+
+func randomDomain(r *rand.Rand, ctx sdk.Context, k keeper.Keeper) (types.Domain, bool) {
+	ds := k.DomainStore(ctx)
+	pk, ok := randomKey(r, ds)
+	if !ok {
+		return types.Domain{}, false
+	}
+	domain := new(types.Domain)
+	ds.Read(pk, domain)
+	return *domain, true
+}
+
+// randomAccount returns a random account from the store, empty accounts excluded
+func randomAccount(r *rand.Rand, ctx sdk.Context, k keeper.Keeper) (types.Account, bool) {
+	as := k.AccountStore(ctx)
+	pk, ok := randomKey(r, as)
+	if !ok {
+		return types.Account{}, false
+	}
+	account := new(types.Account)
+	as.Read(pk, account)
+	if account.Name == nil || *account.Name == types.EmptyAccountName {
+		return types.Account{}, false
+	}
+	return *account, true
+}
+
+// randomKey returns a random primary key of the store
+func randomKey(r *rand.Rand, store crud.Store) (crud.PrimaryKey, bool) {
+	var keys []crud.PrimaryKey
+	store.IterateKeys(func(pk crud.PrimaryKey) bool {
+		keys = append(keys, pk)
+		return true
+	})
+	if len(keys) == 0 {
+		return nil, false
+	}
+	return keys[r.Intn(len(keys))], true
+}
+
+// accountManager returns the address allowed to transfer or delete the account,
+// which is the domain admin in closed domains and the account owner otherwise
+func accountManager(ctx sdk.Context, k keeper.Keeper, account types.Account) sdk.AccAddress {
+	domain := new(types.Domain)
+	if k.DomainStore(ctx).Read((&types.Domain{Name: account.Domain}).PrimaryKey(), domain) && domain.Type == types.ClosedDomain {
+		return domain.Admin
+	}
+	return account.Owner
+}
@@ -0,0 +1,105 @@
+package starname
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/iov-one/iovns/x/starname/controllers/account"
+	"github.com/iov-one/iovns/x/starname/controllers/domain"
+	"github.com/iov-one/iovns/x/starname/controllers/fees"
+	"github.com/iov-one/iovns/x/starname/keeper"
+	"github.com/iov-one/iovns/x/starname/types"
+)
+
+// handlerMsgDepositSponsorship adds coins to the sponsorship of a domain or an account
+func handlerMsgDepositSponsorship(ctx sdk.Context, k keeper.Keeper, msg *types.MsgDepositSponsorship) (*sdk.Result, error) {
+	domainCtrl := domain.NewController(ctx, k, msg.Domain)
+	if err := domainCtrl.MustExist().Validate(); err != nil {
+		return nil, err
+	}
+	// accounts of closed domains expire with their domain so they cannot be renewed
+	if msg.Name != "" {
+		if err := domainCtrl.Type(types.OpenDomain).Validate(); err != nil {
+			return nil, err
+		}
+		if err := account.NewController(ctx, k, msg.Domain, msg.Name).MustExist().Validate(); err != nil {
+			return nil, err
+		}
+	}
+	// renewal fees are paid only in the fee coin
+	feeDenom := k.ConfigurationKeeper.GetFees(ctx).FeeCoinDenom
+	if len(msg.Amount) != 1 || msg.Amount[0].Denom != feeDenom {
+		return nil, sdkerrors.Wrapf(types.ErrInvalidSponsorship, "only %s can be deposited, got %s", feeDenom, msg.Amount)
+	}
+	// collect fees
+	feeCtrl := fees.NewController(ctx, k, domainCtrl.Domain())
+	fee := feeCtrl.GetFee(msg)
+	if err := k.CollectFees(ctx, msg, fee); err != nil {
+		return nil, sdkerrors.Wrap(err, "unable to collect fees")
+	}
+	// deposit
+	sponsorship, ok := k.GetSponsorship(ctx, msg.Domain, msg.Name, msg.Sponsor)
+	if !ok {
+		sponsorship = types.Sponsorship{Domain: msg.Domain, Name: msg.Name, Sponsor: msg.Sponsor}
+	}
+	sponsorship, err := k.DepositSponsorship(ctx, sponsorship, msg.Amount)
+	if err != nil {
+		return nil, sdkerrors.Wrap(err, "unable to deposit sponsorship")
+	}
+	// success
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			sdk.EventTypeMessage,
+			sdk.NewAttribute(sdk.AttributeKeyModule, types.ModuleName),
+			sdk.NewAttribute(sdk.AttributeKeySender, msg.Sponsor.String()),
+			sdk.NewAttribute(sdk.AttributeKeyAction, msg.Type()),
+			sdk.NewAttribute(types.AttributeKeyDomainName, msg.Domain),
+			sdk.NewAttribute(types.AttributeKeyAccountName, msg.Name),
+			sdk.NewAttribute(types.AttributeKeySponsorshipAmount, msg.Amount.String()),
+			sdk.NewAttribute(types.AttributeKeySponsorshipBalance, sponsorship.Balance.String()),
+		),
+	)
+	return &sdk.Result{
+		Events: ctx.EventManager().Events(),
+	}, nil
+}
+
+// handlerMsgWithdrawSponsorship sends coins of a sponsorship back to its sponsor
+func handlerMsgWithdrawSponsorship(ctx sdk.Context, k keeper.Keeper, msg *types.MsgWithdrawSponsorship) (*sdk.Result, error) {
+	sponsorship, ok := k.GetSponsorship(ctx, msg.Domain, msg.Name, msg.Sponsor)
+	if !ok {
+		return nil, sdkerrors.Wrapf(types.ErrSponsorshipDoesNotExist, "not found: %s by %s", types.Sponsorship{Domain: msg.Domain, Name: msg.Name}.Starname(), msg.Sponsor)
+	}
+	amount := msg.Amount
+	if amount.Empty() {
+		amount = sponsorship.Balance
+	}
+	// collect fees, the sponsored domain might have been deleted in the current block
+	d := new(types.Domain)
+	k.DomainStore(ctx).Read((&types.Domain{Name: msg.Domain}).PrimaryKey(), d)
+	feeCtrl := fees.NewController(ctx, k, *d)
+	fee := feeCtrl.GetFee(msg)
+	if err := k.CollectFees(ctx, msg, fee); err != nil {
+		return nil, sdkerrors.Wrap(err, "unable to collect fees")
+	}
+	// withdraw
+	sponsorship, err := k.WithdrawSponsorship(ctx, sponsorship, amount)
+	if err != nil {
+		return nil, sdkerrors.Wrap(err, "unable to withdraw sponsorship")
+	}
+	// success
+	ctx.EventManager().EmitEvent(
+		sdk.NewEvent(
+			sdk.EventTypeMessage,
+			sdk.NewAttribute(sdk.AttributeKeyModule, types.ModuleName),
+			sdk.NewAttribute(sdk.AttributeKeySender, msg.Sponsor.String()),
+			sdk.NewAttribute(sdk.AttributeKeyAction, msg.Type()),
+			sdk.NewAttribute(types.AttributeKeyDomainName, msg.Domain),
+			sdk.NewAttribute(types.AttributeKeyAccountName, msg.Name),
+			sdk.NewAttribute(types.AttributeKeySponsorshipAmount, amount.String()),
+			sdk.NewAttribute(types.AttributeKeySponsorshipBalance, sponsorship.Balance.String()),
+		),
+	)
+	return &sdk.Result{
+		Events: ctx.EventManager().Events(),
+	}, nil
+}
@@ -0,0 +1,329 @@
+package starname
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/iov-one/iovns/pkg/utils"
+	"github.com/iov-one/iovns/x/configuration"
+	"github.com/iov-one/iovns/x/starname/controllers/fees"
+	"github.com/iov-one/iovns/x/starname/keeper"
+	"github.com/iov-one/iovns/x/starname/keeper/executor"
+	"github.com/iov-one/iovns/x/starname/types"
+)
+
+func Test_handlerSponsorships(t *testing.T) {
+	// createDomain creates an open domain owned by alice expiring in 12 hours
+	// with an account owned by bob expiring in 1000 hours
+	createDomain := func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+		keeper.GetConfigSetter(k.ConfigurationKeeper).SetConfig(ctx, configuration.Config{
+			DomainRenewalPeriod:    365 * 24 * time.Hour,
+			DomainRenewalCountMax:  2,
+			DomainGracePeriod:      24 * time.Hour,
+			AccountRenewalPeriod:   365 * 24 * time.Hour,
+			AccountRenewalCountMax: 2,
+		})
+		executor.NewDomain(ctx, k, types.Domain{
+			Name:       "test",
+			Admin:      keeper.AliceKey,
+			ValidUntil: utils.TimeToSeconds(ctx.BlockTime().Add(12 * time.Hour)),
+			Type:       types.OpenDomain,
+		}).Create()
+		executor.NewAccount(ctx, k, types.Account{
+			Domain:     "test",
+			Name:       utils.StrPtr("test"),
+			Owner:      keeper.BobKey,
+			ValidUntil: utils.TimeToSeconds(ctx.BlockTime().Add(1000 * time.Hour)),
+		}).Create()
+	}
+	coins := func(amount int64) sdk.Coins {
+		return sdk.NewCoins(sdk.NewInt64Coin("testcoin", amount))
+	}
+	// sponsor deposits amount to renew the domain
+	sponsor := func(t *testing.T, k keeper.Keeper, ctx sdk.Context, amount sdk.Coins) {
+		_, err := handlerMsgDepositSponsorship(ctx, k, &types.MsgDepositSponsorship{
+			Domain:  "test",
+			Sponsor: keeper.CharlieKey,
+			Amount:  amount,
+		})
+		if err != nil {
+			t.Fatalf("handlerMsgDepositSponsorship() got error: %s", err)
+		}
+	}
+	renewFee := func(k keeper.Keeper, ctx sdk.Context) sdk.Coin {
+		domain := new(types.Domain)
+		k.DomainStore(ctx).Read((&types.Domain{Name: "test"}).PrimaryKey(), domain)
+		return fees.NewController(ctx, k, *domain).GetFee(&types.MsgRenewDomain{Domain: "test"})
+	}
+	cases := map[string]keeper.SubTest{
+		"success deposit twice": {
+			BeforeTest: createDomain,
+			Test: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				var deposited sdk.Coins
+				mocks.Supply.SetSendCoinsFromAccountToModule(func(ctx sdk.Context, addr sdk.AccAddress, moduleName string, coins sdk.Coins) error {
+					if moduleName == types.ModuleName && addr.Equals(keeper.CharlieKey) {
+						deposited = deposited.Add(coins...)
+					}
+					return nil
+				})
+				sponsor(t, k, ctx, coins(10))
+				sponsor(t, k, ctx, coins(5))
+				if !deposited.IsEqual(coins(15)) {
+					t.Fatalf("unexpected deposited amount: %s", deposited)
+				}
+			},
+			AfterTest: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				sponsorship, ok := k.GetSponsorship(ctx, "test", "", keeper.CharlieKey)
+				if !ok {
+					t.Fatal("sponsorship not found")
+				}
+				if !sponsorship.Balance.IsEqual(coins(15)) {
+					t.Fatalf("unexpected balance: %s", sponsorship.Balance)
+				}
+			},
+		},
+		"fail deposit in another denom": {
+			BeforeTest: createDomain,
+			Test: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				_, err := handlerMsgDepositSponsorship(ctx, k, &types.MsgDepositSponsorship{
+					Domain:  "test",
+					Sponsor: keeper.CharlieKey,
+					Amount:  sdk.NewCoins(sdk.NewInt64Coin("other", 10)),
+				})
+				if !errors.Is(err, types.ErrInvalidSponsorship) {
+					t.Fatalf("handlerMsgDepositSponsorship() want: %s, got: %s", types.ErrInvalidSponsorship, err)
+				}
+			},
+		},
+		"fail deposit for missing account": {
+			BeforeTest: createDomain,
+			Test: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				_, err := handlerMsgDepositSponsorship(ctx, k, &types.MsgDepositSponsorship{
+					Domain:  "test",
+					Name:    "none",
+					Sponsor: keeper.CharlieKey,
+					Amount:  coins(10),
+				})
+				if !errors.Is(err, types.ErrAccountDoesNotExist) {
+					t.Fatalf("handlerMsgDepositSponsorship() want: %s, got: %s", types.ErrAccountDoesNotExist, err)
+				}
+			},
+		},
+		"success withdraw part then all": {
+			BeforeTest: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				createDomain(t, k, ctx, mocks)
+				sponsor(t, k, ctx, coins(10))
+			},
+			Test: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				var refunded sdk.Coins
+				mocks.Supply.SetSendCoinsFromModuleToAccount(func(ctx sdk.Context, moduleName string, addr sdk.AccAddress, coins sdk.Coins) error {
+					refunded = refunded.Add(coins...)
+					return nil
+				})
+				msg := &types.MsgWithdrawSponsorship{Domain: "test", Sponsor: keeper.CharlieKey, Amount: coins(11)}
+				if _, err := handlerMsgWithdrawSponsorship(ctx, k, msg); err == nil {
+					t.Fatal("handlerMsgWithdrawSponsorship() expected error withdrawing more than the balance")
+				}
+				msg.Amount = coins(4)
+				if _, err := handlerMsgWithdrawSponsorship(ctx, k, msg); err != nil {
+					t.Fatalf("handlerMsgWithdrawSponsorship() got error: %s", err)
+				}
+				if sponsorship, _ := k.GetSponsorship(ctx, "test", "", keeper.CharlieKey); !sponsorship.Balance.IsEqual(coins(6)) {
+					t.Fatalf("unexpected balance: %s", sponsorship.Balance)
+				}
+				msg.Amount = nil
+				if _, err := handlerMsgWithdrawSponsorship(ctx, k, msg); err != nil {
+					t.Fatalf("handlerMsgWithdrawSponsorship() got error: %s", err)
+				}
+				if !refunded.IsEqual(coins(10)) {
+					t.Fatalf("unexpected refunded amount: %s", refunded)
+				}
+			},
+			AfterTest: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				if _, ok := k.GetSponsorship(ctx, "test", "", keeper.CharlieKey); ok {
+					t.Fatal("sponsorship should not exist")
+				}
+			},
+		},
+		"fail withdraw sponsorship does not exist": {
+			BeforeTest: createDomain,
+			Test: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				_, err := handlerMsgWithdrawSponsorship(ctx, k, &types.MsgWithdrawSponsorship{
+					Domain:  "test",
+					Sponsor: keeper.CharlieKey,
+				})
+				if !errors.Is(err, types.ErrSponsorshipDoesNotExist) {
+					t.Fatalf("handlerMsgWithdrawSponsorship() want: %s, got: %s", types.ErrSponsorshipDoesNotExist, err)
+				}
+			},
+		},
+		"success end blocker renews domain in the renewal window": {
+			BeforeTest: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				createDomain(t, k, ctx, mocks)
+				sponsor(t, k, ctx, coins(1000))
+			},
+			Test: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				var feePayer sdk.AccAddress
+				mocks.Supply.SetSendCoinsFromAccountToModule(func(ctx sdk.Context, addr sdk.AccAddress, moduleName string, coins sdk.Coins) error {
+					feePayer = addr
+					return nil
+				})
+				fee := renewFee(k, ctx)
+				EndBlocker(ctx, k)
+				if !feePayer.Equals(k.ModuleAddress()) {
+					t.Fatalf("unexpected fee payer: %s", feePayer)
+				}
+				sponsorship, _ := k.GetSponsorship(ctx, "test", "", keeper.CharlieKey)
+				if !sponsorship.Balance.IsEqual(coins(1000).Sub(sdk.NewCoins(fee))) {
+					t.Fatalf("unexpected balance: %s", sponsorship.Balance)
+				}
+			},
+			AfterTest: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				domain := new(types.Domain)
+				k.DomainStore(ctx).Read((&types.Domain{Name: "test"}).PrimaryKey(), domain)
+				if !utils.SecondsToTime(domain.ValidUntil).After(ctx.BlockTime().Add(types.SponsoredRenewalWindow)) {
+					t.Fatalf("domain not renewed: %d", domain.ValidUntil)
+				}
+				// the account is outside the renewal window
+				sponsorship := types.Sponsorship{Domain: "test", Name: "test", Sponsor: keeper.CharlieKey, Balance: coins(1000)}
+				k.SetSponsorship(ctx, sponsorship)
+				EndBlocker(ctx, k)
+				if s, _ := k.GetSponsorship(ctx, "test", "test", keeper.CharlieKey); !s.Balance.IsEqual(sponsorship.Balance) {
+					t.Fatalf("unexpected renewal of the account: %s", s.Balance)
+				}
+			},
+		},
+		"success end blocker closes exhausted sponsorship": {
+			BeforeTest: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				createDomain(t, k, ctx, mocks)
+				// make the renewal cost more than one coin
+				fees := configuration.NewFees()
+				fees.SetDefaults("testcoin")
+				fees.FeeCoinPrice = sdk.OneDec()
+				k.ConfigurationKeeper.(keeper.ConfigurationSetter).SetFees(ctx, fees)
+				fee := renewFee(k, ctx)
+				sponsor(t, k, ctx, sdk.NewCoins(sdk.NewCoin(fee.Denom, fee.Amount.SubRaw(1))))
+			},
+			Test: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				var refunded sdk.Coins
+				mocks.Supply.SetSendCoinsFromModuleToAccount(func(ctx sdk.Context, moduleName string, addr sdk.AccAddress, coins sdk.Coins) error {
+					if addr.Equals(keeper.CharlieKey) {
+						refunded = coins
+					}
+					return nil
+				})
+				fee := renewFee(k, ctx)
+				EndBlocker(ctx, k)
+				if !refunded.IsEqual(sdk.NewCoins(sdk.NewCoin(fee.Denom, fee.Amount.SubRaw(1)))) {
+					t.Fatalf("unexpected refunded amount: %s", refunded)
+				}
+			},
+			AfterTest: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				if _, ok := k.GetSponsorship(ctx, "test", "", keeper.CharlieKey); ok {
+					t.Fatal("sponsorship should not exist")
+				}
+			},
+		},
+		"success end blocker closes sponsorship of deleted account": {
+			BeforeTest: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				createDomain(t, k, ctx, mocks)
+				k.SetSponsorship(ctx, types.Sponsorship{Domain: "test", Name: "none", Sponsor: keeper.CharlieKey, Balance: coins(10)})
+			},
+			Test: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				EndBlocker(ctx, k)
+			},
+			AfterTest: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				if _, ok := k.GetSponsorship(ctx, "test", "none", keeper.CharlieKey); ok {
+					t.Fatal("sponsorship should not exist")
+				}
+			},
+		},
+		"success end blocker renews account once it enters the renewal window": {
+			BeforeTest: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				createDomain(t, k, ctx, mocks)
+				_, err := handlerMsgDepositSponsorship(ctx, k, &types.MsgDepositSponsorship{
+					Domain:  "test",
+					Name:    "test",
+					Sponsor: keeper.CharlieKey,
+					Amount:  coins(1000),
+				})
+				if err != nil {
+					t.Fatalf("handlerMsgDepositSponsorship() got error: %s", err)
+				}
+			},
+			Test: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				account := new(types.Account)
+				key := (&types.Account{Domain: "test", Name: utils.StrPtr("test")}).PrimaryKey()
+				k.AccountStore(ctx).Read(key, account)
+				expiry := account.ValidUntil
+				// outside the window
+				EndBlocker(ctx.WithBlockTime(ctx.BlockTime().Add(900*time.Hour)), k)
+				if k.AccountStore(ctx).Read(key, account); account.ValidUntil != expiry {
+					t.Fatalf("account renewed outside the renewal window: %d", account.ValidUntil)
+				}
+				// inside the window
+				EndBlocker(ctx.WithBlockTime(utils.SecondsToTime(expiry).Add(-time.Hour)), k)
+				if k.AccountStore(ctx).Read(key, account); account.ValidUntil == expiry {
+					t.Fatal("account not renewed inside the renewal window")
+				}
+				sponsorship, _ := k.GetSponsorship(ctx, "test", "test", keeper.CharlieKey)
+				if sponsorship.Balance.IsEqual(coins(1000)) {
+					t.Fatal("sponsorship not charged")
+				}
+			},
+		},
+		"success end blocker retries refused renewals after the retry delay": {
+			BeforeTest: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				createDomain(t, k, ctx, mocks)
+				sponsor(t, k, ctx, coins(1000))
+			},
+			Test: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				attempts := 0
+				mocks.Supply.SetSendCoinsFromAccountToModule(func(ctx sdk.Context, addr sdk.AccAddress, moduleName string, coins sdk.Coins) error {
+					attempts++
+					return errors.New("refused")
+				})
+				EndBlocker(ctx, k)
+				EndBlocker(ctx.WithBlockTime(ctx.BlockTime().Add(time.Minute)), k)
+				if attempts != 1 {
+					t.Fatalf("expected 1 renewal attempt before the retry delay, got %d", attempts)
+				}
+				EndBlocker(ctx.WithBlockTime(ctx.BlockTime().Add(types.SponsoredRenewalRetryDelay)), k)
+				if attempts != 2 {
+					t.Fatalf("expected 2 renewal attempts after the retry delay, got %d", attempts)
+				}
+				if s, _ := k.GetSponsorship(ctx, "test", "", keeper.CharlieKey); !s.Balance.IsEqual(coins(1000)) {
+					t.Fatalf("unexpected balance: %s", s.Balance)
+				}
+			},
+		},
+		"success end blocker reports sponsorships which cannot be closed": {
+			BeforeTest: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				createDomain(t, k, ctx, mocks)
+				k.SetSponsorship(ctx, types.Sponsorship{Domain: "test", Name: "none", Sponsor: keeper.CharlieKey, Balance: coins(10)})
+			},
+			Test: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				mocks.Supply.SetSendCoinsFromModuleToAccount(func(ctx sdk.Context, moduleName string, addr sdk.AccAddress, coins sdk.Coins) error {
+					return errors.New("refused")
+				})
+				ctx = ctx.WithEventManager(sdk.NewEventManager())
+				EndBlocker(ctx, k)
+				failed := false
+				for _, event := range ctx.EventManager().Events() {
+					failed = failed || event.Type == types.EventTypeCloseSponsorshipFailed
+				}
+				if !failed {
+					t.Fatalf("missing %s event", types.EventTypeCloseSponsorshipFailed)
+				}
+			},
+			AfterTest: func(t *testing.T, k keeper.Keeper, ctx sdk.Context, mocks *keeper.Mocks) {
+				if _, ok := k.GetSponsorship(ctx, "test", "none", keeper.CharlieKey); !ok {
+					t.Fatal("sponsorship should still exist")
+				}
+			},
+		},
+	}
+	keeper.RunTests(t, cases)
+}
@@ -11,6 +11,7 @@ var ModuleCdc = codec.New()
 
 func init() {
 	RegisterCodec(ModuleCdc)
+	codec.RegisterCrypto(ModuleCdc)
 }
 
 // RegisterCodec registers the sdk.Msg for the module
@@ -27,4 +28,16 @@ func RegisterCodec(cdc *codec.Codec) {
 	cdc.RegisterConcrete(&MsgRenewDomain{}, fmt.Sprintf("%s/RenewDomain", ModuleName), nil)
 	cdc.RegisterConcrete(&MsgReplaceAccountResources{}, fmt.Sprintf("%s/ReplaceAccountResources", ModuleName), nil)
 	cdc.RegisterConcrete(&MsgReplaceAccountMetadata{}, fmt.Sprintf("%s/SetAccountMetadata", ModuleName), nil)
+	cdc.RegisterConcrete(&MsgSetRegistrationPolicy{}, fmt.Sprintf("%s/SetRegistrationPolicy", ModuleName), nil)
+	cdc.RegisterConcrete(&MsgOfferDomainTransfer{}, fmt.Sprintf("%s/OfferDomainTransfer", ModuleName), nil)
+	cdc.RegisterConcrete(&MsgOfferAccountTransfer{}, fmt.Sprintf("%s/OfferAccountTransfer", ModuleName), nil)
+	cdc.RegisterConcrete(&MsgAcceptTransferOffer{}, fmt.Sprintf("%s/AcceptTransferOffer", ModuleName), nil)
+	cdc.RegisterConcrete(&MsgRejectTransferOffer{}, fmt.Sprintf("%s/RejectTransferOffer", ModuleName), nil)
+	cdc.RegisterConcrete(&MsgCancelTransferOffer{}, fmt.Sprintf("%s/CancelTransferOffer", ModuleName), nil)
+	cdc.RegisterConcrete(&MsgDepositSponsorship{}, fmt.Sprintf("%s/DepositSponsorship", ModuleName), nil)
+	cdc.RegisterConcrete(&MsgWithdrawSponsorship{}, fmt.Sprintf("%s/WithdrawSponsorship", ModuleName), nil)
+	cdc.RegisterConcrete(&MsgTransferAccounts{}, fmt.Sprintf("%s/TransferAccounts", ModuleName), nil)
+	cdc.RegisterConcrete(&MsgDeleteAccountsByOwner{}, fmt.Sprintf("%s/DeleteAccountsByOwner", ModuleName), nil)
+	cdc.RegisterConcrete(&MsgSetAccountsResource{}, fmt.Sprintf("%s/SetAccountsResource", ModuleName), nil)
+	cdc.RegisterConcrete(&MsgSetAccountValidUntil{}, fmt.Sprintf("%s/SetAccountValidUntil", ModuleName), nil)
 }
@@ -101,3 +101,47 @@ var ErrStarnameNotContainSep = sdkerrors.Register(ModuleName, 29, "starname does
 
 // ErrStarnameMultipleSeparator returned when provided starname contains more than one separator
 var ErrStarnameMultipleSeparator = sdkerrors.Register(ModuleName, 30, "starname should contain single separator")
+
+// ErrInvalidProof is returned when a merkle proof of the starname store cannot be verified
+var ErrInvalidProof = sdkerrors.Register(ModuleName, 32, "invalid merkle proof")
+
+// ErrInvalidCredential is returned when a registration credential is malformed or its signature is invalid
+var ErrInvalidCredential = sdkerrors.Register(ModuleName, 33, "invalid registration credential")
+
+// ErrInviteCodeUsed is returned when an invite code was already used to register an account
+var ErrInviteCodeUsed = sdkerrors.Register(ModuleName, 34, "invite code already used")
+
+// ErrRegistrationPolicyDoesNotExist is returned when a domain has no registration policy
+var ErrRegistrationPolicyDoesNotExist = sdkerrors.Register(ModuleName, 35, "registration policy does not exist")
+
+// ErrInvalidTransferOffer is returned when a transfer offer is malformed
+var ErrInvalidTransferOffer = sdkerrors.Register(ModuleName, 36, "invalid transfer offer")
+
+// ErrTransferOfferDoesNotExist is returned when a transfer offer is not found
+var ErrTransferOfferDoesNotExist = sdkerrors.Register(ModuleName, 37, "transfer offer does not exist")
+
+// ErrTransferOfferExists is returned when a domain or an account already has a pending transfer offer
+var ErrTransferOfferExists = sdkerrors.Register(ModuleName, 38, "transfer offer already exists")
+
+// ErrTransferOfferExpired is returned when an expired transfer offer is accepted
+var ErrTransferOfferExpired = sdkerrors.Register(ModuleName, 39, "transfer offer expired")
+
+// ErrUnauthorizedTransferOffer is returned when an address other than the recipient
+// or the owner of a transfer offer tries to accept, reject or cancel it
+var ErrUnauthorizedTransferOffer = sdkerrors.Register(ModuleName, 40, "unauthorized transfer offer operation")
+
+// ErrInvalidSponsorship is returned when a sponsorship deposit or withdrawal is malformed
+var ErrInvalidSponsorship = sdkerrors.Register(ModuleName, 41, "invalid sponsorship")
+
+// ErrSponsorshipDoesNotExist is returned when a sponsorship is not found
+var ErrSponsorshipDoesNotExist = sdkerrors.Register(ModuleName, 42, "sponsorship does not exist")
+
+// ErrInvalidAccountValidUntil is returned when the expiration set on an account is not valid
+var ErrInvalidAccountValidUntil = sdkerrors.Register(ModuleName, 43, "invalid account expiration")
+
+// ErrInvalidHistoryRecord is returned when a history record of the genesis is malformed
+var ErrInvalidHistoryRecord = sdkerrors.Register(ModuleName, 44, "invalid history record")
+
+// ErrStarnameOffered is returned when the owner of a domain or an account tries to
+// transfer or delete it while one of its transfer offers is pending
+var ErrStarnameOffered = sdkerrors.Register(ModuleName, 45, "starname has a pending transfer offer")
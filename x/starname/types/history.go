@@ -0,0 +1,132 @@
+package types
+
+import (
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/errors"
+	crud "github.com/iov-one/cosmos-sdk-crud/pkg/crud/types"
+)
+
+const HistoryStarnameIndex = 0x1
+const HistoryDomainIndex = 0x2
+
+// HistoryPruneMax is the maximum number of history records older than the
+// history retention of the configuration removed at the end of a block
+const HistoryPruneMax = 100
+
+// HistoryAction defines the kind of change a history record refers to
+type HistoryAction string
+
+const (
+	// HistoryCreate is recorded when a domain or an account is registered
+	HistoryCreate HistoryAction = "create"
+	// HistoryDelete is recorded when a domain or an account is deleted
+	HistoryDelete HistoryAction = "delete"
+	// HistoryTransfer is recorded when the owner of a domain or an account changes
+	HistoryTransfer HistoryAction = "transfer"
+	// HistoryRenew is recorded when a domain or an account is renewed
+	HistoryRenew HistoryAction = "renew"
+	// HistorySetValidUntil is recorded when the admin of a closed domain sets the expiration
+	// of an account, which may be shortened or cleared
+	HistorySetValidUntil HistoryAction = "set_valid_until"
+	// HistoryReplaceResources is recorded when the resources of an account are replaced
+	HistoryReplaceResources HistoryAction = "replace_resources"
+	// HistoryUpdateMetadata is recorded when the metadata of an account is updated
+	HistoryUpdateMetadata HistoryAction = "update_metadata"
+)
+
+// HistoryRecord defines the state of a domain or an account
+// after a change was applied to it at a given height
+type HistoryRecord struct {
+	// ID is the sequence number of the record, it increases monotonically
+	ID uint64 `json:"id"`
+	// Starname identifies the object the change refers to, it is the
+	// domain name for domains and name*domain for accounts
+	Starname string `json:"starname"`
+	// Domain is the domain the change refers to
+	Domain string `json:"domain"`
+	// Height is the block height at which the change happened
+	Height int64 `json:"height"`
+	// Time is the unix timestamp of the block in which the change happened
+	Time int64 `json:"time"`
+	// Action defines the kind of change
+	Action HistoryAction `json:"action"`
+	// Owner is the owner of the domain or account after the change
+	Owner sdk.AccAddress `json:"owner"`
+	// ValidUntil is the expiration of the domain or account after the change
+	ValidUntil int64 `json:"valid_until"`
+	// Resources are the resources of the account after the change
+	Resources []Resource `json:"resources"`
+	// MetadataURI is the metadata of the account after the change
+	MetadataURI string `json:"metadata_uri"`
+}
+
+// Validate checks the record refers to a domain or to an account of its domain
+// and that its action and owner are set, records can refer to deleted names
+func (h HistoryRecord) Validate() error {
+	if h.Domain == "" {
+		return errors.Wrap(ErrInvalidHistoryRecord, "empty domain")
+	}
+	if h.Starname != h.Domain && !strings.HasSuffix(h.Starname, StarnameSeparator+h.Domain) {
+		return errors.Wrapf(ErrInvalidHistoryRecord, "starname %s does not belong to domain %s", h.Starname, h.Domain)
+	}
+	switch h.Action {
+	case HistoryCreate, HistoryDelete, HistoryTransfer, HistoryRenew, HistorySetValidUntil, HistoryReplaceResources, HistoryUpdateMetadata:
+	default:
+		return errors.Wrapf(ErrInvalidHistoryRecord, "unknown action %q", h.Action)
+	}
+	if h.Owner.Empty() {
+		return errors.Wrap(ErrInvalidHistoryRecord, "empty owner")
+	}
+	return nil
+}
+
+// NewAccountHistoryRecord builds the history record of an account change
+func NewAccountHistoryRecord(action HistoryAction, account Account) HistoryRecord {
+	return HistoryRecord{
+		Starname:    AccountStarname(account.Domain, *account.Name),
+		Domain:      account.Domain,
+		Action:      action,
+		Owner:       account.Owner,
+		ValidUntil:  account.ValidUntil,
+		Resources:   account.Resources,
+		MetadataURI: account.MetadataURI,
+	}
+}
+
+// NewDomainHistoryRecord builds the history record of a domain change
+func NewDomainHistoryRecord(action HistoryAction, domain Domain) HistoryRecord {
+	return HistoryRecord{
+		Starname:   domain.Name,
+		Domain:     domain.Name,
+		Action:     action,
+		Owner:      domain.Admin,
+		ValidUntil: domain.ValidUntil,
+	}
+}
+
+// AccountStarname returns the name*domain representation of an account
+func AccountStarname(domain, name string) string {
+	return strings.Join([]string{name, domain}, StarnameSeparator)
+}
+
+func (h *HistoryRecord) PrimaryKey() crud.PrimaryKey {
+	if h.ID == 0 {
+		return nil
+	}
+	return crud.NewPrimaryKey(sdk.Uint64ToBigEndian(h.ID))
+}
+
+func (h *HistoryRecord) SecondaryKeys() []crud.SecondaryKey {
+	var sk []crud.SecondaryKey
+	// index by starname
+	if h.Starname != "" {
+		sk = append(sk, crud.NewSecondaryKey(HistoryStarnameIndex, []byte(h.Starname)))
+	}
+	// index by domain
+	if h.Domain != "" {
+		sk = append(sk, crud.NewSecondaryKey(HistoryDomainIndex, []byte(h.Domain)))
+	}
+	return sk
+}
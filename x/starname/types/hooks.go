@@ -0,0 +1,104 @@
+package types
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// StarnameHooks defines the functions other modules can implement
+// in order to react to domains and accounts state changes
+type StarnameHooks interface {
+	// AfterDomainCreated is called after a domain and its empty account are created
+	AfterDomainCreated(ctx sdk.Context, domain Domain)
+	// AfterDomainRenewed is called after the expiration of a domain is extended
+	AfterDomainRenewed(ctx sdk.Context, domain Domain)
+	// AfterDomainTransferred is called after a domain admin changes,
+	// before the accounts of the domain are transferred
+	AfterDomainTransferred(ctx sdk.Context, domain Domain, oldAdmin sdk.AccAddress)
+	// BeforeDomainDeleted is called before a domain and its accounts are deleted
+	BeforeDomainDeleted(ctx sdk.Context, domain Domain)
+	// AfterAccountCreated is called after an account is created
+	AfterAccountCreated(ctx sdk.Context, account Account)
+	// AfterAccountRenewed is called after the expiration of an account is extended
+	AfterAccountRenewed(ctx sdk.Context, account Account)
+	// AfterAccountValidUntilSet is called after the admin of a closed domain sets the
+	// expiration of an account, which may be shortened or cleared
+	AfterAccountValidUntilSet(ctx sdk.Context, account Account)
+	// AfterAccountTransferred is called after an account owner changes
+	AfterAccountTransferred(ctx sdk.Context, account Account, oldOwner sdk.AccAddress)
+	// AfterAccountUpdated is called after the resources, the certificates
+	// or the metadata of an account change
+	AfterAccountUpdated(ctx sdk.Context, account Account)
+	// BeforeAccountDeleted is called before an account is deleted
+	BeforeAccountDeleted(ctx sdk.Context, account Account)
+}
+
+// MultiStarnameHooks combines multiple starname hooks,
+// which are called in the order they are provided
+type MultiStarnameHooks []StarnameHooks
+
+var _ StarnameHooks = MultiStarnameHooks(nil)
+
+// NewMultiStarnameHooks is the constructor of MultiStarnameHooks
+func NewMultiStarnameHooks(hooks ...StarnameHooks) MultiStarnameHooks {
+	return hooks
+}
+
+func (h MultiStarnameHooks) AfterDomainCreated(ctx sdk.Context, domain Domain) {
+	for _, hook := range h {
+		hook.AfterDomainCreated(ctx, domain)
+	}
+}
+
+func (h MultiStarnameHooks) AfterDomainRenewed(ctx sdk.Context, domain Domain) {
+	for _, hook := range h {
+		hook.AfterDomainRenewed(ctx, domain)
+	}
+}
+
+func (h MultiStarnameHooks) AfterDomainTransferred(ctx sdk.Context, domain Domain, oldAdmin sdk.AccAddress) {
+	for _, hook := range h {
+		hook.AfterDomainTransferred(ctx, domain, oldAdmin)
+	}
+}
+
+func (h MultiStarnameHooks) BeforeDomainDeleted(ctx sdk.Context, domain Domain) {
+	for _, hook := range h {
+		hook.BeforeDomainDeleted(ctx, domain)
+	}
+}
+
+func (h MultiStarnameHooks) AfterAccountCreated(ctx sdk.Context, account Account) {
+	for _, hook := range h {
+		hook.AfterAccountCreated(ctx, account)
+	}
+}
+
+func (h MultiStarnameHooks) AfterAccountRenewed(ctx sdk.Context, account Account) {
+	for _, hook := range h {
+		hook.AfterAccountRenewed(ctx, account)
+	}
+}
+
+func (h MultiStarnameHooks) AfterAccountValidUntilSet(ctx sdk.Context, account Account) {
+	for _, hook := range h {
+		hook.AfterAccountValidUntilSet(ctx, account)
+	}
+}
+
+func (h MultiStarnameHooks) AfterAccountTransferred(ctx sdk.Context, account Account, oldOwner sdk.AccAddress) {
+	for _, hook := range h {
+		hook.AfterAccountTransferred(ctx, account, oldOwner)
+	}
+}
+
+func (h MultiStarnameHooks) AfterAccountUpdated(ctx sdk.Context, account Account) {
+	for _, hook := range h {
+		hook.AfterAccountUpdated(ctx, account)
+	}
+}
+
+func (h MultiStarnameHooks) BeforeAccountDeleted(ctx sdk.Context, account Account) {
+	for _, hook := range h {
+		hook.BeforeAccountDeleted(ctx, account)
+	}
+}
@@ -3,6 +3,7 @@ package types
 import (
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/iov-one/iovns/pkg/idn"
 )
 
 // MsgWithFeePayer abstracts the Msg type to support a fee payer
@@ -12,6 +13,13 @@ type MsgWithFeePayer interface {
 	FeePayer() sdk.AccAddress
 }
 
+// MsgWithStarname abstracts the Msg type referencing a domain or an account,
+// whose names are converted to their canonical form before being handled
+type MsgWithStarname interface {
+	sdk.Msg
+	Canonicalize()
+}
+
 // MsgAddAccountCertificates is the message used
 // when a user wants to add new certificates
 // to his account
@@ -276,6 +284,12 @@ type MsgRegisterAccount struct {
 	Broker sdk.AccAddress `json:"broker"`
 	// FeePayerAddr is the address of the entity that has to pay product fees
 	FeePayerAddr sdk.AccAddress `json:"fee_payer"`
+	// Credential optionally proves that the registerer is allowed to register
+	// accounts by the registration policy of the closed domain
+	Credential *RegistrationCredential `json:"credential,omitempty"`
+	// ValidUntil optionally sets the expiration in seconds of an account registered
+	// by the admin of a closed domain, which otherwise follows the domain expiration
+	ValidUntil int64 `json:"valid_until,omitempty"`
 }
 
 var _ MsgWithFeePayer = (*MsgRegisterAccount)(nil)
@@ -309,6 +323,14 @@ func (m *MsgRegisterAccount) ValidateBasic() error {
 	if m.Registerer.Empty() {
 		return errors.Wrap(ErrInvalidRegisterer, "empty")
 	}
+	if m.ValidUntil < 0 {
+		return errors.Wrapf(ErrInvalidAccountValidUntil, "negative: %d", m.ValidUntil)
+	}
+	if m.Credential != nil {
+		if err := m.Credential.ValidateBasic(); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -774,3 +796,906 @@ func (m *MsgTransferDomain) GetSigners() []sdk.AccAddress {
 		return []sdk.AccAddress{m.FeePayerAddr, m.Owner}
 	}
 }
+
+// MsgSetRegistrationPolicy is the request model used by the admin of a closed
+// domain to define who else is allowed to register accounts in the domain
+type MsgSetRegistrationPolicy struct {
+	// Domain is the name of the closed domain
+	Domain string `json:"domain"`
+	// Owner is the admin of the domain
+	Owner sdk.AccAddress `json:"owner"`
+	// AllowList contains the addresses allowed to register accounts
+	AllowList []sdk.AccAddress `json:"allow_list"`
+	// CertificateIssuers contains the addresses of the issuers whose
+	// certificates allow their holder to register accounts
+	CertificateIssuers []sdk.AccAddress `json:"certificate_issuers"`
+	// InviteCodes defines if one-time invite codes signed by the domain admin are accepted
+	InviteCodes bool `json:"invite_codes"`
+	// FeePayerAddr is the address of the entity that has to pay product fees
+	FeePayerAddr sdk.AccAddress `json:"fee_payer"`
+}
+
+var _ MsgWithFeePayer = (*MsgSetRegistrationPolicy)(nil)
+
+// Policy returns the registration policy defined by the msg
+func (m *MsgSetRegistrationPolicy) Policy() RegistrationPolicy {
+	return RegistrationPolicy{
+		Domain:             m.Domain,
+		AllowList:          m.AllowList,
+		CertificateIssuers: m.CertificateIssuers,
+		InviteCodes:        m.InviteCodes,
+	}
+}
+
+// FeePayer implements FeePayer interface
+func (m *MsgSetRegistrationPolicy) FeePayer() sdk.AccAddress {
+	if !m.FeePayerAddr.Empty() {
+		return m.FeePayerAddr
+	}
+	return m.Owner
+}
+
+// Route implements sdk.Msg
+func (m *MsgSetRegistrationPolicy) Route() string {
+	return RouterKey
+}
+
+// Type implements sdk.Msg
+func (m *MsgSetRegistrationPolicy) Type() string {
+	return "set_registration_policy"
+}
+
+// ValidateBasic implements sdk.Msg
+func (m *MsgSetRegistrationPolicy) ValidateBasic() error {
+	if m.Owner.Empty() {
+		return errors.Wrap(ErrInvalidOwner, "empty")
+	}
+	return m.Policy().Validate()
+}
+
+// GetSignBytes implements sdk.Msg
+func (m *MsgSetRegistrationPolicy) GetSignBytes() []byte {
+	return sdk.MustSortJSON(ModuleCdc.MustMarshalJSON(m))
+}
+
+// GetSigners implements sdk.Msg
+func (m *MsgSetRegistrationPolicy) GetSigners() []sdk.AccAddress {
+	if m.FeePayerAddr.Empty() {
+		return []sdk.AccAddress{m.Owner}
+	} else {
+		return []sdk.AccAddress{m.FeePayerAddr, m.Owner}
+	}
+}
+
+// MsgOfferDomainTransfer is the request model used by the admin of a domain
+// to offer its transfer, which is applied once accepted by the recipient,
+// until then the admin cannot transfer or delete the domain
+type MsgOfferDomainTransfer struct {
+	// Domain is the name of the domain
+	Domain string `json:"domain"`
+	// Owner is the address of the owner of the domain
+	Owner sdk.AccAddress `json:"owner"`
+	// Recipient is the address of the entity the domain is offered to
+	Recipient sdk.AccAddress `json:"recipient"`
+	// TransferFlag is the flag used to determine how to transfer the domain and the related accounts
+	TransferFlag TransferFlag `json:"transfer_flag"`
+	// Price is the amount the recipient pays to the owner on accept, optional
+	Price sdk.Coins `json:"price"`
+	// Expiry is the unix timestamp after which the offer cannot be accepted, zero means never
+	Expiry int64 `json:"expiry"`
+	// FeePayerAddr is the address of the entity that has to pay product fees
+	FeePayerAddr sdk.AccAddress `json:"fee_payer"`
+}
+
+var _ MsgWithFeePayer = (*MsgOfferDomainTransfer)(nil)
+
+// Offer returns the transfer offer defined by the msg
+func (m *MsgOfferDomainTransfer) Offer() TransferOffer {
+	return TransferOffer{
+		Domain:       m.Domain,
+		Owner:        m.Owner,
+		Recipient:    m.Recipient,
+		Price:        m.Price,
+		TransferFlag: m.TransferFlag,
+		Expiry:       m.Expiry,
+	}
+}
+
+// FeePayer implements FeePayer interface
+func (m *MsgOfferDomainTransfer) FeePayer() sdk.AccAddress {
+	if !m.FeePayerAddr.Empty() {
+		return m.FeePayerAddr
+	}
+	return m.Owner
+}
+
+// Route implements sdk.Msg
+func (m *MsgOfferDomainTransfer) Route() string {
+	return RouterKey
+}
+
+// Type implements sdk.Msg
+func (m *MsgOfferDomainTransfer) Type() string {
+	return "offer_domain_transfer"
+}
+
+// ValidateBasic implements sdk.Msg
+func (m *MsgOfferDomainTransfer) ValidateBasic() error {
+	return m.Offer().Validate()
+}
+
+// GetSignBytes implements sdk.Msg
+func (m *MsgOfferDomainTransfer) GetSignBytes() []byte {
+	return sdk.MustSortJSON(ModuleCdc.MustMarshalJSON(m))
+}
+
+// GetSigners implements sdk.Msg
+func (m *MsgOfferDomainTransfer) GetSigners() []sdk.AccAddress {
+	if m.FeePayerAddr.Empty() {
+		return []sdk.AccAddress{m.Owner}
+	} else {
+		return []sdk.AccAddress{m.FeePayerAddr, m.Owner}
+	}
+}
+
+// MsgOfferAccountTransfer is the request model used by the owner of an account
+// to offer its transfer, which is applied once accepted by the recipient,
+// until then the owner cannot transfer or delete the account
+type MsgOfferAccountTransfer struct {
+	// Domain is the domain name of the account
+	Domain string `json:"domain"`
+	// Name is the account name
+	Name string `json:"name"`
+	// Owner is the actual owner of the account
+	Owner sdk.AccAddress `json:"owner"`
+	// Recipient is the address of the entity the account is offered to
+	Recipient sdk.AccAddress `json:"recipient"`
+	// Reset indicates if the accounts content will be reset
+	Reset bool `json:"reset"`
+	// Price is the amount the recipient pays to the owner on accept, optional
+	Price sdk.Coins `json:"price"`
+	// Expiry is the unix timestamp after which the offer cannot be accepted, zero means never
+	Expiry int64 `json:"expiry"`
+	// FeePayerAddr is the address of the entity that has to pay product fees
+	FeePayerAddr sdk.AccAddress `json:"fee_payer"`
+}
+
+var _ MsgWithFeePayer = (*MsgOfferAccountTransfer)(nil)
+
+// Offer returns the transfer offer defined by the msg
+func (m *MsgOfferAccountTransfer) Offer() TransferOffer {
+	name := m.Name
+	return TransferOffer{
+		Domain:    m.Domain,
+		Name:      &name,
+		Owner:     m.Owner,
+		Recipient: m.Recipient,
+		Price:     m.Price,
+		Reset:     m.Reset,
+		Expiry:    m.Expiry,
+	}
+}
+
+// FeePayer implements FeePayer interface
+func (m *MsgOfferAccountTransfer) FeePayer() sdk.AccAddress {
+	if !m.FeePayerAddr.Empty() {
+		return m.FeePayerAddr
+	}
+	return m.Owner
+}
+
+// Route implements sdk.Msg
+func (m *MsgOfferAccountTransfer) Route() string {
+	return RouterKey
+}
+
+// Type implements sdk.Msg
+func (m *MsgOfferAccountTransfer) Type() string {
+	return "offer_account_transfer"
+}
+
+// ValidateBasic implements sdk.Msg
+func (m *MsgOfferAccountTransfer) ValidateBasic() error {
+	if m.Name == "" {
+		return errors.Wrap(ErrOpEmptyAcc, "empty")
+	}
+	return m.Offer().Validate()
+}
+
+// GetSignBytes implements sdk.Msg
+func (m *MsgOfferAccountTransfer) GetSignBytes() []byte {
+	return sdk.MustSortJSON(ModuleCdc.MustMarshalJSON(m))
+}
+
+// GetSigners implements sdk.Msg
+func (m *MsgOfferAccountTransfer) GetSigners() []sdk.AccAddress {
+	if m.FeePayerAddr.Empty() {
+		return []sdk.AccAddress{m.Owner}
+	} else {
+		return []sdk.AccAddress{m.FeePayerAddr, m.Owner}
+	}
+}
+
+// MsgAcceptTransferOffer is the request model used by the recipient of a
+// transfer offer to accept it, paying its price to the owner, if any
+type MsgAcceptTransferOffer struct {
+	// ID is the ID of the transfer offer
+	ID uint64 `json:"id"`
+	// Recipient is the recipient of the transfer offer
+	Recipient sdk.AccAddress `json:"recipient"`
+	// FeePayerAddr is the address of the entity that has to pay product fees
+	FeePayerAddr sdk.AccAddress `json:"fee_payer"`
+}
+
+var _ MsgWithFeePayer = (*MsgAcceptTransferOffer)(nil)
+
+// FeePayer implements FeePayer interface
+func (m *MsgAcceptTransferOffer) FeePayer() sdk.AccAddress {
+	if !m.FeePayerAddr.Empty() {
+		return m.FeePayerAddr
+	}
+	return m.Recipient
+}
+
+// Route implements sdk.Msg
+func (m *MsgAcceptTransferOffer) Route() string {
+	return RouterKey
+}
+
+// Type implements sdk.Msg
+func (m *MsgAcceptTransferOffer) Type() string {
+	return "accept_transfer_offer"
+}
+
+// ValidateBasic implements sdk.Msg
+func (m *MsgAcceptTransferOffer) ValidateBasic() error {
+	if m.ID == 0 {
+		return errors.Wrap(ErrInvalidTransferOffer, "empty id")
+	}
+	if m.Recipient.Empty() {
+		return errors.Wrap(ErrInvalidTransferOffer, "empty recipient")
+	}
+	return nil
+}
+
+// GetSignBytes implements sdk.Msg
+func (m *MsgAcceptTransferOffer) GetSignBytes() []byte {
+	return sdk.MustSortJSON(ModuleCdc.MustMarshalJSON(m))
+}
+
+// GetSigners implements sdk.Msg
+func (m *MsgAcceptTransferOffer) GetSigners() []sdk.AccAddress {
+	if m.FeePayerAddr.Empty() {
+		return []sdk.AccAddress{m.Recipient}
+	} else {
+		return []sdk.AccAddress{m.FeePayerAddr, m.Recipient}
+	}
+}
+
+// MsgRejectTransferOffer is the request model used
+// by the recipient of a transfer offer to reject it
+type MsgRejectTransferOffer struct {
+	// ID is the ID of the transfer offer
+	ID uint64 `json:"id"`
+	// Recipient is the recipient of the transfer offer
+	Recipient sdk.AccAddress `json:"recipient"`
+	// FeePayerAddr is the address of the entity that has to pay product fees
+	FeePayerAddr sdk.AccAddress `json:"fee_payer"`
+}
+
+var _ MsgWithFeePayer = (*MsgRejectTransferOffer)(nil)
+
+// FeePayer implements FeePayer interface
+func (m *MsgRejectTransferOffer) FeePayer() sdk.AccAddress {
+	if !m.FeePayerAddr.Empty() {
+		return m.FeePayerAddr
+	}
+	return m.Recipient
+}
+
+// Route implements sdk.Msg
+func (m *MsgRejectTransferOffer) Route() string {
+	return RouterKey
+}
+
+// Type implements sdk.Msg
+func (m *MsgRejectTransferOffer) Type() string {
+	return "reject_transfer_offer"
+}
+
+// ValidateBasic implements sdk.Msg
+func (m *MsgRejectTransferOffer) ValidateBasic() error {
+	if m.ID == 0 {
+		return errors.Wrap(ErrInvalidTransferOffer, "empty id")
+	}
+	if m.Recipient.Empty() {
+		return errors.Wrap(ErrInvalidTransferOffer, "empty recipient")
+	}
+	return nil
+}
+
+// GetSignBytes implements sdk.Msg
+func (m *MsgRejectTransferOffer) GetSignBytes() []byte {
+	return sdk.MustSortJSON(ModuleCdc.MustMarshalJSON(m))
+}
+
+// GetSigners implements sdk.Msg
+func (m *MsgRejectTransferOffer) GetSigners() []sdk.AccAddress {
+	if m.FeePayerAddr.Empty() {
+		return []sdk.AccAddress{m.Recipient}
+	} else {
+		return []sdk.AccAddress{m.FeePayerAddr, m.Recipient}
+	}
+}
+
+// MsgCancelTransferOffer is the request model used
+// by the owner of a transfer offer to withdraw it
+type MsgCancelTransferOffer struct {
+	// ID is the ID of the transfer offer
+	ID uint64 `json:"id"`
+	// Owner is the owner of the offered domain or account
+	Owner sdk.AccAddress `json:"owner"`
+	// FeePayerAddr is the address of the entity that has to pay product fees
+	FeePayerAddr sdk.AccAddress `json:"fee_payer"`
+}
+
+var _ MsgWithFeePayer = (*MsgCancelTransferOffer)(nil)
+
+// FeePayer implements FeePayer interface
+func (m *MsgCancelTransferOffer) FeePayer() sdk.AccAddress {
+	if !m.FeePayerAddr.Empty() {
+		return m.FeePayerAddr
+	}
+	return m.Owner
+}
+
+// Route implements sdk.Msg
+func (m *MsgCancelTransferOffer) Route() string {
+	return RouterKey
+}
+
+// Type implements sdk.Msg
+func (m *MsgCancelTransferOffer) Type() string {
+	return "cancel_transfer_offer"
+}
+
+// ValidateBasic implements sdk.Msg
+func (m *MsgCancelTransferOffer) ValidateBasic() error {
+	if m.ID == 0 {
+		return errors.Wrap(ErrInvalidTransferOffer, "empty id")
+	}
+	if m.Owner.Empty() {
+		return errors.Wrap(ErrInvalidOwner, "empty")
+	}
+	return nil
+}
+
+// GetSignBytes implements sdk.Msg
+func (m *MsgCancelTransferOffer) GetSignBytes() []byte {
+	return sdk.MustSortJSON(ModuleCdc.MustMarshalJSON(m))
+}
+
+// GetSigners implements sdk.Msg
+func (m *MsgCancelTransferOffer) GetSigners() []sdk.AccAddress {
+	if m.FeePayerAddr.Empty() {
+		return []sdk.AccAddress{m.Owner}
+	} else {
+		return []sdk.AccAddress{m.FeePayerAddr, m.Owner}
+	}
+}
+
+// MsgDepositSponsorship is the request model used to deposit coins
+// which renew a domain or an account automatically before it expires
+type MsgDepositSponsorship struct {
+	// Domain is the domain sponsored, or the domain of the account sponsored
+	Domain string `json:"domain"`
+	// Name is the name of the account sponsored, empty to sponsor the domain
+	Name string `json:"name"`
+	// Sponsor is the address depositing the coins
+	Sponsor sdk.AccAddress `json:"sponsor"`
+	// Amount is the amount deposited
+	Amount sdk.Coins `json:"amount"`
+	// FeePayerAddr is the address of the entity that has to pay product fees
+	FeePayerAddr sdk.AccAddress `json:"fee_payer"`
+}
+
+var _ MsgWithFeePayer = (*MsgDepositSponsorship)(nil)
+
+// Sponsorship returns the sponsorship defined by the msg with the amount as balance
+func (m *MsgDepositSponsorship) Sponsorship() Sponsorship {
+	return Sponsorship{
+		Domain:  m.Domain,
+		Name:    m.Name,
+		Sponsor: m.Sponsor,
+		Balance: m.Amount,
+	}
+}
+
+// FeePayer implements FeePayer interface
+func (m *MsgDepositSponsorship) FeePayer() sdk.AccAddress {
+	if !m.FeePayerAddr.Empty() {
+		return m.FeePayerAddr
+	}
+	return m.Sponsor
+}
+
+// Route implements sdk.Msg
+func (m *MsgDepositSponsorship) Route() string {
+	return RouterKey
+}
+
+// Type implements sdk.Msg
+func (m *MsgDepositSponsorship) Type() string {
+	return "deposit_sponsorship"
+}
+
+// ValidateBasic implements sdk.Msg
+func (m *MsgDepositSponsorship) ValidateBasic() error {
+	if !m.Amount.IsValid() || m.Amount.Empty() {
+		return errors.Wrapf(ErrInvalidSponsorship, "invalid amount %s", m.Amount)
+	}
+	return m.Sponsorship().Validate()
+}
+
+// GetSignBytes implements sdk.Msg
+func (m *MsgDepositSponsorship) GetSignBytes() []byte {
+	return sdk.MustSortJSON(ModuleCdc.MustMarshalJSON(m))
+}
+
+// GetSigners implements sdk.Msg
+func (m *MsgDepositSponsorship) GetSigners() []sdk.AccAddress {
+	if m.FeePayerAddr.Empty() {
+		return []sdk.AccAddress{m.Sponsor}
+	} else {
+		return []sdk.AccAddress{m.FeePayerAddr, m.Sponsor}
+	}
+}
+
+// MsgWithdrawSponsorship is the request model used by
+// a sponsor to withdraw coins from its sponsorship
+type MsgWithdrawSponsorship struct {
+	// Domain is the domain sponsored, or the domain of the account sponsored
+	Domain string `json:"domain"`
+	// Name is the name of the account sponsored, empty for domain sponsorships
+	Name string `json:"name"`
+	// Sponsor is the address which deposited the coins
+	Sponsor sdk.AccAddress `json:"sponsor"`
+	// Amount is the amount withdrawn, the whole balance is withdrawn if empty
+	Amount sdk.Coins `json:"amount"`
+	// FeePayerAddr is the address of the entity that has to pay product fees
+	FeePayerAddr sdk.AccAddress `json:"fee_payer"`
+}
+
+var _ MsgWithFeePayer = (*MsgWithdrawSponsorship)(nil)
+
+// FeePayer implements FeePayer interface
+func (m *MsgWithdrawSponsorship) FeePayer() sdk.AccAddress {
+	if !m.FeePayerAddr.Empty() {
+		return m.FeePayerAddr
+	}
+	return m.Sponsor
+}
+
+// Route implements sdk.Msg
+func (m *MsgWithdrawSponsorship) Route() string {
+	return RouterKey
+}
+
+// Type implements sdk.Msg
+func (m *MsgWithdrawSponsorship) Type() string {
+	return "withdraw_sponsorship"
+}
+
+// ValidateBasic implements sdk.Msg
+func (m *MsgWithdrawSponsorship) ValidateBasic() error {
+	if !m.Amount.IsValid() && !m.Amount.Empty() {
+		return errors.Wrapf(ErrInvalidSponsorship, "invalid amount %s", m.Amount)
+	}
+	return Sponsorship{Domain: m.Domain, Name: m.Name, Sponsor: m.Sponsor}.Validate()
+}
+
+// GetSignBytes implements sdk.Msg
+func (m *MsgWithdrawSponsorship) GetSignBytes() []byte {
+	return sdk.MustSortJSON(ModuleCdc.MustMarshalJSON(m))
+}
+
+// GetSigners implements sdk.Msg
+func (m *MsgWithdrawSponsorship) GetSigners() []sdk.AccAddress {
+	if m.FeePayerAddr.Empty() {
+		return []sdk.AccAddress{m.Sponsor}
+	} else {
+		return []sdk.AccAddress{m.FeePayerAddr, m.Sponsor}
+	}
+}
+
+// BulkAccountsMax is the maximum number of accounts changed by a single bulk message
+const BulkAccountsMax = 100
+
+// MsgTransferAccounts is the request model used by the admin of a closed
+// domain to transfer a set of accounts of the domain to a new owner
+type MsgTransferAccounts struct {
+	// Domain is the name of the closed domain
+	Domain string `json:"domain"`
+	// Names are the names of the accounts to transfer
+	Names []string `json:"names"`
+	// Owner is the admin of the domain
+	Owner sdk.AccAddress `json:"owner"`
+	// NewOwner is the new owner of the accounts
+	NewOwner sdk.AccAddress `json:"new_owner"`
+	// Reset indicates if the accounts content is reset
+	Reset bool `json:"reset"`
+	// FeePayerAddr is the address of the entity that has to pay product fees
+	FeePayerAddr sdk.AccAddress `json:"fee_payer"`
+}
+
+var _ MsgWithFeePayer = (*MsgTransferAccounts)(nil)
+
+// FeePayer implements FeePayer interface
+func (m *MsgTransferAccounts) FeePayer() sdk.AccAddress {
+	if !m.FeePayerAddr.Empty() {
+		return m.FeePayerAddr
+	}
+	return m.Owner
+}
+
+// Route implements sdk.Msg
+func (m *MsgTransferAccounts) Route() string {
+	return RouterKey
+}
+
+// Type implements sdk.Msg
+func (m *MsgTransferAccounts) Type() string {
+	return "transfer_accounts"
+}
+
+// ValidateBasic implements sdk.Msg
+func (m *MsgTransferAccounts) ValidateBasic() error {
+	if m.Domain == "" {
+		return errors.Wrap(ErrInvalidDomainName, "empty")
+	}
+	if m.Owner.Empty() {
+		return errors.Wrap(ErrInvalidOwner, "empty")
+	}
+	if m.NewOwner.Empty() {
+		return errors.Wrap(ErrInvalidOwner, "new owner is empty")
+	}
+	if len(m.Names) == 0 || len(m.Names) > BulkAccountsMax {
+		return errors.Wrapf(ErrInvalidRequest, "between 1 and %d accounts can be transferred, got %d", BulkAccountsMax, len(m.Names))
+	}
+	names := make(map[string]struct{}, len(m.Names))
+	for _, name := range m.Names {
+		if name == EmptyAccountName {
+			return errors.Wrap(ErrOpEmptyAcc, "the empty account is transferred with its domain")
+		}
+		if _, ok := names[name]; ok {
+			return errors.Wrapf(ErrInvalidRequest, "duplicate account %s", name)
+		}
+		names[name] = struct{}{}
+	}
+	return nil
+}
+
+// GetSignBytes implements sdk.Msg
+func (m *MsgTransferAccounts) GetSignBytes() []byte {
+	return sdk.MustSortJSON(ModuleCdc.MustMarshalJSON(m))
+}
+
+// GetSigners implements sdk.Msg
+func (m *MsgTransferAccounts) GetSigners() []sdk.AccAddress {
+	if m.FeePayerAddr.Empty() {
+		return []sdk.AccAddress{m.Owner}
+	} else {
+		return []sdk.AccAddress{m.FeePayerAddr, m.Owner}
+	}
+}
+
+// MsgDeleteAccountsByOwner is the request model used by the admin of a closed domain to delete
+// the accounts of the domain owned by an address, at most BulkAccountsMax accounts are deleted
+type MsgDeleteAccountsByOwner struct {
+	// Domain is the name of the closed domain
+	Domain string `json:"domain"`
+	// Owner is the admin of the domain
+	Owner sdk.AccAddress `json:"owner"`
+	// AccountOwner is the owner of the accounts to delete
+	AccountOwner sdk.AccAddress `json:"account_owner"`
+	// FeePayerAddr is the address of the entity that has to pay product fees
+	FeePayerAddr sdk.AccAddress `json:"fee_payer"`
+}
+
+var _ MsgWithFeePayer = (*MsgDeleteAccountsByOwner)(nil)
+
+// FeePayer implements FeePayer interface
+func (m *MsgDeleteAccountsByOwner) FeePayer() sdk.AccAddress {
+	if !m.FeePayerAddr.Empty() {
+		return m.FeePayerAddr
+	}
+	return m.Owner
+}
+
+// Route implements sdk.Msg
+func (m *MsgDeleteAccountsByOwner) Route() string {
+	return RouterKey
+}
+
+// Type implements sdk.Msg
+func (m *MsgDeleteAccountsByOwner) Type() string {
+	return "delete_accounts_by_owner"
+}
+
+// ValidateBasic implements sdk.Msg
+func (m *MsgDeleteAccountsByOwner) ValidateBasic() error {
+	if m.Domain == "" {
+		return errors.Wrap(ErrInvalidDomainName, "empty")
+	}
+	if m.Owner.Empty() {
+		return errors.Wrap(ErrInvalidOwner, "empty")
+	}
+	if m.AccountOwner.Empty() {
+		return errors.Wrap(ErrInvalidOwner, "account owner is empty")
+	}
+	return nil
+}
+
+// GetSignBytes implements sdk.Msg
+func (m *MsgDeleteAccountsByOwner) GetSignBytes() []byte {
+	return sdk.MustSortJSON(ModuleCdc.MustMarshalJSON(m))
+}
+
+// GetSigners implements sdk.Msg
+func (m *MsgDeleteAccountsByOwner) GetSigners() []sdk.AccAddress {
+	if m.FeePayerAddr.Empty() {
+		return []sdk.AccAddress{m.Owner}
+	} else {
+		return []sdk.AccAddress{m.FeePayerAddr, m.Owner}
+	}
+}
+
+// MsgSetAccountsResource is the request model used by the admin of a closed domain to set a
+// resource on the accounts of the domain, replacing the resource with the same URI if any,
+// at most BulkAccountsMax accounts which do not have the resource yet are updated, the msg
+// can be sent again to update the remaining accounts
+type MsgSetAccountsResource struct {
+	// Domain is the name of the closed domain
+	Domain string `json:"domain"`
+	// Owner is the admin of the domain
+	Owner sdk.AccAddress `json:"owner"`
+	// Resource is the resource set on the accounts
+	Resource Resource `json:"resource"`
+	// FeePayerAddr is the address of the entity that has to pay product fees
+	FeePayerAddr sdk.AccAddress `json:"fee_payer"`
+}
+
+var _ MsgWithFeePayer = (*MsgSetAccountsResource)(nil)
+
+// FeePayer implements FeePayer interface
+func (m *MsgSetAccountsResource) FeePayer() sdk.AccAddress {
+	if !m.FeePayerAddr.Empty() {
+		return m.FeePayerAddr
+	}
+	return m.Owner
+}
+
+// Route implements sdk.Msg
+func (m *MsgSetAccountsResource) Route() string {
+	return RouterKey
+}
+
+// Type implements sdk.Msg
+func (m *MsgSetAccountsResource) Type() string {
+	return "set_accounts_resource"
+}
+
+// ValidateBasic implements sdk.Msg
+func (m *MsgSetAccountsResource) ValidateBasic() error {
+	if m.Domain == "" {
+		return errors.Wrap(ErrInvalidDomainName, "empty")
+	}
+	if m.Owner.Empty() {
+		return errors.Wrap(ErrInvalidOwner, "empty")
+	}
+	if m.Resource.URI == "" || m.Resource.Resource == "" {
+		return errors.Wrap(ErrInvalidResource, "empty")
+	}
+	return nil
+}
+
+// GetSignBytes implements sdk.Msg
+func (m *MsgSetAccountsResource) GetSignBytes() []byte {
+	return sdk.MustSortJSON(ModuleCdc.MustMarshalJSON(m))
+}
+
+// GetSigners implements sdk.Msg
+func (m *MsgSetAccountsResource) GetSigners() []sdk.AccAddress {
+	if m.FeePayerAddr.Empty() {
+		return []sdk.AccAddress{m.Owner}
+	} else {
+		return []sdk.AccAddress{m.FeePayerAddr, m.Owner}
+	}
+}
+
+// MsgSetAccountValidUntil is the request model used by the admin of a closed domain
+// to set the expiration of an account of the domain
+type MsgSetAccountValidUntil struct {
+	// Domain is the name of the closed domain
+	Domain string `json:"domain"`
+	// Name is the name of the account
+	Name string `json:"name"`
+	// Owner is the admin of the domain
+	Owner sdk.AccAddress `json:"owner"`
+	// ValidUntil is the expiration of the account in seconds, zero
+	// makes the account follow the expiration of the domain
+	ValidUntil int64 `json:"valid_until"`
+	// FeePayerAddr is the address of the entity that has to pay product fees
+	FeePayerAddr sdk.AccAddress `json:"fee_payer"`
+}
+
+var _ MsgWithFeePayer = (*MsgSetAccountValidUntil)(nil)
+
+// FeePayer implements FeePayer interface
+func (m *MsgSetAccountValidUntil) FeePayer() sdk.AccAddress {
+	if !m.FeePayerAddr.Empty() {
+		return m.FeePayerAddr
+	}
+	return m.Owner
+}
+
+// Route implements sdk.Msg
+func (m *MsgSetAccountValidUntil) Route() string {
+	return RouterKey
+}
+
+// Type implements sdk.Msg
+func (m *MsgSetAccountValidUntil) Type() string {
+	return "set_account_valid_until"
+}
+
+// ValidateBasic implements sdk.Msg
+func (m *MsgSetAccountValidUntil) ValidateBasic() error {
+	if m.Domain == "" {
+		return errors.Wrap(ErrInvalidDomainName, "empty")
+	}
+	if m.Name == "" {
+		return ErrOpEmptyAcc
+	}
+	if m.Owner.Empty() {
+		return errors.Wrap(ErrInvalidOwner, "empty")
+	}
+	if m.ValidUntil < 0 {
+		return errors.Wrapf(ErrInvalidAccountValidUntil, "negative: %d", m.ValidUntil)
+	}
+	return nil
+}
+
+// GetSignBytes implements sdk.Msg
+func (m *MsgSetAccountValidUntil) GetSignBytes() []byte {
+	return sdk.MustSortJSON(ModuleCdc.MustMarshalJSON(m))
+}
+
+// GetSigners implements sdk.Msg
+func (m *MsgSetAccountValidUntil) GetSigners() []sdk.AccAddress {
+	if m.FeePayerAddr.Empty() {
+		return []sdk.AccAddress{m.Owner}
+	} else {
+		return []sdk.AccAddress{m.FeePayerAddr, m.Owner}
+	}
+}
+
+// Canonicalize implements MsgWithStarname
+func (m *MsgAddAccountCertificates) Canonicalize() {
+	m.Domain = idn.Canonical(m.Domain)
+	m.Name = idn.Canonical(m.Name)
+}
+
+// Canonicalize implements MsgWithStarname
+func (m *MsgDeleteAccountCertificate) Canonicalize() {
+	m.Domain = idn.Canonical(m.Domain)
+	m.Name = idn.Canonical(m.Name)
+}
+
+// Canonicalize implements MsgWithStarname
+func (m *MsgDeleteAccount) Canonicalize() {
+	m.Domain = idn.Canonical(m.Domain)
+	m.Name = idn.Canonical(m.Name)
+}
+
+// Canonicalize implements MsgWithStarname
+func (m *MsgRegisterAccount) Canonicalize() {
+	m.Domain = idn.Canonical(m.Domain)
+	m.Name = idn.Canonical(m.Name)
+}
+
+// Canonicalize implements MsgWithStarname
+func (m *MsgRenewAccount) Canonicalize() {
+	m.Domain = idn.Canonical(m.Domain)
+	m.Name = idn.Canonical(m.Name)
+}
+
+// Canonicalize implements MsgWithStarname
+func (m *MsgReplaceAccountResources) Canonicalize() {
+	m.Domain = idn.Canonical(m.Domain)
+	m.Name = idn.Canonical(m.Name)
+}
+
+// Canonicalize implements MsgWithStarname
+func (m *MsgReplaceAccountMetadata) Canonicalize() {
+	m.Domain = idn.Canonical(m.Domain)
+	m.Name = idn.Canonical(m.Name)
+}
+
+// Canonicalize implements MsgWithStarname
+func (m *MsgTransferAccount) Canonicalize() {
+	m.Domain = idn.Canonical(m.Domain)
+	m.Name = idn.Canonical(m.Name)
+}
+
+// Canonicalize implements MsgWithStarname
+func (m *MsgRegisterDomain) Canonicalize() {
+	m.Name = idn.Canonical(m.Name)
+}
+
+// Canonicalize implements MsgWithStarname
+func (m *MsgDeleteDomain) Canonicalize() {
+	m.Domain = idn.Canonical(m.Domain)
+}
+
+// Canonicalize implements MsgWithStarname
+func (m *MsgRenewDomain) Canonicalize() {
+	m.Domain = idn.Canonical(m.Domain)
+}
+
+// Canonicalize implements MsgWithStarname
+func (m *MsgTransferDomain) Canonicalize() {
+	m.Domain = idn.Canonical(m.Domain)
+}
+
+// Canonicalize implements MsgWithStarname
+func (m *MsgSetRegistrationPolicy) Canonicalize() {
+	m.Domain = idn.Canonical(m.Domain)
+}
+
+// Canonicalize implements MsgWithStarname
+func (m *MsgOfferDomainTransfer) Canonicalize() {
+	m.Domain = idn.Canonical(m.Domain)
+}
+
+// Canonicalize implements MsgWithStarname
+func (m *MsgOfferAccountTransfer) Canonicalize() {
+	m.Domain = idn.Canonical(m.Domain)
+	m.Name = idn.Canonical(m.Name)
+}
+
+// Canonicalize implements MsgWithStarname
+func (m *MsgDepositSponsorship) Canonicalize() {
+	m.Domain = idn.Canonical(m.Domain)
+	m.Name = idn.Canonical(m.Name)
+}
+
+// Canonicalize implements MsgWithStarname
+func (m *MsgWithdrawSponsorship) Canonicalize() {
+	m.Domain = idn.Canonical(m.Domain)
+	m.Name = idn.Canonical(m.Name)
+}
+
+// Canonicalize implements MsgWithStarname
+func (m *MsgTransferAccounts) Canonicalize() {
+	m.Domain = idn.Canonical(m.Domain)
+	for i, name := range m.Names {
+		m.Names[i] = idn.Canonical(name)
+	}
+}
+
+// Canonicalize implements MsgWithStarname
+func (m *MsgDeleteAccountsByOwner) Canonicalize() {
+	m.Domain = idn.Canonical(m.Domain)
+}
+
+// Canonicalize implements MsgWithStarname
+func (m *MsgSetAccountsResource) Canonicalize() {
+	m.Domain = idn.Canonical(m.Domain)
+}
+
+// Canonicalize implements MsgWithStarname
+func (m *MsgSetAccountValidUntil) Canonicalize() {
+	m.Domain = idn.Canonical(m.Domain)
+	m.Name = idn.Canonical(m.Name)
+}
@@ -29,7 +29,41 @@ const (
 	AttributeKeyNewMetadata             = "new_metadata"
 	AttributeKeyTransferAccountNewOwner = "new_account_owner"
 	AttributeKeyTransferAccountReset    = "transfer_account_reset"
+	AttributeKeyAccountValidUntil       = "account_valid_until"
 
 	AttributeKeyTransferDomainNewOwner = "new_domain_owner"
 	AttributeKeyTransferDomainFlag     = "transfer_domain_flag"
+
+	AttributeKeyInviteCodes = "invite_codes"
+
+	AttributeKeyTransferOfferID        = "transfer_offer_id"
+	AttributeKeyTransferOfferRecipient = "transfer_offer_recipient"
+	AttributeKeyTransferOfferPrice     = "transfer_offer_price"
+	AttributeKeyTransferOfferExpiry    = "transfer_offer_expiry"
+
+	AttributeKeySponsor            = "sponsor"
+	AttributeKeySponsorshipAmount  = "sponsorship_amount"
+	AttributeKeySponsorshipBalance = "sponsorship_balance"
+	AttributeKeySponsorshipReason  = "sponsorship_close_reason"
+	AttributeKeySponsorshipError   = "sponsorship_error"
+)
+
+// Events types
+const (
+	// EventTypeExpireTransferOffer is emitted when an expired transfer offer is removed
+	EventTypeExpireTransferOffer = "expire_transfer_offer"
+	// EventTypeSponsoredRenewal is emitted when a domain or an account is renewed by a sponsorship
+	EventTypeSponsoredRenewal = "sponsored_renewal"
+	// EventTypeCloseSponsorship is emitted when a sponsorship is closed and its balance refunded
+	EventTypeCloseSponsorship = "close_sponsorship"
+	// EventTypeCloseSponsorshipFailed is emitted when the balance of a sponsorship to close cannot be refunded
+	EventTypeCloseSponsorshipFailed = "close_sponsorship_failed"
+)
+
+// Reasons why sponsorships are closed at the end of a block
+const (
+	// SponsorshipExhausted means the balance left cannot pay the next renewal
+	SponsorshipExhausted = "exhausted"
+	// SponsorshipStarnameDeleted means the sponsored domain or account does not exist anymore
+	SponsorshipStarnameDeleted = "starname_deleted"
 )
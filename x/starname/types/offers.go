@@ -0,0 +1,151 @@
+package types
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/errors"
+	crud "github.com/iov-one/cosmos-sdk-crud/pkg/crud/types"
+)
+
+const TransferOfferStarnameIndex = 0x1
+const TransferOfferOwnerIndex = 0x2
+const TransferOfferRecipientIndex = 0x3
+const TransferOfferDomainIndex = 0x4
+
+// TransferOffer is a pending transfer of a domain or an account
+// which is applied only once accepted by its recipient
+type TransferOffer struct {
+	// ID is the sequence number of the offer, it increases monotonically
+	ID uint64 `json:"id"`
+	// Domain is the name of the domain offered, or of the domain of the account offered
+	Domain string `json:"domain"`
+	// Name is the name of the account offered, it is nil for domain offers
+	Name *string `json:"name,omitempty"`
+	// Owner is the owner of the domain or account at the time of the offer
+	Owner sdk.AccAddress `json:"owner"`
+	// Recipient is the address the domain or account is transferred to on accept
+	Recipient sdk.AccAddress `json:"recipient"`
+	// Price is the amount the recipient pays to the owner on accept, it can be empty
+	Price sdk.Coins `json:"price"`
+	// TransferFlag defines how the accounts of an offered domain are transferred
+	TransferFlag TransferFlag `json:"transfer_flag"`
+	// Reset defines if the content of an offered account is reset on transfer
+	Reset bool `json:"reset"`
+	// Expiry is the unix timestamp after which the offer cannot be accepted, zero means never
+	Expiry int64 `json:"expiry"`
+}
+
+// IsDomainOffer returns true if the offer refers to a domain rather than to an account
+func (o TransferOffer) IsDomainOffer() bool {
+	return o.Name == nil
+}
+
+// Starname returns the domain name for domain offers and name*domain for account offers
+func (o TransferOffer) Starname() string {
+	if o.IsDomainOffer() {
+		return o.Domain
+	}
+	return AccountStarname(o.Domain, *o.Name)
+}
+
+// Expired returns true if the offer cannot be accepted anymore at the provided unix time
+func (o TransferOffer) Expired(now int64) bool {
+	return o.Expiry != 0 && o.Expiry <= now
+}
+
+// Validate checks the fields of the offer
+func (o TransferOffer) Validate() error {
+	if o.Domain == "" {
+		return errors.Wrap(ErrInvalidDomainName, "empty")
+	}
+	if o.Owner.Empty() {
+		return errors.Wrap(ErrInvalidOwner, "empty")
+	}
+	if o.Recipient.Empty() {
+		return errors.Wrap(ErrInvalidTransferOffer, "empty recipient")
+	}
+	if o.Recipient.Equals(o.Owner) {
+		return errors.Wrap(ErrInvalidTransferOffer, "recipient is the owner")
+	}
+	if !o.Price.IsValid() && !o.Price.Empty() {
+		return errors.Wrapf(ErrInvalidTransferOffer, "invalid price %s", o.Price)
+	}
+	if o.Expiry < 0 {
+		return errors.Wrapf(ErrInvalidTransferOffer, "invalid expiry %d", o.Expiry)
+	}
+	if o.IsDomainOffer() {
+		if err := validateTransferFlag(o.TransferFlag); err != nil {
+			return err
+		}
+		if o.Reset {
+			return errors.Wrap(ErrInvalidTransferOffer, "reset applies only to account offers")
+		}
+	} else if o.TransferFlag != TransferFlush {
+		return errors.Wrap(ErrInvalidTransferOffer, "transfer flag applies only to domain offers")
+	}
+	return nil
+}
+
+// validateTransferFlag checks that the flag can be used to transfer a domain
+func validateTransferFlag(flag TransferFlag) error {
+	switch flag {
+	case TransferOwned, TransferResetNone, TransferFlush:
+		return nil
+	default:
+		return errors.Wrapf(ErrInvalidRequest, "unknown reset flag: %d", flag)
+	}
+}
+
+func (o *TransferOffer) PrimaryKey() crud.PrimaryKey {
+	if o.ID == 0 {
+		return nil
+	}
+	return crud.NewPrimaryKey(sdk.Uint64ToBigEndian(o.ID))
+}
+
+func (o *TransferOffer) SecondaryKeys() []crud.SecondaryKey {
+	var sk []crud.SecondaryKey
+	// index by starname and by domain
+	if o.Domain != "" {
+		sk = append(sk, crud.NewSecondaryKey(TransferOfferStarnameIndex, lengthPrefixed(o.Starname())))
+		sk = append(sk, crud.NewSecondaryKey(TransferOfferDomainIndex, lengthPrefixed(o.Domain)))
+	}
+	// index by owner
+	if !o.Owner.Empty() {
+		sk = append(sk, crud.NewSecondaryKey(TransferOfferOwnerIndex, o.Owner))
+	}
+	// index by recipient
+	if !o.Recipient.Empty() {
+		sk = append(sk, crud.NewSecondaryKey(TransferOfferRecipientIndex, o.Recipient))
+	}
+	return sk
+}
+
+// TransferOfferStarnameFilter is used to filter the offers
+// of a domain or an account through the starname index
+type TransferOfferStarnameFilter struct {
+	// Starname is the domain name or the name*domain of the account
+	Starname string
+}
+
+func (f *TransferOfferStarnameFilter) PrimaryKey() crud.PrimaryKey {
+	return nil
+}
+
+func (f *TransferOfferStarnameFilter) SecondaryKeys() []crud.SecondaryKey {
+	return []crud.SecondaryKey{crud.NewSecondaryKey(TransferOfferStarnameIndex, lengthPrefixed(f.Starname))}
+}
+
+// TransferOfferDomainFilter is used to filter the offers of a
+// domain and of all its accounts through the domain index
+type TransferOfferDomainFilter struct {
+	// Domain is the name of the domain
+	Domain string
+}
+
+func (f *TransferOfferDomainFilter) PrimaryKey() crud.PrimaryKey {
+	return nil
+}
+
+func (f *TransferOfferDomainFilter) SecondaryKeys() []crud.SecondaryKey {
+	return []crud.SecondaryKey{crud.NewSecondaryKey(TransferOfferDomainIndex, lengthPrefixed(f.Domain))}
+}
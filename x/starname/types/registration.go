@@ -0,0 +1,163 @@
+package types
+
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/iov-one/iovns/pkg/idn"
+	"github.com/tendermint/tendermint/crypto"
+)
+
+// RegistrationPolicy defines the rules which allow addresses other than
+// the domain admin to register accounts in a closed domain
+type RegistrationPolicy struct {
+	// Domain is the name of the closed domain the policy applies to
+	Domain string `json:"domain"`
+	// AllowList contains the addresses allowed to register accounts
+	AllowList []sdk.AccAddress `json:"allow_list"`
+	// CertificateIssuers contains the addresses of the issuers whose
+	// certificates allow their holder to register accounts
+	CertificateIssuers []sdk.AccAddress `json:"certificate_issuers"`
+	// InviteCodes defines if one-time invite codes signed by the domain admin are accepted
+	InviteCodes bool `json:"invite_codes"`
+}
+
+// Empty returns true if the policy does not allow anyone to register accounts
+func (p RegistrationPolicy) Empty() bool {
+	return len(p.AllowList) == 0 && len(p.CertificateIssuers) == 0 && !p.InviteCodes
+}
+
+// Allowed returns true if the address is in the allow list of the policy
+func (p RegistrationPolicy) Allowed(addr sdk.AccAddress) bool {
+	return containsAddress(p.AllowList, addr)
+}
+
+// TrustsIssuer returns true if the address is one of the certificate issuers of the policy
+func (p RegistrationPolicy) TrustsIssuer(addr sdk.AccAddress) bool {
+	return containsAddress(p.CertificateIssuers, addr)
+}
+
+// Validate checks the addresses of the policy
+func (p RegistrationPolicy) Validate() error {
+	if p.Domain == "" {
+		return errors.Wrap(ErrInvalidDomainName, "empty")
+	}
+	if err := validateAddresses(p.AllowList); err != nil {
+		return errors.Wrapf(err, "allow list")
+	}
+	if err := validateAddresses(p.CertificateIssuers); err != nil {
+		return errors.Wrapf(err, "certificate issuers")
+	}
+	return nil
+}
+
+// CredentialType defines the kind of registration credential
+type CredentialType string
+
+const (
+	// CertificateCredential is a certificate signed by one of the issuers of the registration
+	// policy, it is bound to the registerer and can be used multiple times
+	CertificateCredential CredentialType = "certificate"
+	// InviteCredential is an invite code signed by the domain admin, it is bound
+	// to the registerer, so it cannot be front-run, and it can be used once
+	InviteCredential CredentialType = "invite"
+)
+
+// RegistrationCredential proves that the registerer of an account is allowed
+// to register accounts by the registration policy of a closed domain
+type RegistrationCredential struct {
+	// Type is the kind of credential
+	Type CredentialType `json:"type"`
+	// Code is the one-time code of an invite, it is empty for certificates
+	Code string `json:"code,omitempty"`
+	// PubKey is the public key of the issuer of the credential
+	PubKey crypto.PubKey `json:"pub_key"`
+	// Signature is the signature of the issuer over CredentialSignBytes
+	Signature []byte `json:"signature"`
+}
+
+// credentialSignDoc is the document signed by credential issuers
+type credentialSignDoc struct {
+	ChainID    string         `json:"chain_id"`
+	Type       CredentialType `json:"type"`
+	Domain     string         `json:"domain"`
+	Registerer sdk.AccAddress `json:"registerer,omitempty"`
+	Code       string         `json:"code,omitempty"`
+}
+
+// CredentialSignBytes returns the bytes the issuer of a credential signs, credentials
+// are bound to the chain and to the registerer and invites are also bound to their
+// code, the domain name is signed in its canonical form
+func CredentialSignBytes(chainID string, typ CredentialType, domain string, registerer sdk.AccAddress, code string) []byte {
+	doc := credentialSignDoc{ChainID: chainID, Type: typ, Domain: idn.Canonical(domain), Registerer: registerer}
+	if typ == InviteCredential {
+		doc.Code = code
+	}
+	return sdk.MustSortJSON(ModuleCdc.MustMarshalJSON(doc))
+}
+
+// Issuer returns the address of the issuer of the credential
+func (c RegistrationCredential) Issuer() sdk.AccAddress {
+	return sdk.AccAddress(c.PubKey.Address())
+}
+
+// ValidateBasic checks the credential fields
+func (c RegistrationCredential) ValidateBasic() error {
+	switch c.Type {
+	case CertificateCredential:
+		if c.Code != "" {
+			return errors.Wrap(ErrInvalidCredential, "certificates have no code")
+		}
+	case InviteCredential:
+		if c.Code == "" {
+			return errors.Wrap(ErrInvalidCredential, "empty invite code")
+		}
+	default:
+		return errors.Wrapf(ErrInvalidCredential, "unknown credential type %s", c.Type)
+	}
+	if c.PubKey == nil {
+		return errors.Wrap(ErrInvalidCredential, "empty public key")
+	}
+	if len(c.Signature) == 0 {
+		return errors.Wrap(ErrInvalidCredential, "empty signature")
+	}
+	return nil
+}
+
+// Verify checks that the credential was signed by its issuer
+// for the provided chain, domain and registerer
+func (c RegistrationCredential) Verify(chainID, domain string, registerer sdk.AccAddress) error {
+	if err := c.ValidateBasic(); err != nil {
+		return err
+	}
+	if !c.PubKey.VerifyBytes(CredentialSignBytes(chainID, c.Type, domain, registerer, c.Code), c.Signature) {
+		return errors.Wrapf(ErrInvalidCredential, "invalid %s signature of %s", c.Type, c.Issuer())
+	}
+	return nil
+}
+
+// containsAddress checks if addr is in addrs
+func containsAddress(addrs []sdk.AccAddress, addr sdk.AccAddress) bool {
+	for _, a := range addrs {
+		if a.Equals(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// validateAddresses checks that addresses are not empty nor duplicated
+func validateAddresses(addrs []sdk.AccAddress) error {
+	set := make(map[string]struct{}, len(addrs))
+	for _, addr := range addrs {
+		if addr.Empty() {
+			return fmt.Errorf("empty address")
+		}
+		if _, ok := set[addr.String()]; ok {
+			return fmt.Errorf("duplicate address %s", addr)
+		}
+		set[addr.String()] = struct{}{}
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package types
+
+import (
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/errors"
+	crud "github.com/iov-one/cosmos-sdk-crud/pkg/crud/types"
+)
+
+const SponsorshipSponsorIndex = 0x1
+const SponsorshipStarnameIndex = 0x2
+
+// SponsoredRenewalWindow defines how long before their expiry
+// sponsored domains and accounts are renewed automatically
+const SponsoredRenewalWindow = 24 * time.Hour
+
+// SponsoredRenewalRetryDelay defines how long after a failed
+// sponsored renewal or sponsorship closure it is attempted again
+const SponsoredRenewalRetryDelay = time.Hour
+
+// Sponsorship holds the coins deposited by a sponsor to renew
+// a domain or an account automatically before it expires
+type Sponsorship struct {
+	// Domain is the name of the domain sponsored, or of the domain of the account sponsored
+	Domain string `json:"domain"`
+	// Name is the name of the account sponsored, it is empty for domain sponsorships
+	Name string `json:"name,omitempty"`
+	// Sponsor is the address which deposited the coins, remaining coins are refunded to it
+	Sponsor sdk.AccAddress `json:"sponsor"`
+	// Balance is the amount left to pay the renewal fees
+	Balance sdk.Coins `json:"balance"`
+}
+
+// IsDomainSponsorship returns true if the sponsorship renews a domain rather than an account
+func (s Sponsorship) IsDomainSponsorship() bool {
+	return s.Name == ""
+}
+
+// Starname returns the domain name for domain sponsorships and name*domain for account sponsorships
+func (s Sponsorship) Starname() string {
+	if s.IsDomainSponsorship() {
+		return s.Domain
+	}
+	return AccountStarname(s.Domain, s.Name)
+}
+
+// Validate checks the fields of the sponsorship
+func (s Sponsorship) Validate() error {
+	if s.Domain == "" {
+		return errors.Wrap(ErrInvalidDomainName, "empty")
+	}
+	if s.Sponsor.Empty() {
+		return errors.Wrap(ErrInvalidSponsorship, "empty sponsor")
+	}
+	if !s.Balance.IsValid() {
+		return errors.Wrapf(ErrInvalidSponsorship, "invalid balance %s", s.Balance)
+	}
+	return nil
+}
+
+func (s *Sponsorship) PrimaryKey() crud.PrimaryKey {
+	if s.Domain == "" || s.Sponsor.Empty() {
+		return nil
+	}
+	return crud.NewPrimaryKey(append(lengthPrefixed(s.Starname()), s.Sponsor...))
+}
+
+func (s *Sponsorship) SecondaryKeys() []crud.SecondaryKey {
+	var sk []crud.SecondaryKey
+	// index by sponsor
+	if !s.Sponsor.Empty() {
+		sk = append(sk, crud.NewSecondaryKey(SponsorshipSponsorIndex, s.Sponsor))
+	}
+	// index by starname
+	if s.Domain != "" {
+		sk = append(sk, crud.NewSecondaryKey(SponsorshipStarnameIndex, lengthPrefixed(s.Starname())))
+	}
+	return sk
+}
@@ -1,10 +1,12 @@
 package types
 
 import (
+	"encoding/binary"
 	"strings"
 
 	"github.com/cosmos/cosmos-sdk/codec"
 	crud "github.com/iov-one/cosmos-sdk-crud/pkg/crud/types"
+	"github.com/iov-one/iovns/pkg/idn"
 	"github.com/iov-one/iovns/pkg/utils"
 
 	"github.com/cosmos/cosmos-sdk/types/errors"
@@ -13,9 +15,11 @@ import (
 )
 
 const DomainAdminIndex = 0x1
+const DomainSkeletonIndex = 0x2
 const AccountAdminIndex = 0x1
 const AccountDomainIndex = 0x2
 const AccountResourcesIndex = 0x3
+const AccountSkeletonIndex = 0x4
 
 // StarnameSeparator defines the starname separator identifier
 const StarnameSeparator = "*"
@@ -42,10 +46,31 @@ func (d *Domain) PrimaryKey() crud.PrimaryKey {
 }
 
 func (d *Domain) SecondaryKeys() []crud.SecondaryKey {
-	if d.Admin.Empty() {
-		return nil
+	var sk []crud.SecondaryKey
+	// index by admin
+	if !d.Admin.Empty() {
+		sk = append(sk, crud.NewSecondaryKey(DomainAdminIndex, d.Admin))
+	}
+	// index by skeleton
+	if d.Name != "" {
+		sk = append(sk, crud.NewSecondaryKey(DomainSkeletonIndex, skeletonKey(d.Name)))
 	}
-	return []crud.SecondaryKey{crud.NewSecondaryKey(DomainAdminIndex, d.Admin)}
+	return sk
+}
+
+// DomainSkeletonFilter is used to filter domains
+// which are visually confusable with a domain name
+type DomainSkeletonFilter struct {
+	// Name is the domain name to match
+	Name string
+}
+
+func (f *DomainSkeletonFilter) PrimaryKey() crud.PrimaryKey {
+	return nil
+}
+
+func (f *DomainSkeletonFilter) SecondaryKeys() []crud.SecondaryKey {
+	return []crud.SecondaryKey{crud.NewSecondaryKey(DomainSkeletonIndex, skeletonKey(f.Name))}
 }
 
 // DomainType defines the type of the domain
@@ -141,10 +166,44 @@ func (a *Account) SecondaryKeys() []crud.SecondaryKey {
 		// append resource
 		sk = append(sk, crud.NewSecondaryKey(AccountResourcesIndex, []byte(resKey)))
 	}
+	// index by skeleton
+	if len(a.Domain) != 0 && a.Name != nil {
+		sk = append(sk, crud.NewSecondaryKey(AccountSkeletonIndex, append(lengthPrefixed(a.Domain), skeletonKey(*a.Name)...)))
+	}
 	// return keys
 	return sk
 }
 
+// AccountSkeletonFilter is used to filter the accounts of a
+// domain which are visually confusable with an account name
+type AccountSkeletonFilter struct {
+	// Domain is the domain of the accounts
+	Domain string
+	// Name is the account name to match
+	Name string
+}
+
+func (f *AccountSkeletonFilter) PrimaryKey() crud.PrimaryKey {
+	return nil
+}
+
+func (f *AccountSkeletonFilter) SecondaryKeys() []crud.SecondaryKey {
+	return []crud.SecondaryKey{crud.NewSecondaryKey(AccountSkeletonIndex, append(lengthPrefixed(f.Domain), skeletonKey(f.Name)...))}
+}
+
+// skeletonKey returns the skeleton index key of a name, keys are length prefixed
+// as the index is iterated by prefix and a skeleton can be the prefix of another
+func skeletonKey(name string) []byte {
+	return lengthPrefixed(idn.Skeleton(name))
+}
+
+// lengthPrefixed returns the string prefixed by its big endian uint32 length
+func lengthPrefixed(s string) []byte {
+	b := make([]byte, 4, 4+len(s))
+	binary.BigEndian.PutUint32(b, uint32(len(s)))
+	return append(b, s...)
+}
+
 // Resource defines a resource an account can resolve to
 type Resource struct {
 	// URI defines the ID of the resource